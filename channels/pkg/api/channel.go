@@ -54,6 +54,9 @@ type AddonSpec struct {
 	// Manifest is the URL to the manifest that should be applied
 	Manifest *string `json:"manifest,omitempty"`
 
+	// Chart references a Helm chart that is rendered to produce the manifest, instead of Manifest.
+	Chart *ChartSpec `json:"chart,omitempty"`
+
 	// ManifestHash is the sha256 hash of our manifest
 	ManifestHash string `json:"manifestHash,omitempty"`
 
@@ -82,6 +85,18 @@ type AddonSpec struct {
 	Prune *PruneSpec `json:"prune,omitempty"`
 }
 
+// ChartSpec references a Helm chart.
+type ChartSpec struct {
+	// Repository is the URL of the chart repository, or an OCI registry reference prefixed with oci://.
+	Repository string `json:"repository,omitempty"`
+	// Name is the name of the chart in the repository.
+	Name string `json:"name,omitempty"`
+	// Version is the exact version of the chart.
+	Version string `json:"version,omitempty"`
+	// Values holds the values used to render the chart, merged over the chart defaults.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
 // PruneSpec specifies how old objects should be removed (pruned).
 type PruneSpec struct {
 	// Kinds specifies the objects to be pruned, by Kind.
@@ -114,6 +129,9 @@ func (a *Addons) Verify() error {
 		if addon.KubernetesVersion != "" {
 			return fmt.Errorf("bootstrap addon %q has a KubernetesVersion", values.StringValue(addon.Name))
 		}
+		if addon.Manifest != nil && addon.Chart != nil {
+			return fmt.Errorf("addon %q has both a manifest and a chart", values.StringValue(addon.Name))
+		}
 	}
 
 	return nil
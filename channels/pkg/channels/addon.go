@@ -22,9 +22,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 
 	"go.uber.org/multierr"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/vfs"
 
 	certmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
@@ -86,10 +88,29 @@ func (m *AddonMenu) MergeAddons(o *AddonMenu) {
 }
 
 func (a *Addon) ChannelVersion() *ChannelVersion {
+	manifestHash := a.Spec.ManifestHash
+	if manifestHash == "" && a.Spec.Chart != nil {
+		// Without a precomputed hash, we reapply whenever the chart reference or values change
+		chartHash, err := hashChart(a.Spec.Chart)
+		if err != nil {
+			klog.Warningf("unable to hash chart for addon %q: %v", a.Name, err)
+		} else {
+			manifestHash = chartHash
+		}
+	}
+
+	var manifestLocation string
+	if a.Spec.Manifest != nil {
+		if manifestURL, err := a.GetManifestFullUrl(); err == nil {
+			manifestLocation = manifestURL.String()
+		}
+	}
+
 	return &ChannelVersion{
 		Channel:          &a.ChannelName,
 		Id:               a.Spec.Id,
-		ManifestHash:     a.Spec.ManifestHash,
+		ManifestHash:     manifestHash,
+		ManifestLocation: manifestLocation,
 		SystemGeneration: CurrentSystemGeneration,
 	}
 }
@@ -157,7 +178,36 @@ func (a *Addon) GetManifestFullUrl() (*url.URL, error) {
 	return manifestURL, nil
 }
 
-func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, pruner *Pruner, applier Applier, existingVersion *ChannelVersion) (*AddonUpdate, error) {
+// loadManifest returns the manifest to apply and a description of where it came from.
+// If the addon references a chart rather than a manifest, the chart is rendered.
+func (a *Addon) loadManifest(ctx context.Context, vfsContext *vfs.VFSContext) ([]byte, string, error) {
+	if a.Spec.Manifest == nil && a.Spec.Chart != nil {
+		chart := a.Spec.Chart
+		source := chart.Repository + "/" + chart.Name + ":" + chart.Version
+		data, err := RenderChart(ctx, vfsContext, chart, a.Name, a.GetNamespace())
+		if err != nil {
+			return nil, "", fmt.Errorf("error rendering chart %q: %w", source, err)
+		}
+		return data, source, nil
+	}
+
+	manifestURL, err := a.GetManifestFullUrl()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
+	data, err := vfsContext.ReadFile(manifestURL.String())
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading manifest: %w", err)
+	}
+	return data, manifestURL.String(), nil
+}
+
+// EnsureUpdated applies the addon if it is not installed or has changed.
+// If rolloutTimeout is non-zero, upgrades are rolled back if the Deployments and DaemonSets
+// of the new version do not become available within the timeout.
+func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, pruner *Pruner, applier Applier, existingVersion *ChannelVersion, rolloutTimeout time.Duration) (*AddonUpdate, error) {
 	required, err := a.GetRequiredUpdates(ctx, k8sClient, cmClient, existingVersion)
 	if err != nil {
 		return nil, err
@@ -169,7 +219,7 @@ func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k
 	var merr error
 
 	if required.NewVersion != nil {
-		err := a.updateAddon(ctx, k8sClient, vfsContext, pruner, applier, required)
+		err := a.updateAddon(ctx, k8sClient, vfsContext, pruner, applier, required, rolloutTimeout)
 		if err != nil {
 			merr = multierr.Append(merr, err)
 		}
@@ -183,20 +233,54 @@ func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k
 	return required, merr
 }
 
-func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, pruner *Pruner, applier Applier, required *AddonUpdate) error {
-	manifestURL, err := a.GetManifestFullUrl()
+func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, pruner *Pruner, applier Applier, required *AddonUpdate, rolloutTimeout time.Duration) error {
+	channel := a.buildChannel()
+
+	failed, err := channel.IsFailedVersion(ctx, k8sClient, required.NewVersion)
 	if err != nil {
 		return err
 	}
+	if failed {
+		return fmt.Errorf("version %s previously failed to roll out and was rolled back; remove the %s annotation from secret %s/%s to retry", required.NewVersion, failedVersionAnnotation, channel.Namespace, channel.HistoryName())
+	}
 
-	klog.Infof("Applying update from %q", manifestURL)
+	data, source, err := a.loadManifest(ctx, vfsContext)
+	if err != nil {
+		return err
+	}
 
-	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
-	data, err := vfsContext.ReadFile(manifestURL.String())
+	klog.Infof("Applying update from %q", source)
+
+	if err := a.applyManifest(ctx, pruner, applier, data); err != nil {
+		return fmt.Errorf("error updating addon from %q: %w", source, err)
+	}
+
+	// We only gate upgrades; on first install there is nothing to roll back to,
+	// and workloads may not be schedulable until the cluster has finished coming up.
+	if required.ExistingVersion != nil && rolloutTimeout > 0 {
+		if err := waitForRollout(ctx, k8sClient, data, rolloutTimeout); err != nil {
+			return a.rollback(ctx, k8sClient, vfsContext, pruner, applier, required, rolloutTimeout, fmt.Errorf("addon %q from %q failed to roll out: %w", a.Name, source, err))
+		}
+	}
+
+	if err := a.AddNeedsUpdateLabel(ctx, k8sClient, required); err != nil {
+		return fmt.Errorf("error adding needs-update label: %v", err)
+	}
+
+	err = channel.SetInstalledVersion(ctx, k8sClient, a.ChannelVersion())
 	if err != nil {
-		return fmt.Errorf("error reading manifest: %w", err)
+		return fmt.Errorf("error applying annotation to record addon installation: %v", err)
 	}
 
+	if err := channel.SetAppliedManifest(ctx, k8sClient, a.ChannelVersion(), data); err != nil {
+		// Not fatal: the addon is installed, we just won't be able to roll back to this version
+		klog.Warningf("unable to record manifest of addon %q: %v", a.Name, err)
+	}
+	return nil
+}
+
+// applyManifest applies the manifest and prunes objects that are no longer in it.
+func (a *Addon) applyManifest(ctx context.Context, pruner *Pruner, applier Applier, data []byte) error {
 	var merr error
 	var applyError, pruneError error
 
@@ -218,20 +302,53 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 		}
 	}
 
-	if merr != nil {
-		return fmt.Errorf("error updating addon from %q: %w", manifestURL, merr)
+	return merr
+}
+
+// rollback re-applies the manifest of the installed version, after the new version failed to roll out.
+// The new version is recorded as failed, so that we don't apply it again.
+func (a *Addon) rollback(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, pruner *Pruner, applier Applier, required *AddonUpdate, rolloutTimeout time.Duration, rolloutErr error) error {
+	channel := a.buildChannel()
+
+	previous, err := a.previousManifest(ctx, k8sClient, vfsContext, required.ExistingVersion)
+	if err != nil {
+		return multierr.Append(rolloutErr, err)
+	}
+	if previous == nil {
+		klog.Warningf("no manifest recorded for %s, cannot roll back addon %q", required.ExistingVersion, a.Name)
+		return rolloutErr
 	}
 
-	if err := a.AddNeedsUpdateLabel(ctx, k8sClient, required); err != nil {
-		return fmt.Errorf("error adding needs-update label: %v", err)
+	klog.Warningf("%v; rolling back to %s", rolloutErr, required.ExistingVersion)
+
+	if err := a.applyManifest(ctx, pruner, applier, previous); err != nil {
+		return multierr.Append(rolloutErr, fmt.Errorf("error rolling back: %w", err))
+	}
+	if err := channel.SetFailedVersion(ctx, k8sClient, required.NewVersion); err != nil {
+		return multierr.Append(rolloutErr, err)
+	}
+	if err := waitForRollout(ctx, k8sClient, previous, rolloutTimeout); err != nil {
+		return multierr.Append(rolloutErr, fmt.Errorf("rolled back version did not become available: %w", err))
 	}
 
-	channel := a.buildChannel()
-	err = channel.SetInstalledVersion(ctx, k8sClient, a.ChannelVersion())
-	if err != nil {
-		return fmt.Errorf("error applying annotation to record addon installation: %v", err)
+	return fmt.Errorf("%w; rolled back to %s", rolloutErr, required.ExistingVersion)
+}
+
+// previousManifest returns the manifest of the installed version. It is re-read from the location recorded in the version
+// if the manifest there still has the recorded hash; otherwise the copy recorded once the version rolled out is used.
+func (a *Addon) previousManifest(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, version *ChannelVersion) ([]byte, error) {
+	if version.ManifestLocation != "" && version.ManifestHash != "" {
+		data, err := vfsContext.ReadFile(version.ManifestLocation)
+		if err != nil {
+			klog.Warningf("unable to re-read manifest of %s: %v", version, err)
+		} else if hash, err := utils.HashString(string(data)); err == nil && hash == version.ManifestHash {
+			return data, nil
+		} else {
+			klog.V(2).Infof("manifest at %q has changed since %s was applied", version.ManifestLocation, version)
+		}
 	}
-	return nil
+
+	return a.buildChannel().GetAppliedManifest(ctx, k8sClient, version)
 }
 
 func (a *Addon) AddNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.Interface, required *AddonUpdate) error {
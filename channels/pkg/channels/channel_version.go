@@ -51,6 +51,10 @@ type ChannelVersion struct {
 	Id           string  `json:"id,omitempty"`
 	ManifestHash string  `json:"manifestHash,omitempty"`
 
+	// ManifestLocation is where the manifest was read from, so that it can be re-read to roll back a failed upgrade.
+	// It is not compared when deciding whether to reapply, as the manifest hash already is.
+	ManifestLocation string `json:"manifestLocation,omitempty"`
+
 	// SystemGeneration holds the generation of the channels functionality.
 	// It is used so that we reapply when we introduce new features, such as prune.
 	SystemGeneration int `json:"systemGeneration,omitempty"`
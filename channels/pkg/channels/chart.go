@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	// helmChartLayerMediaType is the media type of the chart archive in an OCI chart artifact.
+	helmChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// helmHookAnnotation marks objects that helm runs as hooks rather than installing with the release.
+	helmHookAnnotation = "helm.sh/hook"
+)
+
+// clusterScopedGroupKinds are the well-known kinds that must not be placed in the release namespace.
+var clusterScopedGroupKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:                                                    true,
+	{Group: "", Kind: "PersistentVolume"}:                                             true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 true,
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "policy", Kind: "PodSecurityPolicy"}:                                      true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+}
+
+// chartRepositoryIndex is the subset of a chart repository index.yaml that we need to locate a chart.
+type chartRepositoryIndex struct {
+	Entries map[string][]struct {
+		Version string   `json:"version"`
+		URLs    []string `json:"urls"`
+		Digest  string   `json:"digest"`
+	} `json:"entries"`
+}
+
+// RenderChart fetches the chart and renders it to a manifest, in the same way as `helm template`.
+// Namespaced objects without a namespace are placed in the release namespace, and hooks are dropped.
+func RenderChart(ctx context.Context, vfsContext *vfs.VFSContext, spec *api.ChartSpec, releaseName string, namespace string) ([]byte, error) {
+	data, err := fetchChart(ctx, vfsContext, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error loading chart %q: %w", spec.Name, err)
+	}
+
+	return renderChart(c, spec.Values, releaseName, namespace)
+}
+
+func renderChart(c *chart.Chart, values map[string]interface{}, releaseName string, namespace string) ([]byte, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(c, values); err != nil {
+		return nil, fmt.Errorf("error processing dependencies of chart %q: %w", c.Name(), err)
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(c, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("error building values for chart %q: %w", c.Name(), err)
+	}
+
+	rendered, err := engine.Render(c, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering chart %q: %w", c.Name(), err)
+	}
+
+	var sections [][]byte
+	for _, crd := range c.CRDObjects() {
+		sections = append(sections, crd.File.Data)
+	}
+
+	var filenames []string
+	for filename := range rendered {
+		if path.Base(filename) == "NOTES.txt" {
+			continue
+		}
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	for _, filename := range filenames {
+		sections = append(sections, []byte(rendered[filename]))
+	}
+
+	var objects kubemanifest.ObjectList
+	for _, section := range sections {
+		sectionObjects, err := kubemanifest.LoadObjectsFrom(section)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing rendered chart %q: %w", c.Name(), err)
+		}
+		objects = append(objects, sectionObjects...)
+	}
+
+	clusterScoped := make(map[schema.GroupKind]bool)
+	for gk := range clusterScopedGroupKinds {
+		clusterScoped[gk] = true
+	}
+	for _, object := range objects {
+		if object.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}) {
+			continue
+		}
+		u := object.ToUnstructured()
+		if scope, _, _ := unstructured.NestedString(u.Object, "spec", "scope"); scope == "Cluster" {
+			group, _, _ := unstructured.NestedString(u.Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(u.Object, "spec", "names", "kind")
+			clusterScoped[schema.GroupKind{Group: group, Kind: kind}] = true
+		}
+	}
+
+	var manifest kubemanifest.ObjectList
+	for _, object := range objects {
+		if object.IsEmptyObject() {
+			continue
+		}
+		u := object.ToUnstructured()
+		if hook := u.GetAnnotations()[helmHookAnnotation]; hook != "" {
+			klog.Warningf("skipping %s %q from chart %q: helm hooks (%s) are not supported", object.Kind(), object.GetName(), c.Name(), hook)
+			continue
+		}
+		if u.GetNamespace() == "" && !clusterScoped[object.GroupVersionKind().GroupKind()] {
+			u.SetNamespace(namespace)
+		}
+		manifest = append(manifest, object)
+	}
+
+	return manifest.ToYAML()
+}
+
+// hashChart returns a hash of the chart reference and values, which changes whenever the rendered manifest could.
+func hashChart(spec *api.ChartSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return utils.HashString(string(data))
+}
+
+// fetchChart returns the chart archive, from either an OCI registry or a chart repository.
+func fetchChart(ctx context.Context, vfsContext *vfs.VFSContext, spec *api.ChartSpec) ([]byte, error) {
+	if spec.Repository == "" || spec.Name == "" || spec.Version == "" {
+		return nil, fmt.Errorf("chart must specify repository, name and version")
+	}
+
+	if strings.HasPrefix(spec.Repository, "oci://") {
+		return fetchOCIChart(ctx, spec)
+	}
+
+	repository := strings.TrimSuffix(spec.Repository, "/") + "/"
+	repositoryURL, err := url.Parse(repository)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chart repository %q: %w", spec.Repository, err)
+	}
+
+	indexLocation := repositoryURL.ResolveReference(&url.URL{Path: "index.yaml"}).String()
+	klog.Infof("Loading chart repository index from %q", indexLocation)
+	indexData, err := vfsContext.ReadFile(indexLocation)
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart repository index: %w", err)
+	}
+	index := &chartRepositoryIndex{}
+	if err := utils.YamlUnmarshal(indexData, index); err != nil {
+		return nil, fmt.Errorf("error parsing chart repository index %q: %w", indexLocation, err)
+	}
+
+	for _, entry := range index.Entries[spec.Name] {
+		if entry.Version != spec.Version {
+			continue
+		}
+		if len(entry.URLs) == 0 {
+			return nil, fmt.Errorf("chart %s %s in %q has no urls", spec.Name, spec.Version, spec.Repository)
+		}
+		chartURL, err := url.Parse(entry.URLs[0])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing url %q of chart %s %s: %w", entry.URLs[0], spec.Name, spec.Version, err)
+		}
+		chartLocation := repositoryURL.ResolveReference(chartURL).String()
+
+		klog.Infof("Downloading chart from %q", chartLocation)
+		data, err := vfsContext.ReadFile(chartLocation)
+		if err != nil {
+			return nil, fmt.Errorf("error reading chart: %w", err)
+		}
+		if entry.Digest != "" {
+			hash := sha256.Sum256(data)
+			if actual := hex.EncodeToString(hash[:]); actual != entry.Digest {
+				return nil, fmt.Errorf("chart %q has digest %s, expected %s", chartLocation, actual, entry.Digest)
+			}
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("chart %s version %s not found in %q", spec.Name, spec.Version, spec.Repository)
+}
+
+func fetchOCIChart(ctx context.Context, spec *api.ChartSpec) ([]byte, error) {
+	reference := strings.TrimSuffix(strings.TrimPrefix(spec.Repository, "oci://"), "/") + "/" + spec.Name + ":" + spec.Version
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chart reference %q: %w", reference, err)
+	}
+
+	klog.Infof("Downloading chart from %q", ref)
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching chart %q: %w", ref, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest of chart %q: %w", ref, err)
+	}
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != helmChartLayerMediaType {
+			continue
+		}
+		layer, err := img.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching chart %q: %w", ref, err)
+		}
+		r, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("error fetching chart %q: %w", ref, err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("%q is not a helm chart", ref)
+}
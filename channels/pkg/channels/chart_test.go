@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func buildTestChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "example",
+			Version:    "1.2.3",
+		},
+		Values: map[string]interface{}{
+			"image":    "registry.k8s.io/example:v1",
+			"replicas": 1,
+		},
+		Templates: []*chart.File{
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+      - name: example
+        image: {{ .Values.image }}
+`)},
+			{Name: "templates/clusterrole.yaml", Data: []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ .Release.Name }}
+`)},
+			{Name: "templates/widget.yaml", Data: []byte(`apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: {{ .Release.Name }}
+`)},
+			{Name: "templates/configmap.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: other
+`)},
+			{Name: "templates/test.yaml", Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-test
+  annotations:
+    helm.sh/hook: test
+`)},
+			{Name: "templates/NOTES.txt", Data: []byte(`Installed {{ .Release.Name }}`)},
+		},
+		Files: []*chart.File{
+			{Name: "crds/widgets.yaml", Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  names:
+    kind: Widget
+`)},
+		},
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	manifest, err := renderChart(buildTestChart(), map[string]interface{}{"replicas": 3}, "release", "monitoring")
+	if err != nil {
+		t.Fatalf("renderChart: %v", err)
+	}
+
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		t.Fatalf("parsing rendered manifest: %v", err)
+	}
+
+	var actual []string
+	for _, object := range objects {
+		actual = append(actual, fmt.Sprintf("%s/%s/%s", object.Kind(), object.GetNamespace(), object.GetName()))
+	}
+	expected := []string{
+		"CustomResourceDefinition//widgets.example.com",
+		"ClusterRole//release",
+		"ConfigMap/other/release",
+		"Deployment/monitoring/release",
+		"Widget//release",
+	}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected objects\nexpected: %v\nactual:   %v", expected, actual)
+	}
+
+	if !strings.Contains(string(manifest), "replicas: 3") {
+		t.Errorf("values were not applied to rendered manifest:\n%s", manifest)
+	}
+	if !strings.Contains(string(manifest), "image: registry.k8s.io/example:v1") {
+		t.Errorf("default values were not applied to rendered manifest:\n%s", manifest)
+	}
+}
+
+func TestRenderChartFromRepository(t *testing.T) {
+	ctx := context.Background()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	archivePath, err := chartutil.Save(buildTestChart(), t.TempDir())
+	if err != nil {
+		t.Fatalf("saving chart: %v", err)
+	}
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("reading chart: %v", err)
+	}
+	hash := sha256.Sum256(archive)
+
+	writeFile := func(p string, data []byte) {
+		location, err := vfsContext.BuildVfsPath(p)
+		if err != nil {
+			t.Fatalf("building path: %v", err)
+		}
+		if err := location.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+			t.Fatalf("writing %s: %v", p, err)
+		}
+	}
+	writeFile("memfs://charts/packages/"+filepath.Base(archivePath), archive)
+	writeFile("memfs://charts/index.yaml", []byte(fmt.Sprintf(`apiVersion: v1
+entries:
+  example:
+  - version: 1.2.3
+    digest: %s
+    urls:
+    - packages/%s
+  - version: 1.0.0
+    digest: 0000
+    urls:
+    - packages/example-1.0.0.tgz
+`, hex.EncodeToString(hash[:]), filepath.Base(archivePath))))
+
+	spec := &api.ChartSpec{Repository: "memfs://charts", Name: "example", Version: "1.2.3"}
+	manifest, err := RenderChart(ctx, vfsContext, spec, "release", "kube-system")
+	if err != nil {
+		t.Fatalf("RenderChart: %v", err)
+	}
+	if !strings.Contains(string(manifest), "kind: Deployment") {
+		t.Errorf("unexpected manifest:\n%s", manifest)
+	}
+
+	spec.Version = "1.0.0"
+	if _, err := RenderChart(ctx, vfsContext, spec, "release", "kube-system"); err == nil {
+		t.Errorf("expected error for missing chart archive")
+	}
+
+	spec.Version = "2.0.0"
+	if _, err := RenderChart(ctx, vfsContext, spec, "release", "kube-system"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error for unknown version, got %v", err)
+	}
+}
+
+func TestChartChannelVersion(t *testing.T) {
+	addon := &Addon{
+		Name: "example",
+		Spec: &api.AddonSpec{
+			Chart: &api.ChartSpec{Repository: "oci://registry.example.com/charts", Name: "example", Version: "1.2.3"},
+		},
+	}
+	v1 := addon.ChannelVersion()
+	if v1.ManifestHash == "" {
+		t.Fatalf("expected chart addon to have a manifest hash")
+	}
+
+	addon.Spec.Chart.Values = map[string]interface{}{"replicas": 2}
+	v2 := addon.ChannelVersion()
+	if !v2.replaces(addon.Name, v1) {
+		t.Errorf("expected change in values to replace existing version")
+	}
+
+	addon.Spec.ManifestHash = "abc"
+	if v := addon.ChannelVersion(); v.ManifestHash != "abc" {
+		t.Errorf("expected explicit manifest hash to be used, got %q", v.ManifestHash)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// ObjectChangeAction describes what applying an addon would do to an object.
+type ObjectChangeAction string
+
+const (
+	ObjectChangeCreate    ObjectChangeAction = "create"
+	ObjectChangeUpdate    ObjectChangeAction = "update"
+	ObjectChangeUnchanged ObjectChangeAction = "unchanged"
+	ObjectChangePrune     ObjectChangeAction = "prune"
+)
+
+// ObjectChange is a change that applying an addon would make to one object.
+type ObjectChange struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Action           ObjectChangeAction
+	// Diff is a diff of the object as YAML; it is empty for unchanged objects.
+	Diff string
+}
+
+// Differ computes the changes that applying a manifest would make, using server-side-apply in dry-run mode.
+type Differ struct {
+	Client     dynamic.Interface
+	RESTMapper meta.RESTMapper
+}
+
+// Diff returns the changes that updating the addon would make, including the objects that would be pruned.
+// The cluster is not changed.
+func (a *Addon) Diff(ctx context.Context, vfsContext *vfs.VFSContext, differ *Differ, pruner *Pruner) ([]*ObjectChange, error) {
+	data, _, err := a.loadManifest(ctx, vfsContext)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := differ.Diff(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	prunable, err := pruner.FindPrunable(ctx, data, a.Spec.Prune)
+	if err != nil {
+		return nil, fmt.Errorf("error finding objects to prune: %w", err)
+	}
+	for _, object := range prunable {
+		object, _ = maskSecretData(object, nil)
+		y, err := cleanObjectYAML(object)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &ObjectChange{
+			GroupVersionKind: object.GroupVersionKind(),
+			Namespace:        object.GetNamespace(),
+			Name:             object.GetName(),
+			Action:           ObjectChangePrune,
+			Diff:             diff.FormatDiff(y, ""),
+		})
+	}
+
+	return changes, nil
+}
+
+// Diff returns the changes that applying the manifest would make to each object in it.
+func (d *Differ) Diff(ctx context.Context, manifest []byte) ([]*ObjectChange, error) {
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
+	}
+
+	var changes []*ObjectChange
+	for _, object := range objects {
+		change, err := d.diffObject(ctx, object.ToUnstructured())
+		if err != nil {
+			return nil, fmt.Errorf("error computing diff for %s %s/%s: %w", object.Kind(), object.GetNamespace(), object.GetName(), err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (d *Differ) diffObject(ctx context.Context, desired *unstructured.Unstructured) (*ObjectChange, error) {
+	gvk := desired.GroupVersionKind()
+
+	restMapping, err := d.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// Typically a custom resource whose CRD is created by the same manifest,
+			// so the server can't dry-run it yet.
+			return buildObjectChange(nil, desired)
+		}
+		return nil, fmt.Errorf("error getting rest mapping for %v: %w", gvk, err)
+	}
+
+	var resource dynamic.ResourceInterface
+	if restMapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = d.Client.Resource(restMapping.Resource).Namespace(desired.GetNamespace())
+	} else {
+		resource = d.Client.Resource(restMapping.Resource)
+	}
+
+	current, err := resource.Get(ctx, desired.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		current = nil
+	}
+
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object to JSON: %w", err)
+	}
+
+	// We use the same options as ClientApplier, so that the dry-run matches what we would apply
+	force := true
+	applied, err := resource.Patch(ctx, desired.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: "kops",
+		Force:        &force,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error from dry-run apply: %w", err)
+	}
+
+	return buildObjectChange(current, applied)
+}
+
+// buildObjectChange compares the current object, which is nil if it doesn't exist, with the object after applying.
+func buildObjectChange(current, applied *unstructured.Unstructured) (*ObjectChange, error) {
+	change := &ObjectChange{
+		GroupVersionKind: applied.GroupVersionKind(),
+		Namespace:        applied.GetNamespace(),
+		Name:             applied.GetName(),
+	}
+
+	current, applied = maskSecretData(current, applied)
+
+	appliedYAML, err := cleanObjectYAML(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == nil {
+		change.Action = ObjectChangeCreate
+		change.Diff = diff.FormatDiff("", appliedYAML)
+		return change, nil
+	}
+
+	currentYAML, err := cleanObjectYAML(current)
+	if err != nil {
+		return nil, err
+	}
+	if currentYAML == appliedYAML {
+		change.Action = ObjectChangeUnchanged
+		return change, nil
+	}
+
+	change.Action = ObjectChangeUpdate
+	change.Diff = diff.FormatDiff(currentYAML, appliedYAML)
+	return change, nil
+}
+
+// maskSecretData returns copies of Secrets with their values replaced by "***", as kubectl diff does,
+// so that a diff only shows which keys are added, removed or changed.
+// Either object may be nil; objects that are not Secrets are returned unchanged.
+func maskSecretData(from, to *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured) {
+	if !isSecret(from) && !isSecret(to) {
+		return from, to
+	}
+	if from != nil {
+		from = from.DeepCopy()
+	}
+	if to != nil {
+		to = to.DeepCopy()
+	}
+
+	for _, field := range []string{"data", "stringData"} {
+		fromData := secretValues(from, field)
+		toData := secretValues(to, field)
+		for key, fromValue := range fromData {
+			toValue, found := toData[key]
+			switch {
+			case !found:
+				fromData[key] = "***"
+			case reflect.DeepEqual(fromValue, toValue):
+				fromData[key] = "***"
+				toData[key] = "***"
+			default:
+				fromData[key] = "*** (before)"
+				toData[key] = "*** (after)"
+			}
+		}
+		for key := range toData {
+			if _, found := fromData[key]; !found {
+				toData[key] = "***"
+			}
+		}
+	}
+
+	// The configuration last applied by kubectl holds the values too
+	fromAnnotations := secretValues(from, "metadata", "annotations")
+	toAnnotations := secretValues(to, "metadata", "annotations")
+	fromLastApplied, fromFound := fromAnnotations[lastAppliedConfigurationAnnotation]
+	toLastApplied, toFound := toAnnotations[lastAppliedConfigurationAnnotation]
+	switch {
+	case fromFound && toFound && fromLastApplied != toLastApplied:
+		fromAnnotations[lastAppliedConfigurationAnnotation] = "*** (before)"
+		toAnnotations[lastAppliedConfigurationAnnotation] = "*** (after)"
+	case fromFound && toFound:
+		fromAnnotations[lastAppliedConfigurationAnnotation] = "***"
+		toAnnotations[lastAppliedConfigurationAnnotation] = "***"
+	case fromFound:
+		fromAnnotations[lastAppliedConfigurationAnnotation] = "***"
+	case toFound:
+		toAnnotations[lastAppliedConfigurationAnnotation] = "***"
+	}
+
+	return from, to
+}
+
+const lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func isSecret(object *unstructured.Unstructured) bool {
+	return object != nil && object.GroupVersionKind().GroupKind() == schema.GroupKind{Kind: "Secret"}
+}
+
+// secretValues returns the map at the given path of the object, which is nil if the object or the map doesn't exist.
+// Setting the keys of the map changes the object.
+func secretValues(object *unstructured.Unstructured, fields ...string) map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+	m := object.Object
+	for _, field := range fields {
+		m, _ = m[field].(map[string]interface{})
+	}
+	return m
+}
+
+// cleanObjectYAML renders the object as YAML, without the status and the metadata fields maintained by the server.
+func cleanObjectYAML(object *unstructured.Unstructured) (string, error) {
+	object = object.DeepCopy()
+	unstructured.RemoveNestedField(object.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+		unstructured.RemoveNestedField(object.Object, "metadata", field)
+	}
+
+	y, err := yaml.Marshal(object.Object)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling %s %s/%s to yaml: %w", object.GetKind(), object.GetNamespace(), object.GetName(), err)
+	}
+	return string(y), nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testConfigMap(data string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "app",
+			"namespace":       "kube-system",
+			"resourceVersion": "12",
+			"uid":             "1234",
+			"managedFields":   []interface{}{map[string]interface{}{"manager": "kops"}},
+		},
+		"data": map[string]interface{}{"key": data},
+	}}
+}
+
+func TestBuildObjectChange(t *testing.T) {
+	grid := []struct {
+		name     string
+		current  *unstructured.Unstructured
+		applied  *unstructured.Unstructured
+		action   ObjectChangeAction
+		contains []string
+	}{
+		{name: "create", applied: testConfigMap("a"), action: ObjectChangeCreate, contains: []string{"+ data:", "+   key: a"}},
+		{name: "update", current: testConfigMap("a"), applied: testConfigMap("b"), action: ObjectChangeUpdate, contains: []string{"-   key: a", "+   key: b"}},
+		{name: "unchanged", current: testConfigMap("a"), applied: testConfigMap("a"), action: ObjectChangeUnchanged},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			change, err := buildObjectChange(g.current, g.applied)
+			if err != nil {
+				t.Fatalf("buildObjectChange: %v", err)
+			}
+			if change.Action != g.action {
+				t.Errorf("expected action %q, got %q", g.action, change.Action)
+			}
+			if change.Namespace != "kube-system" || change.Name != "app" || change.GroupVersionKind.Kind != "ConfigMap" {
+				t.Errorf("unexpected object %v %s/%s", change.GroupVersionKind, change.Namespace, change.Name)
+			}
+			if len(g.contains) == 0 && change.Diff != "" {
+				t.Errorf("expected no diff, got:\n%s", change.Diff)
+			}
+			for _, s := range g.contains {
+				if !strings.Contains(change.Diff, s) {
+					t.Errorf("expected diff to contain %q, got:\n%s", s, change.Diff)
+				}
+			}
+			for _, s := range []string{"resourceVersion", "uid", "managedFields"} {
+				if strings.Contains(change.Diff, s) {
+					t.Errorf("expected diff not to contain %q, got:\n%s", s, change.Diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffUnknownKind(t *testing.T) {
+	differ := &Differ{RESTMapper: meta.NewDefaultRESTMapper([]schema.GroupVersion{})}
+
+	manifest := `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`
+	changes, err := differ.Diff(context.Background(), []byte(manifest))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Action != ObjectChangeCreate || changes[0].Name != "widget" {
+		t.Fatalf("expected widget to be created, got %v", changes)
+	}
+	if !strings.Contains(changes[0].Diff, "+ kind: Widget") {
+		t.Errorf("unexpected diff:\n%s", changes[0].Diff)
+	}
+}
+
+func testSecret(data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "kube-system",
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"c2VjcmV0"}}`,
+			},
+		},
+		"data": data,
+	}}
+}
+
+func TestBuildObjectChangeMasksSecrets(t *testing.T) {
+	current := testSecret(map[string]interface{}{"password": "c2VjcmV0", "token": "dG9rZW4=", "removed": "b2xk"})
+	applied := testSecret(map[string]interface{}{"password": "bmV3", "token": "dG9rZW4=", "added": "YWRkZWQ="})
+
+	change, err := buildObjectChange(current, applied)
+	if err != nil {
+		t.Fatalf("buildObjectChange: %v", err)
+	}
+	if change.Action != ObjectChangeUpdate {
+		t.Errorf("expected action %q, got %q", ObjectChangeUpdate, change.Action)
+	}
+	for _, s := range []string{"-   password: '*** (before)'", "+   password: '*** (after)'", "-   removed: '***'", "+   added: '***'", "token: '***'"} {
+		if !strings.Contains(change.Diff, s) {
+			t.Errorf("expected diff to contain %q, got:\n%s", s, change.Diff)
+		}
+	}
+	for _, s := range []string{"c2VjcmV0", "bmV3", "dG9rZW4=", "b2xk", "YWRkZWQ="} {
+		if strings.Contains(change.Diff, s) {
+			t.Errorf("expected diff not to contain %q, got:\n%s", s, change.Diff)
+		}
+	}
+	if current.Object["data"].(map[string]interface{})["password"] != "c2VjcmV0" {
+		t.Errorf("expected the current object not to be modified")
+	}
+
+	change, err = buildObjectChange(testSecret(map[string]interface{}{"password": "c2VjcmV0"}), testSecret(map[string]interface{}{"password": "c2VjcmV0"}))
+	if err != nil {
+		t.Fatalf("buildObjectChange: %v", err)
+	}
+	if change.Action != ObjectChangeUnchanged {
+		t.Errorf("expected action %q, got %q", ObjectChangeUnchanged, change.Action)
+	}
+
+	change, err = buildObjectChange(nil, testSecret(map[string]interface{}{"password": "c2VjcmV0"}))
+	if err != nil {
+		t.Fatalf("buildObjectChange: %v", err)
+	}
+	if strings.Contains(change.Diff, "c2VjcmV0") || !strings.Contains(change.Diff, "+   password: '***'") {
+		t.Errorf("expected masked diff, got:\n%s", change.Diff)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// appliedVersionAnnotation records the ChannelVersion of the manifest stored in the history Secret.
+	appliedVersionAnnotation = AnnotationPrefix + "applied-version"
+	// failedVersionAnnotation records the ChannelVersion that last failed to roll out and was rolled back.
+	failedVersionAnnotation = AnnotationPrefix + "failed-version"
+
+	appliedManifestKey = "manifest.yaml.gz"
+)
+
+// HistoryName is the name of the Secret holding the last manifest that rolled out successfully,
+// which we re-apply if a later version fails to roll out and its manifest is no longer in the state store.
+// It is a Secret rather than a ConfigMap because manifests can contain Secrets, such as cloud credentials.
+func (c *Channel) HistoryName() string {
+	return c.Name + "-manifest"
+}
+
+func (c *Channel) getHistory(ctx context.Context, k8sClient kubernetes.Interface) (*corev1.Secret, error) {
+	secret, err := k8sClient.CoreV1().Secrets(c.Namespace).Get(ctx, c.HistoryName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+	}
+	return secret, nil
+}
+
+// GetAppliedManifest returns the manifest recorded for the version, or nil if it was not recorded.
+func (c *Channel) GetAppliedManifest(ctx context.Context, k8sClient kubernetes.Interface, version *ChannelVersion) ([]byte, error) {
+	secret, err := c.getHistory(ctx, k8sClient)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	if !sameVersion(secret.Annotations[appliedVersionAnnotation], version) {
+		return nil, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(secret.Data[appliedManifestKey]))
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest from secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+	}
+	manifest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest from secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+	}
+	return manifest, nil
+}
+
+// SetAppliedManifest records the manifest of a version that rolled out successfully, clearing any recorded failure.
+func (c *Channel) SetAppliedManifest(ctx context.Context, k8sClient kubernetes.Interface, version *ChannelVersion, manifest []byte) error {
+	value, err := version.Encode()
+	if err != nil {
+		return err
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err := w.Write(manifest); err != nil {
+		return fmt.Errorf("error compressing manifest: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error compressing manifest: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.HistoryName(),
+			Namespace: c.Namespace,
+			Annotations: map[string]string{
+				appliedVersionAnnotation: value,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			appliedManifestKey: compressed.Bytes(),
+		},
+	}
+
+	secrets := k8sClient.CoreV1().Secrets(c.Namespace)
+	existing, err := c.getHistory(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret.ResourceVersion = existing.ResourceVersion
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error recording manifest in secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+	}
+	return nil
+}
+
+// IsFailedVersion returns true if the version previously failed to roll out and was rolled back.
+func (c *Channel) IsFailedVersion(ctx context.Context, k8sClient kubernetes.Interface, version *ChannelVersion) (bool, error) {
+	secret, err := c.getHistory(ctx, k8sClient)
+	if err != nil || secret == nil {
+		return false, err
+	}
+	return sameVersion(secret.Annotations[failedVersionAnnotation], version), nil
+}
+
+// SetFailedVersion records that the version failed to roll out, so that we don't repeatedly apply it.
+func (c *Channel) SetFailedVersion(ctx context.Context, k8sClient kubernetes.Interface, version *ChannelVersion) error {
+	value, err := version.Encode()
+	if err != nil {
+		return err
+	}
+
+	secret, err := c.getHistory(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		// The previous manifest was re-read from the state store, so we record the failure with the same version
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.HistoryName(),
+				Namespace: c.Namespace,
+				Annotations: map[string]string{
+					failedVersionAnnotation: value,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		if _, err := k8sClient.CoreV1().Secrets(c.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error recording failed version in secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+		}
+		return nil
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[failedVersionAnnotation] = value
+	if _, err := k8sClient.CoreV1().Secrets(c.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error recording failed version in secret %s/%s: %w", c.Namespace, c.HistoryName(), err)
+	}
+	return nil
+}
+
+func sameVersion(encoded string, version *ChannelVersion) bool {
+	if encoded == "" || version == nil {
+		return false
+	}
+	recorded, err := ParseChannelVersion(encoded)
+	if err != nil {
+		klog.Warningf("ignoring invalid recorded version %q: %v", encoded, err)
+		return false
+	}
+	return stringValue(recorded.Channel) == stringValue(version.Channel) &&
+		recorded.Id == version.Id &&
+		recorded.ManifestHash == version.ManifestHash &&
+		recorded.SystemGeneration == version.SystemGeneration
+}
@@ -35,28 +35,77 @@ type Pruner struct {
 	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
 }
 
+// prunableObject is an object that is not in the manifest, and so would be deleted by Prune.
+type prunableObject struct {
+	gvr    schema.GroupVersionResource
+	object *unstructured.Unstructured
+}
+
 // Prune prunes objects not in the manifest, according to PruneSpec.
 func (p *Pruner) Prune(ctx context.Context, manifest []byte, spec *api.PruneSpec) error {
 	klog.Infof("Prune spec: %v", spec)
 
+	prunable, err := p.findPrunable(ctx, manifest, spec)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range prunable {
+		name := obj.object.GetName()
+		namespace := obj.object.GetNamespace()
+		key := namespace + "/" + name
+
+		klog.Infof("pruning %s %s", obj.gvr, key)
+
+		var resource dynamic.ResourceInterface
+		if namespace != "" {
+			resource = p.Client.Resource(obj.gvr).Namespace(namespace)
+		} else {
+			resource = p.Client.Resource(obj.gvr)
+		}
+
+		var opts v1.DeleteOptions
+		if err := resource.Delete(ctx, name, opts); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// FindPrunable returns the objects that Prune would delete, without deleting them.
+func (p *Pruner) FindPrunable(ctx context.Context, manifest []byte, spec *api.PruneSpec) ([]*unstructured.Unstructured, error) {
+	prunable, err := p.findPrunable(ctx, manifest, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, obj := range prunable {
+		objects = append(objects, obj.object)
+	}
+	return objects, nil
+}
+
+func (p *Pruner) findPrunable(ctx context.Context, manifest []byte, spec *api.PruneSpec) ([]prunableObject, error) {
 	if spec == nil {
-		return nil
+		return nil, nil
 	}
 
 	objects, err := kubemanifest.LoadObjectsFrom(manifest)
 	if err != nil {
-		return fmt.Errorf("failed to parse objects: %w", err)
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
 	}
 
 	objectsByKind := make(map[schema.GroupKind][]*kubemanifest.Object)
 	for _, object := range objects {
 		gv, err := schema.ParseGroupVersion(object.APIVersion())
 		if err != nil || gv.Version == "" {
-			return fmt.Errorf("failed to parse apiVersion %q", object.APIVersion())
+			return nil, fmt.Errorf("failed to parse apiVersion %q", object.APIVersion())
 		}
 		kind := object.Kind()
 		if kind == "" {
-			return fmt.Errorf("failed to find kind in object")
+			return nil, fmt.Errorf("failed to find kind in object")
 		}
 
 		gvk := gv.WithKind(kind)
@@ -64,23 +113,26 @@ func (p *Pruner) Prune(ctx context.Context, manifest []byte, spec *api.PruneSpec
 		objectsByKind[gk] = append(objectsByKind[gk], object)
 	}
 
+	var prunable []prunableObject
 	for i := range spec.Kinds {
 		pruneKind := &spec.Kinds[i]
 		gk := schema.GroupKind{Group: pruneKind.Group, Kind: pruneKind.Kind}
-		if err := p.pruneObjectsOfKind(ctx, gk, pruneKind, objectsByKind[gk]); err != nil {
-			return fmt.Errorf("failed to prune objects of kind %s: %w", gk, err)
+		kindPrunable, err := p.findPrunableObjectsOfKind(ctx, gk, pruneKind, objectsByKind[gk])
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune objects of kind %s: %w", gk, err)
 		}
+		prunable = append(prunable, kindPrunable...)
 	}
 
-	return nil
+	return prunable, nil
 }
 
-func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, spec *api.PruneKindSpec, keepObjects []*kubemanifest.Object) error {
+func (p *Pruner) findPrunableObjectsOfKind(ctx context.Context, gk schema.GroupKind, spec *api.PruneKindSpec, keepObjects []*kubemanifest.Object) ([]prunableObject, error) {
 	klog.Infof("pruning objects of kind: %v", gk)
 
 	restMapping, err := p.RESTMapper.RESTMapping(gk)
 	if err != nil {
-		return fmt.Errorf("unable to find resource for %s: %w", gk, err)
+		return nil, fmt.Errorf("unable to find resource for %s: %w", gk, err)
 	}
 
 	gvr := restMapping.Resource
@@ -89,61 +141,45 @@ func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, sp
 	listOptions.LabelSelector = spec.LabelSelector
 	listOptions.FieldSelector = spec.FieldSelector
 
+	var prunable []prunableObject
 	baseResource := p.Client.Resource(gvr)
 	if len(spec.Namespaces) == 0 {
 		objects, err := baseResource.List(ctx, listOptions)
 		if err != nil {
-			return fmt.Errorf("error listing objects: %w", err)
-		}
-		if err := p.pruneObjects(ctx, gvr, objects, keepObjects); err != nil {
-			return err
+			return nil, fmt.Errorf("error listing objects: %w", err)
 		}
+		prunable = append(prunable, findPrunableObjects(gvr, objects, keepObjects)...)
 	} else {
 		for _, namespace := range spec.Namespaces {
 			resource := baseResource.Namespace(namespace)
 			actualObjects, err := resource.List(ctx, listOptions)
 			if err != nil {
-				return fmt.Errorf("error listing objects in namespace %s: %w", namespace, err)
-			}
-			if err := p.pruneObjects(ctx, gvr, actualObjects, keepObjects); err != nil {
-				return err
+				return nil, fmt.Errorf("error listing objects in namespace %s: %w", namespace, err)
 			}
+			prunable = append(prunable, findPrunableObjects(gvr, actualObjects, keepObjects)...)
 		}
 	}
 
-	return nil
+	return prunable, nil
 }
 
-func (p *Pruner) pruneObjects(ctx context.Context, gvr schema.GroupVersionResource, actualObjects *unstructured.UnstructuredList, keepObjects []*kubemanifest.Object) error {
+func findPrunableObjects(gvr schema.GroupVersionResource, actualObjects *unstructured.UnstructuredList, keepObjects []*kubemanifest.Object) []prunableObject {
 	keepMap := make(map[string]*kubemanifest.Object)
 	for _, keepObject := range keepObjects {
 		key := keepObject.GetNamespace() + "/" + keepObject.GetName()
 		keepMap[key] = keepObject
 	}
 
-	for _, actualObject := range actualObjects.Items {
-		name := actualObject.GetName()
-		namespace := actualObject.GetNamespace()
-		key := namespace + "/" + name
+	var prunable []prunableObject
+	for i := range actualObjects.Items {
+		actualObject := &actualObjects.Items[i]
+		key := actualObject.GetNamespace() + "/" + actualObject.GetName()
 		if _, found := keepMap[key]; found {
 			// Object is in manifest, don't delete
 			continue
 		}
-
-		klog.Infof("pruning %s %s", gvr, key)
-
-		var resource dynamic.ResourceInterface
-		if namespace != "" {
-			resource = p.Client.Resource(gvr).Namespace(namespace)
-		} else {
-			resource = p.Client.Resource(gvr)
-		}
-
-		var opts v1.DeleteOptions
-		if err := resource.Delete(ctx, name, opts); err != nil {
-			return fmt.Errorf("failed to delete %s: %w", key, err)
-		}
+		prunable = append(prunable, prunableObject{gvr: gvr, object: actualObject})
 	}
 
-	return nil
+	return prunable
 }
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+// rolloutPollInterval is how often we check the status of the workloads in an addon.
+var rolloutPollInterval = 5 * time.Second
+
+// waitForRollout waits until the Deployments and DaemonSets in the manifest are updated and available.
+func waitForRollout(ctx context.Context, k8sClient kubernetes.Interface, manifest []byte, timeout time.Duration) error {
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse objects: %w", err)
+	}
+
+	var workloads []*kubemanifest.Object
+	for _, object := range objects {
+		gk := object.GroupVersionKind().GroupKind()
+		if gk.Group == "apps" && (gk.Kind == "Deployment" || gk.Kind == "DaemonSet") {
+			workloads = append(workloads, object)
+		}
+	}
+	if len(workloads) == 0 {
+		return nil
+	}
+
+	klog.Infof("waiting up to %v for %d workloads to roll out", timeout, len(workloads))
+
+	var notReady []string
+	err = wait.PollUntilContextTimeout(ctx, rolloutPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		notReady = nil
+		for _, workload := range workloads {
+			status, err := workloadStatus(ctx, k8sClient, workload)
+			if err != nil {
+				return false, err
+			}
+			if status != "" {
+				notReady = append(notReady, fmt.Sprintf("%s %s/%s: %s", workload.Kind(), workload.GetNamespace(), workload.GetName(), status))
+			}
+		}
+		for _, s := range notReady {
+			klog.V(2).Infof("waiting for %s", s)
+		}
+		return len(notReady) == 0, nil
+	})
+	if err != nil {
+		if len(notReady) != 0 {
+			return fmt.Errorf("workloads did not become available within %v: %s", timeout, strings.Join(notReady, "; "))
+		}
+		return err
+	}
+	return nil
+}
+
+// workloadStatus returns a description of why the workload has not rolled out, or "" if it is available.
+func workloadStatus(ctx context.Context, k8sClient kubernetes.Interface, workload *kubemanifest.Object) (string, error) {
+	switch workload.Kind() {
+	case "Deployment":
+		deployment, err := k8sClient.AppsV1().Deployments(workload.GetNamespace()).Get(ctx, workload.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting deployment %s/%s: %w", workload.GetNamespace(), workload.GetName(), err)
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation {
+			return "update not yet observed", nil
+		}
+		if deployment.Status.UpdatedReplicas < replicas {
+			return fmt.Sprintf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, replicas), nil
+		}
+		if deployment.Status.AvailableReplicas < replicas || deployment.Status.Replicas > deployment.Status.UpdatedReplicas {
+			return fmt.Sprintf("%d of %d updated replicas available", deployment.Status.AvailableReplicas, replicas), nil
+		}
+
+	case "DaemonSet":
+		daemonSet, err := k8sClient.AppsV1().DaemonSets(workload.GetNamespace()).Get(ctx, workload.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting daemonset %s/%s: %w", workload.GetNamespace(), workload.GetName(), err)
+		}
+		desired := daemonSet.Status.DesiredNumberScheduled
+		if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+			return "update not yet observed", nil
+		}
+		// Pods of DaemonSets with the OnDelete strategy are only updated when they are deleted,
+		// for example when their nodes are replaced by a rolling update, so only their availability is checked.
+		onDelete := daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType
+		if !onDelete && daemonSet.Status.UpdatedNumberScheduled < desired {
+			return fmt.Sprintf("%d of %d pods updated", daemonSet.Status.UpdatedNumberScheduled, desired), nil
+		}
+		if daemonSet.Status.NumberAvailable < desired {
+			return fmt.Sprintf("%d of %d pods available", daemonSet.Status.NumberAvailable, desired), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	fakecertmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekubernetes "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const testDeploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: kube-system
+`
+
+const testConfigMapManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: kube-system
+`
+
+// recordingApplier records the manifests it is asked to apply.
+type recordingApplier struct {
+	applied []string
+}
+
+func (a *recordingApplier) Apply(ctx context.Context, data []byte) error {
+	a.applied = append(a.applied, string(data))
+	return nil
+}
+
+func testDeployment(available bool) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "kube-system", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(2))},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  1,
+		},
+	}
+	if available {
+		deployment.Status.AvailableReplicas = 2
+	}
+	return deployment
+}
+
+func TestWaitForRollout(t *testing.T) {
+	rolloutPollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+
+	daemonSetManifest := `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+  namespace: kube-system
+`
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system", Generation: 3},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberAvailable:        3,
+		},
+	}
+	onDeleteManifest := `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: controller
+  namespace: kube-system
+`
+	onDeleteDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "kube-system", Generation: 3},
+		Spec: appsv1.DaemonSetSpec{
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+		},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     3,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 0,
+			NumberAvailable:        3,
+		},
+	}
+
+	grid := []struct {
+		name     string
+		manifest string
+		objects  []*appsv1.Deployment
+		expected string
+	}{
+		{name: "no workloads", manifest: testConfigMapManifest},
+		{name: "available", manifest: testDeploymentManifest, objects: []*appsv1.Deployment{testDeployment(true)}},
+		{name: "unavailable", manifest: testDeploymentManifest, objects: []*appsv1.Deployment{testDeployment(false)}, expected: "Deployment kube-system/app: 1 of 2 updated replicas available"},
+		{name: "missing", manifest: testDeploymentManifest, expected: "error getting deployment kube-system/app"},
+		{name: "daemonset not observed", manifest: daemonSetManifest, expected: "DaemonSet kube-system/agent: update not yet observed"},
+		{name: "daemonset on delete", manifest: onDeleteManifest},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			k8sClient := fakekubernetes.NewSimpleClientset(daemonSet, onDeleteDaemonSet)
+			for _, object := range g.objects {
+				if _, err := k8sClient.AppsV1().Deployments(object.Namespace).Create(ctx, object, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("creating deployment: %v", err)
+				}
+			}
+
+			err := waitForRollout(ctx, k8sClient, []byte(g.manifest), 50*time.Millisecond)
+			if g.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error containing %q, got %v", g.expected, err)
+			}
+		})
+	}
+}
+
+func TestEnsureUpdatedRollback(t *testing.T) {
+	rolloutPollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	manifestPath, err := vfsContext.BuildVfsPath("memfs://channel/app/new.yaml")
+	if err != nil {
+		t.Fatalf("building path: %v", err)
+	}
+	if err := manifestPath.WriteFile(ctx, bytes.NewReader([]byte(testDeploymentManifest)), nil); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	for _, available := range []bool{false, true} {
+		k8sClient := fakekubernetes.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+			testDeployment(available),
+		)
+		cmClient := fakecertmanager.NewSimpleClientset()
+
+		channelName := "memfs://channel/addons.yaml"
+		existingVersion := &ChannelVersion{Channel: &channelName, ManifestHash: "old", SystemGeneration: CurrentSystemGeneration}
+		channel := &Channel{Namespace: "kube-system", Name: "app"}
+		if err := channel.SetInstalledVersion(ctx, k8sClient, existingVersion); err != nil {
+			t.Fatalf("SetInstalledVersion: %v", err)
+		}
+		if err := channel.SetAppliedManifest(ctx, k8sClient, existingVersion, []byte(testConfigMapManifest)); err != nil {
+			t.Fatalf("SetAppliedManifest: %v", err)
+		}
+
+		addon := &Addon{
+			Name:        "app",
+			ChannelName: channelName,
+			Spec: &api.AddonSpec{
+				Name:         fi.PtrTo("app"),
+				Manifest:     fi.PtrTo("memfs://channel/app/new.yaml"),
+				ManifestHash: "new",
+			},
+		}
+		applier := &recordingApplier{}
+
+		_, err := addon.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, &Pruner{}, applier, existingVersion, 50*time.Millisecond)
+
+		installed, installedErr := channel.GetInstalledVersion(ctx, k8sClient)
+		if installedErr != nil {
+			t.Fatalf("GetInstalledVersion: %v", installedErr)
+		}
+
+		if available {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if installed.ManifestHash != "new" {
+				t.Errorf("expected new version to be installed, got %v", installed)
+			}
+			manifest, err := channel.GetAppliedManifest(ctx, k8sClient, addon.ChannelVersion())
+			if err != nil || string(manifest) != testDeploymentManifest {
+				t.Errorf("expected new manifest to be recorded, got %q, %v", manifest, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), "failed to roll out") || !strings.Contains(err.Error(), "rolled back to") {
+			t.Fatalf("expected rollback error, got %v", err)
+		}
+		if len(applier.applied) != 2 || applier.applied[0] != testDeploymentManifest || applier.applied[1] != testConfigMapManifest {
+			t.Errorf("expected new manifest then previous manifest to be applied, got %q", applier.applied)
+		}
+		if installed.ManifestHash != "old" {
+			t.Errorf("expected previous version to remain installed, got %v", installed)
+		}
+
+		// We don't apply the failed version again
+		applier.applied = nil
+		_, err = addon.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, &Pruner{}, applier, existingVersion, 50*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "previously failed to roll out") {
+			t.Errorf("expected failed version error, got %v", err)
+		}
+		if len(applier.applied) != 0 {
+			t.Errorf("expected failed version not to be applied, got %q", applier.applied)
+		}
+	}
+}
+
+func TestEnsureUpdatedRollbackFromStateStore(t *testing.T) {
+	rolloutPollInterval = 10 * time.Millisecond
+	ctx := context.Background()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	for location, manifest := range map[string]string{
+		"memfs://channel/app/new.yaml": testDeploymentManifest,
+		"memfs://channel/app/old.yaml": testConfigMapManifest,
+	} {
+		p, err := vfsContext.BuildVfsPath(location)
+		if err != nil {
+			t.Fatalf("building path: %v", err)
+		}
+		if err := p.WriteFile(ctx, bytes.NewReader([]byte(manifest)), nil); err != nil {
+			t.Fatalf("writing manifest: %v", err)
+		}
+	}
+	oldHash, err := utils.HashString(testConfigMapManifest)
+	if err != nil {
+		t.Fatalf("hashing manifest: %v", err)
+	}
+
+	k8sClient := fakekubernetes.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		testDeployment(false),
+	)
+	cmClient := fakecertmanager.NewSimpleClientset()
+
+	channelName := "memfs://channel/addons.yaml"
+	// No manifest was recorded in the cluster, so the previous manifest must be re-read from its location
+	existingVersion := &ChannelVersion{Channel: &channelName, ManifestHash: oldHash, ManifestLocation: "memfs://channel/app/old.yaml", SystemGeneration: CurrentSystemGeneration}
+	channel := &Channel{Namespace: "kube-system", Name: "app"}
+	if err := channel.SetInstalledVersion(ctx, k8sClient, existingVersion); err != nil {
+		t.Fatalf("SetInstalledVersion: %v", err)
+	}
+
+	addon := &Addon{
+		Name:        "app",
+		ChannelName: channelName,
+		Spec: &api.AddonSpec{
+			Name:         fi.PtrTo("app"),
+			Manifest:     fi.PtrTo("memfs://channel/app/new.yaml"),
+			ManifestHash: "new",
+		},
+	}
+	applier := &recordingApplier{}
+
+	_, err = addon.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, &Pruner{}, applier, existingVersion, 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "rolled back to") {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	if len(applier.applied) != 2 || applier.applied[1] != testConfigMapManifest {
+		t.Errorf("expected previous manifest to be re-applied from its location, got %q", applier.applied)
+	}
+
+	failed, err := channel.IsFailedVersion(ctx, k8sClient, addon.ChannelVersion())
+	if err != nil || !failed {
+		t.Errorf("expected new version to be recorded as failed, got %v, %v", failed, err)
+	}
+	if _, err := k8sClient.CoreV1().ConfigMaps("kube-system").Get(ctx, channel.HistoryName(), metav1.GetOptions{}); err == nil {
+		t.Errorf("expected no history configmap")
+	}
+}
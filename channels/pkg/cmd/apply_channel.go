@@ -22,6 +22,8 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
@@ -37,7 +39,9 @@ import (
 )
 
 type ApplyChannelOptions struct {
-	Yes bool
+	Yes            bool
+	DryRun         bool
+	RolloutTimeout time.Duration
 }
 
 func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
@@ -53,6 +57,8 @@ func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&options.Yes, "yes", false, "Apply update")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", false, "Show the changes to each object that the update would make, without applying it")
+	cmd.Flags().DurationVar(&options.RolloutTimeout, "rollout-timeout", 10*time.Minute, "Time to wait for the Deployments and DaemonSets of an upgraded addon to become available before rolling it back (0 to disable)")
 
 	return cmd
 }
@@ -95,6 +101,10 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 		return fmt.Errorf("unexpected number of arguments. Only one channel may be processed at the same time.")
 	}
 
+	if options.Yes && options.DryRun {
+		return fmt.Errorf("--yes and --dry-run cannot be used together")
+	}
+
 	channelLocation := args[0]
 
 	// menu is the expected list of addons in the cluster and their configurations.
@@ -103,10 +113,10 @@ func RunApplyChannel(ctx context.Context, f Factory, out io.Writer, options *App
 		return fmt.Errorf("cannot build the addon menu from args: %w", err)
 	}
 
-	return applyMenu(ctx, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options.Yes)
+	return applyMenu(ctx, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options)
 }
 
-func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient versioned.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, apply bool) error {
+func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient versioned.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, options *ApplyChannelOptions) error {
 	// channelVersions is the list of installed addons in the cluster.
 	// It is keyed by <namespace>:<addon name>.
 	channelVersions, err := getChannelVersions(ctx, k8sClient)
@@ -155,16 +165,24 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		}
 	}
 
-	if !apply {
-		fmt.Printf("\nMust specify --yes to update\n")
-		return nil
-	}
-
 	pruner := &channels.Pruner{
 		Client:     dynamicClient,
 		RESTMapper: restMapper,
 	}
 
+	if options.DryRun {
+		differ := &channels.Differ{
+			Client:     dynamicClient,
+			RESTMapper: restMapper,
+		}
+		return printDryRun(ctx, os.Stdout, vfsContext, differ, pruner, updates, needUpdates)
+	}
+
+	if !options.Yes {
+		fmt.Printf("\nMust specify --yes to update\n")
+		return nil
+	}
+
 	applier := &channels.ClientApplier{
 		Client:     dynamicClient,
 		RESTMapper: restMapper,
@@ -173,7 +191,7 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 	var merr error
 
 	for _, needUpdate := range needUpdates {
-		update, err := needUpdate.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, pruner, applier, channelVersions[needUpdate.GetNamespace()+":"+needUpdate.Name])
+		update, err := needUpdate.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, pruner, applier, channelVersions[needUpdate.GetNamespace()+":"+needUpdate.Name], options.RolloutTimeout)
 		if err != nil {
 			merr = multierr.Append(merr, fmt.Errorf("updating %q: %w", needUpdate.Name, err))
 		} else if update != nil {
@@ -184,6 +202,46 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 	return merr
 }
 
+// printDryRun prints the versions and object changes of each addon that would be updated.
+func printDryRun(ctx context.Context, out io.Writer, vfsContext *vfs.VFSContext, differ *channels.Differ, pruner *channels.Pruner, updates []*channels.AddonUpdate, needUpdates []*channels.Addon) error {
+	var merr error
+	for i, update := range updates {
+		addon := needUpdates[i]
+
+		fmt.Fprintf(out, "\nAddon %q\n", update.Name)
+		current := "-"
+		if update.ExistingVersion != nil {
+			current = update.ExistingVersion.String()
+		}
+		fmt.Fprintf(out, "  Current: %s\n", current)
+		if update.NewVersion == nil {
+			// Only PKI needs to be installed
+			fmt.Fprintf(out, "  Update:  -\n")
+			continue
+		}
+		fmt.Fprintf(out, "  Update:  %s\n", update.NewVersion)
+
+		changes, err := addon.Diff(ctx, vfsContext, differ, pruner)
+		if err != nil {
+			merr = multierr.Append(merr, fmt.Errorf("computing changes for %q: %w", update.Name, err))
+			continue
+		}
+		for _, change := range changes {
+			name := change.Name
+			if change.Namespace != "" {
+				name = change.Namespace + "/" + name
+			}
+			fmt.Fprintf(out, "\n  %s %s %s\n", change.Action, change.GroupVersionKind.Kind, name)
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				if line != "" {
+					fmt.Fprintf(out, "    %s\n", line)
+				}
+			}
+		}
+	}
+	return merr
+}
+
 func getUpdates(ctx context.Context, menu *channels.AddonMenu, k8sClient kubernetes.Interface, cmClient versioned.Interface, channelVersions map[string]*channels.ChannelVersion) ([]*channels.AddonUpdate, []*channels.Addon, error) {
 	var updates []*channels.AddonUpdate
 	var needUpdates []*channels.Addon
@@ -164,6 +164,8 @@ func (r *AWSIPAMReconciler) SetupWithManager(mgr ctrl.Manager) error {
 type nodePatchSpec struct {
 	PodCIDR  string   `json:"podCIDR,omitempty"`
 	PodCIDRs []string `json:"podCIDRs,omitempty"`
+
+	Taints []corev1.Taint `json:"taints,omitempty"`
 }
 
 // patchNodePodCIDRs patches the node podCIDR to the specified value.
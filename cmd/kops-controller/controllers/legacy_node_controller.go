@@ -38,11 +38,12 @@ import (
 )
 
 // NewLegacyNodeReconciler is the constructor for a LegacyNodeReconciler
-func NewLegacyNodeReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, configPath string, identifier nodeidentity.LegacyIdentifier) (*LegacyNodeReconciler, error) {
+func NewLegacyNodeReconciler(mgr manager.Manager, vfsContext *vfs.VFSContext, configPath string, identifier nodeidentity.LegacyIdentifier, mapper *nodeidentity.Mapper) (*LegacyNodeReconciler, error) {
 	r := &LegacyNodeReconciler{
 		client:     mgr.GetClient(),
 		log:        ctrl.Log.WithName("controllers").WithName("Node"),
 		identifier: identifier,
+		mapper:     mapper,
 		cache:      vfs.NewCache(),
 	}
 
@@ -71,11 +72,14 @@ type LegacyNodeReconciler struct {
 	log logr.Logger
 
 	// coreV1Client is a client-go client for patching nodes
-	coreV1Client *corev1client.CoreV1Client
+	coreV1Client corev1client.CoreV1Interface
 
 	// identifier is a provider that can securely map node ProviderIDs to InstanceGroups
 	identifier nodeidentity.LegacyIdentifier
 
+	// mapper adds the configured labels and taints to the node identity, if set
+	mapper *nodeidentity.Mapper
+
 	// configBase is the parsed path to the base location of our configuration files
 	configBase vfs.Path
 
@@ -100,28 +104,59 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	cluster, err := r.getClusterForNode(node)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to load cluster object for node %s: %v", node.Name, err)
-	}
+	// Nodes which are not cloud instances, such as bare-metal hosts, only get the labels and taints of external sources
+	var info *nodeidentity.Info
+	labels := make(map[string]string)
+	cloudInstance := node.Spec.ProviderID != "" || r.mapper == nil || !r.mapper.HasSources()
+	if cloudInstance {
+		cluster, err := r.getClusterForNode(node)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to load cluster object for node %s: %v", node.Name, err)
+		}
 
-	ig, err := r.getInstanceGroupForNode(ctx, node)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
-	}
+		ig, err := r.getInstanceGroupForNode(ctx, node)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to load instance group object for node %s: %v", node.Name, err)
+		}
 
-	labels, err := nodelabels.BuildNodeLabels(cluster, ig)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("error building node labels for node %q: %w", node.Name, err)
-	}
+		labels, err = nodelabels.BuildNodeLabels(cluster, ig)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("error building node labels for node %q: %w", node.Name, err)
+		}
 
-	lifecycle, err := r.getInstanceLifecycle(ctx, node)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("unable to get instance lifecycle %s: %v", node.Name, err)
+		identity, err := r.identifier.IdentifyNode(ctx, node)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
+		}
+
+		if len(identity.InstanceLifecycle) > 0 {
+			labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", identity.InstanceLifecycle)] = "true"
+		}
+
+		info = &nodeidentity.Info{
+			InstanceID: identity.InstanceID,
+			Labels:     labels,
+			Tags:       identity.Tags,
+			Attributes: identity.Attributes,
+		}
 	}
 
-	if len(lifecycle) > 0 {
-		labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", lifecycle)] = "true"
+	// If a source fails, we still apply the other labels and taints, and then retry
+	var mapErr error
+	if r.mapper != nil {
+		mapped, err := r.mapper.Map(ctx, node, info)
+		if err != nil {
+			klog.Warningf("failed to map labels and taints of node %s: %v", node.Name, err)
+			mapErr = err
+		}
+		labels = mapped.Labels
+
+		if taints, managed, changed := reconcileNodeTaints(node, mapped.Taints, mapErr == nil); changed {
+			if err := patchNodeTaints(r.coreV1Client, ctx, node, taints, managed); err != nil {
+				klog.Warningf("failed to patch node taints on %s: %v", node.Name, err)
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
 	updateLabels := make(map[string]string)
@@ -133,19 +168,22 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	deleteLabels := make(map[string]struct{})
-	for k := range node.Labels {
-		// If it is one of our managed labels, "prune" values we don't want to be there
-		switch k {
-		case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
-			if _, found := labels[k]; !found {
-				deleteLabels[k] = struct{}{}
+	// We only know which role labels a node should have if it is a cloud instance
+	if cloudInstance {
+		for k := range node.Labels {
+			// If it is one of our managed labels, "prune" values we don't want to be there
+			switch k {
+			case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
+				if _, found := labels[k]; !found {
+					deleteLabels[k] = struct{}{}
+				}
 			}
 		}
 	}
 
 	if len(updateLabels) == 0 && len(deleteLabels) == 0 {
 		klog.V(4).Infof("no label changes needed for %s", node.Name)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, mapErr
 	}
 
 	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels, deleteLabels); err != nil {
@@ -153,7 +191,7 @@ func (r *LegacyNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, mapErr
 }
 
 func (r *LegacyNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -173,16 +211,6 @@ func (r *LegacyNodeReconciler) getClusterForNode(node *corev1.Node) (*api.Cluste
 	return cluster, nil
 }
 
-// getInstanceLifecycle returns InstanceLifecycle string object
-func (r *LegacyNodeReconciler) getInstanceLifecycle(ctx context.Context, node *corev1.Node) (string, error) {
-	identity, err := r.identifier.IdentifyNode(ctx, node)
-	if err != nil {
-		return "", fmt.Errorf("error identifying node %q: %v", node.Name, err)
-	}
-
-	return identity.InstanceLifecycle, nil
-}
-
 // getInstanceGroupForNode returns the api.InstanceGroup object for the node
 func (r *LegacyNodeReconciler) getInstanceGroupForNode(ctx context.Context, node *corev1.Node) (*api.InstanceGroup, error) {
 	// We assume that if the instancegroup label is set, that it is correct
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -35,12 +37,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// managedTaintsAnnotation records the keys and effects of the taints which kops-controller added to the node,
+// so that they can be removed once they are no longer configured.
+const managedTaintsAnnotation = "kops.k8s.io/managed-taints"
+
 // NewNodeReconciler is the constructor for a NodeReconciler
-func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier) (*NodeReconciler, error) {
+func NewNodeReconciler(mgr manager.Manager, identifier nodeidentity.Identifier, mapper *nodeidentity.Mapper) (*NodeReconciler, error) {
 	r := &NodeReconciler{
 		client:     mgr.GetClient(),
 		log:        ctrl.Log.WithName("controllers").WithName("Node"),
 		identifier: identifier,
+		mapper:     mapper,
 	}
 
 	coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
@@ -62,10 +69,13 @@ type NodeReconciler struct {
 	log logr.Logger
 
 	// coreV1Client is a client-go client for patching nodes
-	coreV1Client *corev1client.CoreV1Client
+	coreV1Client corev1client.CoreV1Interface
 
 	// identifier is a provider that can securely map node ProviderIDs to labels
 	identifier nodeidentity.Identifier
+
+	// mapper adds the configured labels and taints to the node identity, if set
+	mapper *nodeidentity.Mapper
 }
 
 // +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch;patch
@@ -85,9 +95,33 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 
-	info, err := r.identifier.IdentifyNode(ctx, node)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
+	// Nodes which are not cloud instances, such as bare-metal hosts, only get the labels and taints of external sources
+	var info *nodeidentity.Info
+	cloudInstance := node.Spec.ProviderID != "" || r.mapper == nil || !r.mapper.HasSources()
+	if cloudInstance {
+		identified, err := r.identifier.IdentifyNode(ctx, node)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("error identifying node %q: %v", node.Name, err)
+		}
+		info = identified
+	}
+
+	// If a source fails, we still apply the other labels and taints, and then retry
+	var mapErr error
+	if r.mapper != nil {
+		mapped, err := r.mapper.Map(ctx, node, info)
+		if err != nil {
+			klog.Warningf("failed to map labels and taints of node %s: %v", node.Name, err)
+			mapErr = err
+		}
+		info = mapped
+	}
+
+	if taints, managed, changed := reconcileNodeTaints(node, info.Taints, mapErr == nil); changed {
+		if err := patchNodeTaints(r.coreV1Client, ctx, node, taints, managed); err != nil {
+			klog.Warningf("failed to patch node taints on %s: %v", node.Name, err)
+			return ctrl.Result{}, err
+		}
 	}
 
 	labels := info.Labels
@@ -101,19 +135,22 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 
 	deleteLabels := make(map[string]struct{})
-	for k := range node.Labels {
-		// If it is one of our managed labels, "prune" values we don't want to be there
-		switch k {
-		case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
-			if _, found := labels[k]; !found {
-				deleteLabels[k] = struct{}{}
+	// We only know which role labels a node should have if it is a cloud instance
+	if cloudInstance {
+		for k := range node.Labels {
+			// If it is one of our managed labels, "prune" values we don't want to be there
+			switch k {
+			case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
+				if _, found := labels[k]; !found {
+					deleteLabels[k] = struct{}{}
+				}
 			}
 		}
 	}
 
 	if len(updateLabels) == 0 && len(deleteLabels) == 0 {
 		klog.V(4).Infof("no label changes needed for %s", node.Name)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, mapErr
 	}
 
 	if err := patchNodeLabels(r.coreV1Client, ctx, node, updateLabels, deleteLabels); err != nil {
@@ -121,7 +158,7 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, mapErr
 }
 
 func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -136,11 +173,23 @@ type nodePatch struct {
 }
 
 type nodePatchMetadata struct {
-	Labels map[string]*string `json:"labels,omitempty"`
+	Labels          map[string]*string `json:"labels,omitempty"`
+	Annotations     map[string]*string `json:"annotations,omitempty"`
+	ResourceVersion string             `json:"resourceVersion,omitempty"`
+}
+
+// nodeTaintsPatch is a patch of the taints of a node; unlike nodePatchSpec, an empty list of taints is sent.
+type nodeTaintsPatch struct {
+	Spec     nodeTaintsPatchSpec `json:"spec"`
+	Metadata *nodePatchMetadata  `json:"metadata,omitempty"`
+}
+
+type nodeTaintsPatchSpec struct {
+	Taints []corev1.Taint `json:"taints"`
 }
 
 // patchNodeLabels patches the node labels to set the specified labels
-func patchNodeLabels(client *corev1client.CoreV1Client, ctx context.Context, node *corev1.Node, setLabels map[string]string, deleteLabels map[string]struct{}) error {
+func patchNodeLabels(client corev1client.NodesGetter, ctx context.Context, node *corev1.Node, setLabels map[string]string, deleteLabels map[string]struct{}) error {
 	nodePatchMetadata := &nodePatchMetadata{
 		Labels: make(map[string]*string),
 	}
@@ -169,3 +218,122 @@ func patchNodeLabels(client *corev1client.CoreV1Client, ctx context.Context, nod
 
 	return nil
 }
+
+// reconcileNodeTaints returns the taints the node should have, the new value of the managedTaintsAnnotation,
+// and whether either changed. The specified taints are added or updated, replacing taints of the node with the same key and effect.
+// Taints which kops-controller previously added, as recorded in the annotation, are removed if they are no longer specified,
+// unless prune is false; other taints are kept.
+func reconcileNodeTaints(node *corev1.Node, taints []corev1.Taint, prune bool) ([]corev1.Taint, string, bool) {
+	previous := parseManagedTaints(node.Annotations[managedTaintsAnnotation])
+
+	managed := append([]corev1.Taint(nil), taints...)
+	if !prune {
+		// Keep remembering the taints we added, so that they are removed once we know they are no longer wanted
+		for i := range previous {
+			if !hasTaint(managed, &previous[i]) {
+				managed = append(managed, previous[i])
+			}
+		}
+	}
+
+	changed := false
+	var result []corev1.Taint
+	for _, taint := range node.Spec.Taints {
+		if prune && hasTaint(previous, &taint) && !hasTaint(taints, &taint) {
+			changed = true
+			continue
+		}
+		result = append(result, taint)
+	}
+
+	for _, taint := range taints {
+		found := false
+		for i := range result {
+			if !result[i].MatchTaint(&taint) {
+				continue
+			}
+			found = true
+			if result[i].Value != taint.Value {
+				result[i].Value = taint.Value
+				changed = true
+			}
+		}
+		if !found {
+			result = append(result, corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+			changed = true
+		}
+	}
+
+	annotation := formatManagedTaints(managed)
+	if annotation != node.Annotations[managedTaintsAnnotation] {
+		changed = true
+	}
+	return result, annotation, changed
+}
+
+// hasTaint returns true if one of the taints has the same key and effect as the taint.
+func hasTaint(taints []corev1.Taint, taint *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatManagedTaints formats the keys and effects of the taints for the managedTaintsAnnotation, as a sorted list of key:effect.
+func formatManagedTaints(taints []corev1.Taint) string {
+	var values []string
+	for _, taint := range taints {
+		values = append(values, taint.Key+":"+string(taint.Effect))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+// parseManagedTaints parses the keys and effects of the taints of the managedTaintsAnnotation.
+func parseManagedTaints(annotation string) []corev1.Taint {
+	var taints []corev1.Taint
+	for _, value := range strings.Split(annotation, ",") {
+		key, effect, found := strings.Cut(value, ":")
+		if !found {
+			continue
+		}
+		taints = append(taints, corev1.Taint{Key: key, Effect: corev1.TaintEffect(effect)})
+	}
+	return taints
+}
+
+// patchNodeTaints patches the node to have the specified taints, and records the taints added by kops-controller in the managedTaintsAnnotation.
+// Taints are replaced as a list, so the patch is only applied if the node has not changed since we read it.
+func patchNodeTaints(client corev1client.NodesGetter, ctx context.Context, node *corev1.Node, taints []corev1.Taint, managed string) error {
+	if taints == nil {
+		taints = []corev1.Taint{}
+	}
+	var annotation *string
+	if managed != "" {
+		annotation = &managed
+	}
+	nodePatch := &nodeTaintsPatch{
+		Spec: nodeTaintsPatchSpec{
+			Taints: taints,
+		},
+		Metadata: &nodePatchMetadata{
+			Annotations:     map[string]*string{managedTaintsAnnotation: annotation},
+			ResourceVersion: node.ResourceVersion,
+		},
+	}
+	nodePatchJson, err := json.Marshal(nodePatch)
+	if err != nil {
+		return fmt.Errorf("error building node patch: %v", err)
+	}
+
+	klog.V(2).Infof("sending patch for node %q: %q", node.Name, string(nodePatchJson))
+
+	_, err = client.Nodes().Patch(ctx, node.Name, types.StrategicMergePatchType, nodePatchJson, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error applying patch to node: %v", err)
+	}
+
+	return nil
+}
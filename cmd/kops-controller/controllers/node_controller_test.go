@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/pkg/nodeidentity"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileNodeTaints(t *testing.T) {
+	pool := corev1.Taint{Key: "pool", Value: "batch", Effect: corev1.TaintEffectNoSchedule}
+	spot := corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}
+	other := corev1.Taint{Key: "example.com/maintenance", Effect: corev1.TaintEffectNoExecute}
+
+	grid := []struct {
+		name               string
+		nodeTaints         []corev1.Taint
+		managed            string
+		taints             []corev1.Taint
+		prune              bool
+		expectedTaints     []corev1.Taint
+		expectedAnnotation string
+		expectedChanged    bool
+	}{
+		{
+			name:               "add",
+			nodeTaints:         []corev1.Taint{other},
+			taints:             []corev1.Taint{pool},
+			prune:              true,
+			expectedTaints:     []corev1.Taint{other, pool},
+			expectedAnnotation: "pool:NoSchedule",
+			expectedChanged:    true,
+		},
+		{
+			name:               "unchanged",
+			nodeTaints:         []corev1.Taint{other, pool},
+			managed:            "pool:NoSchedule",
+			taints:             []corev1.Taint{pool},
+			prune:              true,
+			expectedTaints:     []corev1.Taint{other, pool},
+			expectedAnnotation: "pool:NoSchedule",
+		},
+		{
+			name:               "update value",
+			nodeTaints:         []corev1.Taint{{Key: "pool", Value: "general", Effect: corev1.TaintEffectNoSchedule}},
+			managed:            "pool:NoSchedule",
+			taints:             []corev1.Taint{pool},
+			prune:              true,
+			expectedTaints:     []corev1.Taint{pool},
+			expectedAnnotation: "pool:NoSchedule",
+			expectedChanged:    true,
+		},
+		{
+			name:               "remove stale",
+			nodeTaints:         []corev1.Taint{other, pool, spot},
+			managed:            "pool:NoSchedule,spot:PreferNoSchedule",
+			taints:             []corev1.Taint{pool},
+			prune:              true,
+			expectedTaints:     []corev1.Taint{other, pool},
+			expectedAnnotation: "pool:NoSchedule",
+			expectedChanged:    true,
+		},
+		{
+			name:               "remove last",
+			nodeTaints:         []corev1.Taint{pool},
+			managed:            "pool:NoSchedule",
+			prune:              true,
+			expectedAnnotation: "",
+			expectedChanged:    true,
+		},
+		{
+			name:               "keep stale without pruning",
+			nodeTaints:         []corev1.Taint{pool, spot},
+			managed:            "pool:NoSchedule,spot:PreferNoSchedule",
+			taints:             []corev1.Taint{pool},
+			prune:              false,
+			expectedTaints:     []corev1.Taint{pool, spot},
+			expectedAnnotation: "pool:NoSchedule,spot:PreferNoSchedule",
+		},
+		{
+			name:               "unmanaged taint is kept",
+			nodeTaints:         []corev1.Taint{other},
+			prune:              true,
+			expectedTaints:     []corev1.Taint{other},
+			expectedAnnotation: "",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Spec:       corev1.NodeSpec{Taints: g.nodeTaints},
+			}
+			if g.managed != "" {
+				node.Annotations = map[string]string{managedTaintsAnnotation: g.managed}
+			}
+
+			taints, annotation, changed := reconcileNodeTaints(node, g.taints, g.prune)
+			if !reflect.DeepEqual(taints, g.expectedTaints) {
+				t.Errorf("unexpected taints: got %v, expected %v", taints, g.expectedTaints)
+			}
+			if annotation != g.expectedAnnotation {
+				t.Errorf("unexpected annotation: got %q, expected %q", annotation, g.expectedAnnotation)
+			}
+			if changed != g.expectedChanged {
+				t.Errorf("unexpected changed: got %v, expected %v", changed, g.expectedChanged)
+			}
+		})
+	}
+}
+
+type staticIdentifier struct {
+	info *nodeidentity.Info
+}
+
+func (i *staticIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*nodeidentity.Info, error) {
+	return i.info, nil
+}
+
+type failingSource struct{}
+
+func (s *failingSource) Lookup(ctx context.Context, node *corev1.Node, info *nodeidentity.Info) (*nodeidentity.SourceResult, error) {
+	return nil, errors.New("inventory unavailable")
+}
+
+func TestNodeReconcilerSourceError(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Annotations: map[string]string{managedTaintsAnnotation: "dedicated:NoSchedule"},
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "aws:///us-test-1a/i-0123456789",
+			Taints:     []corev1.Taint{{Key: "dedicated", Value: "storage", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	coreClient := fake.NewSimpleClientset(node.DeepCopy())
+
+	r := &NodeReconciler{
+		client:       ctrlfake.NewClientBuilder().WithObjects(node.DeepCopy()).Build(),
+		coreV1Client: coreClient.CoreV1(),
+		identifier: &staticIdentifier{info: &nodeidentity.Info{
+			Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+			Tags:   map[string]string{"pool": "general"},
+		}},
+		mapper: nodeidentity.NewMapper(&nodeidentity.Options{
+			Taints: []nodeidentity.TaintMapping{{Tag: "pool", Key: "pool", Effect: corev1.TaintEffectNoSchedule}},
+		}, &failingSource{}),
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-1"}})
+	if err == nil {
+		t.Errorf("expected the source error to be returned, so that the node is reconciled again")
+	}
+
+	updated, err := coreClient.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting node: %v", err)
+	}
+	if updated.Labels["kops.k8s.io/instancegroup"] != "nodes" {
+		t.Errorf("expected the instance group label to be set, got %v", updated.Labels)
+	}
+	expectedTaints := []corev1.Taint{
+		{Key: "dedicated", Value: "storage", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "pool", Value: "general", Effect: corev1.TaintEffectNoSchedule},
+	}
+	if !reflect.DeepEqual(updated.Spec.Taints, expectedTaints) {
+		t.Errorf("unexpected taints: got %v, expected %v", updated.Spec.Taints, expectedTaints)
+	}
+	if annotation := updated.Annotations[managedTaintsAnnotation]; annotation != "dedicated:NoSchedule,pool:NoSchedule" {
+		t.Errorf("unexpected managed taints: %q", annotation)
+	}
+}
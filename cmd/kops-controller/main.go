@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
@@ -314,8 +315,16 @@ func addNodeController(mgr manager.Manager, vfsContext *vfs.VFSContext, opt *con
 		return fmt.Errorf("identifier for cloud %q not implemented", opt.Cloud)
 	}
 
+	var mapper *nodeidentity.Mapper
+	if opt.NodeIdentity != nil {
+		mapper, err = buildNodeIdentityMapper(mgr, opt.NodeIdentity)
+		if err != nil {
+			return err
+		}
+	}
+
 	if identifier != nil {
-		nodeController, err := controllers.NewNodeReconciler(mgr, identifier)
+		nodeController, err := controllers.NewNodeReconciler(mgr, identifier, mapper)
 		if err != nil {
 			return err
 		}
@@ -330,7 +339,7 @@ func addNodeController(mgr manager.Manager, vfsContext *vfs.VFSContext, opt *con
 			return fmt.Errorf("must specify secretStore")
 		}
 
-		nodeController, err := controllers.NewLegacyNodeReconciler(mgr, vfsContext, opt.ConfigBase, legacyIdentifier)
+		nodeController, err := controllers.NewLegacyNodeReconciler(mgr, vfsContext, opt.ConfigBase, legacyIdentifier, mapper)
 		if err != nil {
 			return err
 		}
@@ -342,6 +351,22 @@ func addNodeController(mgr manager.Manager, vfsContext *vfs.VFSContext, opt *con
 	return nil
 }
 
+// buildNodeIdentityMapper builds the mapper for the configured node labels and taints, and their external sources
+func buildNodeIdentityMapper(mgr manager.Manager, options *nodeidentity.Options) (*nodeidentity.Mapper, error) {
+	var sources []nodeidentity.Source
+	if options.ConfigMap != nil {
+		coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("error building corev1 client: %w", err)
+		}
+		sources = append(sources, nodeidentity.NewConfigMapSource(coreClient, options.ConfigMap))
+	}
+	if options.HTTP != nil {
+		sources = append(sources, nodeidentity.NewHTTPSource(options.HTTP))
+	}
+	return nodeidentity.NewMapper(options, sources...), nil
+}
+
 func addGossipController(mgr manager.Manager, opt *config.Options) error {
 	if opt.Discovery == nil || !opt.Discovery.Enabled {
 		return nil
@@ -17,7 +17,9 @@ limitations under the License.
 package config
 
 import (
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/nodeidentity"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
@@ -40,6 +42,12 @@ type Options struct {
 
 	// Discovery configures options relating to discovery, particularly for gossip mode.
 	Discovery *DiscoveryOptions `json:"discovery,omitempty"`
+
+	// StateStoreEncryption configures the keys which secrets in the SecretStore are encrypted with.
+	StateStoreEncryption *kops.StateStoreEncryptionSpec `json:"stateStoreEncryption,omitempty"`
+
+	// NodeIdentity configures additional labels and taints for nodes, and their external sources.
+	NodeIdentity *nodeidentity.Options `json:"nodeIdentity,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
@@ -66,6 +74,8 @@ type ServerOptions struct {
 	SigningCAs []string `json:"signingCAs"`
 	// CertNames is the list of active certificate names.
 	CertNames []string `json:"certNames"`
+	// KeyAlgorithm is the algorithm of the node keys we accept, in addition to RSA.
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
 }
 
 type ServerProviderOptions struct {
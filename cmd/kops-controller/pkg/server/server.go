@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io"
+	"net"
 	"net/http"
 	"runtime/debug"
 	"time"
@@ -56,7 +57,7 @@ type Server struct {
 	server      *http.Server
 	verifier    bootstrap.Verifier
 	keystore    pki.Keystore
-	secretStore fi.SecretStore
+	secretStore fi.SecretStoreReader
 
 	// configBase is the base of the configuration storage.
 	configBase vfs.Path
@@ -96,7 +97,7 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse SecretStore %q: %w", opt.SecretStore, err)
 	}
-	s.secretStore = secrets.NewVFSSecretStore(nil, p)
+	s.secretStore = secrets.NewVFSSecretStoreReader(p, opt.StateStoreEncryption)
 
 	s.keystore, s.keypairIDs, err = newKeystore(opt.Server.CABasePath, opt.Server.SigningCAs)
 	if err != nil {
@@ -176,7 +177,16 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	{
 		node := &corev1.Node{}
 		err := s.uncachedClient.Get(ctx, types.NamespacedName{Name: id.NodeName}, node)
-		if err == nil {
+		if err == nil && isReconfiguring(node) {
+			// A node being reconfigured in place may bootstrap again, but only from the node itself.
+			if err := verifyReconfiguringNode(node, id, r.RemoteAddr); err != nil {
+				klog.Infof("bootstrap %s node %q is being reconfigured in place, but %v; denying to avoid node-impersonation attacks", r.RemoteAddr, id.NodeName, err)
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte("node already registered"))
+				return
+			}
+			klog.Infof("bootstrap %s node %q is being reconfigured in place; allowing bootstrap", r.RemoteAddr, id.NodeName)
+		} else if err == nil {
 			for _, condition := range node.Status.Conditions {
 				if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
 					klog.Infof("bootstrap %s node %q already exists; denying to avoid node-impersonation attacks", r.RemoteAddr, id.NodeName)
@@ -261,13 +271,24 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string) (string, error) {
 	block, _ := pem.Decode([]byte(pubKey))
-	if block.Type != "RSA PUBLIC KEY" {
+	if block == nil {
+		return "", fmt.Errorf("no PEM data found in key")
+	}
+	if block.Type != "RSA PUBLIC KEY" && block.Type != "PUBLIC KEY" {
 		return "", fmt.Errorf("unexpected key type %q", block.Type)
 	}
 	key, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
 		return "", fmt.Errorf("parsing key: %v", err)
 	}
+	algorithm, err := pki.KeyAlgorithmOf(key)
+	if err != nil {
+		return "", err
+	}
+	// RSA keys are accepted from nodes that have not yet been updated to the configured algorithm.
+	if algorithm != pki.KeyAlgorithmRSA && string(algorithm) != s.opt.Server.KeyAlgorithm {
+		return "", fmt.Errorf("key algorithm %q not enabled", algorithm)
+	}
 
 	issueReq := &pki.IssueCertRequest{
 		Signer:    fi.CertificateIDCA,
@@ -337,3 +358,31 @@ func recovery(next http.Handler) http.Handler {
 		next.ServeHTTP(w, req)
 	})
 }
+
+// isReconfiguring returns true if a rolling update has cordoned the node and requested that nodeup runs again on it.
+// Such a node is allowed to bootstrap again, to fetch its new configuration and certificates.
+func isReconfiguring(node *corev1.Node) bool {
+	return node.Spec.Unschedulable && node.Annotations[nodeup.AnnotationReconfigureRequested] != ""
+}
+
+// verifyReconfiguringNode checks that a bootstrap request for a node which is being reconfigured comes from the node itself:
+// the request must come from one of the addresses of the node, and the node must belong to the verified instance group.
+func verifyReconfiguringNode(node *corev1.Node, id *bootstrap.VerifyResult, remoteAddr string) error {
+	if id.InstanceGroupName != "" && node.Labels[kops.NodeLabelInstanceGroup] != id.InstanceGroupName {
+		return fmt.Errorf("node is in instance group %q, not %q", node.Labels[kops.NodeLabelInstanceGroup], id.InstanceGroupName)
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeInternalIP, corev1.NodeExternalIP:
+			if address.Address == host {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("request does not come from an address of the node")
+}
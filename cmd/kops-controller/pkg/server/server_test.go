@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/bootstrap"
+)
+
+func TestVerifyReconfiguringNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "10.0.0.9"},
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+			},
+		},
+	}
+
+	grid := []struct {
+		instanceGroup string
+		remoteAddr    string
+		expectError   bool
+	}{
+		{instanceGroup: "nodes", remoteAddr: "10.0.0.1:41234"},
+		{instanceGroup: "", remoteAddr: "10.0.0.1:41234"},
+		{instanceGroup: "nodes", remoteAddr: "100.96.1.5:41234", expectError: true},
+		{instanceGroup: "nodes", remoteAddr: "10.0.0.9:41234", expectError: true},
+		{instanceGroup: "control-plane", remoteAddr: "10.0.0.1:41234", expectError: true},
+	}
+	for _, g := range grid {
+		id := &bootstrap.VerifyResult{NodeName: "node-1", InstanceGroupName: g.instanceGroup}
+		err := verifyReconfiguringNode(node, id, g.remoteAddr)
+		if g.expectError && err == nil {
+			t.Errorf("expected error for %+v", g)
+		}
+		if !g.expectError && err != nil {
+			t.Errorf("unexpected error for %+v: %v", g, err)
+		}
+	}
+}
@@ -164,7 +164,7 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 	}
 
 	if options.Keyset != "all" {
-		return createKeypair(ctx, out, options, options.Keyset, keyStore)
+		return createKeypair(ctx, out, options, options.Keyset, keyStore, pki.KeyAlgorithm(cluster.KeyAlgorithm()))
 	}
 
 	keysets, err := keyStore.ListKeysets()
@@ -174,7 +174,7 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 
 	for name := range keysets {
 		if rotatableKeysetFilter(name, nil) {
-			if err := createKeypair(ctx, out, options, name, keyStore); err != nil {
+			if err := createKeypair(ctx, out, options, name, keyStore, pki.KeyAlgorithm(cluster.KeyAlgorithm())); err != nil {
 				return fmt.Errorf("creating keypair for %s: %v", name, err)
 			}
 		}
@@ -183,7 +183,7 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 	return nil
 }
 
-func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOptions, name string, keyStore fi.CAStore) error {
+func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOptions, name string, keyStore fi.CAStore, keyAlgorithm pki.KeyAlgorithm) error {
 	var err error
 	var privateKey *pki.PrivateKey
 	if options.PrivateKeyPath != "" {
@@ -202,7 +202,11 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 	var cert *pki.Certificate
 	if options.CertPath == "" {
 		if privateKey == nil {
-			privateKey, err = pki.GeneratePrivateKey()
+			// The service-account keypair always uses RSA, as service account issuer discovery only advertises RS256.
+			if name == "service-account" {
+				keyAlgorithm = pki.KeyAlgorithmRSA
+			}
+			privateKey, err = pki.GeneratePrivateKeyWithAlgorithm(keyAlgorithm)
 			if err != nil {
 				return fmt.Errorf("error generating private key: %v", err)
 			}
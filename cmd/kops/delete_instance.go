@@ -70,6 +70,9 @@ type DeleteInstanceOptions struct {
 	InstanceID string
 
 	Surge bool
+
+	// AllowExecHooks allows the rolling update hooks of the cluster spec to run commands on this machine.
+	AllowExecHooks bool
 }
 
 func (o *DeleteInstanceOptions) initDefaults() {
@@ -139,6 +142,7 @@ func NewCmdDeleteInstance(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.CloudOnly, "cloudonly", options.CloudOnly, "Perform deletion update without confirming progress with Kubernetes")
 	cmd.Flags().BoolVar(&options.Surge, "surge", options.Surge, "Surge by detaching the node from the ASG before deletion")
+	cmd.Flags().BoolVar(&options.AllowExecHooks, "allow-exec-hooks", options.AllowExecHooks, "Allow the rolling update hooks of the cluster spec to run commands on this machine")
 
 	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for a cluster to validate")
 	cmd.Flags().Int32Var(&options.ValidateCount, "validate-count", options.ValidateCount, "Number of times that a cluster needs to be validated after single node update")
@@ -237,6 +241,8 @@ func RunDeleteInstance(ctx context.Context, f *util.Factory, out io.Writer, opti
 		// TODO should we expose this to the UI?
 		ValidateTickDuration:    30 * time.Second,
 		ValidateSuccessDuration: 10 * time.Second,
+
+		Options: instancegroups.RollingUpdateOptions{AllowExecHooks: options.AllowExecHooks},
 	}
 
 	var clusterValidator validation.ClusterValidator
@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/pretty"
@@ -42,7 +44,11 @@ var (
 	(original) and download (local repository) locations.
 
 	When invoked with the ` + pretty.Bash("--copy") + ` flag, will copy each asset from the
-	canonical to the download location.`))
+	canonical to the download location.
+
+	When invoked with the ` + pretty.Bash("--bundle") + ` flag, will write each asset to a
+	bundle file, which can be imported into the local repositories with
+	` + pretty.Bash("kops import assets") + `, for clusters that cannot reach the canonical locations.`))
 
 	getAssetsExample = templates.Examples(i18n.T(`
 	# Display all assets.
@@ -50,6 +56,9 @@ var (
 
 	# Copy assets to the local repositories configured in the cluster spec.
 	kops get assets --copy 
+
+	# Write assets to a bundle file, for import into the local repositories of an air-gapped environment.
+	kops get assets --bundle assets.tar
 	`))
 
 	getAssetsShort = i18n.T(`Display assets for cluster.`)
@@ -57,7 +66,8 @@ var (
 
 type GetAssetsOptions struct {
 	*GetOptions
-	Copy bool
+	Copy   bool
+	Bundle string
 }
 
 type Image struct {
@@ -96,6 +106,8 @@ func NewCmdGetAssets(f *util.Factory, out io.Writer, getOptions *GetOptions) *co
 	}
 
 	cmd.Flags().BoolVar(&options.Copy, "copy", options.Copy, "copy assets to local repository")
+	cmd.Flags().StringVar(&options.Bundle, "bundle", options.Bundle, "write assets to a bundle file, for use with kops import assets")
+	cmd.MarkFlagFilename("bundle", "tar")
 
 	return cmd
 }
@@ -147,6 +159,12 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 		}
 	}
 
+	if options.Bundle != "" {
+		if err := writeAssetBundle(ctx, f, updateClusterResults.Cluster, options.Bundle, updateClusterResults.ImageAssets, updateClusterResults.FileAssets); err != nil {
+			return err
+		}
+	}
+
 	switch options.Output {
 	case OutputTable:
 		if err = imageOutputTable(result.Images, out); err != nil {
@@ -176,6 +194,22 @@ func RunGetAssets(ctx context.Context, f *util.Factory, out io.Writer, options *
 	return nil
 }
 
+func writeAssetBundle(ctx context.Context, f *util.Factory, cluster *kops.Cluster, path string, imageAssets []*assets.ImageAsset, fileAssets []*assets.FileAsset) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+	if err := assets.WriteBundle(ctx, file, imageAssets, fileAssets, f.VFSContext(), cluster); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	return nil
+}
+
 func imageOutputTable(images []*Image, out io.Writer) error {
 	fmt.Println("")
 	t := &tables.Table{}
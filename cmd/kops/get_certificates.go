@@ -0,0 +1,383 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/agent"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/dump"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/resources"
+	resourceops "k8s.io/kops/pkg/resources/ops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	Display the certificates of the cluster's keypairs, and optionally the certificates installed on its nodes.
+
+	With --expiring-within, the command exits with a non-zero status if any certificate
+	that is not distrusted expires within the given duration, or has already expired.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# List the certificates of all keypairs.
+	kops get certificates
+
+	# Also list the certificates installed on the nodes.
+	kops get certificates --nodes
+
+	# Fail if any certificate expires within the next 30 days.
+	kops get certificates --nodes --expiring-within=30d`))
+
+	getCertificatesShort = i18n.T(`Get the certificates of the cluster and their expiry.`)
+)
+
+const (
+	certificateStatusPrimary    = "primary"
+	certificateStatusTrusted    = "trusted"
+	certificateStatusDistrusted = "distrusted"
+)
+
+type GetCertificatesOptions struct {
+	*GetOptions
+
+	// ExpiringWithin makes the command fail if a certificate expires within this duration.
+	ExpiringWithin string
+
+	// Nodes enables collection of the certificates installed on the nodes.
+	Nodes      bool
+	PrivateKey string
+	SSHUser    string
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := &GetCertificatesOptions{
+		GetOptions: getOptions,
+		PrivateKey: "~/.ssh/id_rsa",
+		SSHUser:    "ubuntu",
+	}
+	cmd := &cobra.Command{
+		Use:     "certificates",
+		Aliases: []string{"certificate", "certs", "cert"},
+		Short:   getCertificatesShort,
+		Long:    getCertificatesLong,
+		Example: getCertificatesExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+			if len(args) != 0 {
+				return fmt.Errorf("unexpected arguments: %v", args)
+			}
+			return nil
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetCertificates(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.ExpiringWithin, "expiring-within", options.ExpiringWithin, "Exit with a non-zero status if a certificate expires within this duration, such as 30d or 72h")
+	cmd.RegisterFlagCompletionFunc("expiring-within", cobra.NoFileCompletions)
+	cmd.Flags().BoolVar(&options.Nodes, "nodes", options.Nodes, "Also collect the certificates installed on the nodes, using SSH")
+	cmd.Flags().StringVar(&options.PrivateKey, "private-key", options.PrivateKey, "File containing private key to use for SSH access to instances")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "The remote user for SSH access to instances")
+	cmd.RegisterFlagCompletionFunc("ssh-user", cobra.NoFileCompletions)
+
+	return cmd
+}
+
+type certificateItem struct {
+	Name      string    `json:"name"`
+	ID        string    `json:"id,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Type      string    `json:"type"`
+	Serial    string    `json:"serial"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	Status    string    `json:"status,omitempty"`
+}
+
+func newCertificateItem(name string, cert *x509.Certificate) *certificateItem {
+	return &certificateItem{
+		Name:      name,
+		Type:      pki.BuildTypeDescription(cert),
+		Serial:    cert.SerialNumber.String(),
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+	}
+}
+
+// listKeysetCertificates returns the certificates of all items of all keysets in the keystore.
+func listKeysetCertificates(keyStore fi.CAStore) ([]*certificateItem, error) {
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing Keysets: %v", err)
+	}
+
+	var items []*certificateItem
+	for name, keyset := range keysets {
+		for _, keysetItem := range keyset.Items {
+			if keysetItem.Certificate == nil {
+				continue
+			}
+			item := newCertificateItem(name, keysetItem.Certificate.Certificate)
+			item.ID = keysetItem.Id
+			switch {
+			case keysetItem.DistrustTimestamp != nil:
+				item.Status = certificateStatusDistrusted
+			case keyset.Primary != nil && keysetItem.Id == keyset.Primary.Id:
+				item.Status = certificateStatusPrimary
+			default:
+				item.Status = certificateStatusTrusted
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// parseExpiringWithin parses a duration, additionally accepting a whole number of days such as "30d".
+func parseExpiringWithin(s string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// findExpiring returns the items that are not distrusted and expire before the deadline.
+func findExpiring(items []*certificateItem, deadline time.Time) []*certificateItem {
+	var expiring []*certificateItem
+	for _, item := range items {
+		if item.Status == certificateStatusDistrusted {
+			continue
+		}
+		if item.NotAfter.Before(deadline) {
+			expiring = append(expiring, item)
+		}
+	}
+	return expiring
+}
+
+func RunGetCertificates(ctx context.Context, f commandutils.Factory, out io.Writer, options *GetCertificatesOptions) error {
+	var expiringWithin time.Duration
+	if options.ExpiringWithin != "" {
+		d, err := parseExpiringWithin(options.ExpiringWithin)
+		if err != nil {
+			return fmt.Errorf("parsing --expiring-within: %w", err)
+		}
+		expiringWithin = d
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	items, err := listKeysetCertificates(keyStore)
+	if err != nil {
+		return err
+	}
+
+	if options.Nodes {
+		nodeItems, err := collectNodeCertificates(ctx, cluster, options)
+		if err != nil {
+			return err
+		}
+		items = append(items, nodeItems...)
+	}
+
+	if len(items) == 0 {
+		return fmt.Errorf("no certificates found")
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Node != items[j].Node {
+			return items[i].Node < items[j].Node
+		}
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].NotAfter.Before(items[j].NotAfter)
+	})
+
+	switch options.Output {
+
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("NAME", func(i *certificateItem) string {
+			return i.Name
+		})
+		t.AddColumn("ID", func(i *certificateItem) string {
+			return i.ID
+		})
+		t.AddColumn("TYPE", func(i *certificateItem) string {
+			return i.Type
+		})
+		t.AddColumn("SUBJECT", func(i *certificateItem) string {
+			return i.Subject
+		})
+		t.AddColumn("ISSUER", func(i *certificateItem) string {
+			return i.Issuer
+		})
+		t.AddColumn("SERIAL", func(i *certificateItem) string {
+			return i.Serial
+		})
+		t.AddColumn("ISSUED", func(i *certificateItem) string {
+			return i.NotBefore.Local().Format("2006-01-02")
+		})
+		t.AddColumn("EXPIRES", func(i *certificateItem) string {
+			return i.NotAfter.Local().Format("2006-01-02")
+		})
+		t.AddColumn("STATUS", func(i *certificateItem) string {
+			return i.Status
+		})
+		if err := t.Render(items, out, "NAME", "ID", "TYPE", "SUBJECT", "ISSUER", "SERIAL", "ISSUED", "EXPIRES", "STATUS"); err != nil {
+			return err
+		}
+
+	case OutputYaml:
+		y, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+
+	case OutputJSON:
+		j, err := json.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.Output)
+	}
+
+	if options.ExpiringWithin != "" {
+		if expiring := findExpiring(items, time.Now().Add(expiringWithin)); len(expiring) != 0 {
+			var names []string
+			for _, item := range expiring {
+				names = append(names, item.Name)
+			}
+			return fmt.Errorf("%d certificate(s) expire within %s: %s", len(expiring), options.ExpiringWithin, strings.Join(names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// collectNodeCertificates collects the certificates installed on the cluster's instances over SSH.
+func collectNodeCertificates(ctx context.Context, cluster *kops.Cluster, options *GetCertificatesOptions) ([]*certificateItem, error) {
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceMap, err := resourceops.ListResources(cloud, cluster)
+	if err != nil {
+		return nil, err
+	}
+	d, err := resources.BuildDump(ctx, cloud, resourceMap)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig, keyRing, err := buildSSHConfig(options.PrivateKey, options.SSHUser)
+	if err != nil {
+		return nil, err
+	}
+	defer func(keyRing agent.Agent) {
+		_ = keyRing.RemoveAll()
+	}(keyRing)
+
+	k8sClient, err := createK8sClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	bastionAddress, additionalIPs, additionalPrivateIPs := instanceAddresses(d)
+	collector := dump.NewCertificateCollector(bastionAddress, sshConfig, keyRing)
+	nodeCertificates, errs := collector.CollectCertificates(ctx, *nodes, additionalIPs, additionalPrivateIPs)
+	for _, err := range errs {
+		klog.Warningf("%v", err)
+	}
+	if len(errs) != 0 && len(nodeCertificates) == 0 {
+		return nil, fmt.Errorf("unable to collect certificates from any node")
+	}
+
+	var items []*certificateItem
+	for _, nodeCertificate := range nodeCertificates {
+		item := newCertificateItem(nodeCertificate.Node+":"+nodeCertificate.Path, nodeCertificate.Certificate)
+		item.Node = nodeCertificate.Node
+		item.Path = nodeCertificate.Path
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExpiringWithin(t *testing.T) {
+	grid := []struct {
+		input    string
+		expected time.Duration
+		err      bool
+	}{
+		{input: "30d", expected: 30 * 24 * time.Hour},
+		{input: "0d", expected: 0},
+		{input: "72h", expected: 72 * time.Hour},
+		{input: "90m", expected: 90 * time.Minute},
+		{input: "d", err: true},
+		{input: "1.5d", err: true},
+		{input: "-1d", err: true},
+		{input: "-1h", err: true},
+		{input: "soon", err: true},
+	}
+	for _, g := range grid {
+		t.Run(g.input, func(t *testing.T) {
+			actual, err := parseExpiringWithin(g.input)
+			if g.err {
+				if err == nil {
+					t.Errorf("expected error, got %v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != g.expected {
+				t.Errorf("expected %v, got %v", g.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFindExpiring(t *testing.T) {
+	now := time.Now()
+	items := []*certificateItem{
+		{Name: "expired", NotAfter: now.Add(-time.Hour), Status: certificateStatusTrusted},
+		{Name: "expiring", NotAfter: now.Add(10 * 24 * time.Hour), Status: certificateStatusPrimary},
+		{Name: "valid", NotAfter: now.Add(100 * 24 * time.Hour), Status: certificateStatusPrimary},
+		{Name: "distrusted", NotAfter: now.Add(time.Hour), Status: certificateStatusDistrusted},
+		{Name: "node:/etc/kubernetes/kubelet.crt", NotAfter: now.Add(24 * time.Hour)},
+	}
+
+	expiring := findExpiring(items, now.Add(30*24*time.Hour))
+	var names []string
+	for _, item := range expiring {
+		names = append(names, item.Name)
+	}
+	expected := []string{"expired", "expiring", "node:/etc/kubernetes/kubelet.crt"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+}
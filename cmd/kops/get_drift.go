@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/drift"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/pkg/resources/ops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+)
+
+var (
+	getDriftLong = pretty.LongDesc(i18n.T(`
+	Display the differences between the cloud resources of a cluster and its model.
+
+	The resources of the model are looked up in the cloud, as by a dry run of ` + pretty.Bash("kops update cluster") + `.
+	Resources that differ from the model, are missing, or would be deleted are reported,
+	as are resources tagged as belonging to the cluster that the model does not manage.
+	Changes to the cluster or instance groups that were not yet applied are also reported.
+
+	The command exits with status 2 if any difference is found.`))
+
+	getDriftExample = templates.Examples(i18n.T(`
+	# Display the differences between the cloud resources of a cluster and its model.
+	kops get drift k8s-cluster.example.com
+
+	# Display the differences as JSON, for example from a scheduled job.
+	kops get drift k8s-cluster.example.com -o json
+	`))
+
+	getDriftShort = i18n.T(`Display cloud resources that differ from the cluster model.`)
+)
+
+func NewCmdGetDrift(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "drift [CLUSTER]",
+		Short:             getDriftShort,
+		Long:              getDriftLong,
+		Example:           getDriftExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			drifts, err := RunGetDrift(cmd.Context(), f, out, options)
+			if err != nil {
+				return err
+			}
+
+			// We want to exit non-zero if drift was found, so that it can be detected by scheduled jobs.
+			if len(drifts) != 0 {
+				os.Exit(2)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func RunGetDrift(ctx context.Context, f *util.Factory, out io.Writer, options *GetOptions) ([]drift.Drift, error) {
+	switch options.Output {
+	case OutputTable, OutputYaml, OutputJSON:
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:              cloud,
+		Clientset:          clientset,
+		Cluster:            cluster.DeepCopy(),
+		DryRun:             true,
+		TargetName:         cloudup.TargetDryRun,
+		LifecycleOverrides: map[string]fi.Lifecycle{},
+		DeletionProcessing: fi.DeletionProcessingModeDeleteIncludingDeferred,
+		DryRunOutput:       io.Discard,
+		MessageOutput:      os.Stderr,
+		SkipChangePolicy:   true,
+	}
+	if err := applyCmd.Run(ctx); err != nil {
+		return nil, err
+	}
+	plan, err := applyCmd.Target.(*fi.CloudupDryRunTarget).Plan(applyCmd.TaskMap)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudResources, err := ops.ListResources(cloud, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error listing cloud resources: %w", err)
+	}
+
+	drifts := drift.Detect(plan, applyCmd.TaskMap, cloudResources)
+
+	switch options.Output {
+	case OutputTable:
+		if len(drifts) == 0 {
+			fmt.Fprintf(out, "No drift found for cluster %q.\n", cluster.Name)
+			return drifts, nil
+		}
+		return drifts, driftOutputTable(drifts, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(drifts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		if drifts == nil {
+			drifts = []drift.Drift{}
+		}
+		b, err := json.Marshal(drifts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		b = append(b, '\n')
+		if _, err := out.Write(b); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	}
+	return drifts, nil
+}
+
+func driftOutputTable(drifts []drift.Drift, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("KIND", func(d drift.Drift) string {
+		return string(d.Kind)
+	})
+	t.AddColumn("TYPE", func(d drift.Drift) string {
+		return d.Type
+	})
+	t.AddColumn("NAME", func(d drift.Drift) string {
+		return d.Name
+	})
+	t.AddColumn("ID", func(d drift.Drift) string {
+		return d.ID
+	})
+	t.AddColumn("FIELDS", func(d drift.Drift) string {
+		var fields []string
+		for _, field := range d.Fields {
+			fields = append(fields, field.Name)
+		}
+		return strings.Join(fields, ",")
+	})
+
+	return t.Render(drifts, out, "KIND", "TYPE", "NAME", "ID", "FIELDS")
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -95,6 +96,7 @@ type keypairItem struct {
 	IsCA              bool       `json:"isCA,omitempty"`
 	NotBefore         *time.Time `json:"notBefore,omitempty"`
 	NotAfter          *time.Time `json:"notAfter,omitempty"`
+	KeyAlgorithm      string     `json:"keyAlgorithm,omitempty"`
 	KeyLength         *int       `json:"keyLength,omitempty"`
 	HasPrivateKey     bool       `json:"hasPrivateKey,omitempty"`
 }
@@ -152,6 +154,9 @@ func listKeypairs(keyStore fi.CAStore, names []string, includeDistrusted bool) (
 						sort.Strings(alternateNames)
 						keypair.AlternateNames = alternateNames
 					}
+					if algorithm, err := pki.KeyAlgorithmOf(cert.PublicKey); err == nil {
+						keypair.KeyAlgorithm = string(algorithm)
+					}
 					if rsaKey, ok := cert.PublicKey.(*rsa.PublicKey); ok {
 						keypair.KeyLength = fi.PtrTo(rsaKey.N.BitLen())
 					}
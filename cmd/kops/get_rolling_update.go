@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/instancegroups/journal"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/util/pkg/tables"
+)
+
+var (
+	getRollingUpdateLong = pretty.LongDesc(i18n.T(`
+	Display the progress of an in-flight or interrupted rolling update.
+
+	The progress is recorded in the state store by ` + pretty.Bash("kops rolling-update cluster --yes") + `
+	and removed once the rolling update completes successfully.`))
+
+	getRollingUpdateExample = templates.Examples(i18n.T(`
+	# Display the progress of the rolling update of a cluster.
+	kops get rolling-update k8s-cluster.example.com
+
+	# Display the progress as YAML.
+	kops get rolling-update k8s-cluster.example.com -o yaml
+	`))
+
+	getRollingUpdateShort = i18n.T(`Display the progress of a rolling update.`)
+)
+
+func NewCmdGetRollingUpdate(f *util.Factory, out io.Writer, options *GetOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "rolling-update [CLUSTER]",
+		Short:             getRollingUpdateShort,
+		Long:              getRollingUpdateLong,
+		Example:           getRollingUpdateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetRollingUpdate(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunGetRollingUpdate(ctx context.Context, f *util.Factory, out io.Writer, options *GetOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	j, err := clientset.RollingUpdateJournalFor(cluster).Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch options.Output {
+	case OutputTable:
+		if j == nil {
+			fmt.Fprintf(out, "No rolling update in progress for cluster %q.\n", cluster.Name)
+			return nil
+		}
+		return rollingUpdateOutputTable(j, out)
+	case OutputYaml:
+		y, err := yaml.Marshal(j)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
+	case OutputJSON:
+		b, err := json.Marshal(j)
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(b); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+}
+
+func rollingUpdateOutputTable(j *journal.Journal, out io.Writer) error {
+	fmt.Fprintf(out, "Rolling update of cluster %q started at %s by %s, last updated at %s.\n",
+		j.ClusterName, j.StartedAt.Format(time.RFC3339), j.Owner, j.UpdatedAt.Format(time.RFC3339))
+	if j.LastError != "" {
+		fmt.Fprintf(out, "Stopped by error: %s\n", j.LastError)
+	}
+	fmt.Fprintf(out, "\n")
+
+	t := &tables.Table{}
+	t.AddColumn("ID", func(i *journal.Instance) string {
+		return i.ID
+	})
+	t.AddColumn("INSTANCE-GROUP", func(i *journal.Instance) string {
+		return i.InstanceGroup
+	})
+	t.AddColumn("NODE-NAME", func(i *journal.Instance) string {
+		return i.NodeName
+	})
+	t.AddColumn("PHASE", func(i *journal.Instance) string {
+		return string(i.Phase)
+	})
+	t.AddColumn("UPDATED", func(i *journal.Instance) string {
+		return i.UpdatedAt.Format(time.RFC3339)
+	})
+
+	return t.Render(j.Instances, out, "ID", "INSTANCE-GROUP", "NODE-NAME", "PHASE", "UPDATED")
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	importAssetsLong = pretty.LongDesc(i18n.T(`
+	Import the assets of a bundle written by ` + pretty.Bash("kops get assets --bundle") + ` into the
+	local repositories configured in the cluster spec.
+
+	Images are pushed to ` + pretty.Bash("spec.assets.containerRegistry") + ` or ` + pretty.Bash("spec.assets.containerProxy") + `,
+	and files are uploaded to ` + pretty.Bash("spec.assets.fileRepository") + `, at the locations the cluster
+	will download them from. The hash of each file and the digest of each image are verified.`))
+
+	importAssetsExample = templates.Examples(i18n.T(`
+	# Import a bundle into the local repositories of the cluster.
+	kops import assets assets.tar --name k8s-cluster.example.com
+	`))
+
+	importAssetsShort = i18n.T(`Import a bundle of assets into the local repositories.`)
+)
+
+type ImportAssetsOptions struct {
+	ClusterName string
+	Bundle      string
+}
+
+func NewCmdImportAssets(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ImportAssetsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "assets BUNDLE",
+		Short:   importAssetsShort,
+		Long:    importAssetsLong,
+		Example: importAssetsExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+			if len(args) != 1 {
+				return fmt.Errorf("must specify the bundle to import")
+			}
+			options.Bundle = args[0]
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{"tar"}, cobra.ShellCompDirectiveFilterFileExt
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunImportAssets(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunImportAssets(ctx context.Context, f *util.Factory, out io.Writer, options *ImportAssetsOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	file, err := os.Open(options.Bundle)
+	if err != nil {
+		return fmt.Errorf("error opening bundle: %w", err)
+	}
+	defer file.Close()
+
+	assetBuilder := assets.NewAssetBuilder(f.VFSContext(), cluster.Spec.Assets, cluster.Spec.KubernetesVersion, false)
+	if err := assets.ImportBundle(ctx, file, assetBuilder, f.VFSContext(), cluster); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Imported assets from %s\n", options.Bundle)
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 
 	"k8s.io/kops"
+	"k8s.io/kops/pkg/otel/otelsetup"
 )
 
 func main() {
@@ -39,7 +40,7 @@ func run(ctx context.Context) error {
 		serviceVersion += ".git-" + kops.GitVersion
 	}
 
-	otelShutdown, err := setupOTelSDK(ctx, serviceName, serviceVersion)
+	otelShutdown, err := otelsetup.SetupOTelSDK(ctx, serviceName, serviceVersion)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
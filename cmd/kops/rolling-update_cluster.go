@@ -89,6 +89,15 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Update up to 4 node instance groups of the k8s-cluster.example.com kOps cluster at once,
+		# never updating two groups in the same zone at the same time.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --max-concurrent-node-groups 4 --one-group-per-zone
+
+		# Roll back any node instance group whose new instances fail cluster validation.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --rollback-on-failure
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -144,6 +153,9 @@ type RollingUpdateOptions struct {
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
 
+	// Resume resumes an interrupted rolling update from the progress journal in the state store.
+	Resume bool
+
 	// TODO: Move more/all above options to RollingUpdateOptions
 	instancegroups.RollingUpdateOptions
 }
@@ -166,6 +178,8 @@ func (o *RollingUpdateOptions) InitDefaults() {
 
 	o.DrainTimeout = 15 * time.Minute
 
+	o.Resume = true
+
 	o.RollingUpdateOptions.InitDefaults()
 }
 
@@ -214,6 +228,12 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "Fail if draining a node fails")
 	cmd.Flags().BoolVar(&options.FailOnValidate, "fail-on-validate-error", true, "Fail if the cluster fails to validate")
 
+	cmd.Flags().IntVar(&options.MaxConcurrentNodeGroups, "max-concurrent-node-groups", options.MaxConcurrentNodeGroups, "Maximum number of node instance groups to update at the same time")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume an interrupted rolling update, replacing only the instances it selected")
+	cmd.Flags().BoolVar(&options.OneGroupPerZone, "one-group-per-zone", options.OneGroupPerZone, "Do not update two node instance groups sharing a zone at the same time")
+	cmd.Flags().BoolVar(&options.RollbackOnFailure, "rollback-on-failure", options.RollbackOnFailure, "Roll a node instance group back to its previously applied spec if the cluster fails to validate after replacing its instances")
+	cmd.Flags().BoolVar(&options.AllowExecHooks, "allow-exec-hooks", options.AllowExecHooks, "Allow the rolling update hooks of the cluster spec to run commands on this machine")
+
 	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		switch name {
 		case "ig", "instance-groups":
@@ -371,6 +391,8 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 
 		// TODO: Move more of the passthrough options here, instead of duplicating them.
 		Options: options.RollingUpdateOptions,
+
+		Journal: clientset.RollingUpdateJournalFor(cluster),
 	}
 
 	err = d.AdjustNeedUpdate(groups)
@@ -378,6 +400,20 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return err
 	}
 
+	if options.Resume {
+		resumed, err := d.ResumeFromJournal(ctx, groups)
+		if err != nil {
+			return err
+		}
+		if resumed != nil {
+			fmt.Fprintf(out, "Resuming rolling update started at %s by %s.\n", resumed.StartedAt.Format(time.RFC3339), resumed.Owner)
+			if resumed.LastError != "" {
+				fmt.Fprintf(out, "The rolling update was stopped by: %s\n", resumed.LastError)
+			}
+			fmt.Fprintf(out, "Use --resume=false to start a new rolling update instead.\n\n")
+		}
+	}
+
 	{
 		t := &tables.Table{}
 		t.AddColumn("NAME", func(r *cloudinstances.CloudInstanceGroup) string {
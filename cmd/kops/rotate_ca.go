@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/carotation"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/pretty"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rotateCALong = pretty.LongDesc(i18n.T(`
+	Rotate the keypairs of a CA keyset.
+
+	A new keypair is created and staged, then promoted to be the primary,
+	then the previous keypairs are distrusted. After each of these steps the
+	cluster is updated, rolling-updated and validated, as by ` + pretty.Bash("kops update cluster --yes") + `,
+	` + pretty.Bash("kops rolling-update cluster --yes") + ` and ` + pretty.Bash("kops validate cluster") + `.
+	The previous keypairs are only distrusted once every node has been replaced
+	with one trusting the new keypair.
+
+	The phase the rotation reached is recorded in the state store, so running the
+	command again resumes an interrupted rotation.
+
+	When rotating "kubernetes-ca", the kubeconfig is exported after each step, and
+	the rotation pauses after staging and after promoting the new keypair, so that the
+	new certificate-authority-data and credentials can be distributed to the
+	clients of the cluster. Run the command again to continue.
+
+	Without --yes, the phase of the rotation in progress is printed.
+	`))
+
+	rotateCAExample = templates.Examples(i18n.T(`
+	# Rotate the Kubernetes general CA, exporting admin credentials after each step.
+	kops rotate ca kubernetes-ca --admin --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Rotate the CA of the main etcd cluster.
+	kops rotate ca etcd-manager-ca-main --yes \
+		--name k8s-cluster.example.com --state s3://my-state-store
+	`))
+
+	rotateCAShort = i18n.T(`Rotate the keypairs of a CA keyset.`)
+)
+
+type RotateCAOptions struct {
+	ClusterName string
+	Keyset      string
+	Yes         bool
+
+	// Pause is true if the rotation of "kubernetes-ca" pauses for clients to be given the new kubeconfig.
+	Pause bool
+	// ValidationTimeout is the maximum time to wait for the cluster to validate after each step.
+	ValidationTimeout time.Duration
+
+	admin time.Duration
+	user  string
+}
+
+func (o *RotateCAOptions) InitDefaults() {
+	o.Pause = true
+	o.ValidationTimeout = 15 * time.Minute
+}
+
+// NewCmdRotateCA returns a rotate ca command.
+func NewCmdRotateCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateCAOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "ca KEYSET",
+		Short:   rotateCAShort,
+		Long:    rotateCALong,
+		Example: rotateCAExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("must specify name of keyset to rotate")
+			}
+			if len(args) > 1 {
+				return fmt.Errorf("can only rotate one keyset at a time")
+			}
+			options.Keyset = args[0]
+
+			if options.admin != 0 && options.user != "" {
+				return fmt.Errorf("cannot use both --admin and --user")
+			}
+
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeRotateCAKeyset(cmd.Context(), f, args, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRotateCA(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rotation; without --yes the phase of the rotation in progress is printed")
+	cmd.Flags().BoolVar(&options.Pause, "pause", options.Pause, "Pause the rotation of kubernetes-ca after staging and after promoting the new keypair")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for the cluster to validate after each step")
+	cmd.Flags().DurationVar(&options.admin, "admin", options.admin, "Also export a cluster admin user credential with the specified lifetime after each step of the rotation of kubernetes-ca")
+	cmd.Flags().Lookup("admin").NoOptDefVal = kubeconfig.DefaultKubecfgAdminLifetime.String()
+	cmd.Flags().StringVar(&options.user, "user", options.user, "Existing user in kubeconfig file to use when exporting the kubeconfig")
+	cmd.RegisterFlagCompletionFunc("user", completeKubecfgUser)
+
+	return cmd
+}
+
+// RunRotateCA rotates the keypairs of a CA keyset, or resumes its rotation.
+func RunRotateCA(ctx context.Context, f *util.Factory, out io.Writer, options *RotateCAOptions) error {
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	if !carotation.IsCAKeyset(cluster, options.Keyset) {
+		return fmt.Errorf("rotating %q is not supported; only CA keysets can be rotated: %s", options.Keyset, strings.Join(carotation.CAKeysets(cluster), ", "))
+	}
+
+	clientSet, err := f.KopsClient()
+	if err != nil {
+		return fmt.Errorf("getting clientset: %v", err)
+	}
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return fmt.Errorf("getting keystore: %v", err)
+	}
+
+	store := clientSet.CARotationsFor(cluster)
+
+	if !options.Yes {
+		rotation, err := store.Get(ctx, options.Keyset)
+		if err != nil {
+			return err
+		}
+		if rotation == nil {
+			fmt.Fprintf(out, "No rotation of %s is in progress.\n", options.Keyset)
+			fmt.Fprintf(out, "\nMust specify --yes to start the rotation\n")
+			return nil
+		}
+		fmt.Fprintf(out, "Rotation of %s to keypair %s started at %s reached phase %s.\n", rotation.Keyset, rotation.NewKeypairID, rotation.StartedAt.Format(time.RFC3339), rotation.Phase)
+		if rotation.LastError != "" {
+			fmt.Fprintf(out, "It stopped with error: %s\n", rotation.LastError)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to continue the rotation\n")
+		return nil
+	}
+
+	isKubernetesCA := options.Keyset == "kubernetes-ca"
+	rotator := &carotation.Rotator{
+		KeyStore:        keyStore,
+		Store:           store,
+		Cluster:         &rotateCACluster{f: f, out: out, options: options, exportKubeconfig: isKubernetesCA},
+		Out:             out,
+		PauseForClients: isKubernetesCA && options.Pause,
+		KeyAlgorithm:    pki.KeyAlgorithm(cluster.KeyAlgorithm()),
+	}
+	done, err := rotator.Run(ctx, options.Keyset)
+	if err != nil {
+		return err
+	}
+	if !done {
+		fmt.Fprintf(out, "\nDistribute the exported kubeconfig to the clients of the cluster, then run kops rotate ca %s --yes to continue.\n", options.Keyset)
+	}
+	return nil
+}
+
+// rotateCACluster applies the keystore to a cluster using the update, rolling-update, validate and export commands.
+type rotateCACluster struct {
+	f                *util.Factory
+	out              io.Writer
+	options          *RotateCAOptions
+	exportKubeconfig bool
+}
+
+var _ carotation.Cluster = &rotateCACluster{}
+
+func (c *rotateCACluster) Update(ctx context.Context) error {
+	updateOptions := &UpdateClusterOptions{}
+	updateOptions.InitDefaults()
+	updateOptions.ClusterName = c.options.ClusterName
+	updateOptions.Yes = true
+	updateOptions.CreateKubecfg = false
+	if _, err := RunUpdateCluster(ctx, c.f, c.out, updateOptions); err != nil {
+		return fmt.Errorf("updating cluster: %w", err)
+	}
+
+	rollingUpdateOptions := &RollingUpdateOptions{}
+	rollingUpdateOptions.InitDefaults()
+	rollingUpdateOptions.ClusterName = c.options.ClusterName
+	rollingUpdateOptions.Yes = true
+	rollingUpdateOptions.FailOnDrainError = true
+	rollingUpdateOptions.ValidationTimeout = c.options.ValidationTimeout
+	if err := RunRollingUpdateCluster(ctx, c.f, c.out, rollingUpdateOptions); err != nil {
+		return fmt.Errorf("rolling-updating cluster: %w", err)
+	}
+
+	validateOptions := &ValidateClusterOptions{}
+	validateOptions.InitDefaults()
+	validateOptions.ClusterName = c.options.ClusterName
+	validateOptions.wait = c.options.ValidationTimeout
+	validateOptions.count = 1
+	if _, err := RunValidateCluster(ctx, c.f, c.out, validateOptions); err != nil {
+		return fmt.Errorf("validating cluster: %w", err)
+	}
+	return nil
+}
+
+func (c *rotateCACluster) VerifyNodes(ctx context.Context, keyset string, item *fi.KeysetItem, primary bool) error {
+	clientSet, err := c.f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, c.f, c.options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	cloud, err := cloudup.BuildCloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := createK8sClient(cluster)
+	if err != nil {
+		return err
+	}
+
+	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+
+	list, err := clientSet.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	configs, err := readNodeupConfigs(ctx, cluster, instanceGroups)
+	if err != nil {
+		return err
+	}
+	if err := carotation.CheckCABundles(keyset, item, configs, primary); err != nil {
+		return err
+	}
+
+	groups, err := cloud.GetCloudGroups(cluster, instanceGroups, false, nodeList.Items)
+	if err != nil {
+		return err
+	}
+	return carotation.CheckNodesUpdated(groups, nodeList.Items)
+}
+
+// readNodeupConfigs reads the nodeup configurations of the instance groups from the state store,
+// keyed by the name of their instance group. Bastions are skipped, as they are not given any CA.
+func readNodeupConfigs(ctx context.Context, cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup) (map[string]*nodeup.Config, error) {
+	configBase, err := vfs.Context.BuildVfsPath(cluster.Spec.ConfigStore.Base)
+	if err != nil {
+		return nil, fmt.Errorf("parsing configStore.base %q: %w", cluster.Spec.ConfigStore.Base, err)
+	}
+
+	configs := make(map[string]*nodeup.Config)
+	for _, ig := range instanceGroups {
+		if ig.Spec.Role == kopsapi.InstanceGroupRoleBastion {
+			continue
+		}
+		p := configBase.Join("igconfig", ig.Spec.Role.ToLowerString(), ig.Name, "nodeupconfig.yaml")
+		b, err := p.ReadFile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading nodeup config of instance group %s: %w", ig.Name, err)
+		}
+		config := &nodeup.Config{}
+		if err := utils.YamlUnmarshal(b, config); err != nil {
+			return nil, fmt.Errorf("parsing nodeup config of instance group %s: %w", ig.Name, err)
+		}
+		configs[ig.Name] = config
+	}
+	return configs, nil
+}
+
+func (c *rotateCACluster) ExportKubeconfig(ctx context.Context) error {
+	if !c.exportKubeconfig {
+		return nil
+	}
+	exportOptions := &ExportKubeconfigOptions{
+		ClusterName: c.options.ClusterName,
+		admin:       c.options.admin,
+		user:        c.options.user,
+	}
+	if err := RunExportKubeconfig(ctx, c.f, c.out, exportOptions, nil); err != nil {
+		return fmt.Errorf("exporting kubeconfig: %w", err)
+	}
+	return nil
+}
+
+func completeRotateCAKeyset(ctx context.Context, f commandutils.Factory, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return commandutils.CompletionError("too many arguments", nil)
+	}
+
+	commandutils.ConfigureKlogForCompletion()
+
+	cluster, clientSet, completions, directive := GetClusterForCompletion(ctx, f, nil)
+	if cluster == nil {
+		return completions, directive
+	}
+
+	_, _, completions, directive = completeKeyset(ctx, cluster, clientSet, args, func(name string, _ *fi.Keyset) bool {
+		return carotation.IsCAKeyset(cluster, name)
+	})
+	return completions, directive
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -27,8 +28,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
@@ -53,6 +56,16 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After the cluster has been edited or upgraded, update the cloud resources with:
 	kops update cluster k8s-cluster.example.com --yes --state=s3://my-state-store --yes
+
+	# Print the changes that would be made as JSON, for processing by other tools.
+	kops update cluster k8s-cluster.example.com --output json
+
+	# Save the changes that would be made, then apply exactly those changes after review.
+	kops update cluster k8s-cluster.example.com --out-plan=plan.json
+	kops update cluster k8s-cluster.example.com --plan=plan.json --yes
+
+	# Update the cloud resources, allowing the changes forbidden by the "iam" rule of the change policy.
+	kops update cluster k8s-cluster.example.com --allow-policy-rule=iam --yes
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -86,6 +99,19 @@ type UpdateClusterOptions struct {
 	// The goal is that the cluster can keep running even during more disruptive
 	// infrastructure changes.
 	Prune bool
+
+	// Output, if set, is the format (json or yaml) in which a dry run prints the planned changes.
+	Output string
+
+	// OutPlan, if set, is the file a dry run saves its plan to.
+	OutPlan string
+	// Plan, if set, is a file saved by a dry run; only the changes it holds are applied.
+	Plan string
+
+	// Policies are the locations of change policies checked in addition to the one in the state store.
+	Policies []string
+	// AllowedPolicyRules are the names of change policy rules whose forbidden changes are allowed.
+	AllowedPolicyRules []string
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -143,6 +169,20 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.Prune, "prune", options.Prune, "Delete old revisions of cloud resources that were needed during an upgrade")
 
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Print the planned changes of a dry run in a machine-readable format. One of: json, yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	cmd.Flags().StringVar(&options.OutPlan, "out-plan", options.OutPlan, "Save the planned changes of a dry run to a file, to apply them later with --plan")
+	cmd.MarkFlagFilename("out-plan")
+	cmd.Flags().StringVar(&options.Plan, "plan", options.Plan, "Apply the changes saved to a file with --out-plan, refusing if the cluster changed since")
+	cmd.MarkFlagFilename("plan")
+
+	cmd.Flags().StringSliceVar(&options.Policies, "policy", options.Policies, "Change policies to check the changes against, in addition to the one in the state store")
+	cmd.MarkFlagFilename("policy", "yaml", "yml")
+	cmd.Flags().StringSliceVar(&options.AllowedPolicyRules, "allow-policy-rule", options.AllowedPolicyRules, "Names of change policy rules whose forbidden changes are allowed")
+
 	return cmd
 }
 
@@ -198,6 +238,24 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		targetName = cloudup.TargetDryRun
 	}
 
+	switch c.Output {
+	case "", OutputJSON, OutputYaml:
+	default:
+		return results, fmt.Errorf("unsupported output format %q, available formats: %s, %s", c.Output, OutputJSON, OutputYaml)
+	}
+	if c.Output != "" && !isDryrun {
+		return results, fmt.Errorf("--output can only be used for a dry run")
+	}
+	if c.OutPlan != "" && (!isDryrun || c.Target != cloudup.TargetDirect) {
+		return results, fmt.Errorf("--out-plan can only be used for a dry run of the %s target", cloudup.TargetDirect)
+	}
+	if c.Plan != "" && (isDryrun || c.Target != cloudup.TargetDirect) {
+		return results, fmt.Errorf("--plan can only be used with --yes and the %s target", cloudup.TargetDirect)
+	}
+	if c.OutPlan != "" && c.GetAssets {
+		return results, fmt.Errorf("--out-plan cannot be used to get assets")
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
@@ -290,10 +348,51 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return nil, err
 	}
 
+	var savedPlan *cloudup.SavedPlan
+	var planFingerprint *cloudup.PlanFingerprint
+	var instanceGroups []*kops.InstanceGroup
+	if c.OutPlan != "" || c.Plan != "" {
+		if c.Plan != "" {
+			savedPlan, err = cloudup.ReadSavedPlan(c.Plan)
+			if err != nil {
+				return results, err
+			}
+		}
+
+		list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return results, err
+		}
+		for i := range list.Items {
+			instanceGroups = append(instanceGroups, &list.Items[i])
+		}
+		planFingerprint, err = cloudup.BuildPlanFingerprint(cluster, instanceGroups, keyStore)
+		if err != nil {
+			return results, err
+		}
+	}
+	planOptions := cloudup.SavedPlanOptions{
+		Phase: string(phase),
+		Prune: c.Prune,
+	}
+	if len(lifecycleOverrideMap) != 0 {
+		planOptions.LifecycleOverrides = make(map[string]string)
+		for taskName, lifecycle := range lifecycleOverrideMap {
+			planOptions.LifecycleOverrides[taskName] = string(lifecycle)
+		}
+	}
+
+	if savedPlan != nil {
+		if err := savedPlan.Verify(cluster.ObjectMeta.Name, planFingerprint, planOptions); err != nil {
+			return results, fmt.Errorf("cannot apply plan %q: %w", c.Plan, err)
+		}
+	}
+
 	applyCmd := &cloudup.ApplyClusterCmd{
 		Cloud:              cloud,
 		Clientset:          clientset,
 		Cluster:            cluster,
+		InstanceGroups:     instanceGroups,
 		DryRun:             isDryrun,
 		AllowKopsDowngrade: c.AllowKopsDowngrade,
 		RunTasksOptions:    &c.RunTasksOptions,
@@ -303,10 +402,16 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		LifecycleOverrides: lifecycleOverrideMap,
 		GetAssets:          c.GetAssets,
 		DeletionProcessing: deletionProcessing,
+		PlanFormat:         fi.PlanFormat(c.Output),
+
+		ChangePolicyPaths:        c.Policies,
+		AllowedChangePolicyRules: c.AllowedPolicyRules,
+
+		SavedPlan: savedPlan,
 	}
 
 	if err := applyCmd.Run(ctx); err != nil {
-		return results, err
+		return results, withPolicyHint(err)
 	}
 
 	results.Target = applyCmd.Target
@@ -315,7 +420,32 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 	results.FileAssets = applyCmd.FileAssets
 	results.Cluster = cluster
 
+	if c.OutPlan != "" {
+		plan, err := applyCmd.Target.(*fi.CloudupDryRunTarget).Plan(applyCmd.TaskMap)
+		if err != nil {
+			return results, err
+		}
+		savedPlan := &cloudup.SavedPlan{
+			KopsVersion: kopsbase.Version,
+			ClusterName: cluster.ObjectMeta.Name,
+			Fingerprint: planFingerprint,
+			Options:     planOptions,
+			Tasks:       applyCmd.Tasks,
+			Plan:        plan,
+		}
+		if err := cloudup.WriteSavedPlan(c.OutPlan, savedPlan); err != nil {
+			return results, err
+		}
+		if c.Output == "" {
+			fmt.Fprintf(out, "Plan saved to %s; apply it with --plan=%s --yes\n", c.OutPlan, c.OutPlan)
+		}
+	}
+
 	if isDryrun && !c.GetAssets {
+		if c.Output != "" {
+			// Only the plan is printed, so that it can be parsed
+			return results, nil
+		}
 		target := applyCmd.Target.(*fi.CloudupDryRunTarget)
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
@@ -472,6 +602,15 @@ func clusterIsInKubeConfig(contextName string) (bool, error) {
 	return false, nil
 }
 
+// withPolicyHint adds how to allow the forbidden changes to an error returned because of the change policy.
+func withPolicyHint(err error) error {
+	var policyErr *cloudup.ChangePolicyError
+	if errors.As(err, &policyErr) {
+		return fmt.Errorf("%w\nto allow the changes forbidden by a rule, use --allow-policy-rule=<rule>", err)
+	}
+	return err
+}
+
 func completeUpdateClusterTarget(f commandutils.Factory, options *UpdateClusterOptions) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		ctx := cmd.Context()
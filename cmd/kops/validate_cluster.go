@@ -179,7 +179,7 @@ func RunValidateCluster(ctx context.Context, f *util.Factory, out io.Writer, opt
 			return nil, fmt.Errorf("wait time exceeded during validation")
 		}
 
-		result, err := validator.Validate()
+		result, err := validator.Validate(ctx)
 		if err != nil {
 			consecutive = 0
 			if options.wait > 0 {
@@ -17,6 +17,7 @@ limitations under the License.
 package main // import "k8s.io/kops/cmd/nodeup"
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -25,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/pkg/otel/otelsetup"
 	"k8s.io/kops/upup/pkg/fi/nodeup"
 )
 
@@ -36,33 +38,60 @@ const (
 func main() {
 	klog.InitFlags(nil)
 
-	var flagConf, flagCacheDir, gitVersion string
+	var flagConf, flagCacheDir, flagReport, flagRunLog, gitVersion string
 	var flagRetries int
-	var dryrun, installSystemdUnit bool
+	var dryrun, reconfigure, installSystemdUnit bool
 	target := "direct"
 
-	if kops.GitVersion != "" {
-		gitVersion = fmt.Sprintf(" (git-%s)", kops.GitVersion)
-	}
-	fmt.Printf("nodeup version %s%s\n", kops.Version, gitVersion)
 	flag.StringVar(&flagConf, "conf", "node.yaml", "configuration location")
 	flag.StringVar(&flagCacheDir, "cache", "/var/cache/nodeup", "the location for the local asset cache")
 	flag.IntVar(&flagRetries, "retries", -1, "maximum number of retries on failure: -1 means retry forever")
 	flag.BoolVar(&dryrun, "dryrun", false, "Don't create cloud resources; just show what would be done")
+	flag.BoolVar(&dryrun, "dry-run", false, "Don't change the node; just show what would be done")
+	flag.StringVar(&flagReport, "report", "", "With --dry-run, print a report of the drift of the node from its configuration in this format (json), and record it on the Node")
+	flag.BoolVar(&reconfigure, "reconfigure", false, "Run only if a rolling update requested that the node is reconfigured in place, applying the requested configuration")
+	flag.StringVar(&flagRunLog, "run-log", nodeup.DefaultRunLogPath, "Write a structured JSON record of the run, with the duration, retries and errors of each task, to this path; empty to disable")
 	flag.StringVar(&target, "target", target, "Target - direct, dryrun")
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
 
+	flag.Set("logtostderr", "true")
+	flag.Parse()
+
 	if dryrun {
 		target = "dryrun"
 	}
 
-	flag.Set("logtostderr", "true")
-	flag.Parse()
+	// Keep stdout for the report
+	versionOut := os.Stdout
+	if flagReport != "" {
+		versionOut = os.Stderr
+	}
+	if kops.GitVersion != "" {
+		gitVersion = fmt.Sprintf(" (git-%s)", kops.GitVersion)
+	}
+	fmt.Fprintf(versionOut, "nodeup version %s%s\n", kops.Version, gitVersion)
 
 	if flagConf == "" {
 		klog.Exitf("--conf is required")
 	}
 
+	// Set up OpenTelemetry; spans are only exported if an OTEL_EXPORTER_OTLP_* destination is set in the environment.
+	serviceVersion := kops.Version
+	if kops.GitVersion != "" {
+		serviceVersion += ".git-" + kops.GitVersion
+	}
+	otelShutdown, err := otelsetup.SetupOTelSDK(context.Background(), "nodeup", serviceVersion)
+	if err != nil {
+		klog.Exitf("error setting up OpenTelemetry: %v", err)
+	}
+	// exit exports the remaining spans before exiting, as deferred functions are not run.
+	exit := func(code int) {
+		if err := otelShutdown(context.Background()); err != nil {
+			klog.Warningf("error shutting down otel: %v", err)
+		}
+		os.Exit(code)
+	}
+
 	retries := flagRetries
 
 	for {
@@ -113,17 +142,22 @@ func main() {
 				ConfigLocation: flagConf,
 				Target:         target,
 				CacheDir:       flagCacheDir,
+				ReportFormat:   flagReport,
+				Reconfigure:    reconfigure,
+				RunLogPath:     flagRunLog,
 			}
 			err = cmd.Run(os.Stdout)
 			if err == nil {
-				fmt.Printf("success")
-				os.Exit(0)
+				if flagReport == "" {
+					fmt.Printf("success")
+				}
+				exit(0)
 			}
 		}
 
 		if retries == 0 {
-			klog.Exitf("error running nodeup: %v", err)
-			os.Exit(1)
+			klog.Errorf("error running nodeup: %v", err)
+			exit(1)
 		}
 
 		if retries > 0 {
@@ -124,6 +124,7 @@ func (b BootstrapClientBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 	}
 	bootstrapClientTask.UseChallengeCallback = b.UseChallengeCallback(b.CloudProvider())
 	bootstrapClientTask.ClusterName = b.NodeupConfig.ClusterName
+	bootstrapClientTask.KeyAlgorithm = b.NodeupConfig.KeyAlgorithm
 
 	for _, cert := range b.bootstrapCerts {
 		cert.Cert.Task = bootstrapClientTask
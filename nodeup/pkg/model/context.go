@@ -206,6 +206,14 @@ func (c *NodeupModelContext) FileAssetsDefaultPath() string {
 	return filepath.Join(c.PathSrvKubernetes(), "assets")
 }
 
+// FileAssetPath returns the path the file asset is written to.
+func (c *NodeupModelContext) FileAssetPath(asset *kops.FileAssetSpec) string {
+	if asset.Path != "" {
+		return asset.Path
+	}
+	return filepath.Join(c.FileAssetsDefaultPath(), asset.Name)
+}
+
 // PathSrvSshproxy returns the path for the SSH proxy
 func (c *NodeupModelContext) PathSrvSshproxy() string {
 	switch c.Distribution {
@@ -224,11 +232,12 @@ func (c *NodeupModelContext) KubeletKubeConfig() string {
 // BuildIssuedKubeconfig generates a kubeconfig with a locally issued client certificate.
 func (c *NodeupModelContext) BuildIssuedKubeconfig(name string, subject nodetasks.PKIXName, ctx *fi.NodeupModelBuilderContext) *fi.NodeupTaskDependentResource {
 	issueCert := &nodetasks.IssueCert{
-		Name:      name,
-		Signer:    fi.CertificateIDCA,
-		KeypairID: c.NodeupConfig.KeypairIDs[fi.CertificateIDCA],
-		Type:      "client",
-		Subject:   subject,
+		Name:         name,
+		Signer:       fi.CertificateIDCA,
+		KeypairID:    c.NodeupConfig.KeypairIDs[fi.CertificateIDCA],
+		Type:         "client",
+		Subject:      subject,
+		KeyAlgorithm: c.NodeupConfig.KeyAlgorithm,
 	}
 	ctx.AddTask(issueCert)
 	certResource, keyResource, caResource := issueCert.GetResources()
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const driftCheckServiceName = "kops-drift-check"
+
+// DriftCheckBuilder installs a systemd timer that periodically runs nodeup in dry-run mode,
+// to report differences between the node and its configuration.
+type DriftCheckBuilder struct {
+	*NodeupModelContext
+
+	// NodeupCommand is the nodeup binary and the arguments to load the configuration of this node.
+	NodeupCommand []string
+}
+
+var _ fi.NodeupModelBuilder = &DriftCheckBuilder{}
+
+// Build is responsible for configuring the drift check service and timer
+func (b *DriftCheckBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if b.NodeupConfig.DriftCheckInterval == nil {
+		return nil
+	}
+	interval := b.NodeupConfig.DriftCheckInterval.Duration
+
+	{
+		command := append(append([]string{}, b.NodeupCommand...), "--dry-run", "--report=json", "--retries=0")
+
+		manifest := &systemd.Manifest{}
+		manifest.Set("Unit", "Description", "Check the node for drift from its kOps configuration")
+		manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+		manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+		manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+		manifest.Set("Service", "ExecStart", strings.Join(command, " "))
+		manifest.Set("Service", "Type", "oneshot")
+
+		// The service is started by the timer
+		service := &nodetasks.Service{
+			Name:       driftCheckServiceName + ".service",
+			Definition: s(manifest.Render()),
+			Running:    fi.PtrTo(false),
+		}
+		service.InitDefaults()
+		c.AddTask(service)
+	}
+
+	{
+		seconds := func(f float64) string {
+			return fmt.Sprintf("%ds", int64(f))
+		}
+
+		unit := &systemd.Manifest{}
+		unit.Set("Unit", "Description", "Periodic check of the node for drift from its kOps configuration")
+		unit.Set("Timer", "OnBootSec", seconds(interval.Seconds()))
+		unit.Set("Timer", "OnUnitActiveSec", seconds(interval.Seconds()))
+		// Spread the load of the checks on the state store or kops-controller
+		unit.Set("Timer", "RandomizedDelaySec", seconds(interval.Seconds()/4))
+		unit.Set("Install", "WantedBy", "timers.target")
+
+		service := &nodetasks.Service{
+			Name:       driftCheckServiceName + ".timer",
+			Definition: s(unit.Render()),
+		}
+		service.InitDefaults()
+		c.AddTask(service)
+	}
+
+	return nil
+}
@@ -52,10 +52,7 @@ func (f *FileAssetsBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 func (f *FileAssetsBuilder) buildFileAssets(c *fi.NodeupModelBuilderContext, assets []kops.FileAssetSpec, tracker map[string]bool) error {
 	for _, asset := range assets {
 		// @check if e have a path and if not use the default path
-		assetPath := asset.Path
-		if assetPath == "" {
-			assetPath = filepath.Join(f.FileAssetsDefaultPath(), asset.Name)
-		}
+		assetPath := f.FileAssetPath(&asset)
 		// @check if the file has already been done and skip
 		if _, found := tracker[assetPath]; found {
 			continue
@@ -20,7 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
@@ -63,6 +65,18 @@ func (h *HookBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			}
 			hookNames[name] = true
 
+			if hook.Script != nil {
+				if hook.Enabled != nil && !*hook.Enabled {
+					continue
+				}
+				task, err := h.buildHookScript(name, hook.Script)
+				if err != nil {
+					return err
+				}
+				c.AddTask(task)
+				continue
+			}
+
 			// are we disabling the service?
 			if hook.Enabled != nil && !*hook.Enabled {
 				enabled := false
@@ -141,6 +155,60 @@ func (h *HookBuilder) buildSystemdService(name string, hook *kops.HookSpec) (*no
 	return service, nil
 }
 
+// buildHookScript is responsible for generating the task running a script hook
+func (h *HookBuilder) buildHookScript(name string, script *kops.ScriptHookAction) (*nodetasks.HookScript, error) {
+	var fileAsset *kops.FileAssetSpec
+	for i := range h.NodeupConfig.FileAssets {
+		if h.NodeupConfig.FileAssets[i].Name == script.FileAsset {
+			fileAsset = &h.NodeupConfig.FileAssets[i]
+			break
+		}
+	}
+	if fileAsset == nil {
+		return nil, fmt.Errorf("file asset %q of hook %q not found", script.FileAsset, name)
+	}
+
+	task := &nodetasks.HookScript{
+		Name:             name,
+		Path:             h.FileAssetPath(fileAsset),
+		Interpreter:      script.Interpreter,
+		Environment:      script.Environment,
+		Timeout:          metav1.Duration{Duration: 5 * time.Minute},
+		Retries:          3,
+		SuccessExitCodes: []int{0},
+		Creates:          script.Creates,
+	}
+	if task.Interpreter == "" {
+		task.Interpreter = "/bin/bash"
+	}
+	if script.Timeout != nil {
+		task.Timeout = *script.Timeout
+	}
+	if script.Retries != nil {
+		task.Retries = int(*script.Retries)
+	}
+	if len(script.SuccessExitCodes) > 0 {
+		task.SuccessExitCodes = nil
+		for _, code := range script.SuccessExitCodes {
+			task.SuccessExitCodes = append(task.SuccessExitCodes, int(code))
+		}
+	}
+
+	switch script.Phase {
+	case kops.ScriptHookPhasePreContainerd:
+		task.BeforeServices = []string{"containerd.service", kubeletService}
+	case kops.ScriptHookPhasePreKubelet, "":
+		task.BeforeServices = []string{kubeletService}
+	case kops.ScriptHookPhasePostKubeletReady:
+		task.AfterServices = []string{kubeletService}
+		task.WaitForHealthy = "http://127.0.0.1:10248/healthz"
+	default:
+		return nil, fmt.Errorf("unknown phase %q of hook %q", script.Phase, name)
+	}
+
+	return task, nil
+}
+
 // buildContainerdService is responsible for generating a containerd exec unit file
 func (h *HookBuilder) buildContainerdService(unit *systemd.Manifest, hook *kops.HookSpec, name string) error {
 	containerdImage := hook.ExecContainer.Image
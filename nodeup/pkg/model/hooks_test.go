@@ -28,3 +28,10 @@ func TestContainerdHooksBuilder(t *testing.T) {
 		return builder.Build(target)
 	})
 }
+
+func TestScriptHooksBuilder(t *testing.T) {
+	RunGoldenTest(t, "tests/golden/hooks-script", "hooks", func(nodeupModelContext *NodeupModelContext, target *fi.NodeupModelBuilderContext) error {
+		builder := HookBuilder{NodeupModelContext: nodeupModelContext}
+		return builder.Build(target)
+	})
+}
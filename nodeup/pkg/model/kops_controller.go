@@ -60,6 +60,7 @@ func (b *KopsControllerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		Type:           "server",
 		Subject:        nodetasks.PKIXName{CommonName: "kops-controller"},
 		AlternateNames: []string{"kops-controller.internal." + b.NodeupConfig.ClusterName},
+		KeyAlgorithm:   b.NodeupConfig.KeyAlgorithm,
 	}
 	if len(b.BootConfig.APIServerIPs) > 0 {
 		issueCert.AlternateNames = append(issueCert.AlternateNames, b.BootConfig.APIServerIPs...)
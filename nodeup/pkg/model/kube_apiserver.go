@@ -137,6 +137,7 @@ func (b *KubeAPIServerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			Subject: nodetasks.PKIXName{
 				CommonName: "kube-apiserver",
 			},
+			KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 		}
 		c.AddTask(issueCert)
 		if err := issueCert.AddFileTasks(c, pathSrvKAPI, issueCert.Name, "", nil); err != nil {
@@ -161,7 +162,8 @@ func (b *KubeAPIServerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 			KeypairID: b.NodeupConfig.KeypairIDs["apiserver-aggregator-ca"],
 			Type:      "client",
 			// Must match RequestheaderAllowedNames
-			Subject: nodetasks.PKIXName{CommonName: "aggregator"},
+			Subject:      nodetasks.PKIXName{CommonName: "aggregator"},
+			KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 		}
 		c.AddTask(issueCert)
 		err := issueCert.AddFileTasks(c, pathSrvKAPI, "apiserver-aggregator", "", nil)
@@ -356,6 +358,7 @@ func (b *KubeAPIServerBuilder) writeAuthenticationConfig(c *fi.NodeupModelBuilde
 					"localhost",
 					"127.0.0.1",
 				},
+				KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 			}
 			c.AddTask(issueCert)
 			certificate, privateKey, _ := issueCert.GetResources()
@@ -443,6 +446,7 @@ func (b *KubeAPIServerBuilder) writeServerCertificate(c *fi.NodeupModelBuilderCo
 			Type:           "server",
 			Subject:        nodetasks.PKIXName{CommonName: "kubernetes-master"},
 			AlternateNames: alternateNames,
+			KeyAlgorithm:   b.NodeupConfig.KeyAlgorithm,
 		}
 
 		// Including the CA certificate is more correct, and is needed for e.g. AWS WebIdentity federation
@@ -469,11 +473,12 @@ func (b *KubeAPIServerBuilder) writeKubeletAPICertificate(c *fi.NodeupModelBuild
 	pathSrvKAPI := filepath.Join(b.PathSrvKubernetes(), "kube-apiserver")
 
 	issueCert := &nodetasks.IssueCert{
-		Name:      "kubelet-api",
-		Signer:    fi.CertificateIDCA,
-		KeypairID: b.NodeupConfig.KeypairIDs[fi.CertificateIDCA],
-		Type:      "client",
-		Subject:   nodetasks.PKIXName{CommonName: "kubelet-api"},
+		Name:         "kubelet-api",
+		Signer:       fi.CertificateIDCA,
+		KeypairID:    b.NodeupConfig.KeypairIDs[fi.CertificateIDCA],
+		Type:         "client",
+		Subject:      nodetasks.PKIXName{CommonName: "kubelet-api"},
+		KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 	}
 	c.AddTask(issueCert)
 	err := issueCert.AddFileTasks(c, pathSrvKAPI, "kubelet-api", "", nil)
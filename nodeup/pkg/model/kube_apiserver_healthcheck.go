@@ -82,6 +82,7 @@ func (b *KubeAPIServerBuilder) addHealthcheckSidecarTasks(c *fi.NodeupModelBuild
 		Subject: nodetasks.PKIXName{
 			CommonName: id,
 		},
+		KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 	}
 	c.AddTask(issueCert)
 	return issueCert.AddFileTasks(c, secretsDir, "client", "ca", s(userName))
@@ -127,6 +127,7 @@ func (b *KubeControllerManagerBuilder) writeServerCertificate(c *fi.NodeupModelB
 			Type:           "server",
 			Subject:        nodetasks.PKIXName{CommonName: "kube-controller-manager"},
 			AlternateNames: alternateNames,
+			KeyAlgorithm:   b.NodeupConfig.KeyAlgorithm,
 		}
 
 		c.AddTask(issueCert)
@@ -165,6 +165,7 @@ func (b *KubeSchedulerBuilder) writeServerCertificate(c *fi.NodeupModelBuilderCo
 			Type:           "server",
 			Subject:        nodetasks.PKIXName{CommonName: "kube-scheduler"},
 			AlternateNames: alternateNames,
+			KeyAlgorithm:   b.NodeupConfig.KeyAlgorithm,
 		}
 
 		c.AddTask(issueCert)
@@ -764,6 +764,7 @@ func (b *KubeletBuilder) buildKubeletServingCertificate(c *fi.NodeupModelBuilder
 				CommonName: names[0],
 			},
 			AlternateNames: names,
+			KeyAlgorithm:   b.NodeupConfig.KeyAlgorithm,
 		}
 		c.AddTask(issueCert)
 		return issueCert.AddFileTasks(c, dir, name, "", nil)
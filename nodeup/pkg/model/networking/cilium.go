@@ -164,6 +164,7 @@ func (b *CiliumBuilder) buildCiliumEtcdSecrets(c *fi.NodeupModelBuilderContext)
 			Subject: nodetasks.PKIXName{
 				CommonName: "cilium",
 			},
+			KeyAlgorithm: b.NodeupConfig.KeyAlgorithm,
 		}
 		c.AddTask(issueCert)
 		return issueCert.AddFileTasks(c, dir, name, "", nil)
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const reconfigureServiceName = "kops-reconfigure"
+
+// ReconfigureBuilder installs a systemd timer that runs nodeup again when a rolling update
+// requests that the node is reconfigured in place.
+type ReconfigureBuilder struct {
+	*NodeupModelContext
+
+	// NodeupCommand is the nodeup binary and the arguments to load the configuration of this node.
+	NodeupCommand []string
+}
+
+var _ fi.NodeupModelBuilder = &ReconfigureBuilder{}
+
+// Build is responsible for configuring the reconfigure service and timer
+func (b *ReconfigureBuilder) Build(c *fi.NodeupModelBuilderContext) error {
+	if !b.NodeupConfig.InPlaceReconfiguration {
+		return nil
+	}
+
+	{
+		command := append(append([]string{}, b.NodeupCommand...), "--reconfigure", "--retries=0")
+
+		manifest := &systemd.Manifest{}
+		manifest.Set("Unit", "Description", "Reconfigure the node when requested by a kOps rolling update")
+		manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+		manifest.Set("Service", "EnvironmentFile", "/etc/sysconfig/kops-configuration")
+		manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+		manifest.Set("Service", "ExecStart", strings.Join(command, " "))
+		manifest.Set("Service", "Type", "oneshot")
+
+		// The service is started by the timer; it is not running while nodeup runs from kops-configuration,
+		// and is activating while nodeup runs from this service, so nodeup never stops itself.
+		service := &nodetasks.Service{
+			Name:       reconfigureServiceName + ".service",
+			Definition: s(manifest.Render()),
+			Running:    fi.PtrTo(false),
+		}
+		service.InitDefaults()
+		c.AddTask(service)
+	}
+
+	{
+		unit := &systemd.Manifest{}
+		unit.Set("Unit", "Description", "Check whether a kOps rolling update requested that the node is reconfigured")
+		unit.Set("Timer", "OnBootSec", "60s")
+		unit.Set("Timer", "OnUnitInactiveSec", "30s")
+		unit.Set("Timer", "AccuracySec", "5s")
+		unit.Set("Install", "WantedBy", "timers.target")
+
+		service := &nodetasks.Service{
+			Name:       reconfigureServiceName + ".timer",
+			Definition: s(unit.Render()),
+		}
+		service.InitDefaults()
+		c.AddTask(service)
+	}
+
+	return nil
+}
@@ -55,6 +55,8 @@ type ClusterSpec struct {
 	Channel string `json:"channel,omitempty"`
 	// Additional addons that should be installed on the cluster
 	Addons []AddonSpec `json:"addons,omitempty"`
+	// BootstrapAddons holds or pins the versions of addons in the bootstrap channel managed by kOps.
+	BootstrapAddons []BootstrapAddonSpec `json:"bootstrapAddons,omitempty"`
 	// ConfigStore configures the stores that nodes use to get their configuration.
 	ConfigStore ConfigStoreSpec `json:"configStore"`
 	// CloudProvider configures the cloud provider to use.
@@ -87,6 +89,8 @@ type ClusterSpec struct {
 	//   'automatic' (default): apply updates automatically (apply OS security upgrades, avoiding rebooting when possible)
 	//   'external': do not apply updates automatically; they are applied manually or by an external system
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
+	// NodeDriftCheck periodically compares each node with the configuration nodeup would apply, and reports differences on the Node.
+	NodeDriftCheck *NodeDriftCheckSpec `json:"nodeDriftCheck,omitempty"`
 	// ExternalPolicies allows the insertion of pre-existing managed policies on IG Roles
 	ExternalPolicies map[string][]string `json:"externalPolicies,omitempty"`
 	// Additional policies to add for roles
@@ -133,6 +137,8 @@ type ClusterSpec struct {
 	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
 	// NodeAuthorization defined the custom node authorization configuration
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
+	// NodeIdentity configures additional labels and taints which kops-controller sets on nodes.
+	NodeIdentity *NodeIdentitySpec `json:"nodeIdentity,omitempty"`
 	// CloudLabels defines additional tags or labels on cloud provider resources
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
@@ -143,6 +149,10 @@ type ClusterSpec struct {
 	IAM *IAMSpec `json:"iam,omitempty"`
 	// EncryptionConfig controls if encryption is enabled
 	EncryptionConfig *bool `json:"encryptionConfig,omitempty"`
+	// PKI configures the keypairs issued by kOps.
+	PKI *PKISpec `json:"pki,omitempty"`
+	// StateStoreEncryption configures envelope encryption of private keys and secrets in the state store.
+	StateStoreEncryption *StateStoreEncryptionSpec `json:"stateStoreEncryption,omitempty"`
 	// Target allows for us to nest extra config for targets such as terraform
 	Target *TargetSpec `json:"target,omitempty"`
 	// UseHostCertificates will mount /etc/ssl/certs to inside needed containers.
@@ -154,6 +164,8 @@ type ClusterSpec struct {
 	SysctlParameters []string `json:"sysctlParameters,omitempty"`
 	// RollingUpdate defines the default rolling-update settings for instance groups.
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+	// ClusterValidation configures additional checks performed when validating the cluster.
+	ClusterValidation *ClusterValidationSpec `json:"clusterValidation,omitempty"`
 	// ClusterAutoscaler defines the cluster autoscaler configuration.
 	ClusterAutoscaler *ClusterAutoscalerConfig `json:"clusterAutoscaler,omitempty"`
 	// ServiceAccountIssuerDiscovery configures the OIDC Issuer for ServiceAccounts.
@@ -322,10 +334,121 @@ type NodeAuthorizerSpec struct {
 	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
 }
 
+// NodeIdentitySpec configures labels and taints which kops-controller sets on nodes,
+// in addition to the labels of their instance group.
+type NodeIdentitySpec struct {
+	// Labels set node labels from the cloud tags or the attributes of the instance of the node.
+	Labels []NodeIdentityLabelSpec `json:"labels,omitempty"`
+	// Taints set node taints from the cloud tags of the instance of the node.
+	Taints []NodeIdentityTaintSpec `json:"taints,omitempty"`
+	// ConfigMap looks up labels and taints for nodes in a ConfigMap in the kube-system namespace.
+	ConfigMap *NodeIdentityConfigMapSpec `json:"configMap,omitempty"`
+	// HTTP looks up labels and taints for nodes by calling an HTTP endpoint.
+	HTTP *NodeIdentityHTTPSpec `json:"http,omitempty"`
+}
+
+// NodeIdentityAttribute is an attribute of the instance of a node.
+type NodeIdentityAttribute string
+
+const (
+	// NodeIdentityAttributeInstanceType is the instance type (or machine type) of the instance.
+	NodeIdentityAttributeInstanceType NodeIdentityAttribute = "instanceType"
+	// NodeIdentityAttributeArchitecture is the CPU architecture of the instance.
+	NodeIdentityAttributeArchitecture NodeIdentityAttribute = "architecture"
+	// NodeIdentityAttributeVCPUs is the number of virtual CPUs of the instance type.
+	NodeIdentityAttributeVCPUs NodeIdentityAttribute = "vcpus"
+	// NodeIdentityAttributeMemoryMiB is the memory of the instance type, in MiB.
+	NodeIdentityAttributeMemoryMiB NodeIdentityAttribute = "memoryMiB"
+	// NodeIdentityAttributeGPUs is the number of GPUs of the instance type.
+	NodeIdentityAttributeGPUs NodeIdentityAttribute = "gpus"
+)
+
+// NodeIdentityLabelSpec sets a node label from a cloud tag or an attribute of the instance of the node.
+type NodeIdentityLabelSpec struct {
+	// Label is the key of the node label.
+	Label string `json:"label,omitempty"`
+	// Tag is the cloud tag whose value is used as the value of the label.
+	Tag string `json:"tag,omitempty"`
+	// Attribute is the attribute of the instance whose value is used as the value of the label.
+	// Supported values are instanceType, architecture, vcpus, memoryMiB and gpus.
+	Attribute NodeIdentityAttribute `json:"attribute,omitempty"`
+}
+
+// NodeIdentityTaintSpec sets a node taint when the instance of the node has a cloud tag.
+type NodeIdentityTaintSpec struct {
+	// Tag is the cloud tag which the instance must have.
+	Tag string `json:"tag,omitempty"`
+	// Key is the key of the taint.
+	Key string `json:"key,omitempty"`
+	// Value is the value of the taint. Defaults to the value of the tag.
+	Value *string `json:"value,omitempty"`
+	// Effect is the effect of the taint: NoSchedule, PreferNoSchedule or NoExecute.
+	Effect string `json:"effect,omitempty"`
+}
+
+// NodeIdentityConfigMapSpec looks up labels and taints for nodes in a ConfigMap.
+type NodeIdentityConfigMapSpec struct {
+	// Name is the name of the ConfigMap in the kube-system namespace.
+	// Each key of the ConfigMap is the name of a node, with a YAML value holding the labels and taints of that node.
+	Name string `json:"name,omitempty"`
+}
+
+// NodeIdentityHTTPSpec looks up labels and taints for nodes by calling an HTTP endpoint.
+type NodeIdentityHTTPSpec struct {
+	// URL is the endpoint which is sent a POST request for each node, and responds with the labels and taints of that node.
+	URL string `json:"url,omitempty"`
+	// Timeout is the maximum duration of each request. Defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // AddonSpec defines an addon that we want to install in the cluster
 type AddonSpec struct {
 	// Manifest is a path to the manifest that defines the addon
 	Manifest string `json:"manifest,omitempty"`
+	// Chart is a Helm chart that is rendered and applied as an addon, instead of a manifest
+	Chart *HelmChartAddonSpec `json:"chart,omitempty"`
+}
+
+// NodeDriftCheckSpec configures the periodic check of nodes for drift from their configuration
+type NodeDriftCheckSpec struct {
+	// Enabled runs the check on every node. Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval is how often the check runs. Default: 1h
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// BootstrapAddonSpec holds or pins the version of an addon in the bootstrap channel managed by kOps
+type BootstrapAddonSpec struct {
+	// Name is the name of the addon in the bootstrap channel, e.g. coredns.addons.k8s.io
+	Name string `json:"name,omitempty"`
+	// Hold keeps the addon at the version currently in the bootstrap channel, instead of the version kOps renders
+	Hold bool `json:"hold,omitempty"`
+	// Manifest is the location of a manifest that is applied instead of the one kOps renders
+	Manifest string `json:"manifest,omitempty"`
+	// KubernetesVersion is a semver range of the Kubernetes versions supported by the held or pinned addon, e.g. ">=1.27.0 <1.30.0".
+	// kOps warns when the cluster's Kubernetes version is outside the range.
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+}
+
+// HelmChartAddonSpec defines an addon that is rendered from a Helm chart
+type HelmChartAddonSpec struct {
+	// Name is the name of the addon, which is also used as the release name
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace the chart is installed into. Defaults to kube-system.
+	Namespace string `json:"namespace,omitempty"`
+	// Repository is the URL of the chart repository, or an OCI registry reference prefixed with oci://
+	Repository string `json:"repository,omitempty"`
+	// Chart is the name of the chart in the repository
+	Chart string `json:"chart,omitempty"`
+	// Version is the exact version of the chart
+	Version string `json:"version,omitempty"`
+	// Values is a YAML document with the values used to render the chart
+	Values string `json:"values,omitempty"`
+	// NeedsRollingUpdate marks nodes as needing an update when the rendered chart changes.
+	// Legal values are control-plane, workers and all.
+	NeedsRollingUpdate string `json:"needsRollingUpdate,omitempty"`
+	// NeedsPKI provisions a CA and a cert-manager issuer for the addon
+	NeedsPKI bool `json:"needsPKI,omitempty"`
 }
 
 // FileAssetSpec defines the structure for a file asset
@@ -352,6 +475,18 @@ type AssetsSpec struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a container registry.
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// ImageVerification configures the verification of the signatures of container images.
+	ImageVerification *ImageVerificationSpec `json:"imageVerification,omitempty"`
+}
+
+// ImageVerificationSpec configures the verification of the cosign signatures of container images.
+type ImageVerificationSpec struct {
+	// PublicKeys are the PEM-encoded public keys trusted to sign images.
+	// Every image must have a cosign signature by one of the keys.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+	// RequireSBOM requires every image to also have a cosign attestation of an SPDX or CycloneDX SBOM,
+	// signed by one of the keys.
+	RequireSBOM bool `json:"requireSBOM,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -386,6 +521,42 @@ type HookSpec struct {
 	// of the systemd unit, unmodified. Before and Requires are ignored when used together
 	// with this value (and validation shouldn't allow them to be set)
 	UseRawManifest bool `json:"useRawManifest,omitempty"`
+	// Script is a script, delivered as a file asset, which nodeup runs at a phase of the node configuration.
+	// The services of the later phases are not started until the script succeeds.
+	Script *ScriptHookAction `json:"script,omitempty"`
+}
+
+// ScriptHookPhase is the phase of the node configuration at which nodeup runs a script hook.
+type ScriptHookPhase string
+
+const (
+	// ScriptHookPhasePreContainerd runs the script before containerd and kubelet are started.
+	ScriptHookPhasePreContainerd ScriptHookPhase = "PreContainerd"
+	// ScriptHookPhasePreKubelet runs the script before kubelet is started.
+	ScriptHookPhasePreKubelet ScriptHookPhase = "PreKubelet"
+	// ScriptHookPhasePostKubeletReady runs the script once kubelet is started and healthy.
+	ScriptHookPhasePostKubeletReady ScriptHookPhase = "PostKubeletReady"
+)
+
+// ScriptHookAction defines a script run by nodeup
+type ScriptHookAction struct {
+	// FileAsset is the name of the file asset containing the script.
+	FileAsset string `json:"fileAsset,omitempty"`
+	// Interpreter is the program which runs the script. Default: /bin/bash
+	Interpreter string `json:"interpreter,omitempty"`
+	// Phase is when nodeup runs the script: PreContainerd, PreKubelet or PostKubeletReady. Default: PreKubelet
+	Phase ScriptHookPhase `json:"phase,omitempty"`
+	// Timeout is the maximum duration of each run of the script. Default: 5m
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Retries is the number of times the script is run again after failing, before nodeup fails. Default: 3
+	Retries *int32 `json:"retries,omitempty"`
+	// SuccessExitCodes are the exit codes of the script which indicate success. Default: [0]
+	SuccessExitCodes []int32 `json:"successExitCodes,omitempty"`
+	// Creates is a path which the script creates when it succeeds. If set, the script fails unless it creates the path,
+	// and is not run again once the path exists.
+	Creates string `json:"creates,omitempty"`
+	// Environment is a map of environment variables set for the script.
+	Environment map[string]string `json:"environment,omitempty"`
 }
 
 // ExecContainerAction defines an hood action
@@ -925,6 +1096,14 @@ func (c *Cluster) UsesNoneDNS() bool {
 	return false
 }
 
+// KeyAlgorithm returns the algorithm of the private keys kOps generates, or "" for the default of RSA.
+func (c *Cluster) KeyAlgorithm() string {
+	if c.Spec.PKI == nil {
+		return ""
+	}
+	return c.Spec.PKI.KeyAlgorithm
+}
+
 func (c *Cluster) APIInternalName() string {
 	return "api.internal." + c.ObjectMeta.Name
 }
@@ -1030,6 +1209,218 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Hooks are checks or actions run for every instance replaced during the rolling update.
+	// +optional
+	Hooks []RollingUpdateHook `json:"hooks,omitempty"`
+	// Strategy is how the instances of the instance group are replaced: Rolling (the default), Canary,
+	// which first replaces a few canary instances and waits for a soak period before replacing the rest,
+	// or InPlace, which reconfigures the instances by running nodeup again instead of replacing them.
+	// +optional
+	Strategy RollingUpdateStrategy `json:"strategy,omitempty"`
+	// Canary configures the Canary strategy.
+	// +optional
+	Canary *RollingUpdateCanary `json:"canary,omitempty"`
+}
+
+// RollingUpdateStrategy is how the instances of an instance group are replaced.
+type RollingUpdateStrategy string
+
+const (
+	// RollingUpdateStrategyRolling replaces the instances as limited by maxSurge and maxUnavailable.
+	RollingUpdateStrategyRolling RollingUpdateStrategy = "Rolling"
+	// RollingUpdateStrategyCanary first replaces the canary instances and validates the cluster for the soak duration,
+	// before replacing the remaining instances as for the Rolling strategy.
+	RollingUpdateStrategyCanary RollingUpdateStrategy = "Canary"
+	// RollingUpdateStrategyInPlace drains each instance and runs nodeup on it again to apply the new configuration,
+	// then uncordons it. Instances whose image or machine type changed are replaced as for the Rolling strategy.
+	RollingUpdateStrategyInPlace RollingUpdateStrategy = "InPlace"
+)
+
+// RollingUpdateCanary configures the Canary rolling update strategy.
+type RollingUpdateCanary struct {
+	// Count is the number of canary instances.
+	// The value can be an absolute number (for example 5) or a percentage of the instances
+	// in the group (for example "10%"). The absolute number is calculated from a percentage by
+	// rounding up. Defaults to 1.
+	// +optional
+	Count *intstr.IntOrString `json:"count,omitempty"`
+	// SoakDuration is the time for which the cluster must continue to validate after the canary instances are replaced.
+	// Defaults to 10 minutes.
+	// +optional
+	SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+}
+
+// RollingUpdateHookPhase is the point in the replacement of an instance at which a hook is run.
+type RollingUpdateHookPhase string
+
+const (
+	// RollingUpdateHookPhaseBeforeDrain runs the hook before the node is drained.
+	RollingUpdateHookPhaseBeforeDrain RollingUpdateHookPhase = "BeforeDrain"
+	// RollingUpdateHookPhaseAfterValidate runs the hook once the cluster validates after the instance was replaced.
+	RollingUpdateHookPhaseAfterValidate RollingUpdateHookPhase = "AfterValidate"
+)
+
+// RollingUpdateHookFailurePolicy is what a rolling update does when a hook does not succeed within its timeout.
+type RollingUpdateHookFailurePolicy string
+
+const (
+	// RollingUpdateHookFailurePolicyFail stops the rolling update.
+	RollingUpdateHookFailurePolicyFail RollingUpdateHookFailurePolicy = "Fail"
+	// RollingUpdateHookFailurePolicyIgnore logs the failure and continues the rolling update.
+	RollingUpdateHookFailurePolicyIgnore RollingUpdateHookFailurePolicy = "Ignore"
+)
+
+// RollingUpdateHook is a check or action run for every instance replaced during a rolling update.
+// Exactly one of Exec, HTTP or Job must be set.
+// The hook is retried until it succeeds or the timeout expires.
+type RollingUpdateHook struct {
+	// Name identifies the hook.
+	Name string `json:"name"`
+	// Phase is when the hook is run: BeforeDrain or AfterValidate.
+	Phase RollingUpdateHookPhase `json:"phase"`
+	// Exec runs a command on the machine running the rolling update, if it is run with --allow-exec-hooks.
+	Exec *RollingUpdateExecHook `json:"exec,omitempty"`
+	// HTTP sends a GET request, succeeding on a 2xx response.
+	HTTP *RollingUpdateHTTPHook `json:"http,omitempty"`
+	// Job runs a Kubernetes Job in the cluster, succeeding when the Job completes.
+	Job *RollingUpdateJobHook `json:"job,omitempty"`
+	// Timeout is the maximum time to wait for the hook to succeed.
+	// Defaults to 5 minutes.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy is Fail (the default), which stops the rolling update when the hook does not succeed,
+	// or Ignore, which continues the rolling update.
+	FailurePolicy RollingUpdateHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// RollingUpdateExecHook runs a command on the machine running the rolling update.
+// The command is run with the KOPS_CLUSTER_NAME, KOPS_INSTANCE_GROUP, KOPS_INSTANCE_ID,
+// KOPS_NODE_NAME and KOPS_HOOK_PHASE environment variables set.
+type RollingUpdateExecHook struct {
+	// Command is the command and its arguments. It succeeds if it exits with status 0.
+	Command []string `json:"command"`
+}
+
+// RollingUpdateHTTPHook sends a GET request.
+type RollingUpdateHTTPHook struct {
+	// URL is the URL to request. References to the environment variables set for exec hooks,
+	// such as ${KOPS_NODE_NAME}, are expanded.
+	URL string `json:"url"`
+}
+
+// RollingUpdateJobHook runs a Kubernetes Job in the cluster.
+// The environment variables set for exec hooks are added to every container of the Job.
+type RollingUpdateJobHook struct {
+	// Manifest is the YAML manifest of the batch/v1 Job.
+	// If the Job has no name, a name is generated. If it has no namespace, it is created in kube-system.
+	Manifest string `json:"manifest"`
+}
+
+// PKISpec configures the keypairs issued by kOps.
+type PKISpec struct {
+	// KeyAlgorithm is the algorithm of the private keys kOps generates: RSA (the default), ECDSA-P256 or Ed25519.
+	// Existing CA keypairs keep their algorithm until they are rotated.
+	// The service-account keypair always uses RSA.
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+}
+
+// StateStoreEncryptionSpec configures envelope encryption of private keys and secrets in the state store.
+// Each private key and secret is encrypted with its own data key, which is wrapped by the configured key encryption key.
+// Exactly one key encryption key must be configured.
+type StateStoreEncryptionSpec struct {
+	// AWSKMSKeyARN is the ARN of an AWS KMS key.
+	AWSKMSKeyARN string `json:"awsKMSKeyARN,omitempty"`
+	// GCPKMSKeyName is the resource name of a GCP Cloud KMS key,
+	// of the form projects/<project>/locations/<location>/keyRings/<keyRing>/cryptoKeys/<key>.
+	GCPKMSKeyName string `json:"gcpKMSKeyName,omitempty"`
+	// Vault configures a key of a HashiCorp Vault transit secrets engine.
+	Vault *VaultTransitKeySpec `json:"vault,omitempty"`
+	// KeyFile is the path of a local file containing a base64-encoded 256-bit key.
+	// It is intended for testing.
+	KeyFile string `json:"keyFile,omitempty"`
+	// PreviousKeys are key encryption keys which were used before the configured key.
+	// They are only used to decrypt private keys and secrets which were encrypted with them.
+	PreviousKeys []StateStoreEncryptionKeySpec `json:"previousKeys,omitempty"`
+}
+
+// StateStoreEncryptionKeySpec is a key encryption key which is used to decrypt private keys and secrets.
+// Exactly one key must be configured.
+type StateStoreEncryptionKeySpec struct {
+	// AWSKMSKeyARN is the ARN of an AWS KMS key.
+	AWSKMSKeyARN string `json:"awsKMSKeyARN,omitempty"`
+	// GCPKMSKeyName is the resource name of a GCP Cloud KMS key.
+	GCPKMSKeyName string `json:"gcpKMSKeyName,omitempty"`
+	// Vault configures a key of a HashiCorp Vault transit secrets engine.
+	Vault *VaultTransitKeySpec `json:"vault,omitempty"`
+	// KeyFile is the path of a local file containing a base64-encoded 256-bit key.
+	KeyFile string `json:"keyFile,omitempty"`
+}
+
+// VaultTransitKeySpec configures a key of a HashiCorp Vault transit secrets engine.
+// The Vault token is read from the VAULT_TOKEN environment variable.
+type VaultTransitKeySpec struct {
+	// Address is the address of the Vault server, e.g. https://vault.example.com:8200.
+	Address string `json:"address,omitempty"`
+	// MountPath is the path the transit secrets engine is mounted at. Defaults to "transit".
+	MountPath string `json:"mountPath,omitempty"`
+	// KeyName is the name of the transit key.
+	KeyName string `json:"keyName,omitempty"`
+}
+
+// ClusterValidationSpec configures additional checks performed when validating the cluster,
+// both by `kops validate cluster` and during rolling updates.
+type ClusterValidationSpec struct {
+	// Checks are the additional checks.
+	Checks []ValidationCheck `json:"checks,omitempty"`
+}
+
+// ValidationCheck is an additional check performed when validating the cluster.
+// Exactly one of Deployments, Prometheus or NodeConditions must be set.
+type ValidationCheck struct {
+	// Name identifies the check, and is reported as the kind of its validation failures.
+	Name string `json:"name"`
+	// InstanceGroups are the names of the instance groups whose rolling updates are blocked by failures of this check.
+	// If empty, failures block the rolling updates of all instance groups.
+	InstanceGroups []string `json:"instanceGroups,omitempty"`
+	// Deployments checks that deployments have all their replicas available.
+	Deployments *DeploymentsValidationCheck `json:"deployments,omitempty"`
+	// Prometheus checks that a Prometheus query returns no results, such as a query for firing alerts.
+	Prometheus *PrometheusValidationCheck `json:"prometheus,omitempty"`
+	// NodeConditions checks the conditions of the nodes.
+	NodeConditions *NodeConditionsValidationCheck `json:"nodeConditions,omitempty"`
+}
+
+// DeploymentsValidationCheck checks that deployments have all their replicas available.
+type DeploymentsValidationCheck struct {
+	// Namespaces are the namespaces of the deployments to check.
+	Namespaces []string `json:"namespaces"`
+	// Selector is a label selector restricting the deployments to check.
+	Selector string `json:"selector,omitempty"`
+}
+
+// PrometheusValidationCheck checks that a Prometheus query returns no results.
+type PrometheusValidationCheck struct {
+	// URL is the base URL of the Prometheus server, such as http://prometheus.monitoring.svc:9090.
+	URL string `json:"url"`
+	// Query is the PromQL instant query, such as ALERTS{alertstate="firing",severity="critical"}.
+	// Each result is reported as a validation failure.
+	Query string `json:"query"`
+}
+
+// NodeConditionsValidationCheck checks the conditions of the nodes.
+type NodeConditionsValidationCheck struct {
+	// Conditions are the required node conditions.
+	Conditions []NodeConditionRequirement `json:"conditions"`
+}
+
+// NodeConditionRequirement is a node condition that must have the given status.
+type NodeConditionRequirement struct {
+	// Type is the type of the node condition, such as NetworkUnavailable.
+	Type string `json:"type"`
+	// Status is the required status of the condition: True, False or Unknown.
+	// Nodes not reporting the condition fail the check, unless AllowMissing is set.
+	Status string `json:"status"`
+	// AllowMissing makes nodes not reporting the condition pass the check.
+	AllowMissing bool `json:"allowMissing,omitempty"`
 }
 
 type PackagesConfig struct {
@@ -31,6 +31,8 @@ const (
 	PathClusterCompleted = "cluster-completed.spec"
 	// PathKopsVersionUpdated is the path for the version of kops last used to apply the cluster.
 	PathKopsVersionUpdated = "kops-version.txt"
+	// PathChangePolicy is the path for the policy checked against the changes of cluster updates in the state store.
+	PathChangePolicy = "change-policy.yaml"
 )
 
 func ConfigBase(vfsContext *vfs.VFSContext, c *api.Cluster) (vfs.Path, error) {
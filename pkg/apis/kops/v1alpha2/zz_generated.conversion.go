@@ -184,6 +184,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*BootstrapAddonSpec)(nil), (*kops.BootstrapAddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec(a.(*BootstrapAddonSpec), b.(*kops.BootstrapAddonSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.BootstrapAddonSpec)(nil), (*BootstrapAddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec(a.(*kops.BootstrapAddonSpec), b.(*BootstrapAddonSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CNINetworkingSpec)(nil), (*kops.CNINetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CNINetworkingSpec_To_kops_CNINetworkingSpec(a.(*CNINetworkingSpec), b.(*kops.CNINetworkingSpec), scope)
 	}); err != nil {
@@ -294,6 +304,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterValidationSpec)(nil), (*kops.ClusterValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(a.(*ClusterValidationSpec), b.(*kops.ClusterValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ClusterValidationSpec)(nil), (*ClusterValidationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(a.(*kops.ClusterValidationSpec), b.(*ClusterValidationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ContainerdConfig)(nil), (*kops.ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(a.(*ContainerdConfig), b.(*kops.ContainerdConfig), scope)
 	}); err != nil {
@@ -344,6 +364,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DeploymentsValidationCheck)(nil), (*kops.DeploymentsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(a.(*DeploymentsValidationCheck), b.(*kops.DeploymentsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.DeploymentsValidationCheck)(nil), (*DeploymentsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(a.(*kops.DeploymentsValidationCheck), b.(*DeploymentsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DockerConfig)(nil), (*kops.DockerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DockerConfig_To_kops_DockerConfig(a.(*DockerConfig), b.(*kops.DockerConfig), scope)
 	}); err != nil {
@@ -504,6 +534,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HelmChartAddonSpec)(nil), (*kops.HelmChartAddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec(a.(*HelmChartAddonSpec), b.(*kops.HelmChartAddonSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HelmChartAddonSpec)(nil), (*HelmChartAddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec(a.(*kops.HelmChartAddonSpec), b.(*HelmChartAddonSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HubbleSpec)(nil), (*kops.HubbleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HubbleSpec_To_kops_HubbleSpec(a.(*HubbleSpec), b.(*kops.HubbleSpec), scope)
 	}); err != nil {
@@ -534,6 +574,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageVerificationSpec)(nil), (*kops.ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(a.(*ImageVerificationSpec), b.(*kops.ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ImageVerificationSpec)(nil), (*ImageVerificationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(a.(*kops.ImageVerificationSpec), b.(*ImageVerificationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*InstanceGroup)(nil), (*kops.InstanceGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(a.(*InstanceGroup), b.(*kops.InstanceGroup), scope)
 	}); err != nil {
@@ -864,6 +914,86 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeConditionRequirement)(nil), (*kops.NodeConditionRequirement)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement(a.(*NodeConditionRequirement), b.(*kops.NodeConditionRequirement), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeConditionRequirement)(nil), (*NodeConditionRequirement)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement(a.(*kops.NodeConditionRequirement), b.(*NodeConditionRequirement), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeConditionsValidationCheck)(nil), (*kops.NodeConditionsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(a.(*NodeConditionsValidationCheck), b.(*kops.NodeConditionsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeConditionsValidationCheck)(nil), (*NodeConditionsValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(a.(*kops.NodeConditionsValidationCheck), b.(*NodeConditionsValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeDriftCheckSpec)(nil), (*kops.NodeDriftCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec(a.(*NodeDriftCheckSpec), b.(*kops.NodeDriftCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeDriftCheckSpec)(nil), (*NodeDriftCheckSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec(a.(*kops.NodeDriftCheckSpec), b.(*NodeDriftCheckSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentityConfigMapSpec)(nil), (*kops.NodeIdentityConfigMapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec(a.(*NodeIdentityConfigMapSpec), b.(*kops.NodeIdentityConfigMapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentityConfigMapSpec)(nil), (*NodeIdentityConfigMapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec(a.(*kops.NodeIdentityConfigMapSpec), b.(*NodeIdentityConfigMapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentityHTTPSpec)(nil), (*kops.NodeIdentityHTTPSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec(a.(*NodeIdentityHTTPSpec), b.(*kops.NodeIdentityHTTPSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentityHTTPSpec)(nil), (*NodeIdentityHTTPSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec(a.(*kops.NodeIdentityHTTPSpec), b.(*NodeIdentityHTTPSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentityLabelSpec)(nil), (*kops.NodeIdentityLabelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec(a.(*NodeIdentityLabelSpec), b.(*kops.NodeIdentityLabelSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentityLabelSpec)(nil), (*NodeIdentityLabelSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec(a.(*kops.NodeIdentityLabelSpec), b.(*NodeIdentityLabelSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentitySpec)(nil), (*kops.NodeIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec(a.(*NodeIdentitySpec), b.(*kops.NodeIdentitySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentitySpec)(nil), (*NodeIdentitySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec(a.(*kops.NodeIdentitySpec), b.(*NodeIdentitySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeIdentityTaintSpec)(nil), (*kops.NodeIdentityTaintSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec(a.(*NodeIdentityTaintSpec), b.(*kops.NodeIdentityTaintSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeIdentityTaintSpec)(nil), (*NodeIdentityTaintSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec(a.(*kops.NodeIdentityTaintSpec), b.(*NodeIdentityTaintSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -984,6 +1114,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PKISpec)(nil), (*kops.PKISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PKISpec_To_kops_PKISpec(a.(*PKISpec), b.(*kops.PKISpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PKISpec)(nil), (*PKISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PKISpec_To_v1alpha2_PKISpec(a.(*kops.PKISpec), b.(*PKISpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PackagesConfig)(nil), (*kops.PackagesConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(a.(*PackagesConfig), b.(*kops.PackagesConfig), scope)
 	}); err != nil {
@@ -1004,6 +1144,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PrometheusValidationCheck)(nil), (*kops.PrometheusValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck(a.(*PrometheusValidationCheck), b.(*kops.PrometheusValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PrometheusValidationCheck)(nil), (*PrometheusValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck(a.(*kops.PrometheusValidationCheck), b.(*PrometheusValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
 	}); err != nil {
@@ -1024,6 +1174,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateCanary)(nil), (*kops.RollingUpdateCanary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(a.(*RollingUpdateCanary), b.(*kops.RollingUpdateCanary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateCanary)(nil), (*RollingUpdateCanary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(a.(*kops.RollingUpdateCanary), b.(*RollingUpdateCanary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateExecHook)(nil), (*kops.RollingUpdateExecHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook(a.(*RollingUpdateExecHook), b.(*kops.RollingUpdateExecHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateExecHook)(nil), (*RollingUpdateExecHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook(a.(*kops.RollingUpdateExecHook), b.(*RollingUpdateExecHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateHTTPHook)(nil), (*kops.RollingUpdateHTTPHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook(a.(*RollingUpdateHTTPHook), b.(*kops.RollingUpdateHTTPHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateHTTPHook)(nil), (*RollingUpdateHTTPHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook(a.(*kops.RollingUpdateHTTPHook), b.(*RollingUpdateHTTPHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateHook)(nil), (*kops.RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(a.(*RollingUpdateHook), b.(*kops.RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateHook)(nil), (*RollingUpdateHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(a.(*kops.RollingUpdateHook), b.(*RollingUpdateHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdateJobHook)(nil), (*kops.RollingUpdateJobHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook(a.(*RollingUpdateJobHook), b.(*kops.RollingUpdateJobHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdateJobHook)(nil), (*RollingUpdateJobHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook(a.(*kops.RollingUpdateJobHook), b.(*RollingUpdateJobHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
@@ -1084,6 +1284,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ScriptHookAction)(nil), (*kops.ScriptHookAction)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction(a.(*ScriptHookAction), b.(*kops.ScriptHookAction), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ScriptHookAction)(nil), (*ScriptHookAction)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction(a.(*kops.ScriptHookAction), b.(*ScriptHookAction), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ServiceAccountExternalPermission)(nil), (*kops.ServiceAccountExternalPermission)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(a.(*ServiceAccountExternalPermission), b.(*kops.ServiceAccountExternalPermission), scope)
 	}); err != nil {
@@ -1114,6 +1324,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*StateStoreEncryptionKeySpec)(nil), (*kops.StateStoreEncryptionKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec(a.(*StateStoreEncryptionKeySpec), b.(*kops.StateStoreEncryptionKeySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.StateStoreEncryptionKeySpec)(nil), (*StateStoreEncryptionKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec(a.(*kops.StateStoreEncryptionKeySpec), b.(*StateStoreEncryptionKeySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StateStoreEncryptionSpec)(nil), (*kops.StateStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec(a.(*StateStoreEncryptionSpec), b.(*kops.StateStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.StateStoreEncryptionSpec)(nil), (*StateStoreEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec(a.(*kops.StateStoreEncryptionSpec), b.(*StateStoreEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1144,6 +1374,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ValidationCheck)(nil), (*kops.ValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ValidationCheck_To_kops_ValidationCheck(a.(*ValidationCheck), b.(*kops.ValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ValidationCheck)(nil), (*ValidationCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ValidationCheck_To_v1alpha2_ValidationCheck(a.(*kops.ValidationCheck), b.(*ValidationCheck), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VaultTransitKeySpec)(nil), (*kops.VaultTransitKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(a.(*VaultTransitKeySpec), b.(*kops.VaultTransitKeySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VaultTransitKeySpec)(nil), (*VaultTransitKeySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(a.(*kops.VaultTransitKeySpec), b.(*VaultTransitKeySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1465,6 +1715,15 @@ func Convert_kops_AccessLogSpec_To_v1alpha2_AccessLogSpec(in *kops.AccessLogSpec
 
 func autoConvert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(kops.HelmChartAddonSpec)
+		if err := Convert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -1475,6 +1734,15 @@ func Convert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.Addon
 
 func autoConvert_kops_AddonSpec_To_v1alpha2_AddonSpec(in *kops.AddonSpec, out *AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(HelmChartAddonSpec)
+		if err := Convert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -1549,6 +1817,15 @@ func autoConvert_v1alpha2_AssetsSpec_To_kops_AssetsSpec(in *AssetsSpec, out *kop
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(kops.ImageVerificationSpec)
+		if err := Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -1561,6 +1838,15 @@ func autoConvert_kops_AssetsSpec_To_v1alpha2_AssetsSpec(in *kops.AssetsSpec, out
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerificationSpec)
+		if err := Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ImageVerification = nil
+	}
 	return nil
 }
 
@@ -1767,6 +2053,32 @@ func Convert_kops_BastionSpec_To_v1alpha2_BastionSpec(in *kops.BastionSpec, out
 	return autoConvert_kops_BastionSpec_To_v1alpha2_BastionSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec(in *BootstrapAddonSpec, out *kops.BootstrapAddonSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Hold = in.Hold
+	out.Manifest = in.Manifest
+	out.KubernetesVersion = in.KubernetesVersion
+	return nil
+}
+
+// Convert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec is an autogenerated conversion function.
+func Convert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec(in *BootstrapAddonSpec, out *kops.BootstrapAddonSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec(in, out, s)
+}
+
+func autoConvert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec(in *kops.BootstrapAddonSpec, out *BootstrapAddonSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Hold = in.Hold
+	out.Manifest = in.Manifest
+	out.KubernetesVersion = in.KubernetesVersion
+	return nil
+}
+
+// Convert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec is an autogenerated conversion function.
+func Convert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec(in *kops.BootstrapAddonSpec, out *BootstrapAddonSpec, s conversion.Scope) error {
+	return autoConvert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_CNINetworkingSpec_To_kops_CNINetworkingSpec(in *CNINetworkingSpec, out *kops.CNINetworkingSpec, s conversion.Scope) error {
 	out.UsesSecondaryIP = in.UsesSecondaryIP
 	return nil
@@ -2418,6 +2730,17 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Addons = nil
 	}
+	if in.BootstrapAddons != nil {
+		in, out := &in.BootstrapAddons, &out.BootstrapAddons
+		*out = make([]kops.BootstrapAddonSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_BootstrapAddonSpec_To_kops_BootstrapAddonSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.BootstrapAddons = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	// INFO: in.ConfigBase opted out of conversion generation
 	out.CloudProvider = in.CloudProvider
@@ -2466,6 +2789,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	// INFO: in.KubernetesAPIAccess opted out of conversion generation
 	// INFO: in.IsolateMasters opted out of conversion generation
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeDriftCheck != nil {
+		in, out := &in.NodeDriftCheck, &out.NodeDriftCheck
+		*out = new(kops.NodeDriftCheckSpec)
+		if err := Convert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeDriftCheck = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -2672,6 +3004,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeIdentity != nil {
+		in, out := &in.NodeIdentity, &out.NodeIdentity
+		*out = new(kops.NodeIdentitySpec)
+		if err := Convert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeIdentity = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -2703,6 +3044,24 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.PKI != nil {
+		in, out := &in.PKI, &out.PKI
+		*out = new(kops.PKISpec)
+		if err := Convert_v1alpha2_PKISpec_To_kops_PKISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PKI = nil
+	}
+	if in.StateStoreEncryption != nil {
+		in, out := &in.StateStoreEncryption, &out.StateStoreEncryption
+		*out = new(kops.StateStoreEncryptionSpec)
+		if err := Convert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.StateStoreEncryption = nil
+	}
 	// INFO: in.TagSubnets opted out of conversion generation
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -2724,6 +3083,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.RollingUpdate = nil
 	}
+	if in.ClusterValidation != nil {
+		in, out := &in.ClusterValidation, &out.ClusterValidation
+		*out = new(kops.ClusterValidationSpec)
+		if err := Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterValidation = nil
+	}
 	if in.ClusterAutoscaler != nil {
 		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
 		*out = new(kops.ClusterAutoscalerConfig)
@@ -2778,6 +3146,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Addons = nil
 	}
+	if in.BootstrapAddons != nil {
+		in, out := &in.BootstrapAddons, &out.BootstrapAddons
+		*out = make([]BootstrapAddonSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_BootstrapAddonSpec_To_v1alpha2_BootstrapAddonSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.BootstrapAddons = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.CloudProvider = in.CloudProvider
 	if in.GossipConfig != nil {
@@ -2806,6 +3185,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	out.NodePortAccess = in.NodePortAccess
 	out.SSHKeyName = in.SSHKeyName
 	out.UpdatePolicy = in.UpdatePolicy
+	if in.NodeDriftCheck != nil {
+		in, out := &in.NodeDriftCheck, &out.NodeDriftCheck
+		*out = new(NodeDriftCheckSpec)
+		if err := Convert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeDriftCheck = nil
+	}
 	out.ExternalPolicies = in.ExternalPolicies
 	out.AdditionalPolicies = in.AdditionalPolicies
 	if in.FileAssets != nil {
@@ -3008,6 +3396,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeIdentity != nil {
+		in, out := &in.NodeIdentity, &out.NodeIdentity
+		*out = new(NodeIdentitySpec)
+		if err := Convert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeIdentity = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -3039,6 +3436,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.PKI != nil {
+		in, out := &in.PKI, &out.PKI
+		*out = new(PKISpec)
+		if err := Convert_kops_PKISpec_To_v1alpha2_PKISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PKI = nil
+	}
+	if in.StateStoreEncryption != nil {
+		in, out := &in.StateStoreEncryption, &out.StateStoreEncryption
+		*out = new(StateStoreEncryptionSpec)
+		if err := Convert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.StateStoreEncryption = nil
+	}
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
 		*out = new(TargetSpec)
@@ -3059,6 +3474,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.RollingUpdate = nil
 	}
+	if in.ClusterValidation != nil {
+		in, out := &in.ClusterValidation, &out.ClusterValidation
+		*out = new(ClusterValidationSpec)
+		if err := Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterValidation = nil
+	}
 	if in.ClusterAutoscaler != nil {
 		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
 		*out = new(ClusterAutoscalerConfig)
@@ -3156,6 +3580,46 @@ func Convert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in *kops.Clust
 	return autoConvert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in *ClusterValidationSpec, out *kops.ClusterValidationSpec, s conversion.Scope) error {
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]kops.ValidationCheck, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_ValidationCheck_To_kops_ValidationCheck(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Checks = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in *ClusterValidationSpec, out *kops.ClusterValidationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ClusterValidationSpec_To_kops_ClusterValidationSpec(in, out, s)
+}
+
+func autoConvert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in *kops.ClusterValidationSpec, out *ClusterValidationSpec, s conversion.Scope) error {
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ValidationCheck, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ValidationCheck_To_v1alpha2_ValidationCheck(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Checks = nil
+	}
+	return nil
+}
+
+// Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec is an autogenerated conversion function.
+func Convert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in *kops.ClusterValidationSpec, out *ClusterValidationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ClusterValidationSpec_To_v1alpha2_ClusterValidationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
 	out.Address = in.Address
 	out.ConfigAdditions = in.ConfigAdditions
@@ -3374,6 +3838,28 @@ func Convert_kops_DNSControllerGossipConfigSecondary_To_v1alpha2_DNSControllerGo
 	return autoConvert_kops_DNSControllerGossipConfigSecondary_To_v1alpha2_DNSControllerGossipConfigSecondary(in, out, s)
 }
 
+func autoConvert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in *DeploymentsValidationCheck, out *kops.DeploymentsValidationCheck, s conversion.Scope) error {
+	out.Namespaces = in.Namespaces
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in *DeploymentsValidationCheck, out *kops.DeploymentsValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in *kops.DeploymentsValidationCheck, out *DeploymentsValidationCheck, s conversion.Scope) error {
+	out.Namespaces = in.Namespaces
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck is an autogenerated conversion function.
+func Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in *kops.DeploymentsValidationCheck, out *DeploymentsValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_DockerConfig_To_kops_DockerConfig(in *DockerConfig, out *kops.DockerConfig, s conversion.Scope) error {
 	out.AuthorizationPlugins = in.AuthorizationPlugins
 	out.Bridge = in.Bridge
@@ -3992,6 +4478,40 @@ func Convert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha2_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec(in *HelmChartAddonSpec, out *kops.HelmChartAddonSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.Repository = in.Repository
+	out.Chart = in.Chart
+	out.Version = in.Version
+	out.Values = in.Values
+	out.NeedsRollingUpdate = in.NeedsRollingUpdate
+	out.NeedsPKI = in.NeedsPKI
+	return nil
+}
+
+// Convert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec is an autogenerated conversion function.
+func Convert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec(in *HelmChartAddonSpec, out *kops.HelmChartAddonSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_HelmChartAddonSpec_To_kops_HelmChartAddonSpec(in, out, s)
+}
+
+func autoConvert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec(in *kops.HelmChartAddonSpec, out *HelmChartAddonSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespace = in.Namespace
+	out.Repository = in.Repository
+	out.Chart = in.Chart
+	out.Version = in.Version
+	out.Values = in.Values
+	out.NeedsRollingUpdate = in.NeedsRollingUpdate
+	out.NeedsPKI = in.NeedsPKI
+	return nil
+}
+
+// Convert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec is an autogenerated conversion function.
+func Convert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec(in *kops.HelmChartAddonSpec, out *HelmChartAddonSpec, s conversion.Scope) error {
+	return autoConvert_kops_HelmChartAddonSpec_To_v1alpha2_HelmChartAddonSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.HookSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Enabled = in.Enabled
@@ -4017,6 +4537,15 @@ func autoConvert_v1alpha2_HookSpec_To_kops_HookSpec(in *HookSpec, out *kops.Hook
 	}
 	out.Manifest = in.Manifest
 	out.UseRawManifest = in.UseRawManifest
+	if in.Script != nil {
+		in, out := &in.Script, &out.Script
+		*out = new(kops.ScriptHookAction)
+		if err := Convert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Script = nil
+	}
 	return nil
 }
 
@@ -4045,6 +4574,15 @@ func autoConvert_kops_HookSpec_To_v1alpha2_HookSpec(in *kops.HookSpec, out *Hook
 	}
 	out.Manifest = in.Manifest
 	out.UseRawManifest = in.UseRawManifest
+	if in.Script != nil {
+		in, out := &in.Script, &out.Script
+		*out = new(ScriptHookAction)
+		if err := Convert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Script = nil
+	}
 	return nil
 }
 
@@ -4138,6 +4676,28 @@ func Convert_kops_IAMSpec_To_v1alpha2_IAMSpec(in *kops.IAMSpec, out *IAMSpec, s
 	return autoConvert_kops_IAMSpec_To_v1alpha2_IAMSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	out.PublicKeys = in.PublicKeys
+	out.RequireSBOM = in.RequireSBOM
+	return nil
+}
+
+// Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in *ImageVerificationSpec, out *kops.ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ImageVerificationSpec_To_kops_ImageVerificationSpec(in, out, s)
+}
+
+func autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	out.PublicKeys = in.PublicKeys
+	out.RequireSBOM = in.RequireSBOM
+	return nil
+}
+
+// Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec is an autogenerated conversion function.
+func Convert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in *kops.ImageVerificationSpec, out *ImageVerificationSpec, s conversion.Scope) error {
+	return autoConvert_kops_ImageVerificationSpec_To_v1alpha2_ImageVerificationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_InstanceGroup_To_kops_InstanceGroup(in *InstanceGroup, out *kops.InstanceGroup, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -6448,6 +7008,282 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement(in *NodeConditionRequirement, out *kops.NodeConditionRequirement, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Status = in.Status
+	out.AllowMissing = in.AllowMissing
+	return nil
+}
+
+// Convert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement is an autogenerated conversion function.
+func Convert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement(in *NodeConditionRequirement, out *kops.NodeConditionRequirement, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement(in, out, s)
+}
+
+func autoConvert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement(in *kops.NodeConditionRequirement, out *NodeConditionRequirement, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Status = in.Status
+	out.AllowMissing = in.AllowMissing
+	return nil
+}
+
+// Convert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement is an autogenerated conversion function.
+func Convert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement(in *kops.NodeConditionRequirement, out *NodeConditionRequirement, s conversion.Scope) error {
+	return autoConvert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in *NodeConditionsValidationCheck, out *kops.NodeConditionsValidationCheck, s conversion.Scope) error {
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]kops.NodeConditionRequirement, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodeConditionRequirement_To_kops_NodeConditionRequirement(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in *NodeConditionsValidationCheck, out *kops.NodeConditionsValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in *kops.NodeConditionsValidationCheck, out *NodeConditionsValidationCheck, s conversion.Scope) error {
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NodeConditionRequirement, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodeConditionRequirement_To_v1alpha2_NodeConditionRequirement(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
+	return nil
+}
+
+// Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck is an autogenerated conversion function.
+func Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in *kops.NodeConditionsValidationCheck, out *NodeConditionsValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec(in *NodeDriftCheckSpec, out *kops.NodeDriftCheckSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec(in *NodeDriftCheckSpec, out *kops.NodeDriftCheckSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeDriftCheckSpec_To_kops_NodeDriftCheckSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec(in *kops.NodeDriftCheckSpec, out *NodeDriftCheckSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec is an autogenerated conversion function.
+func Convert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec(in *kops.NodeDriftCheckSpec, out *NodeDriftCheckSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeDriftCheckSpec_To_v1alpha2_NodeDriftCheckSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec(in *NodeIdentityConfigMapSpec, out *kops.NodeIdentityConfigMapSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec(in *NodeIdentityConfigMapSpec, out *kops.NodeIdentityConfigMapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec(in *kops.NodeIdentityConfigMapSpec, out *NodeIdentityConfigMapSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec(in *kops.NodeIdentityConfigMapSpec, out *NodeIdentityConfigMapSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec(in *NodeIdentityHTTPSpec, out *kops.NodeIdentityHTTPSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec(in *NodeIdentityHTTPSpec, out *kops.NodeIdentityHTTPSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec(in *kops.NodeIdentityHTTPSpec, out *NodeIdentityHTTPSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec(in *kops.NodeIdentityHTTPSpec, out *NodeIdentityHTTPSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec(in *NodeIdentityLabelSpec, out *kops.NodeIdentityLabelSpec, s conversion.Scope) error {
+	out.Label = in.Label
+	out.Tag = in.Tag
+	out.Attribute = kops.NodeIdentityAttribute(in.Attribute)
+	return nil
+}
+
+// Convert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec(in *NodeIdentityLabelSpec, out *kops.NodeIdentityLabelSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec(in *kops.NodeIdentityLabelSpec, out *NodeIdentityLabelSpec, s conversion.Scope) error {
+	out.Label = in.Label
+	out.Tag = in.Tag
+	out.Attribute = NodeIdentityAttribute(in.Attribute)
+	return nil
+}
+
+// Convert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec(in *kops.NodeIdentityLabelSpec, out *NodeIdentityLabelSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec(in *NodeIdentitySpec, out *kops.NodeIdentitySpec, s conversion.Scope) error {
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]kops.NodeIdentityLabelSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodeIdentityLabelSpec_To_kops_NodeIdentityLabelSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Labels = nil
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]kops.NodeIdentityTaintSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Taints = nil
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(kops.NodeIdentityConfigMapSpec)
+		if err := Convert_v1alpha2_NodeIdentityConfigMapSpec_To_kops_NodeIdentityConfigMapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ConfigMap = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(kops.NodeIdentityHTTPSpec)
+		if err := Convert_v1alpha2_NodeIdentityHTTPSpec_To_kops_NodeIdentityHTTPSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec(in *NodeIdentitySpec, out *kops.NodeIdentitySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeIdentitySpec_To_kops_NodeIdentitySpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec(in *kops.NodeIdentitySpec, out *NodeIdentitySpec, s conversion.Scope) error {
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]NodeIdentityLabelSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodeIdentityLabelSpec_To_v1alpha2_NodeIdentityLabelSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Labels = nil
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]NodeIdentityTaintSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Taints = nil
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(NodeIdentityConfigMapSpec)
+		if err := Convert_kops_NodeIdentityConfigMapSpec_To_v1alpha2_NodeIdentityConfigMapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ConfigMap = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(NodeIdentityHTTPSpec)
+		if err := Convert_kops_NodeIdentityHTTPSpec_To_v1alpha2_NodeIdentityHTTPSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	return nil
+}
+
+// Convert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec(in *kops.NodeIdentitySpec, out *NodeIdentitySpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentitySpec_To_v1alpha2_NodeIdentitySpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec(in *NodeIdentityTaintSpec, out *kops.NodeIdentityTaintSpec, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = in.Effect
+	return nil
+}
+
+// Convert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec(in *NodeIdentityTaintSpec, out *kops.NodeIdentityTaintSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeIdentityTaintSpec_To_kops_NodeIdentityTaintSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec(in *kops.NodeIdentityTaintSpec, out *NodeIdentityTaintSpec, s conversion.Scope) error {
+	out.Tag = in.Tag
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = in.Effect
+	return nil
+}
+
+// Convert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec is an autogenerated conversion function.
+func Convert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec(in *kops.NodeIdentityTaintSpec, out *NodeIdentityTaintSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeIdentityTaintSpec_To_v1alpha2_NodeIdentityTaintSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.ExternalCoreFile = in.ExternalCoreFile
@@ -6920,6 +7756,26 @@ func Convert_kops_PDCSIDriver_To_v1alpha2_PDCSIDriver(in *kops.PDCSIDriver, out
 	return autoConvert_kops_PDCSIDriver_To_v1alpha2_PDCSIDriver(in, out, s)
 }
 
+func autoConvert_v1alpha2_PKISpec_To_kops_PKISpec(in *PKISpec, out *kops.PKISpec, s conversion.Scope) error {
+	out.KeyAlgorithm = in.KeyAlgorithm
+	return nil
+}
+
+// Convert_v1alpha2_PKISpec_To_kops_PKISpec is an autogenerated conversion function.
+func Convert_v1alpha2_PKISpec_To_kops_PKISpec(in *PKISpec, out *kops.PKISpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PKISpec_To_kops_PKISpec(in, out, s)
+}
+
+func autoConvert_kops_PKISpec_To_v1alpha2_PKISpec(in *kops.PKISpec, out *PKISpec, s conversion.Scope) error {
+	out.KeyAlgorithm = in.KeyAlgorithm
+	return nil
+}
+
+// Convert_kops_PKISpec_To_v1alpha2_PKISpec is an autogenerated conversion function.
+func Convert_kops_PKISpec_To_v1alpha2_PKISpec(in *kops.PKISpec, out *PKISpec, s conversion.Scope) error {
+	return autoConvert_kops_PKISpec_To_v1alpha2_PKISpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_PackagesConfig_To_kops_PackagesConfig(in *PackagesConfig, out *kops.PackagesConfig, s conversion.Scope) error {
 	out.HashAmd64 = in.HashAmd64
 	out.HashArm64 = in.HashArm64
@@ -6968,6 +7824,28 @@ func Convert_kops_PodIdentityWebhookSpec_To_v1alpha2_PodIdentityWebhookSpec(in *
 	return autoConvert_kops_PodIdentityWebhookSpec_To_v1alpha2_PodIdentityWebhookSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck(in *PrometheusValidationCheck, out *kops.PrometheusValidationCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck(in *PrometheusValidationCheck, out *kops.PrometheusValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck(in *kops.PrometheusValidationCheck, out *PrometheusValidationCheck, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck is an autogenerated conversion function.
+func Convert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck(in *kops.PrometheusValidationCheck, out *PrometheusValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck(in, out, s)
+}
+
 func autoConvert_v1alpha2_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RBACAuthorizationSpec, out *kops.RBACAuthorizationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -6990,6 +7868,27 @@ func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]kops.RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
+	out.Strategy = kops.RollingUpdateStrategy(in.Strategy)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(kops.RollingUpdateCanary)
+		if err := Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Canary = nil
+	}
 	return nil
 }
 
@@ -7002,6 +7901,27 @@ func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]RollingUpdateHook, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Hooks = nil
+	}
+	out.Strategy = RollingUpdateStrategy(in.Strategy)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(RollingUpdateCanary)
+		if err := Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Canary = nil
+	}
 	return nil
 }
 
@@ -7010,6 +7930,168 @@ func Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUpdate
 	return autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in, out, s)
 }
 
+func autoConvert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in *RollingUpdateCanary, out *kops.RollingUpdateCanary, s conversion.Scope) error {
+	out.Count = in.Count
+	out.SoakDuration = in.SoakDuration
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in *RollingUpdateCanary, out *kops.RollingUpdateCanary, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateCanary_To_kops_RollingUpdateCanary(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in *kops.RollingUpdateCanary, out *RollingUpdateCanary, s conversion.Scope) error {
+	out.Count = in.Count
+	out.SoakDuration = in.SoakDuration
+	return nil
+}
+
+// Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary is an autogenerated conversion function.
+func Convert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in *kops.RollingUpdateCanary, out *RollingUpdateCanary, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateCanary_To_v1alpha2_RollingUpdateCanary(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook(in *RollingUpdateExecHook, out *kops.RollingUpdateExecHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook(in *RollingUpdateExecHook, out *kops.RollingUpdateExecHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook(in *kops.RollingUpdateExecHook, out *RollingUpdateExecHook, s conversion.Scope) error {
+	out.Command = in.Command
+	return nil
+}
+
+// Convert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook(in *kops.RollingUpdateExecHook, out *RollingUpdateExecHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook(in *RollingUpdateHTTPHook, out *kops.RollingUpdateHTTPHook, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook(in *RollingUpdateHTTPHook, out *kops.RollingUpdateHTTPHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook(in *kops.RollingUpdateHTTPHook, out *RollingUpdateHTTPHook, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook(in *kops.RollingUpdateHTTPHook, out *RollingUpdateHTTPHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Phase = kops.RollingUpdateHookPhase(in.Phase)
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(kops.RollingUpdateExecHook)
+		if err := Convert_v1alpha2_RollingUpdateExecHook_To_kops_RollingUpdateExecHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(kops.RollingUpdateHTTPHook)
+		if err := Convert_v1alpha2_RollingUpdateHTTPHook_To_kops_RollingUpdateHTTPHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(kops.RollingUpdateJobHook)
+		if err := Convert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	out.Timeout = in.Timeout
+	out.FailurePolicy = kops.RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in *RollingUpdateHook, out *kops.RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateHook_To_kops_RollingUpdateHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Phase = RollingUpdateHookPhase(in.Phase)
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(RollingUpdateExecHook)
+		if err := Convert_kops_RollingUpdateExecHook_To_v1alpha2_RollingUpdateExecHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Exec = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(RollingUpdateHTTPHook)
+		if err := Convert_kops_RollingUpdateHTTPHook_To_v1alpha2_RollingUpdateHTTPHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(RollingUpdateJobHook)
+		if err := Convert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Job = nil
+	}
+	out.Timeout = in.Timeout
+	out.FailurePolicy = RollingUpdateHookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in *kops.RollingUpdateHook, out *RollingUpdateHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateHook_To_v1alpha2_RollingUpdateHook(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook(in *RollingUpdateJobHook, out *kops.RollingUpdateJobHook, s conversion.Scope) error {
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook(in *RollingUpdateJobHook, out *kops.RollingUpdateJobHook, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdateJobHook_To_kops_RollingUpdateJobHook(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook(in *kops.RollingUpdateJobHook, out *RollingUpdateJobHook, s conversion.Scope) error {
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook is an autogenerated conversion function.
+func Convert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook(in *kops.RollingUpdateJobHook, out *RollingUpdateJobHook, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdateJobHook_To_v1alpha2_RollingUpdateJobHook(in, out, s)
+}
+
 func autoConvert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(in *RomanaNetworkingSpec, out *kops.RomanaNetworkingSpec, s conversion.Scope) error {
 	out.DaemonServiceIP = in.DaemonServiceIP
 	out.EtcdServiceIP = in.EtcdServiceIP
@@ -7180,6 +8262,40 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction(in *ScriptHookAction, out *kops.ScriptHookAction, s conversion.Scope) error {
+	out.FileAsset = in.FileAsset
+	out.Interpreter = in.Interpreter
+	out.Phase = kops.ScriptHookPhase(in.Phase)
+	out.Timeout = in.Timeout
+	out.Retries = in.Retries
+	out.SuccessExitCodes = in.SuccessExitCodes
+	out.Creates = in.Creates
+	out.Environment = in.Environment
+	return nil
+}
+
+// Convert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction is an autogenerated conversion function.
+func Convert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction(in *ScriptHookAction, out *kops.ScriptHookAction, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ScriptHookAction_To_kops_ScriptHookAction(in, out, s)
+}
+
+func autoConvert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction(in *kops.ScriptHookAction, out *ScriptHookAction, s conversion.Scope) error {
+	out.FileAsset = in.FileAsset
+	out.Interpreter = in.Interpreter
+	out.Phase = ScriptHookPhase(in.Phase)
+	out.Timeout = in.Timeout
+	out.Retries = in.Retries
+	out.SuccessExitCodes = in.SuccessExitCodes
+	out.Creates = in.Creates
+	out.Environment = in.Environment
+	return nil
+}
+
+// Convert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction is an autogenerated conversion function.
+func Convert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction(in *kops.ScriptHookAction, out *ScriptHookAction, s conversion.Scope) error {
+	return autoConvert_kops_ScriptHookAction_To_v1alpha2_ScriptHookAction(in, out, s)
+}
+
 func autoConvert_v1alpha2_ServiceAccountExternalPermission_To_kops_ServiceAccountExternalPermission(in *ServiceAccountExternalPermission, out *kops.ServiceAccountExternalPermission, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Namespace = in.Namespace
@@ -7266,6 +8382,112 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha2_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec(in *StateStoreEncryptionKeySpec, out *kops.StateStoreEncryptionKeySpec, s conversion.Scope) error {
+	out.AWSKMSKeyARN = in.AWSKMSKeyARN
+	out.GCPKMSKeyName = in.GCPKMSKeyName
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(kops.VaultTransitKeySpec)
+		if err := Convert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec is an autogenerated conversion function.
+func Convert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec(in *StateStoreEncryptionKeySpec, out *kops.StateStoreEncryptionKeySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec(in, out, s)
+}
+
+func autoConvert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec(in *kops.StateStoreEncryptionKeySpec, out *StateStoreEncryptionKeySpec, s conversion.Scope) error {
+	out.AWSKMSKeyARN = in.AWSKMSKeyARN
+	out.GCPKMSKeyName = in.GCPKMSKeyName
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultTransitKeySpec)
+		if err := Convert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec is an autogenerated conversion function.
+func Convert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec(in *kops.StateStoreEncryptionKeySpec, out *StateStoreEncryptionKeySpec, s conversion.Scope) error {
+	return autoConvert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec(in *StateStoreEncryptionSpec, out *kops.StateStoreEncryptionSpec, s conversion.Scope) error {
+	out.AWSKMSKeyARN = in.AWSKMSKeyARN
+	out.GCPKMSKeyName = in.GCPKMSKeyName
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(kops.VaultTransitKeySpec)
+		if err := Convert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	out.KeyFile = in.KeyFile
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]kops.StateStoreEncryptionKeySpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_StateStoreEncryptionKeySpec_To_kops_StateStoreEncryptionKeySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec(in *StateStoreEncryptionSpec, out *kops.StateStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_StateStoreEncryptionSpec_To_kops_StateStoreEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec(in *kops.StateStoreEncryptionSpec, out *StateStoreEncryptionSpec, s conversion.Scope) error {
+	out.AWSKMSKeyARN = in.AWSKMSKeyARN
+	out.GCPKMSKeyName = in.GCPKMSKeyName
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultTransitKeySpec)
+		if err := Convert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
+	out.KeyFile = in.KeyFile
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]StateStoreEncryptionKeySpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_StateStoreEncryptionKeySpec_To_v1alpha2_StateStoreEncryptionKeySpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec(in *kops.StateStoreEncryptionSpec, out *StateStoreEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_StateStoreEncryptionSpec_To_v1alpha2_StateStoreEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -7379,6 +8601,106 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_ValidationCheck_To_kops_ValidationCheck(in *ValidationCheck, out *kops.ValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	out.InstanceGroups = in.InstanceGroups
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = new(kops.DeploymentsValidationCheck)
+		if err := Convert_v1alpha2_DeploymentsValidationCheck_To_kops_DeploymentsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Deployments = nil
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(kops.PrometheusValidationCheck)
+		if err := Convert_v1alpha2_PrometheusValidationCheck_To_kops_PrometheusValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Prometheus = nil
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = new(kops.NodeConditionsValidationCheck)
+		if err := Convert_v1alpha2_NodeConditionsValidationCheck_To_kops_NodeConditionsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConditions = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_ValidationCheck_To_kops_ValidationCheck is an autogenerated conversion function.
+func Convert_v1alpha2_ValidationCheck_To_kops_ValidationCheck(in *ValidationCheck, out *kops.ValidationCheck, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ValidationCheck_To_kops_ValidationCheck(in, out, s)
+}
+
+func autoConvert_kops_ValidationCheck_To_v1alpha2_ValidationCheck(in *kops.ValidationCheck, out *ValidationCheck, s conversion.Scope) error {
+	out.Name = in.Name
+	out.InstanceGroups = in.InstanceGroups
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = new(DeploymentsValidationCheck)
+		if err := Convert_kops_DeploymentsValidationCheck_To_v1alpha2_DeploymentsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Deployments = nil
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusValidationCheck)
+		if err := Convert_kops_PrometheusValidationCheck_To_v1alpha2_PrometheusValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Prometheus = nil
+	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = new(NodeConditionsValidationCheck)
+		if err := Convert_kops_NodeConditionsValidationCheck_To_v1alpha2_NodeConditionsValidationCheck(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeConditions = nil
+	}
+	return nil
+}
+
+// Convert_kops_ValidationCheck_To_v1alpha2_ValidationCheck is an autogenerated conversion function.
+func Convert_kops_ValidationCheck_To_v1alpha2_ValidationCheck(in *kops.ValidationCheck, out *ValidationCheck, s conversion.Scope) error {
+	return autoConvert_kops_ValidationCheck_To_v1alpha2_ValidationCheck(in, out, s)
+}
+
+func autoConvert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(in *VaultTransitKeySpec, out *kops.VaultTransitKeySpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec is an autogenerated conversion function.
+func Convert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(in *VaultTransitKeySpec, out *kops.VaultTransitKeySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VaultTransitKeySpec_To_kops_VaultTransitKeySpec(in, out, s)
+}
+
+func autoConvert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(in *kops.VaultTransitKeySpec, out *VaultTransitKeySpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec is an autogenerated conversion function.
+func Convert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(in *kops.VaultTransitKeySpec, out *VaultTransitKeySpec, s conversion.Scope) error {
+	return autoConvert_kops_VaultTransitKeySpec_To_v1alpha2_VaultTransitKeySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -18,6 +18,7 @@ package validation
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
@@ -294,6 +295,40 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		allErrs = append(allErrs, validateContainerdConfig(&cluster.Spec, g.Spec.Containerd, field.NewPath("spec", "containerd"), false)...)
 	}
 
+	allErrs = append(allErrs, validateScriptHookFileAssets(g, cluster)...)
+
+	return allErrs
+}
+
+// validateScriptHookFileAssets checks that the scripts of the hooks run on the instance group are file assets of the instance group.
+func validateScriptHookFileAssets(g *kops.InstanceGroup, cluster *kops.Cluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	fileAssets := make(map[string]bool)
+	for _, fileAssetSpecs := range [][]kops.FileAssetSpec{g.Spec.FileAssets, cluster.Spec.FileAssets} {
+		for _, fileAsset := range fileAssetSpecs {
+			if len(fileAsset.Roles) == 0 || slices.Contains(fileAsset.Roles, g.Spec.Role) {
+				fileAssets[fileAsset.Name] = true
+			}
+		}
+	}
+
+	validateHooks := func(hooks []kops.HookSpec, fieldPath *field.Path) {
+		for i, hook := range hooks {
+			if hook.Script == nil || hook.Script.FileAsset == "" {
+				continue
+			}
+			if len(hook.Roles) > 0 && !slices.Contains(hook.Roles, g.Spec.Role) {
+				continue
+			}
+			if !fileAssets[hook.Script.FileAsset] {
+				allErrs = append(allErrs, field.NotFound(fieldPath.Index(i).Child("script", "fileAsset"), hook.Script.FileAsset))
+			}
+		}
+	}
+	validateHooks(g.Spec.Hooks, field.NewPath("spec", "hooks"))
+	validateHooks(cluster.Spec.Hooks, field.NewPath("cluster", "spec", "hooks"))
+
 	return allErrs
 }
 
@@ -229,6 +229,47 @@ func TestValidBootDevice(t *testing.T) {
 	}
 }
 
+func TestValidScriptHookFileAssets(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			FileAssets: []kops.FileAssetSpec{
+				{Name: "cluster-script", Content: "true"},
+				{Name: "control-plane-script", Roles: []kops.InstanceGroupRole{kops.InstanceGroupRoleControlPlane}, Content: "true"},
+			},
+			Hooks: []kops.HookSpec{
+				{Name: "control-plane-hook", Roles: []kops.InstanceGroupRole{kops.InstanceGroupRoleControlPlane}, Script: &kops.ScriptHookAction{FileAsset: "missing"}},
+			},
+		},
+	}
+	grid := []struct {
+		fileAsset string
+		expected  []string
+	}{
+		{
+			fileAsset: "cluster-script",
+		},
+		{
+			fileAsset: "ig-script",
+		},
+		{
+			fileAsset: "control-plane-script",
+			expected:  []string{"Not found::spec.hooks[0].script.fileAsset"},
+		},
+		{
+			fileAsset: "missing",
+			expected:  []string{"Not found::spec.hooks[0].script.fileAsset"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+		ig.Spec.FileAssets = []kops.FileAssetSpec{{Name: "ig-script", Content: "true"}}
+		ig.Spec.Hooks = []kops.HookSpec{{Script: &kops.ScriptHookAction{FileAsset: g.fileAsset}}}
+		errs := CrossValidateInstanceGroup(ig, cluster, nil, true)
+		testErrors(t, g.fileAsset, errs, g.expected)
+	}
+}
+
 func TestValidNodeLabels(t *testing.T) {
 	grid := []struct {
 		label    string
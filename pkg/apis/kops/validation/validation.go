@@ -17,6 +17,9 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"net"
@@ -24,12 +27,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/blang/semver/v4"
 	"golang.org/x/net/ipv4"
 	"golang.org/x/net/ipv6"
 	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -40,6 +45,8 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/nodelabels"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/utils"
 )
@@ -126,6 +133,16 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		}
 	}
 
+	for i := range spec.Addons {
+		allErrs = append(allErrs, validateAddonSpec(&spec.Addons[i], fieldPath.Child("addons").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateBootstrapAddons(spec.BootstrapAddons, fieldPath.Child("bootstrapAddons"))...)
+
+	if spec.NodeDriftCheck != nil && spec.NodeDriftCheck.Interval != nil && spec.NodeDriftCheck.Interval.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("nodeDriftCheck", "interval"), spec.NodeDriftCheck.Interval.Duration.String(), "must be at least 1m"))
+	}
+
 	if spec.KubeAPIServer != nil {
 		allErrs = append(allErrs, validateKubeAPIServer(spec.KubeAPIServer, c, fieldPath.Child("kubeAPIServer"), strict)...)
 	}
@@ -208,6 +225,9 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		if spec.Assets.ContainerProxy != nil && spec.Assets.ContainerRegistry != nil {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("assets", "containerProxy"), "containerProxy cannot be used in conjunction with containerRegistry"))
 		}
+		if spec.Assets.ImageVerification != nil {
+			allErrs = append(allErrs, validateImageVerification(spec.Assets.ImageVerification, fieldPath.Child("assets", "imageVerification"))...)
+		}
 	}
 
 	for i, sysctlParameter := range spec.SysctlParameters {
@@ -220,6 +240,22 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, validateRollingUpdate(spec.RollingUpdate, fieldPath.Child("rollingUpdate"), false)...)
 	}
 
+	if spec.ClusterValidation != nil {
+		allErrs = append(allErrs, validateClusterValidation(spec.ClusterValidation, fieldPath.Child("clusterValidation"))...)
+	}
+
+	if spec.NodeIdentity != nil {
+		allErrs = append(allErrs, validateNodeIdentity(spec.NodeIdentity, spec.GetCloudProvider(), fieldPath.Child("nodeIdentity"))...)
+	}
+
+	if spec.PKI != nil {
+		allErrs = append(allErrs, validatePKI(spec.PKI, fieldPath.Child("pki"))...)
+	}
+
+	if spec.StateStoreEncryption != nil {
+		allErrs = append(allErrs, validateStateStoreEncryption(spec.StateStoreEncryption, fieldPath.Child("stateStoreEncryption"))...)
+	}
+
 	if spec.API.LoadBalancer != nil {
 		lbSpec := spec.API.LoadBalancer
 		lbPath := fieldPath.Child("api", "loadBalancer")
@@ -680,6 +716,86 @@ func validateFileAssetSpec(v *kops.FileAssetSpec, fieldPath *field.Path) field.E
 	return allErrs
 }
 
+func validateAddonSpec(v *kops.AddonSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.Chart == nil {
+		if v.Manifest == "" {
+			allErrs = append(allErrs, field.Required(fieldPath, "you must set either manifest or chart for an addon"))
+		}
+		return allErrs
+	}
+
+	if v.Manifest != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("manifest"), "you cannot set both manifest and chart for an addon"))
+	}
+
+	chart := v.Chart
+	fieldPath = fieldPath.Child("chart")
+	if chart.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+	} else {
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(chart.Name) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), chart.Name, msg))
+		}
+	}
+	if chart.Namespace != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(chart.Namespace) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("namespace"), chart.Namespace, msg))
+		}
+	}
+	if chart.Repository == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("repository"), ""))
+	}
+	if chart.Chart == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("chart"), ""))
+	}
+	if chart.Version == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("version"), ""))
+	}
+	if chart.Values != "" {
+		values := make(map[string]interface{})
+		if err := utils.YamlUnmarshal([]byte(chart.Values), &values); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("values"), chart.Values, "must be a YAML object"))
+		}
+	}
+	if chart.NeedsRollingUpdate != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("needsRollingUpdate"), &chart.NeedsRollingUpdate, []string{"control-plane", "workers", "all"})...)
+	}
+
+	return allErrs
+}
+
+func validateBootstrapAddons(addons []kops.BootstrapAddonSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, addon := range addons {
+		addonPath := fieldPath.Index(i)
+		if addon.Name == "" {
+			allErrs = append(allErrs, field.Required(addonPath.Child("name"), ""))
+		} else if names.Has(addon.Name) {
+			allErrs = append(allErrs, field.Duplicate(addonPath.Child("name"), addon.Name))
+		} else {
+			names.Insert(addon.Name)
+		}
+
+		if addon.Hold && addon.Manifest != "" {
+			allErrs = append(allErrs, field.Forbidden(addonPath.Child("manifest"), "you cannot set both hold and manifest for a bootstrap addon"))
+		} else if !addon.Hold && addon.Manifest == "" {
+			allErrs = append(allErrs, field.Required(addonPath, "you must set either hold or manifest for a bootstrap addon"))
+		}
+
+		if addon.KubernetesVersion != "" {
+			if _, err := semver.ParseRange(addon.KubernetesVersion); err != nil {
+				allErrs = append(allErrs, field.Invalid(addonPath.Child("kubernetesVersion"), addon.KubernetesVersion, fmt.Sprintf("must be a semver range: %v", err)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
 func validateHookSpec(v *kops.HookSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -688,8 +804,19 @@ func validateHookSpec(v *kops.HookSpec, fieldPath *field.Path) field.ErrorList {
 		return allErrs
 	}
 
+	if v.Script != nil {
+		if v.ExecContainer != nil || v.Manifest != "" || v.UseRawManifest {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("script"), "script may not be used with manifest or execContainer"))
+		}
+		if v.Before != nil || v.Requires != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("script"), "script may not be used with before or requires (use phase instead)"))
+		}
+		allErrs = append(allErrs, validateScriptHookAction(v.Script, fieldPath.Child("script"))...)
+		return allErrs
+	}
+
 	if v.ExecContainer == nil && v.Manifest == "" {
-		allErrs = append(allErrs, field.Required(fieldPath, "you must set either manifest or execContainer for a hook"))
+		allErrs = append(allErrs, field.Required(fieldPath, "you must set either manifest, execContainer or script for a hook"))
 	}
 
 	if v.ExecContainer != nil && v.UseRawManifest {
@@ -725,6 +852,40 @@ func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Pat
 	return allErrs
 }
 
+func validateScriptHookAction(v *kops.ScriptHookAction, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.FileAsset == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("fileAsset"), "fileAsset must be specified"))
+	}
+	if v.Interpreter != "" && !filepath.IsAbs(v.Interpreter) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interpreter"), v.Interpreter, "interpreter must be an absolute path"))
+	}
+	if v.Phase != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("phase"), &v.Phase, []kops.ScriptHookPhase{
+			kops.ScriptHookPhasePreContainerd,
+			kops.ScriptHookPhasePreKubelet,
+			kops.ScriptHookPhasePostKubeletReady,
+		})...)
+	}
+	if v.Timeout != nil && v.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), v.Timeout.Duration.String(), "timeout must be positive"))
+	}
+	if v.Retries != nil && *v.Retries < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retries"), *v.Retries, "retries cannot be negative"))
+	}
+	for i, code := range v.SuccessExitCodes {
+		if code < 0 || code > 255 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("successExitCodes").Index(i), code, "exit codes must be between 0 and 255"))
+		}
+	}
+	if v.Creates != "" && !filepath.IsAbs(v.Creates) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("creates"), v.Creates, "creates must be an absolute path"))
+	}
+
+	return allErrs
+}
+
 func validateKubeAPIServer(v *kops.KubeAPIServerConfig, c *kops.Cluster, fldPath *field.Path, strict bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -1810,6 +1971,303 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+
+	if rollingUpdate.Strategy != "" {
+		allErrs = append(allErrs, IsValidValue(fldpath.Child("strategy"), &rollingUpdate.Strategy, []kops.RollingUpdateStrategy{
+			kops.RollingUpdateStrategyRolling,
+			kops.RollingUpdateStrategyCanary,
+			kops.RollingUpdateStrategyInPlace,
+		})...)
+	}
+	if canary := rollingUpdate.Canary; canary != nil {
+		if canary.Count != nil {
+			count, err := intstr.GetScaledValueFromIntOrPercent(canary.Count, 1000, true)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(fldpath.Child("canary", "count"), canary.Count,
+					fmt.Sprintf("Unable to parse: %v", err)))
+			} else if count < 1 {
+				allErrs = append(allErrs, field.Invalid(fldpath.Child("canary", "count"), canary.Count, "Must be positive"))
+			}
+		}
+		if canary.SoakDuration != nil && canary.SoakDuration.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("canary", "soakDuration"), canary.SoakDuration.Duration.String(), "Cannot be negative"))
+		}
+	}
+
+	hookNames := sets.NewString()
+	for i := range rollingUpdate.Hooks {
+		hook := &rollingUpdate.Hooks[i]
+		hookPath := fldpath.Child("hooks").Index(i)
+		if hookNames.Has(hook.Name) {
+			allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), hook.Name))
+		}
+		hookNames.Insert(hook.Name)
+		allErrs = append(allErrs, validateRollingUpdateHook(hook, hookPath)...)
+	}
+	return allErrs
+}
+
+func validateRollingUpdateHook(hook *kops.RollingUpdateHook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if hook.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+	}
+
+	if hook.Phase == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("phase"), ""))
+	} else {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("phase"), &hook.Phase, []kops.RollingUpdateHookPhase{
+			kops.RollingUpdateHookPhaseBeforeDrain,
+			kops.RollingUpdateHookPhaseAfterValidate,
+		})...)
+	}
+
+	if hook.FailurePolicy != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("failurePolicy"), &hook.FailurePolicy, []kops.RollingUpdateHookFailurePolicy{
+			kops.RollingUpdateHookFailurePolicyFail,
+			kops.RollingUpdateHookFailurePolicyIgnore,
+		})...)
+	}
+
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), hook.Timeout.Duration.String(), "must be positive"))
+	}
+
+	actions := 0
+	if hook.Exec != nil {
+		actions++
+		if len(hook.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("exec", "command"), ""))
+		}
+	}
+	if hook.HTTP != nil {
+		actions++
+		if hook.HTTP.URL == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("http", "url"), ""))
+		} else if u, err := url.Parse(hook.HTTP.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http", "url"), hook.HTTP.URL, "must be an http or https URL"))
+		}
+	}
+	if hook.Job != nil {
+		actions++
+		if hook.Job.Manifest == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("job", "manifest"), ""))
+		}
+	}
+	if actions != 1 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "exactly one of exec, http and job must be specified"))
+	}
+
+	return allErrs
+}
+
+func validatePKI(spec *kops.PKISpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.KeyAlgorithm != "" {
+		keyAlgorithm := pki.KeyAlgorithm(spec.KeyAlgorithm)
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("keyAlgorithm"), &keyAlgorithm, pki.KeyAlgorithms)...)
+	}
+
+	return allErrs
+}
+
+func validateStateStoreEncryption(spec *kops.StateStoreEncryptionSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := validateStateStoreEncryptionKey(&kops.StateStoreEncryptionKeySpec{
+		AWSKMSKeyARN:  spec.AWSKMSKeyARN,
+		GCPKMSKeyName: spec.GCPKMSKeyName,
+		Vault:         spec.Vault,
+		KeyFile:       spec.KeyFile,
+	}, fldPath)
+
+	for i := range spec.PreviousKeys {
+		allErrs = append(allErrs, validateStateStoreEncryptionKey(&spec.PreviousKeys[i], fldPath.Child("previousKeys").Index(i))...)
+	}
+
+	return allErrs
+}
+
+func validateStateStoreEncryptionKey(spec *kops.StateStoreEncryptionKeySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	count := 0
+	if spec.AWSKMSKeyARN != "" {
+		count++
+		parsedARN, err := arn.Parse(spec.AWSKMSKeyARN)
+		if err != nil || parsedARN.Service != "kms" || !strings.HasPrefix(parsedARN.Resource, "key/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("awsKMSKeyARN"), spec.AWSKMSKeyARN,
+				"must be a valid KMS key ARN such as arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"))
+		}
+	}
+	if spec.GCPKMSKeyName != "" {
+		count++
+		tokens := strings.Split(spec.GCPKMSKeyName, "/")
+		if len(tokens) != 8 || tokens[0] != "projects" || tokens[2] != "locations" || tokens[4] != "keyRings" || tokens[6] != "cryptoKeys" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("gcpKMSKeyName"), spec.GCPKMSKeyName,
+				"must be of the form projects/<project>/locations/<location>/keyRings/<keyRing>/cryptoKeys/<key>"))
+		}
+	}
+	if spec.Vault != nil {
+		count++
+		if spec.Vault.Address == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("vault", "address"), ""))
+		}
+		if spec.Vault.KeyName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("vault", "keyName"), ""))
+		}
+	}
+	if spec.KeyFile != "" {
+		count++
+	}
+
+	if count == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "a key encryption key must be configured"))
+	} else if count > 1 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one key encryption key may be configured"))
+	}
+
+	return allErrs
+}
+
+func validateClusterValidation(spec *kops.ClusterValidationSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	checkNames := sets.NewString()
+	for i := range spec.Checks {
+		check := &spec.Checks[i]
+		checkPath := fldPath.Child("checks").Index(i)
+
+		if check.Name == "" {
+			allErrs = append(allErrs, field.Required(checkPath.Child("name"), ""))
+		} else if checkNames.Has(check.Name) {
+			allErrs = append(allErrs, field.Duplicate(checkPath.Child("name"), check.Name))
+		}
+		checkNames.Insert(check.Name)
+
+		actions := 0
+		if check.Deployments != nil {
+			actions++
+			if len(check.Deployments.Namespaces) == 0 {
+				allErrs = append(allErrs, field.Required(checkPath.Child("deployments", "namespaces"), ""))
+			}
+			if check.Deployments.Selector != "" {
+				if _, err := labels.Parse(check.Deployments.Selector); err != nil {
+					allErrs = append(allErrs, field.Invalid(checkPath.Child("deployments", "selector"), check.Deployments.Selector, err.Error()))
+				}
+			}
+		}
+		if check.Prometheus != nil {
+			actions++
+			if u, err := url.Parse(check.Prometheus.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				allErrs = append(allErrs, field.Invalid(checkPath.Child("prometheus", "url"), check.Prometheus.URL, "must be an http or https URL"))
+			}
+			if check.Prometheus.Query == "" {
+				allErrs = append(allErrs, field.Required(checkPath.Child("prometheus", "query"), ""))
+			}
+		}
+		if check.NodeConditions != nil {
+			actions++
+			if len(check.NodeConditions.Conditions) == 0 {
+				allErrs = append(allErrs, field.Required(checkPath.Child("nodeConditions", "conditions"), ""))
+			}
+			for j, condition := range check.NodeConditions.Conditions {
+				conditionPath := checkPath.Child("nodeConditions", "conditions").Index(j)
+				if condition.Type == "" {
+					allErrs = append(allErrs, field.Required(conditionPath.Child("type"), ""))
+				}
+				allErrs = append(allErrs, IsValidValue(conditionPath.Child("status"), &condition.Status, []string{"True", "False", "Unknown"})...)
+			}
+		}
+		if actions != 1 {
+			allErrs = append(allErrs, field.Forbidden(checkPath, "exactly one of deployments, prometheus and nodeConditions must be specified"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateNodeIdentity(spec *kops.NodeIdentitySpec, cloudProvider kops.CloudProviderID, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if cloudProvider == kops.CloudProviderDO && (len(spec.Labels) != 0 || len(spec.Taints) != 0) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "label and taint mappings are not supported on DigitalOcean"))
+	}
+
+	for i := range spec.Labels {
+		label := &spec.Labels[i]
+		labelPath := fldPath.Child("labels").Index(i)
+
+		if label.Label == "" {
+			allErrs = append(allErrs, field.Required(labelPath.Child("label"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsQualifiedName(label.Label) {
+				allErrs = append(allErrs, field.Invalid(labelPath.Child("label"), label.Label, msg))
+			}
+			switch label.Label {
+			case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
+				allErrs = append(allErrs, field.Forbidden(labelPath.Child("label"), "node role labels are set from the instance group"))
+			}
+		}
+
+		if label.Tag != "" && label.Attribute != "" {
+			allErrs = append(allErrs, field.Forbidden(labelPath, "only one of tag and attribute may be specified"))
+		} else if label.Tag == "" && label.Attribute == "" {
+			allErrs = append(allErrs, field.Required(labelPath.Child("tag"), "one of tag and attribute must be specified"))
+		}
+		if label.Attribute != "" {
+			attribute := string(label.Attribute)
+			allErrs = append(allErrs, IsValidValue(labelPath.Child("attribute"), &attribute, []string{
+				string(kops.NodeIdentityAttributeInstanceType),
+				string(kops.NodeIdentityAttributeArchitecture),
+				string(kops.NodeIdentityAttributeVCPUs),
+				string(kops.NodeIdentityAttributeMemoryMiB),
+				string(kops.NodeIdentityAttributeGPUs),
+			})...)
+		}
+	}
+
+	for i := range spec.Taints {
+		taint := &spec.Taints[i]
+		taintPath := fldPath.Child("taints").Index(i)
+
+		if taint.Tag == "" {
+			allErrs = append(allErrs, field.Required(taintPath.Child("tag"), ""))
+		}
+		if taint.Key == "" {
+			allErrs = append(allErrs, field.Required(taintPath.Child("key"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsQualifiedName(taint.Key) {
+				allErrs = append(allErrs, field.Invalid(taintPath.Child("key"), taint.Key, msg))
+			}
+		}
+		if taint.Value != nil {
+			for _, msg := range utilvalidation.IsValidLabelValue(*taint.Value) {
+				allErrs = append(allErrs, field.Invalid(taintPath.Child("value"), *taint.Value, msg))
+			}
+		}
+		allErrs = append(allErrs, IsValidValue(taintPath.Child("effect"), &taint.Effect, []string{"NoSchedule", "PreferNoSchedule", "NoExecute"})...)
+	}
+
+	if spec.ConfigMap != nil {
+		if spec.ConfigMap.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("configMap", "name"), ""))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(spec.ConfigMap.Name) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("configMap", "name"), spec.ConfigMap.Name, msg))
+			}
+		}
+	}
+
+	if spec.HTTP != nil {
+		if u, err := url.Parse(spec.HTTP.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http", "url"), spec.HTTP.URL, "must be an http or https URL"))
+		}
+		if spec.HTTP.Timeout != nil && spec.HTTP.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http", "timeout"), spec.HTTP.Timeout.Duration.String(), "must be greater than zero"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -1957,3 +2415,25 @@ func validateCertManager(cluster *kops.Cluster, spec *kops.CertManagerConfig, fl
 	}
 	return allErrs
 }
+
+func validateImageVerification(spec *kops.ImageVerificationSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(spec.PublicKeys) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("publicKeys"), "at least one public key must be trusted"))
+	}
+	for i, publicKey := range spec.PublicKeys {
+		key, err := pki.ParsePEMPublicKey([]byte(publicKey))
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKeys").Index(i), publicKey, "must be a PEM-encoded public key"))
+			continue
+		}
+		switch key.Key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("publicKeys").Index(i), publicKey, "must be an ECDSA, RSA or Ed25519 public key"))
+		}
+	}
+
+	return allErrs
+}
@@ -1307,6 +1307,138 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Forbidden::testField.maxSurge"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyCanary,
+				Canary: &kops.RollingUpdateCanary{
+					Count:        intStr(intstr.FromString("10%")),
+					SoakDuration: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyInPlace,
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: "BlueGreen",
+			},
+			ExpectedErrors: []string{"Unsupported value::testField.strategy"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Strategy: kops.RollingUpdateStrategyCanary,
+				Canary: &kops.RollingUpdateCanary{
+					Count:        intStr(intstr.FromInt(0)),
+					SoakDuration: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.canary.count",
+				"Invalid value::testField.canary.soakDuration",
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Canary: &kops.RollingUpdateCanary{
+					Count: intStr(intstr.FromString("nope")),
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.canary.count"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "drain-check",
+						Phase: kops.RollingUpdateHookPhaseBeforeDrain,
+						Exec:  &kops.RollingUpdateExecHook{Command: []string{"/bin/true"}},
+					},
+					{
+						Name:          "smoke-test",
+						Phase:         kops.RollingUpdateHookPhaseAfterValidate,
+						HTTP:          &kops.RollingUpdateHTTPHook{URL: "https://example.com/check?node=${KOPS_NODE_NAME}"},
+						FailurePolicy: kops.RollingUpdateHookFailurePolicyIgnore,
+					},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "hook",
+						Phase: kops.RollingUpdateHookPhaseBeforeDrain,
+						Exec:  &kops.RollingUpdateExecHook{Command: []string{"/bin/true"}},
+					},
+					{
+						Name:  "hook",
+						Phase: kops.RollingUpdateHookPhaseBeforeDrain,
+						Exec:  &kops.RollingUpdateExecHook{Command: []string{"/bin/true"}},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Duplicate value::testField.hooks[1].name"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Phase: "AfterDrain",
+						Exec:  &kops.RollingUpdateExecHook{},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.hooks[0].name",
+				"Unsupported value::testField.hooks[0].phase",
+				"Required value::testField.hooks[0].exec.command",
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:          "hook",
+						Phase:         kops.RollingUpdateHookPhaseBeforeDrain,
+						HTTP:          &kops.RollingUpdateHTTPHook{URL: "ftp://example.com"},
+						Timeout:       &metav1.Duration{Duration: -time.Second},
+						FailurePolicy: "Retry",
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.hooks[0].http.url",
+				"Invalid value::testField.hooks[0].timeout",
+				"Unsupported value::testField.hooks[0].failurePolicy",
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "hook",
+						Phase: kops.RollingUpdateHookPhaseBeforeDrain,
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.hooks[0]"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				Hooks: []kops.RollingUpdateHook{
+					{
+						Name:  "hook",
+						Phase: kops.RollingUpdateHookPhaseBeforeDrain,
+						Exec:  &kops.RollingUpdateExecHook{Command: []string{"/bin/true"}},
+						Job:   &kops.RollingUpdateJobHook{Manifest: "kind: Job"},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.hooks[0]"},
+		},
 	}
 	for _, g := range grid {
 		errs := validateRollingUpdate(&g.Input, field.NewPath("testField"), g.OnMasterIG)
@@ -1318,6 +1450,330 @@ func intStr(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
 
+func Test_Validate_HookSpec(t *testing.T) {
+	grid := []struct {
+		Input          kops.HookSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.HookSpec{Manifest: "Type=oneshot"},
+		},
+		{
+			Input:          kops.HookSpec{},
+			ExpectedErrors: []string{"Required value::testField"},
+		},
+		{
+			Input: kops.HookSpec{
+				Script: &kops.ScriptHookAction{
+					FileAsset:        "format-disk",
+					Phase:            kops.ScriptHookPhasePreContainerd,
+					Timeout:          &metav1.Duration{Duration: 10 * time.Minute},
+					Retries:          fi.PtrTo(int32(1)),
+					SuccessExitCodes: []int32{0, 3},
+					Creates:          "/var/lib/disk-formatted",
+				},
+			},
+		},
+		{
+			Input: kops.HookSpec{
+				Script:   &kops.ScriptHookAction{FileAsset: "enroll"},
+				Manifest: "Type=oneshot",
+				Before:   []string{"kubelet.service"},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::testField.script",
+				"Forbidden::testField.script",
+			},
+		},
+		{
+			Input: kops.HookSpec{
+				Script: &kops.ScriptHookAction{
+					Interpreter:      "bash",
+					Phase:            "PostContainerd",
+					Timeout:          &metav1.Duration{},
+					Retries:          fi.PtrTo(int32(-1)),
+					SuccessExitCodes: []int32{256},
+					Creates:          "done",
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.script.fileAsset",
+				"Invalid value::testField.script.interpreter",
+				"Unsupported value::testField.script.phase",
+				"Invalid value::testField.script.timeout",
+				"Invalid value::testField.script.retries",
+				"Invalid value::testField.script.successExitCodes[0]",
+				"Invalid value::testField.script.creates",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateHookSpec(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ClusterValidation(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterValidationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ValidationCheck{
+					{
+						Name: "ingress",
+						Deployments: &kops.DeploymentsValidationCheck{
+							Namespaces: []string{"ingress-nginx"},
+							Selector:   "app.kubernetes.io/component=controller",
+						},
+					},
+					{
+						Name:           "alerts",
+						InstanceGroups: []string{"nodes"},
+						Prometheus: &kops.PrometheusValidationCheck{
+							URL:   "http://prometheus.monitoring.svc:9090",
+							Query: `ALERTS{alertstate="firing"}`,
+						},
+					},
+					{
+						Name: "network",
+						NodeConditions: &kops.NodeConditionsValidationCheck{
+							Conditions: []kops.NodeConditionRequirement{
+								{Type: "NetworkUnavailable", Status: "False"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ValidationCheck{
+					{
+						Deployments: &kops.DeploymentsValidationCheck{
+							Selector: "app in (",
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.checks[0].name",
+				"Required value::testField.checks[0].deployments.namespaces",
+				"Invalid value::testField.checks[0].deployments.selector",
+			},
+		},
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ValidationCheck{
+					{
+						Name: "alerts",
+						Prometheus: &kops.PrometheusValidationCheck{
+							URL: "prometheus:9090",
+						},
+					},
+					{
+						Name: "alerts",
+						NodeConditions: &kops.NodeConditionsValidationCheck{
+							Conditions: []kops.NodeConditionRequirement{
+								{Status: "Maybe"},
+							},
+						},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.checks[0].prometheus.url",
+				"Required value::testField.checks[0].prometheus.query",
+				"Duplicate value::testField.checks[1].name",
+				"Required value::testField.checks[1].nodeConditions.conditions[0].type",
+				"Unsupported value::testField.checks[1].nodeConditions.conditions[0].status",
+			},
+		},
+		{
+			Input: kops.ClusterValidationSpec{
+				Checks: []kops.ValidationCheck{
+					{
+						Name: "none",
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.checks[0]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateClusterValidation(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeIdentity(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeIdentitySpec
+		CloudProvider  kops.CloudProviderID
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeIdentitySpec{
+				Labels: []kops.NodeIdentityLabelSpec{
+					{Label: "topology.example.com/rack", Tag: "rack"},
+					{Label: "node.example.com/instance-type", Attribute: kops.NodeIdentityAttributeInstanceType},
+				},
+				Taints: []kops.NodeIdentityTaintSpec{
+					{Tag: "dedicated", Key: "dedicated", Effect: "NoSchedule"},
+				},
+				ConfigMap: &kops.NodeIdentityConfigMapSpec{Name: "node-identity"},
+				HTTP: &kops.NodeIdentityHTTPSpec{
+					URL:     "https://inventory.example.com/nodes",
+					Timeout: &metav1.Duration{Duration: 5 * time.Second},
+				},
+			},
+			CloudProvider: kops.CloudProviderAWS,
+		},
+		{
+			Input: kops.NodeIdentitySpec{
+				Labels: []kops.NodeIdentityLabelSpec{
+					{Tag: "rack"},
+					{Label: "node-role.kubernetes.io/control-plane", Tag: "role"},
+					{Label: "rack", Tag: "rack", Attribute: kops.NodeIdentityAttributeVCPUs},
+					{Label: "rack"},
+					{Label: "cpus", Attribute: "cores"},
+				},
+			},
+			CloudProvider: kops.CloudProviderAWS,
+			ExpectedErrors: []string{
+				"Required value::testField.labels[0].label",
+				"Forbidden::testField.labels[1].label",
+				"Forbidden::testField.labels[2]",
+				"Required value::testField.labels[3].tag",
+				"Unsupported value::testField.labels[4].attribute",
+			},
+		},
+		{
+			Input: kops.NodeIdentitySpec{
+				Taints: []kops.NodeIdentityTaintSpec{
+					{Key: "dedicated", Effect: "NoSchedule"},
+					{Tag: "dedicated", Key: "-dedicated", Value: fi.PtrTo("a b"), Effect: "Never"},
+				},
+			},
+			CloudProvider: kops.CloudProviderGCE,
+			ExpectedErrors: []string{
+				"Required value::testField.taints[0].tag",
+				"Invalid value::testField.taints[1].key",
+				"Invalid value::testField.taints[1].value",
+				"Unsupported value::testField.taints[1].effect",
+			},
+		},
+		{
+			Input: kops.NodeIdentitySpec{
+				Labels: []kops.NodeIdentityLabelSpec{
+					{Label: "rack", Tag: "rack"},
+				},
+			},
+			CloudProvider:  kops.CloudProviderDO,
+			ExpectedErrors: []string{"Forbidden::testField"},
+		},
+		{
+			Input: kops.NodeIdentitySpec{
+				ConfigMap: &kops.NodeIdentityConfigMapSpec{Name: "Node_Identity"},
+				HTTP: &kops.NodeIdentityHTTPSpec{
+					URL:     "inventory:8080",
+					Timeout: &metav1.Duration{},
+				},
+			},
+			CloudProvider: kops.CloudProviderDO,
+			ExpectedErrors: []string{
+				"Invalid value::testField.configMap.name",
+				"Invalid value::testField.http.url",
+				"Invalid value::testField.http.timeout",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeIdentity(&g.Input, g.CloudProvider, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_PKI(t *testing.T) {
+	grid := []struct {
+		Input          kops.PKISpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.PKISpec{},
+		},
+		{
+			Input: kops.PKISpec{KeyAlgorithm: "ECDSA-P256"},
+		},
+		{
+			Input: kops.PKISpec{KeyAlgorithm: "Ed25519"},
+		},
+		{
+			Input:          kops.PKISpec{KeyAlgorithm: "ECDSA-P384"},
+			ExpectedErrors: []string{"Unsupported value::testField.keyAlgorithm"},
+		},
+	}
+	for _, g := range grid {
+		errs := validatePKI(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_StateStoreEncryption(t *testing.T) {
+	grid := []struct {
+		Input          kops.StateStoreEncryptionSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input:          kops.StateStoreEncryptionSpec{},
+			ExpectedErrors: []string{"Required value::testField"},
+		},
+		{
+			Input: kops.StateStoreEncryptionSpec{AWSKMSKeyARN: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+		},
+		{
+			Input:          kops.StateStoreEncryptionSpec{AWSKMSKeyARN: "arn:aws:iam::123456789012:role/KopsExampleRole"},
+			ExpectedErrors: []string{"Invalid value::testField.awsKMSKeyARN"},
+		},
+		{
+			Input: kops.StateStoreEncryptionSpec{GCPKMSKeyName: "projects/example/locations/global/keyRings/kops/cryptoKeys/state"},
+		},
+		{
+			Input:          kops.StateStoreEncryptionSpec{GCPKMSKeyName: "projects/example/keyRings/kops"},
+			ExpectedErrors: []string{"Invalid value::testField.gcpKMSKeyName"},
+		},
+		{
+			Input: kops.StateStoreEncryptionSpec{Vault: &kops.VaultTransitKeySpec{Address: "https://vault.example.com:8200", KeyName: "kops"}},
+		},
+		{
+			Input:          kops.StateStoreEncryptionSpec{Vault: &kops.VaultTransitKeySpec{}},
+			ExpectedErrors: []string{"Required value::testField.vault.address", "Required value::testField.vault.keyName"},
+		},
+		{
+			Input:          kops.StateStoreEncryptionSpec{KeyFile: "/tmp/key", GCPKMSKeyName: "projects/example/locations/global/keyRings/kops/cryptoKeys/state"},
+			ExpectedErrors: []string{"Forbidden::testField"},
+		},
+		{
+			Input: kops.StateStoreEncryptionSpec{
+				KeyFile:      "/tmp/key",
+				PreviousKeys: []kops.StateStoreEncryptionKeySpec{{GCPKMSKeyName: "projects/example/locations/global/keyRings/kops/cryptoKeys/state"}},
+			},
+		},
+		{
+			Input: kops.StateStoreEncryptionSpec{
+				KeyFile:      "/tmp/key",
+				PreviousKeys: []kops.StateStoreEncryptionKeySpec{{}, {Vault: &kops.VaultTransitKeySpec{KeyName: "kops"}}},
+			},
+			ExpectedErrors: []string{"Required value::testField.previousKeys[0]", "Required value::testField.previousKeys[1].vault.address"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateStateStoreEncryption(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 func Test_Validate_NodeLocalDNS(t *testing.T) {
 	grid := []struct {
 		Input          kops.ClusterSpec
@@ -1768,3 +2224,138 @@ func Test_Validate_NriConfig(t *testing.T) {
 		testErrors(t, g.Input.Containerd, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_ImageVerification(t *testing.T) {
+	ecdsaKey := "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE8V9WO1L0my0/p53HA8OTFowkbbKC\nmx20swg6EyzozpXhm8E10b36lv2vWIf3n874ugdn1Y9QFcfNTwCaiixV9g==\n-----END PUBLIC KEY-----\n"
+	grid := []struct {
+		Input          kops.ImageVerificationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input:          kops.ImageVerificationSpec{},
+			ExpectedErrors: []string{"Required value::testField.publicKeys"},
+		},
+		{
+			Input: kops.ImageVerificationSpec{PublicKeys: []string{ecdsaKey}},
+		},
+		{
+			Input:          kops.ImageVerificationSpec{PublicKeys: []string{ecdsaKey, "not a key"}},
+			ExpectedErrors: []string{"Invalid value::testField.publicKeys[1]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateImageVerification(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AddonSpec(t *testing.T) {
+	grid := []struct {
+		Input          kops.AddonSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AddonSpec{Manifest: "s3://bucket/addons/addon.yaml"},
+		},
+		{
+			Input:          kops.AddonSpec{},
+			ExpectedErrors: []string{"Required value::testField"},
+		},
+		{
+			Input: kops.AddonSpec{
+				Chart: &kops.HelmChartAddonSpec{
+					Name:       "metrics-server",
+					Repository: "https://kubernetes-sigs.github.io/metrics-server",
+					Chart:      "metrics-server",
+					Version:    "3.12.0",
+					Values:     "replicas: 2\n",
+				},
+			},
+		},
+		{
+			Input: kops.AddonSpec{
+				Manifest: "s3://bucket/addons/addon.yaml",
+				Chart: &kops.HelmChartAddonSpec{
+					Name:       "metrics-server",
+					Repository: "oci://registry.example.com/charts",
+					Chart:      "metrics-server",
+					Version:    "3.12.0",
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::testField.manifest"},
+		},
+		{
+			Input: kops.AddonSpec{
+				Chart: &kops.HelmChartAddonSpec{},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField.chart.name",
+				"Required value::testField.chart.repository",
+				"Required value::testField.chart.chart",
+				"Required value::testField.chart.version",
+			},
+		},
+		{
+			Input: kops.AddonSpec{
+				Chart: &kops.HelmChartAddonSpec{
+					Name:               "Metrics_Server",
+					Namespace:          "Monitoring",
+					Repository:         "https://kubernetes-sigs.github.io/metrics-server",
+					Chart:              "metrics-server",
+					Version:            "3.12.0",
+					Values:             "- not\n- an object\n",
+					NeedsRollingUpdate: "nodes",
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.chart.name",
+				"Invalid value::testField.chart.namespace",
+				"Invalid value::testField.chart.values",
+				"Unsupported value::testField.chart.needsRollingUpdate",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAddonSpec(&g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_BootstrapAddons(t *testing.T) {
+	grid := []struct {
+		Input          []kops.BootstrapAddonSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.BootstrapAddonSpec{
+				{Name: "coredns.addons.k8s.io", Hold: true, KubernetesVersion: ">=1.27.0 <1.30.0"},
+				{Name: "networking.cilium.io", Manifest: "s3://bucket/addons/cilium.yaml"},
+			},
+		},
+		{
+			Input: []kops.BootstrapAddonSpec{
+				{Hold: true},
+				{Name: "coredns.addons.k8s.io"},
+			},
+			ExpectedErrors: []string{
+				"Required value::testField[0].name",
+				"Required value::testField[1]",
+			},
+		},
+		{
+			Input: []kops.BootstrapAddonSpec{
+				{Name: "coredns.addons.k8s.io", Hold: true},
+				{Name: "coredns.addons.k8s.io", Hold: true, Manifest: "s3://bucket/addons/coredns.yaml", KubernetesVersion: "1.29"},
+			},
+			ExpectedErrors: []string{
+				"Duplicate value::testField[1].name",
+				"Forbidden::testField[1].manifest",
+				"Invalid value::testField[1].kubernetesVersion",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateBootstrapAddons(g.Input, field.NewPath("testField"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
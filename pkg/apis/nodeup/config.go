@@ -18,8 +18,10 @@ package nodeup
 
 import (
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/util/pkg/architectures"
@@ -46,6 +48,8 @@ type Config struct {
 
 	// ConfigStore configures the stores that nodes use to get their configuration when they don't use kops-controller.
 	ConfigStore *kops.ConfigStoreSpec `json:"configStore,omitempty"`
+	// StateStoreEncryption configures the keys which private keys and secrets in the ConfigStore are encrypted with.
+	StateStoreEncryption *kops.StateStoreEncryptionSpec `json:"stateStoreEncryption,omitempty"`
 
 	// EtcdClusterNames are the names of the etcd clusters.
 	EtcdClusterNames []string `json:",omitempty"`
@@ -56,6 +60,8 @@ type Config struct {
 	CAs map[string]string
 	// KeypairIDs are the IDs of keysets used to sign things.
 	KeypairIDs map[string]string
+	// KeyAlgorithm is the algorithm of the private keys nodeup generates, if not RSA.
+	KeyAlgorithm string `json:",omitempty"`
 	// DefaultMachineType is the first-listed instance machine type, used if querying instance metadata fails.
 	DefaultMachineType *string `json:",omitempty"`
 	// EnableLifecycleHook defines whether we need to complete a lifecycle hook.
@@ -81,6 +87,10 @@ type Config struct {
 	SysctlParameters []string `json:",omitempty"`
 	// UpdatePolicy determines the policy for applying upgrades automatically.
 	UpdatePolicy string
+	// DriftCheckInterval, if set, is how often nodeup checks the node for drift from this configuration.
+	DriftCheckInterval *metav1.Duration `json:",omitempty"`
+	// InPlaceReconfiguration is true if rolling updates reconfigure the node by running nodeup again when requested on the Node.
+	InPlaceReconfiguration bool `json:",omitempty"`
 	// VolumeMounts are a collection of volume mounts.
 	VolumeMounts []kops.VolumeMountSpec `json:",omitempty"`
 
@@ -234,6 +244,7 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		Hooks:                [][]kops.HookSpec{igHooks, clusterHooks},
 		UsesLegacyGossip:     cluster.UsesLegacyGossip(),
 		UsesNoneDNS:          cluster.UsesNoneDNS(),
+		KeyAlgorithm:         cluster.KeyAlgorithm(),
 	}
 
 	bootConfig := BootConfig{
@@ -297,6 +308,22 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 		config.UpdatePolicy = kops.UpdatePolicyAutomatic
 	}
 
+	if driftCheck := cluster.Spec.NodeDriftCheck; driftCheck != nil && driftCheck.Enabled != nil && *driftCheck.Enabled {
+		config.DriftCheckInterval = driftCheck.Interval
+		if config.DriftCheckInterval == nil {
+			config.DriftCheckInterval = &metav1.Duration{Duration: time.Hour}
+		}
+	}
+
+	var strategy kops.RollingUpdateStrategy
+	if cluster.Spec.RollingUpdate != nil {
+		strategy = cluster.Spec.RollingUpdate.Strategy
+	}
+	if instanceGroup.Spec.RollingUpdate != nil && instanceGroup.Spec.RollingUpdate.Strategy != "" {
+		strategy = instanceGroup.Spec.RollingUpdate.Strategy
+	}
+	config.InPlaceReconfiguration = strategy == kops.RollingUpdateStrategyInPlace
+
 	if cluster.Spec.Networking.AmazonVPC != nil {
 		config.Networking.AmazonVPC = &kops.AmazonVPCNetworkingSpec{}
 		config.DefaultMachineType = aws.String(strings.Split(instanceGroup.Spec.MachineType, ",")[0])
@@ -361,6 +388,7 @@ func NewConfig(cluster *kops.Cluster, instanceGroup *kops.InstanceGroup) (*Confi
 			Keypairs: cluster.Spec.ConfigStore.Keypairs,
 			Secrets:  cluster.Spec.ConfigStore.Secrets,
 		}
+		config.StateStoreEncryption = cluster.Spec.StateStoreEncryption
 	}
 
 	if instanceGroup.HasAPIServer() || cluster.UsesLegacyGossip() {
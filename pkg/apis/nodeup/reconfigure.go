@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const (
+	// AnnotationReconfigureRequested is set on a Node by the rolling update to request that nodeup runs again on the node.
+	// Its value is the hash of the nodeup configuration that nodeup must apply.
+	AnnotationReconfigureRequested = "kops.k8s.io/reconfigure-requested"
+	// AnnotationReconfigured is set on a Node by nodeup once it has reconfigured the node.
+	// Its value is the hash of the nodeup configuration it applied.
+	AnnotationReconfigured = "kops.k8s.io/reconfigured"
+)
+
+// HashConfig returns the hash of the serialized nodeup configuration, as recorded in BootConfig.NodeupConfigHash.
+func HashConfig(b []byte) string {
+	hash := sha256.Sum256(b)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
@@ -89,6 +89,9 @@ type ImageAsset struct {
 	DownloadLocation string
 	// CanonicalLocation will be the source location of the image.
 	CanonicalLocation string
+	// RunLocation is the image the cluster runs.
+	// When images are verified, it is pinned to the digest of the image, so that the verified image is the one run.
+	RunLocation string
 }
 
 // FileAsset models a file's location.
@@ -140,6 +143,36 @@ func (a *AssetBuilder) RemapManifest(data []byte) ([]byte, error) {
 
 // RemapImage normalizes a containers location if a user sets the AssetsLocation ContainerRegistry location.
 func (a *AssetBuilder) RemapImage(image string) (string, error) {
+	asset, image := a.remapImage(image)
+	asset.RunLocation = image
+
+	a.ImageAssets = append(a.ImageAssets, asset)
+
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+
+	// Verified images must be pinned, so that the tag can't be moved to another image after it was verified
+	verified := a.AssetsLocation != nil && a.AssetsLocation.ImageVerification != nil && !a.GetAssets
+	if !verified && (!featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "") {
+		return image, nil
+	}
+
+	digest, err := crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		if verified {
+			return "", fmt.Errorf("failed to digest image %q to verify it: %w", image, err)
+		}
+		klog.Warningf("failed to digest image %q: %s", image, err)
+		return image, nil
+	}
+
+	asset.RunLocation = image + "@" + digest
+	return asset.RunLocation, nil
+}
+
+// remapImage returns the asset for an image, and the image that should be run.
+func (a *AssetBuilder) remapImage(image string) (*ImageAsset, string) {
 	asset := &ImageAsset{
 		DownloadLocation:  image,
 		CanonicalLocation: image,
@@ -217,23 +250,7 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 		image = asset.DownloadLocation
 	}
 
-	a.ImageAssets = append(a.ImageAssets, asset)
-
-	if !featureflag.ImageDigest.Enabled() || os.Getenv("KOPS_BASE_URL") != "" {
-		return image, nil
-	}
-
-	if strings.Contains(image, "@") {
-		return image, nil
-	}
-
-	digest, err := crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
-	if err != nil {
-		klog.Warningf("failed to digest image %q: %s", image, err)
-		return image, nil
-	}
-
-	return image + "@" + digest, nil
+	return asset, image
 }
 
 // RemapFileAndSHA returns a remapped URL for the file, if AssetsLocation is defined.
@@ -0,0 +1,529 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// An asset bundle is a tar archive holding the image and file assets of a cluster,
+// so that they can be transferred to a repository that is not reachable from the canonical locations:
+//
+//	bundle.yaml     the bundleManifest
+//	images/         the images, as an OCI image layout, with each image annotated with its canonical location,
+//	                and their cosign signatures and attestations, annotated with the canonical location of their tag
+//	files/<sha>     the contents of each file
+const (
+	bundleManifestPath = "bundle.yaml"
+	bundleImagesDir    = "images"
+	bundleFilesDir     = "files"
+
+	// annotationRefName is the OCI annotation holding the canonical location of an image in the layout.
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// bundleManifest lists the contents of an asset bundle.
+type bundleManifest struct {
+	Images []*bundleImage `json:"images,omitempty"`
+	Files  []*bundleFile  `json:"files,omitempty"`
+}
+
+// bundleImage is an image in an asset bundle.
+type bundleImage struct {
+	// Canonical is the canonical location of the image.
+	Canonical string `json:"canonical"`
+	// Digest is the digest of the image manifest or index.
+	Digest string `json:"digest"`
+	// Attachments are the tags of the cosign signatures and attestations of the image.
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// bundleFile is a file in an asset bundle.
+type bundleFile struct {
+	// Canonical is the canonical URL of the file.
+	Canonical string `json:"canonical"`
+	// SHA is the hash of the file.
+	SHA string `json:"sha"`
+	// Path is the path of the file in the bundle.
+	Path string `json:"path"`
+}
+
+// WriteBundle downloads the image and file assets from their canonical locations and writes them as an asset bundle.
+// The hash of each file is verified, as are the signatures of the images if the cluster configures image verification.
+func WriteBundle(ctx context.Context, w io.Writer, imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VFSContext, cluster *kops.Cluster) error {
+	verifier, err := NewImageVerifier(ctx, cluster.Spec.Assets)
+	if err != nil {
+		return err
+	}
+	if verifier != nil {
+		var images []string
+		for _, imageAsset := range imageAssets {
+			images = append(images, imageAsset.CanonicalLocation)
+		}
+		if err := verifier.VerifyImages(images); err != nil {
+			return err
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kops-assets")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := &bundleManifest{}
+
+	imagesDir := filepath.Join(tmpDir, bundleImagesDir)
+	layoutPath, err := layout.Write(imagesDir, empty.Index)
+	if err != nil {
+		return fmt.Errorf("creating image layout: %w", err)
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx)}
+
+	seen := map[string]bool{}
+	for _, imageAsset := range imageAssets {
+		canonical := imageAsset.CanonicalLocation
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		ref, err := name.ParseReference(canonical)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", canonical, err)
+		}
+
+		klog.Infof("adding image %v to bundle", ref)
+		desc, err := remote.Get(ref, options...)
+		if err != nil {
+			return fmt.Errorf("fetching %q: %w", canonical, err)
+		}
+
+		annotations := layout.WithAnnotations(map[string]string{annotationRefName: canonical})
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			idx, err := desc.ImageIndex()
+			if err != nil {
+				return fmt.Errorf("reading index %q: %w", canonical, err)
+			}
+			if err := layoutPath.AppendIndex(idx, annotations); err != nil {
+				return fmt.Errorf("writing index %q: %w", canonical, err)
+			}
+		default:
+			// Assume anything else is an image, since some registries don't set mediaTypes properly.
+			img, err := desc.Image()
+			if err != nil {
+				return fmt.Errorf("reading image %q: %w", canonical, err)
+			}
+			if err := layoutPath.AppendImage(img, annotations); err != nil {
+				return fmt.Errorf("writing image %q: %w", canonical, err)
+			}
+		}
+
+		attachments, err := appendAttachments(layoutPath, ref, desc.Digest, options...)
+		if err != nil {
+			return err
+		}
+
+		manifest.Images = append(manifest.Images, &bundleImage{
+			Canonical:   canonical,
+			Digest:      desc.Digest.String(),
+			Attachments: attachments,
+		})
+	}
+
+	tw := tar.NewWriter(w)
+
+	if err := addDirToTar(tw, tmpDir, imagesDir); err != nil {
+		return err
+	}
+
+	seen = map[string]bool{}
+	for _, fileAsset := range fileAssets {
+		canonical := fileAsset.CanonicalURL.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		sha := strings.TrimSpace(fileAsset.SHAValue)
+		hash, err := hashing.FromString(sha)
+		if err != nil {
+			return fmt.Errorf("unable to parse sha %q for %q: %w", sha, canonical, err)
+		}
+
+		klog.Infof("adding file %q to bundle", canonical)
+		data, err := vfsContext.ReadFile(canonical)
+		if err != nil {
+			return fmt.Errorf("error downloading file %q: %w", canonical, err)
+		}
+		if err := verifyHash(data, hash); err != nil {
+			return fmt.Errorf("file %q: %w", canonical, err)
+		}
+
+		bundlePath := path.Join(bundleFilesDir, hash.Hex())
+		if !seen[bundlePath] {
+			if err := addFileToTar(tw, bundlePath, data); err != nil {
+				return err
+			}
+			seen[bundlePath] = true
+		}
+
+		manifest.Files = append(manifest.Files, &bundleFile{
+			Canonical: canonical,
+			SHA:       sha,
+			Path:      bundlePath,
+		})
+	}
+
+	manifestData, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("error marshaling bundle manifest: %w", err)
+	}
+	if err := addFileToTar(tw, bundleManifestPath, manifestData); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// appendAttachments adds the cosign signatures and attestations of an image digest to the layout, if there are any,
+// and returns their tags.
+func appendAttachments(layoutPath layout.Path, ref name.Reference, digest v1.Hash, options ...remote.Option) ([]string, error) {
+	var tags []string
+	for _, suffix := range []string{signatureTagSuffix, attestationTagSuffix} {
+		tag := attachedTag(digest, suffix)
+		attachmentRef := ref.Context().Tag(tag)
+		img, err := remote.Image(attachmentRef, options...)
+		if err != nil {
+			var terr *transport.Error
+			if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("fetching %v: %w", attachmentRef, err)
+		}
+		if err := layoutPath.AppendImage(img, layout.WithAnnotations(map[string]string{annotationRefName: attachmentRef.String()})); err != nil {
+			return nil, fmt.Errorf("writing %v: %w", attachmentRef, err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// ImportBundle reads an asset bundle and uploads its images and files to the repositories of the AssetBuilder,
+// as they would be remapped for the cluster.
+// The hash of each file and the digest of each image is verified.
+func ImportBundle(ctx context.Context, r io.Reader, assetBuilder *AssetBuilder, vfsContext *vfs.VFSContext, cluster *kops.Cluster) error {
+	tmpDir, err := os.MkdirTemp("", "kops-assets")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := extractTar(r, tmpDir); err != nil {
+		return fmt.Errorf("error extracting bundle: %w", err)
+	}
+
+	manifestData, err := os.ReadFile(filepath.Join(tmpDir, bundleManifestPath))
+	if err != nil {
+		return fmt.Errorf("error reading bundle manifest: %w", err)
+	}
+	manifest := &bundleManifest{}
+	if err := yaml.Unmarshal(manifestData, manifest); err != nil {
+		return fmt.Errorf("error parsing bundle manifest: %w", err)
+	}
+
+	if len(manifest.Images) != 0 {
+		if err := importImages(ctx, filepath.Join(tmpDir, bundleImagesDir), manifest.Images, assetBuilder); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range manifest.Files {
+		canonicalURL, err := url.Parse(file.Canonical)
+		if err != nil {
+			return fmt.Errorf("parsing %q: %w", file.Canonical, err)
+		}
+		if assetBuilder.AssetsLocation == nil || assetBuilder.AssetsLocation.FileRepository == nil {
+			return fmt.Errorf("assets.fileRepository must be set to import file %q", file.Canonical)
+		}
+		target, err := assetBuilder.RemapFileAndSHAValue(canonicalURL, file.SHA)
+		if err != nil {
+			return err
+		}
+
+		source, err := bundleFilePath(tmpDir, file.Path)
+		if err != nil {
+			return err
+		}
+		copyFileTask := &CopyFile{
+			Name:       file.Canonical,
+			SourceFile: source,
+			TargetFile: target.String(),
+			SHA:        file.SHA,
+			VFSContext: vfsContext,
+			Cluster:    cluster,
+		}
+		if err := copyFileTask.Run(); err != nil {
+			return fmt.Errorf("%s: %w", file.Canonical, err)
+		}
+	}
+
+	return nil
+}
+
+// importImages pushes the images of the image layout to the locations they are remapped to.
+func importImages(ctx context.Context, imagesDir string, images []*bundleImage, assetBuilder *AssetBuilder) error {
+	layoutPath, err := layout.FromPath(imagesDir)
+	if err != nil {
+		return fmt.Errorf("reading image layout: %w", err)
+	}
+	index, err := layoutPath.ImageIndex()
+	if err != nil {
+		return fmt.Errorf("reading image layout: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("reading image layout: %w", err)
+	}
+
+	descriptors := map[string]v1.Descriptor{}
+	for _, desc := range indexManifest.Manifests {
+		if canonical := desc.Annotations[annotationRefName]; canonical != "" {
+			descriptors[canonical] = desc
+		}
+	}
+
+	options := []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx)}
+
+	for _, image := range images {
+		desc, found := descriptors[image.Canonical]
+		if !found {
+			return fmt.Errorf("image %q not found in bundle", image.Canonical)
+		}
+		if desc.Digest.String() != image.Digest {
+			return fmt.Errorf("image %q has digest %s in bundle, expected %s", image.Canonical, desc.Digest, image.Digest)
+		}
+
+		asset, _ := assetBuilder.remapImage(image.Canonical)
+		if asset.DownloadLocation == asset.CanonicalLocation {
+			return fmt.Errorf("assets.containerRegistry or assets.containerProxy must be set to import image %q", image.Canonical)
+		}
+		targetRef, err := name.ParseReference(asset.DownloadLocation)
+		if err != nil {
+			return fmt.Errorf("parsing reference for %q: %w", asset.DownloadLocation, err)
+		}
+
+		if err := importAttachments(index, descriptors, image, targetRef, options...); err != nil {
+			return err
+		}
+
+		targetDesc, err := remote.Get(targetRef, options...)
+		if err == nil && targetDesc.Digest == desc.Digest {
+			klog.Infof("no need to import image %v", targetRef)
+			continue
+		}
+
+		klog.Infof("importing image %q to %v", image.Canonical, targetRef)
+		switch desc.MediaType {
+		case types.OCIImageIndex, types.DockerManifestList:
+			idx, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("reading index %q: %w", image.Canonical, err)
+			}
+			if err := verifyDigest(idx, desc.Digest); err != nil {
+				return fmt.Errorf("index %q: %w", image.Canonical, err)
+			}
+			if err := remote.WriteIndex(targetRef, idx, options...); err != nil {
+				return fmt.Errorf("writing index %v: %w", targetRef, err)
+			}
+		default:
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("reading image %q: %w", image.Canonical, err)
+			}
+			if err := verifyDigest(img, desc.Digest); err != nil {
+				return fmt.Errorf("image %q: %w", image.Canonical, err)
+			}
+			if err := remote.Write(targetRef, img, options...); err != nil {
+				return fmt.Errorf("writing image %v: %w", targetRef, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importAttachments pushes the cosign signatures and attestations of an image in the layout alongside the image.
+func importAttachments(index v1.ImageIndex, descriptors map[string]v1.Descriptor, image *bundleImage, targetRef name.Reference, options ...remote.Option) error {
+	canonicalRef, err := name.ParseReference(image.Canonical)
+	if err != nil {
+		return fmt.Errorf("parsing reference %q: %w", image.Canonical, err)
+	}
+	for _, tag := range image.Attachments {
+		sourceRef := canonicalRef.Context().Tag(tag)
+		desc, found := descriptors[sourceRef.String()]
+		if !found {
+			return fmt.Errorf("%v not found in bundle", sourceRef)
+		}
+		img, err := index.Image(desc.Digest)
+		if err != nil {
+			return fmt.Errorf("reading %v: %w", sourceRef, err)
+		}
+		if err := verifyDigest(img, desc.Digest); err != nil {
+			return fmt.Errorf("%v: %w", sourceRef, err)
+		}
+		attachmentRef := targetRef.Context().Tag(tag)
+		klog.Infof("importing %v to %v", sourceRef, attachmentRef)
+		if err := remote.Write(attachmentRef, img, options...); err != nil {
+			return fmt.Errorf("writing %v: %w", attachmentRef, err)
+		}
+	}
+	return nil
+}
+
+// verifyDigest checks that the manifest of an image or index in the layout matches its digest.
+// The registry verifies the digests of the blobs as they are uploaded.
+func verifyDigest(manifest interface{ Digest() (v1.Hash, error) }, expected v1.Hash) error {
+	actual, err := manifest.Digest()
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("digest %s does not match expected %s", actual, expected)
+	}
+	return nil
+}
+
+// verifyHash checks that data matches a hash.
+func verifyHash(data []byte, expected *hashing.Hash) error {
+	actual, err := expected.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(actual) {
+		return fmt.Errorf("hash %s does not match expected %s", actual.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+// addDirToTar adds the files under dir to the archive, named relative to baseDir.
+func addDirToTar(tw *tar.Writer, baseDir string, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return addFileToTar(tw, filepath.ToSlash(rel), data)
+	})
+}
+
+func addFileToTar(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name: name,
+		Mode: 0o644,
+		Size: int64(len(data)),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %w", name, err)
+	}
+	return nil
+}
+
+// extractTar extracts the regular files in an archive into dir.
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		p, err := bundleFilePath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// bundleFilePath returns the local path of a file in an extracted bundle, rejecting names outside of it.
+func bundleFilePath(dir string, name string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("invalid path %q in bundle", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
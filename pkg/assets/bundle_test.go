@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestBundleFiles(t *testing.T) {
+	ctx := context.Background()
+	vfsContext := vfs.NewTestingVFSContext()
+
+	data := []byte("#!/bin/sh\necho kubelet\n")
+	hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("hashing: %v", err)
+	}
+
+	source, err := vfsContext.BuildVfsPath("memfs://source/release/v1.29.0/bin/linux/amd64/kubelet")
+	if err != nil {
+		t.Fatalf("building path: %v", err)
+	}
+	if err := source.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("writing source: %v", err)
+	}
+
+	canonicalURL, err := url.Parse(source.Path())
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+	fileAssets := []*FileAsset{
+		{CanonicalURL: canonicalURL, DownloadURL: canonicalURL, SHAValue: hash.Hex()},
+		{CanonicalURL: canonicalURL, DownloadURL: canonicalURL, SHAValue: hash.Hex()},
+	}
+
+	var bundle bytes.Buffer
+	if err := WriteBundle(ctx, &bundle, nil, fileAssets, vfsContext, &kops.Cluster{}); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	fileRepository := "memfs://repository/"
+	assetBuilder := &AssetBuilder{
+		vfsContext:     vfsContext,
+		AssetsLocation: &kops.AssetsSpec{FileRepository: &fileRepository},
+	}
+	if err := ImportBundle(ctx, bytes.NewReader(bundle.Bytes()), assetBuilder, vfsContext, cluster); err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+
+	imported, err := vfsContext.ReadFile("memfs://repository/release/v1.29.0/bin/linux/amd64/kubelet")
+	if err != nil {
+		t.Fatalf("reading imported file: %v", err)
+	}
+	if !bytes.Equal(imported, data) {
+		t.Errorf("imported file has contents %q, expected %q", imported, data)
+	}
+	importedSHA, err := vfsContext.ReadFile("memfs://repository/release/v1.29.0/bin/linux/amd64/kubelet.sha256")
+	if err != nil {
+		t.Fatalf("reading imported sha: %v", err)
+	}
+	if string(importedSHA) != hash.Hex() {
+		t.Errorf("imported sha is %q, expected %q", importedSHA, hash.Hex())
+	}
+
+	// A bundle with modified file contents is rejected
+	tampered := rewriteBundle(t, bundle.Bytes(), "files/"+hash.Hex(), []byte("#!/bin/sh\necho evil\n"))
+	otherFileRepository := "memfs://other/"
+	assetBuilder.AssetsLocation.FileRepository = &otherFileRepository
+	err = ImportBundle(ctx, bytes.NewReader(tampered), assetBuilder, vfsContext, cluster)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("ImportBundle of tampered bundle returned error %v", err)
+	}
+
+	// Importing requires a file repository
+	assetBuilder.AssetsLocation.FileRepository = nil
+	err = ImportBundle(ctx, bytes.NewReader(bundle.Bytes()), assetBuilder, vfsContext, cluster)
+	if err == nil || !strings.Contains(err.Error(), "fileRepository must be set") {
+		t.Errorf("ImportBundle without file repository returned error %v", err)
+	}
+}
+
+func TestBundleRejectsUnsafePaths(t *testing.T) {
+	var bundle bytes.Buffer
+	tw := tar.NewWriter(&bundle)
+	if err := addFileToTar(tw, "../escape", []byte("data")); err != nil {
+		t.Fatalf("writing tar: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("writing tar: %v", err)
+	}
+
+	err := ImportBundle(context.Background(), &bundle, &AssetBuilder{}, vfs.NewTestingVFSContext(), &kops.Cluster{})
+	if err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("ImportBundle returned error %v", err)
+	}
+}
+
+// rewriteBundle returns a copy of the bundle with the contents of one file replaced.
+func rewriteBundle(t *testing.T, bundle []byte, name string, data []byte) []byte {
+	var out bytes.Buffer
+	tr := tar.NewReader(bytes.NewReader(bundle))
+	tw := tar.NewWriter(&out)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading bundle: %v", err)
+		}
+		contents, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("reading bundle: %v", err)
+		}
+		if header.Name == name {
+			contents = data
+		}
+		if err := addFileToTar(tw, header.Name, contents); err != nil {
+			t.Fatalf("writing bundle: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("writing bundle: %v", err)
+	}
+	return out.Bytes()
+}
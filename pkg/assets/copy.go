@@ -17,6 +17,7 @@ limitations under the License.
 package assets
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -32,12 +33,28 @@ type assetTask interface {
 func Copy(imageAssets []*ImageAsset, fileAssets []*FileAsset, vfsContext *vfs.VFSContext, cluster *kops.Cluster) error {
 	tasks := map[string]assetTask{}
 
+	// Verify the images before copying any of them
+	verifier, err := NewImageVerifier(context.TODO(), cluster.Spec.Assets)
+	if err != nil {
+		return err
+	}
+	if verifier != nil {
+		var images []string
+		for _, imageAsset := range imageAssets {
+			images = append(images, imageAsset.CanonicalLocation)
+		}
+		if err := verifier.VerifyImages(images); err != nil {
+			return err
+		}
+	}
+
 	for _, imageAsset := range imageAssets {
 		if imageAsset.DownloadLocation != imageAsset.CanonicalLocation {
 			copyImageTask := &CopyImage{
-				Name:        imageAsset.DownloadLocation,
-				SourceImage: imageAsset.CanonicalLocation,
-				TargetImage: imageAsset.DownloadLocation,
+				Name:            imageAsset.DownloadLocation,
+				SourceImage:     imageAsset.CanonicalLocation,
+				TargetImage:     imageAsset.DownloadLocation,
+				CopyAttachments: verifier != nil,
 			}
 
 			if existing, ok := tasks[copyImageTask.Name]; ok {
@@ -17,11 +17,15 @@ limitations under the License.
 package assets
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"k8s.io/klog/v2"
 )
@@ -32,6 +36,8 @@ type CopyImage struct {
 	Name        string
 	SourceImage string
 	TargetImage string
+	// CopyAttachments also copies the cosign signatures and attestations of the image.
+	CopyAttachments bool
 }
 
 func (e *CopyImage) Run() error {
@@ -55,6 +61,12 @@ func (e *CopyImage) Run() error {
 		return fmt.Errorf("fetching %q: %v", source, err)
 	}
 
+	if e.CopyAttachments {
+		if err := copyAttachments(desc.Digest, sourceRef, targetRef, options...); err != nil {
+			return err
+		}
+	}
+
 	targetDesc, err := remote.Get(targetRef, options...)
 	if err == nil && desc.Digest.String() == targetDesc.Digest.String() {
 		klog.Infof("no need to copy image from %v to %v", sourceRef, targetRef)
@@ -87,6 +99,29 @@ func copyImage(desc *remote.Descriptor, sourceRef name.Reference, targetRef name
 	return remote.Write(targetRef, img, options...)
 }
 
+// copyAttachments copies the cosign signatures and attestations of an image digest, if there are any.
+func copyAttachments(digest v1.Hash, sourceRef name.Reference, targetRef name.Reference, options ...remote.Option) error {
+	for _, suffix := range []string{signatureTagSuffix, attestationTagSuffix} {
+		tag := attachedTag(digest, suffix)
+		sourceAttachment := sourceRef.Context().Tag(tag)
+		targetAttachment := targetRef.Context().Tag(tag)
+
+		img, err := remote.Image(sourceAttachment, options...)
+		if err != nil {
+			var terr *transport.Error
+			if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return fmt.Errorf("fetching %v: %v", sourceAttachment, err)
+		}
+		klog.Infof("copying %v to %v", sourceAttachment, targetAttachment)
+		if err := remote.Write(targetAttachment, img, options...); err != nil {
+			return fmt.Errorf("writing %v: %v", targetAttachment, err)
+		}
+	}
+	return nil
+}
+
 func copyIndex(desc *remote.Descriptor, sourceRef name.Reference, targetRef name.Reference, options ...remote.Option) error {
 	klog.Infof("copying image index from %v to %v", sourceRef, targetRef)
 
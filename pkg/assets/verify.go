@@ -0,0 +1,357 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+)
+
+const (
+	// cosignSignatureAnnotation is the annotation of a layer of a cosign signature image holding the signature of the layer.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// cosignSignatureType is the type of the payload of a cosign signature.
+	cosignSignatureType = "cosign container image signature"
+	// inTotoPayloadType is the DSSE payload type of cosign attestations.
+	inTotoPayloadType = "application/vnd.in-toto+json"
+
+	// signatureTagSuffix and attestationTagSuffix are the suffixes of the tags cosign attaches signatures and attestations to.
+	signatureTagSuffix   = "sig"
+	attestationTagSuffix = "att"
+)
+
+// sbomPredicateTypes are the prefixes of the in-toto predicate types of SBOM attestations.
+var sbomPredicateTypes = []string{
+	"https://spdx.dev/Document",
+	"https://cyclonedx.org/bom",
+}
+
+// ImageVerifier verifies the cosign signatures of images against a set of public keys.
+type ImageVerifier struct {
+	publicKeys  []crypto.PublicKey
+	requireSBOM bool
+	options     []remote.Option
+}
+
+// NewImageVerifier builds an ImageVerifier for the image verification policy of the cluster.
+// It returns nil if the cluster does not configure image verification.
+func NewImageVerifier(ctx context.Context, spec *kops.AssetsSpec) (*ImageVerifier, error) {
+	if spec == nil || spec.ImageVerification == nil {
+		return nil, nil
+	}
+
+	v := &ImageVerifier{
+		requireSBOM: spec.ImageVerification.RequireSBOM,
+		options:     []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx)},
+	}
+	for i, s := range spec.ImageVerification.PublicKeys {
+		publicKey, err := parsePublicKey([]byte(s))
+		if err != nil {
+			return nil, fmt.Errorf("parsing imageVerification.publicKeys[%d]: %w", i, err)
+		}
+		v.publicKeys = append(v.publicKeys, publicKey)
+	}
+	if len(v.publicKeys) == 0 {
+		return nil, fmt.Errorf("imageVerification.publicKeys must not be empty")
+	}
+	return v, nil
+}
+
+// VerifyImages verifies the signatures of every image, returning an error listing the images that fail verification.
+func (v *ImageVerifier) VerifyImages(images []string) error {
+	seen := map[string]bool{}
+	var failures []string
+	for _, image := range images {
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+
+		if err := v.VerifyImage(image); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) != 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("image verification failed:\n  %s", strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+// VerifyImage verifies that an image is signed by one of the public keys,
+// and if an SBOM is required, that it has an SBOM attestation signed by one of the public keys.
+func (v *ImageVerifier) VerifyImage(image string) error {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return fmt.Errorf("%s: parsing reference: %w", image, err)
+	}
+	desc, err := remote.Head(ref, v.options...)
+	if err != nil {
+		return fmt.Errorf("%s: fetching image: %w", image, err)
+	}
+
+	if err := v.verifySignature(ref, desc.Digest); err != nil {
+		return fmt.Errorf("%s: %w", image, err)
+	}
+	if v.requireSBOM {
+		if err := v.verifySBOM(ref, desc.Digest); err != nil {
+			return fmt.Errorf("%s: %w", image, err)
+		}
+	}
+
+	klog.V(2).Infof("verified signature of image %s@%s", image, desc.Digest)
+	return nil
+}
+
+// verifySignature verifies the cosign signatures attached to an image digest.
+func (v *ImageVerifier) verifySignature(ref name.Reference, digest v1.Hash) error {
+	payloads, err := v.attachedPayloads(ref, digest, signatureTagSuffix)
+	if err != nil {
+		return err
+	}
+	if len(payloads) == 0 {
+		return fmt.Errorf("no signature found")
+	}
+
+	var errs []error
+	for _, payload := range payloads {
+		signature, err := base64.StdEncoding.DecodeString(payload.annotations[cosignSignatureAnnotation])
+		if err != nil || len(signature) == 0 {
+			errs = append(errs, fmt.Errorf("missing signature annotation"))
+			continue
+		}
+		if err := v.verifyWithAnyKey(payload.data, signature); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var simpleSigning struct {
+			Critical struct {
+				Image struct {
+					DockerManifestDigest string `json:"docker-manifest-digest"`
+				} `json:"image"`
+				Type string `json:"type"`
+			} `json:"critical"`
+		}
+		if err := json.Unmarshal(payload.data, &simpleSigning); err != nil {
+			errs = append(errs, fmt.Errorf("parsing signature payload: %w", err))
+			continue
+		}
+		if simpleSigning.Critical.Type != cosignSignatureType {
+			errs = append(errs, fmt.Errorf("unexpected signature type %q", simpleSigning.Critical.Type))
+			continue
+		}
+		if simpleSigning.Critical.Image.DockerManifestDigest != digest.String() {
+			errs = append(errs, fmt.Errorf("signature is for digest %s", simpleSigning.Critical.Image.DockerManifestDigest))
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("no valid signature found: %w", errors.Join(errs...))
+}
+
+// verifySBOM verifies that an SBOM attestation signed by one of the keys is attached to an image digest.
+func (v *ImageVerifier) verifySBOM(ref name.Reference, digest v1.Hash) error {
+	payloads, err := v.attachedPayloads(ref, digest, attestationTagSuffix)
+	if err != nil {
+		return err
+	}
+	if len(payloads) == 0 {
+		return fmt.Errorf("no SBOM attestation found")
+	}
+
+	var errs []error
+	for _, payload := range payloads {
+		var envelope struct {
+			PayloadType string `json:"payloadType"`
+			Payload     string `json:"payload"`
+			Signatures  []struct {
+				Sig string `json:"sig"`
+			} `json:"signatures"`
+		}
+		if err := json.Unmarshal(payload.data, &envelope); err != nil {
+			errs = append(errs, fmt.Errorf("parsing attestation: %w", err))
+			continue
+		}
+		if envelope.PayloadType != inTotoPayloadType {
+			continue
+		}
+		statementData, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decoding attestation payload: %w", err))
+			continue
+		}
+
+		verified := false
+		for _, s := range envelope.Signatures {
+			signature, err := base64.StdEncoding.DecodeString(s.Sig)
+			if err != nil {
+				continue
+			}
+			if err := v.verifyWithAnyKey(dssePAE(envelope.PayloadType, statementData), signature); err == nil {
+				verified = true
+				break
+			}
+		}
+		if !verified {
+			errs = append(errs, fmt.Errorf("attestation not signed by a trusted key"))
+			continue
+		}
+
+		var statement struct {
+			PredicateType string `json:"predicateType"`
+			Subject       []struct {
+				Digest map[string]string `json:"digest"`
+			} `json:"subject"`
+		}
+		if err := json.Unmarshal(statementData, &statement); err != nil {
+			errs = append(errs, fmt.Errorf("parsing attestation statement: %w", err))
+			continue
+		}
+		if !isSBOMPredicateType(statement.PredicateType) {
+			continue
+		}
+		for _, subject := range statement.Subject {
+			if subject.Digest[digest.Algorithm] == digest.Hex {
+				return nil
+			}
+		}
+		errs = append(errs, fmt.Errorf("SBOM attestation is not for digest %s", digest))
+	}
+	return fmt.Errorf("no valid SBOM attestation found: %w", errors.Join(errs...))
+}
+
+// attachedPayload is a layer of a cosign signature or attestation image.
+type attachedPayload struct {
+	data        []byte
+	annotations map[string]string
+}
+
+// attachedPayloads returns the layers of the image cosign attaches to the digest with the tag suffix.
+// It returns no payloads if there is no such image.
+func (v *ImageVerifier) attachedPayloads(ref name.Reference, digest v1.Hash, suffix string) ([]*attachedPayload, error) {
+	attachedRef := ref.Context().Tag(attachedTag(digest, suffix))
+	img, err := remote.Image(attachedRef, v.options...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching %s: %w", attachedRef, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", attachedRef, err)
+	}
+
+	var payloads []*attachedPayload
+	for _, layerDesc := range manifest.Layers {
+		layer, err := img.LayerByDigest(layerDesc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", attachedRef, err)
+		}
+		r, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", attachedRef, err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", attachedRef, err)
+		}
+		payloads = append(payloads, &attachedPayload{data: data, annotations: layerDesc.Annotations})
+	}
+	return payloads, nil
+}
+
+// attachedTag returns the tag cosign attaches signatures or attestations of a digest to.
+func attachedTag(digest v1.Hash, suffix string) string {
+	return digest.Algorithm + "-" + digest.Hex + "." + suffix
+}
+
+// verifyWithAnyKey verifies a signature of data against each of the public keys.
+func (v *ImageVerifier) verifyWithAnyKey(data []byte, signature []byte) error {
+	for _, publicKey := range v.publicKeys {
+		if verifySignature(publicKey, data, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature not made by a trusted key")
+}
+
+func verifySignature(publicKey crypto.PublicKey, data []byte, signature []byte) bool {
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		hash := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(k, hash[:], signature)
+	case *rsa.PublicKey:
+		hash := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, data, signature)
+	default:
+		return false
+	}
+}
+
+// dssePAE returns the DSSE pre-authentication encoding of a payload, which is what is signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func isSBOMPredicateType(predicateType string) bool {
+	for _, prefix := range sbomPredicateTypes {
+		if strings.HasPrefix(predicateType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	publicKey, err := pki.ParsePEMPublicKey(data)
+	if err != nil {
+		return nil, err
+	}
+	switch publicKey.Key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return publicKey.Key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey.Key)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// fakeRegistry serves manifests and blobs over the registry API.
+type fakeRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, string) {
+	r := &fakeRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, strings.TrimPrefix(server.URL, "http://")
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	p := req.URL.Path
+	var data []byte
+	var found bool
+	switch {
+	case p == "/v2/":
+		return
+	case strings.Contains(p, "/manifests/"):
+		i := strings.Index(p, "/manifests/")
+		data, found = r.manifests[p[len("/v2/"):i]+"/"+p[i+len("/manifests/"):]]
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+	case strings.Contains(p, "/blobs/"):
+		data, found = r.blobs[p[strings.Index(p, "/blobs/")+len("/blobs/"):]]
+	}
+	if !found {
+		http.Error(w, `{"errors":[{"code":"MANIFEST_UNKNOWN"}]}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", sha256Digest(data))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+func sha256Digest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+func (r *fakeRegistry) addBlob(data []byte) string {
+	digest := sha256Digest(data)
+	r.blobs[digest] = data
+	return digest
+}
+
+// addImage adds an image whose layers have the given contents and annotations, returning its digest.
+func (r *fakeRegistry) addImage(repository, tag string, layers [][]byte, annotations []map[string]string) string {
+	config := []byte("{}")
+	manifest := map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"config": map[string]any{
+			"mediaType": "application/vnd.oci.image.config.v1+json",
+			"digest":    r.addBlob(config),
+			"size":      len(config),
+		},
+	}
+	var layerDescriptors []map[string]any
+	for i, layer := range layers {
+		layerDescriptors = append(layerDescriptors, map[string]any{
+			"mediaType":   "application/vnd.dev.cosign.simplesigning.v1+json",
+			"digest":      r.addBlob(layer),
+			"size":        len(layer),
+			"annotations": annotations[i],
+		})
+	}
+	manifest["layers"] = layerDescriptors
+	data, _ := json.Marshal(manifest)
+	digest := sha256Digest(data)
+	r.manifests[repository+"/"+tag] = data
+	r.manifests[repository+"/"+digest] = data
+	return digest
+}
+
+func generateSigningKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatalf("marshaling public key: %v", err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func sign(t *testing.T, key crypto.Signer, data []byte) string {
+	hash := sha256.Sum256(data)
+	signature, err := key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+// addSignature signs an image digest the way cosign does.
+func (r *fakeRegistry) addSignature(t *testing.T, key crypto.Signer, repository string, digest string) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, repository, digest))
+	tag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	r.addImage(repository, tag, [][]byte{payload}, []map[string]string{{cosignSignatureAnnotation: sign(t, key, payload)}})
+}
+
+// addSBOMAttestation attests an SBOM for an image digest the way cosign does.
+func (r *fakeRegistry) addSBOMAttestation(t *testing.T, key crypto.Signer, digest string, predicateType string) {
+	statement := []byte(fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"%s","subject":[{"name":"image","digest":{"sha256":"%s"}}],"predicate":{}}`, predicateType, strings.TrimPrefix(digest, "sha256:")))
+	envelope, _ := json.Marshal(map[string]any{
+		"payloadType": inTotoPayloadType,
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []map[string]string{{"sig": sign(t, key, dssePAE(inTotoPayloadType, statement))}},
+	})
+	tag := strings.Replace(digest, ":", "-", 1) + ".att"
+	r.addImage("app", tag, [][]byte{envelope}, []map[string]string{nil})
+}
+
+func TestVerifyImages(t *testing.T) {
+	registry, host := newFakeRegistry(t)
+
+	key, publicKey := generateSigningKey(t)
+	otherKey, _ := generateSigningKey(t)
+
+	signedDigest := registry.addImage("app", "signed", nil, nil)
+	registry.addSignature(t, key, "app", signedDigest)
+	registry.addSBOMAttestation(t, key, signedDigest, "https://spdx.dev/Document")
+
+	registry.addImage("app", "unsigned", [][]byte{[]byte("unsigned")}, []map[string]string{nil})
+
+	otherDigest := registry.addImage("app", "other", [][]byte{[]byte("other")}, []map[string]string{nil})
+	registry.addSignature(t, otherKey, "app", otherDigest)
+
+	ctx := context.Background()
+
+	grid := []struct {
+		name        string
+		image       string
+		requireSBOM bool
+		expected    string
+	}{
+		{name: "signed", image: host + "/app:signed"},
+		{name: "signed with sbom", image: host + "/app:signed", requireSBOM: true},
+		{name: "unsigned", image: host + "/app:unsigned", expected: "no signature found"},
+		{name: "untrusted key", image: host + "/app:other", expected: "signature not made by a trusted key"},
+		{name: "missing image", image: host + "/app:missing", expected: "fetching image"},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier, err := NewImageVerifier(ctx, &kops.AssetsSpec{
+				ImageVerification: &kops.ImageVerificationSpec{
+					PublicKeys:  []string{publicKey},
+					RequireSBOM: g.requireSBOM,
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewImageVerifier: %v", err)
+			}
+			err = verifier.VerifyImages([]string{g.image})
+			if g.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error containing %q, got %v", g.expected, err)
+			}
+		})
+	}
+}
+
+func TestRemapVerifiedImage(t *testing.T) {
+	registry, host := newFakeRegistry(t)
+	key, publicKey := generateSigningKey(t)
+
+	digest := registry.addImage("app", "signed", nil, nil)
+	registry.addSignature(t, key, "app", digest)
+
+	spec := &kops.AssetsSpec{
+		ImageVerification: &kops.ImageVerificationSpec{
+			PublicKeys: []string{publicKey},
+		},
+	}
+	builder := NewAssetBuilder(vfs.Context, spec, "1.29.0", false)
+
+	image, err := builder.RemapImage(host + "/app:signed")
+	if err != nil {
+		t.Fatalf("RemapImage: %v", err)
+	}
+	expected := host + "/app:signed@" + digest
+	if image != expected {
+		t.Errorf("expected verified image to be pinned to %q, got %q", expected, image)
+	}
+	if builder.ImageAssets[0].RunLocation != expected {
+		t.Errorf("expected run location %q, got %q", expected, builder.ImageAssets[0].RunLocation)
+	}
+
+	verifier, err := NewImageVerifier(context.Background(), spec)
+	if err != nil {
+		t.Fatalf("NewImageVerifier: %v", err)
+	}
+	if err := verifier.VerifyImage(image); err != nil {
+		t.Errorf("unexpected error verifying pinned image: %v", err)
+	}
+
+	if _, err := builder.RemapImage(host + "/app:missing"); err == nil {
+		t.Errorf("expected error for an image whose digest can't be resolved")
+	}
+}
+
+func TestVerifySBOMRequired(t *testing.T) {
+	registry, host := newFakeRegistry(t)
+	key, publicKey := generateSigningKey(t)
+
+	digest := registry.addImage("app", "nosbom", nil, nil)
+	registry.addSignature(t, key, "app", digest)
+
+	verifier, err := NewImageVerifier(context.Background(), &kops.AssetsSpec{
+		ImageVerification: &kops.ImageVerificationSpec{
+			PublicKeys:  []string{publicKey},
+			RequireSBOM: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewImageVerifier: %v", err)
+	}
+	if err := verifier.VerifyImage(host + "/app:nosbom"); err == nil || !strings.Contains(err.Error(), "no SBOM attestation found") {
+		t.Errorf("expected missing SBOM error, got %v", err)
+	}
+
+	registry.addSBOMAttestation(t, key, digest, "https://slsa.dev/provenance/v0.2")
+	if err := verifier.VerifyImage(host + "/app:nosbom"); err == nil || !strings.Contains(err.Error(), "no valid SBOM attestation found") {
+		t.Errorf("expected invalid SBOM error, got %v", err)
+	}
+
+	registry.addSBOMAttestation(t, key, digest, "https://cyclonedx.org/bom")
+	if err := verifier.VerifyImage(host + "/app:nosbom"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewImageVerifier(t *testing.T) {
+	ctx := context.Background()
+
+	verifier, err := NewImageVerifier(ctx, &kops.AssetsSpec{})
+	if err != nil || verifier != nil {
+		t.Errorf("expected no verifier without imageVerification, got %v, %v", verifier, err)
+	}
+
+	if _, err := NewImageVerifier(ctx, &kops.AssetsSpec{ImageVerification: &kops.ImageVerificationSpec{}}); err == nil {
+		t.Errorf("expected error without public keys")
+	}
+
+	if _, err := NewImageVerifier(ctx, &kops.AssetsSpec{ImageVerification: &kops.ImageVerificationSpec{PublicKeys: []string{"not a key"}}}); err == nil {
+		t.Errorf("expected error for invalid public key")
+	}
+}
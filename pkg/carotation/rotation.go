@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carotation
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phase is the last completed phase of a CA rotation.
+type Phase string
+
+const (
+	// PhaseCreated means the new keypair has been created.
+	PhaseCreated Phase = "Created"
+	// PhaseStaged means the cluster has been updated to trust the new keypair.
+	PhaseStaged Phase = "Staged"
+	// PhasePromoted means the new keypair has been promoted to primary, and the cluster updated to use it.
+	PhasePromoted Phase = "Promoted"
+	// PhaseDistrusted means the previous keypairs have been distrusted, and the cluster updated to no longer trust them.
+	PhaseDistrusted Phase = "Distrusted"
+)
+
+// Rotation records the progress of the rotation of a CA keyset, so that an interrupted rotation can be resumed.
+type Rotation struct {
+	// Keyset is the name of the keyset being rotated.
+	Keyset string `json:"keyset"`
+	// NewKeypairID is the ID of the keypair replacing the previous ones.
+	NewKeypairID string `json:"newKeypairID"`
+	// PreviousKeypairIDs are the IDs of the keypairs that were trusted when the rotation started.
+	PreviousKeypairIDs []string `json:"previousKeypairIDs,omitempty"`
+	// Phase is the last completed phase.
+	Phase Phase `json:"phase"`
+	// StartedAt is when the rotation was started.
+	StartedAt metav1.Time `json:"startedAt"`
+	// UpdatedAt is when the rotation was last recorded.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+	// LastError is the error that stopped the rotation, if any.
+	LastError string `json:"lastError,omitempty"`
+}
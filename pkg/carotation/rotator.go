@@ -0,0 +1,363 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carotation
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Store records the progress of CA rotations.
+type Store interface {
+	Get(ctx context.Context, keyset string) (*Rotation, error)
+	Put(ctx context.Context, rotation *Rotation) error
+	Delete(ctx context.Context, keyset string) error
+}
+
+// Cluster applies the keystore to a cluster.
+type Cluster interface {
+	// Update updates the cloud resources to match the keystore, replaces the instances that need updating,
+	// and validates the cluster.
+	Update(ctx context.Context) error
+	// VerifyNodes returns an error unless every node runs with the configuration of its instance group,
+	// and those configurations trust the keypair of the keyset, also issuing certificates with it if primary is true.
+	VerifyNodes(ctx context.Context, keyset string, item *fi.KeysetItem, primary bool) error
+	// ExportKubeconfig exports the kubeconfig of the cluster, if requested.
+	ExportKubeconfig(ctx context.Context) error
+}
+
+// Rotator rotates a CA keyset: it creates a new keypair, stages it, promotes it, and distrusts the previous keypairs,
+// updating the cluster after each step.
+type Rotator struct {
+	KeyStore fi.CAStore
+	Store    Store
+	Cluster  Cluster
+	Out      io.Writer
+
+	// PauseForClients is true if the rotation stops after staging and after promoting the new keypair,
+	// so that clients of the cluster can be given the new certificate-authority-data and credentials.
+	PauseForClients bool
+
+	// KeyAlgorithm is the algorithm of the new CA keypair.
+	KeyAlgorithm pki.KeyAlgorithm
+}
+
+// CAKeysets returns the names of the CA keysets of the cluster.
+func CAKeysets(cluster *kops.Cluster) []string {
+	names := []string{
+		fi.CertificateIDCA,
+		"apiserver-aggregator-ca",
+		"etcd-clients-ca",
+		"etcd-clients-ca-cilium",
+	}
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		names = append(names, "etcd-manager-ca-"+etcdCluster.Name, "etcd-peers-ca-"+etcdCluster.Name)
+		if etcdCluster.Name != "main" && etcdCluster.Name != "events" {
+			names = append(names, "etcd-clients-ca-"+etcdCluster.Name)
+		}
+	}
+	return names
+}
+
+// IsCAKeyset returns true if the keyset is a CA of the cluster that can be rotated.
+func IsCAKeyset(cluster *kops.Cluster, name string) bool {
+	for _, caName := range CAKeysets(cluster) {
+		if name == caName {
+			return true
+		}
+	}
+	return false
+}
+
+// Run continues the rotation of the keyset, starting it if it is not in flight.
+// It returns true once the rotation has completed, or false if it paused for clients.
+func (r *Rotator) Run(ctx context.Context, keyset string) (bool, error) {
+	rotation, err := r.Store.Get(ctx, keyset)
+	if err != nil {
+		return false, err
+	}
+	if rotation == nil {
+		rotation, err = r.start(ctx, keyset)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		fmt.Fprintf(r.Out, "Resuming rotation of %s to keypair %s after phase %s.\n", keyset, rotation.NewKeypairID, rotation.Phase)
+	}
+
+	for {
+		var next Phase
+		var err error
+		switch rotation.Phase {
+		case PhaseCreated:
+			next, err = PhaseStaged, r.stage(ctx, rotation)
+		case PhaseStaged:
+			next, err = PhasePromoted, r.promote(ctx, rotation)
+		case PhasePromoted:
+			next, err = PhaseDistrusted, r.distrust(ctx, rotation)
+		case PhaseDistrusted:
+			if err := r.Store.Delete(ctx, keyset); err != nil {
+				return false, err
+			}
+			fmt.Fprintf(r.Out, "Rotation of %s to keypair %s completed.\n", keyset, rotation.NewKeypairID)
+			return true, nil
+		default:
+			return false, fmt.Errorf("unknown phase %q of rotation of %s", rotation.Phase, keyset)
+		}
+
+		if err != nil {
+			rotation.LastError = err.Error()
+			if putErr := r.put(ctx, rotation); putErr != nil {
+				klog.Warningf("error recording CA rotation: %v", putErr)
+			}
+			return false, fmt.Errorf("rotation of %s failed after phase %s: %w", keyset, rotation.Phase, err)
+		}
+
+		rotation.Phase = next
+		rotation.LastError = ""
+		if err := r.put(ctx, rotation); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(r.Out, "Rotation of %s reached phase %s.\n", keyset, next)
+
+		if err := r.Cluster.ExportKubeconfig(ctx); err != nil {
+			return false, err
+		}
+		if r.PauseForClients && (next == PhaseStaged || next == PhasePromoted) {
+			return false, nil
+		}
+	}
+}
+
+func (r *Rotator) put(ctx context.Context, rotation *Rotation) error {
+	rotation.UpdatedAt = metav1.Now()
+	return r.Store.Put(ctx, rotation)
+}
+
+// start creates the new keypair and records the start of the rotation.
+func (r *Rotator) start(ctx context.Context, name string) (*Rotation, error) {
+	keyset, err := r.KeyStore.FindKeyset(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("reading keyset %s: %w", name, err)
+	}
+	if keyset == nil || keyset.Primary == nil {
+		return nil, fmt.Errorf("keyset %s not found", name)
+	}
+
+	rotation := &Rotation{
+		Keyset:    name,
+		Phase:     PhaseCreated,
+		StartedAt: metav1.Now(),
+	}
+	for id, item := range keyset.Items {
+		if item.DistrustTimestamp == nil {
+			rotation.PreviousKeypairIDs = append(rotation.PreviousKeypairIDs, id)
+		}
+	}
+	sort.Strings(rotation.PreviousKeypairIDs)
+
+	privateKey, err := pki.GeneratePrivateKeyWithAlgorithm(r.KeyAlgorithm)
+	if err != nil {
+		return nil, fmt.Errorf("generating private key: %w", err)
+	}
+	serial := pki.BuildPKISerial(time.Now().UnixNano())
+	req := pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: name, SerialNumber: serial.String()},
+		Serial:     serial,
+		PrivateKey: privateKey,
+	}
+	cert, _, _, err := pki.IssueCert(ctx, &req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("issuing certificate: %w", err)
+	}
+	item, err := keyset.AddItem(cert, privateKey, false)
+	if err != nil {
+		return nil, err
+	}
+	rotation.NewKeypairID = item.Id
+
+	// The rotation is recorded first, so that a failure to store the keyset does not leave a keypair behind unknown
+	if err := r.put(ctx, rotation); err != nil {
+		return nil, err
+	}
+	if err := r.KeyStore.StoreKeyset(ctx, name, keyset); err != nil {
+		if deleteErr := r.Store.Delete(ctx, name); deleteErr != nil {
+			klog.Warningf("error deleting CA rotation: %v", deleteErr)
+		}
+		return nil, fmt.Errorf("storing keyset %s: %w", name, err)
+	}
+	fmt.Fprintf(r.Out, "Created %s keypair %s.\n", name, item.Id)
+	return rotation, nil
+}
+
+// stage updates the cluster to trust the new keypair.
+func (r *Rotator) stage(ctx context.Context, rotation *Rotation) error {
+	if _, err := r.findItem(ctx, rotation); err != nil {
+		return err
+	}
+	return r.Cluster.Update(ctx)
+}
+
+// promote makes the new keypair the primary, once every node trusts it, and updates the cluster to use it.
+func (r *Rotator) promote(ctx context.Context, rotation *Rotation) error {
+	keyset, err := r.findItem(ctx, rotation)
+	if err != nil {
+		return err
+	}
+	if keyset.Primary == nil || keyset.Primary.Id != rotation.NewKeypairID {
+		if err := r.Cluster.VerifyNodes(ctx, rotation.Keyset, keyset.Items[rotation.NewKeypairID], false); err != nil {
+			return fmt.Errorf("refusing to promote the new keypair: %w", err)
+		}
+		keyset.Primary = keyset.Items[rotation.NewKeypairID]
+		if err := r.KeyStore.StoreKeyset(ctx, rotation.Keyset, keyset); err != nil {
+			return fmt.Errorf("storing keyset %s: %w", rotation.Keyset, err)
+		}
+		fmt.Fprintf(r.Out, "Promoted %s keypair %s.\n", rotation.Keyset, rotation.NewKeypairID)
+	}
+	return r.Cluster.Update(ctx)
+}
+
+// distrust distrusts the previous keypairs, once every node uses the new one, and updates the cluster.
+func (r *Rotator) distrust(ctx context.Context, rotation *Rotation) error {
+	keyset, err := r.findItem(ctx, rotation)
+	if err != nil {
+		return err
+	}
+	if keyset.Primary == nil || keyset.Primary.Id != rotation.NewKeypairID {
+		return fmt.Errorf("keypair %s is no longer the primary of %s", rotation.NewKeypairID, rotation.Keyset)
+	}
+	if err := r.Cluster.VerifyNodes(ctx, rotation.Keyset, keyset.Primary, true); err != nil {
+		return fmt.Errorf("refusing to distrust the previous keypairs: %w", err)
+	}
+
+	changed := false
+	for _, id := range rotation.PreviousKeypairIDs {
+		item := keyset.Items[id]
+		if item == nil || item.DistrustTimestamp != nil || id == rotation.NewKeypairID {
+			continue
+		}
+		now := time.Now().UTC().Round(0)
+		item.DistrustTimestamp = &now
+		changed = true
+	}
+	if changed {
+		if err := r.KeyStore.StoreKeyset(ctx, rotation.Keyset, keyset); err != nil {
+			return fmt.Errorf("storing keyset %s: %w", rotation.Keyset, err)
+		}
+		fmt.Fprintf(r.Out, "Distrusted %s keypairs %s.\n", rotation.Keyset, strings.Join(rotation.PreviousKeypairIDs, ", "))
+	}
+	return r.Cluster.Update(ctx)
+}
+
+// findItem reads the keyset, and checks the new keypair is still trusted.
+func (r *Rotator) findItem(ctx context.Context, rotation *Rotation) (*fi.Keyset, error) {
+	keyset, err := r.KeyStore.FindKeyset(ctx, rotation.Keyset)
+	if err != nil {
+		return nil, fmt.Errorf("reading keyset %s: %w", rotation.Keyset, err)
+	}
+	if keyset == nil {
+		return nil, fmt.Errorf("keyset %s not found", rotation.Keyset)
+	}
+	item := keyset.Items[rotation.NewKeypairID]
+	if item == nil {
+		return nil, fmt.Errorf("keypair %s of %s not found", rotation.NewKeypairID, rotation.Keyset)
+	}
+	if item.DistrustTimestamp != nil {
+		return nil, fmt.Errorf("keypair %s of %s is distrusted", rotation.NewKeypairID, rotation.Keyset)
+	}
+	return keyset, nil
+}
+
+// CheckNodesUpdated returns an error unless every node is an instance of a cloud group
+// that does not need updating to the current configuration of its instance group.
+func CheckNodesUpdated(groups map[string]*cloudinstances.CloudInstanceGroup, nodes []v1.Node) error {
+	ready := make(map[string]bool)
+	var problems []string
+	for _, group := range groups {
+		for _, instance := range group.Ready {
+			if instance.Node != nil {
+				ready[instance.Node.Name] = true
+			}
+		}
+		for _, instance := range group.NeedUpdate {
+			problems = append(problems, fmt.Sprintf("instance %s of %s needs updating", instance.ID, group.HumanName))
+		}
+	}
+	for _, node := range nodes {
+		if !ready[node.Name] {
+			problems = append(problems, fmt.Sprintf("node %s is not an up-to-date instance of an instance group", node.Name))
+		}
+	}
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("not every node runs with the new CA bundle: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CheckCABundles returns an error unless the nodeup configuration of every instance group given the keyset
+// trusts the keypair, and also issues certificates with it if primary is true.
+// The configurations are keyed by the name of their instance group.
+func CheckCABundles(keyset string, item *fi.KeysetItem, configs map[string]*nodeup.Config, primary bool) error {
+	if item.Certificate == nil {
+		return fmt.Errorf("keypair %s of %s has no certificate", item.Id, keyset)
+	}
+	certificate, err := item.Certificate.AsString()
+	if err != nil {
+		return fmt.Errorf("encoding certificate of keypair %s of %s: %w", item.Id, keyset, err)
+	}
+
+	found := false
+	var problems []string
+	for name, config := range configs {
+		bundle, ok := config.CAs[keyset]
+		if !ok {
+			continue
+		}
+		found = true
+		if !strings.Contains(bundle, certificate) {
+			problems = append(problems, fmt.Sprintf("instance group %s does not trust keypair %s", name, item.Id))
+		}
+		if primary && config.KeypairIDs[keyset] != item.Id {
+			problems = append(problems, fmt.Sprintf("instance group %s does not issue certificates with keypair %s", name, item.Id))
+		}
+	}
+	if !found {
+		return fmt.Errorf("no instance group is given the CA bundle of %s", keyset)
+	}
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("not every instance group is given the new CA bundle: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carotation
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+type fakeStore struct {
+	rotations map[string]Rotation
+}
+
+func (s *fakeStore) Get(ctx context.Context, keyset string) (*Rotation, error) {
+	rotation, found := s.rotations[keyset]
+	if !found {
+		return nil, nil
+	}
+	return &rotation, nil
+}
+
+func (s *fakeStore) Put(ctx context.Context, rotation *Rotation) error {
+	s.rotations[rotation.Keyset] = *rotation
+	return nil
+}
+
+func (s *fakeStore) Delete(ctx context.Context, keyset string) error {
+	delete(s.rotations, keyset)
+	return nil
+}
+
+type fakeCluster struct {
+	keyStore   fi.CAStore
+	updates    []string
+	verified   []string
+	nodesError error
+}
+
+// Update records the trusted and primary keypairs of the keyset the cluster is updated to.
+func (c *fakeCluster) Update(ctx context.Context) error {
+	keyset, err := c.keyStore.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		return err
+	}
+	trusted := 0
+	for _, item := range keyset.Items {
+		if item.DistrustTimestamp == nil {
+			trusted++
+		}
+	}
+	c.updates = append(c.updates, fmt.Sprintf("trusted=%d primary=%s", trusted, keyset.Primary.Id))
+	return nil
+}
+
+// VerifyNodes records the keypair the nodes are verified to trust, or to use if primary.
+func (c *fakeCluster) VerifyNodes(ctx context.Context, keyset string, item *fi.KeysetItem, primary bool) error {
+	c.verified = append(c.verified, fmt.Sprintf("%s/%s primary=%t", keyset, item.Id, primary))
+	return c.nodesError
+}
+
+func (c *fakeCluster) ExportKubeconfig(ctx context.Context) error {
+	return nil
+}
+
+func newTestKeyStore(t *testing.T) (fi.CAStore, string) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests/pki")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+	keyStore := fi.NewVFSCAStore(&kops.Cluster{}, basePath)
+
+	privateKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+	cert, _, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: "kubernetes-ca"},
+		Serial:     pki.BuildPKISerial(1),
+		PrivateKey: privateKey,
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	keyset, err := fi.NewKeyset(cert, privateKey)
+	if err != nil {
+		t.Fatalf("error building keyset: %v", err)
+	}
+	if err := keyStore.StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error storing keyset: %v", err)
+	}
+	return keyStore, keyset.Primary.Id
+}
+
+func TestRotatorRun(t *testing.T) {
+	ctx := context.TODO()
+	keyStore, previousID := newTestKeyStore(t)
+	store := &fakeStore{rotations: map[string]Rotation{}}
+	cluster := &fakeCluster{keyStore: keyStore}
+	rotator := &Rotator{
+		KeyStore:        keyStore,
+		Store:           store,
+		Cluster:         cluster,
+		Out:             io.Discard,
+		PauseForClients: true,
+	}
+
+	done, err := rotator.Run(ctx, "kubernetes-ca")
+	assert.NoError(t, err)
+	assert.False(t, done)
+	rotation := store.rotations["kubernetes-ca"]
+	assert.Equal(t, PhaseStaged, rotation.Phase)
+	assert.Equal(t, []string{previousID}, rotation.PreviousKeypairIDs)
+	newID := rotation.NewKeypairID
+	assert.NotEqual(t, previousID, newID)
+	assert.Equal(t, []string{"trusted=2 primary=" + previousID}, cluster.updates)
+
+	done, err = rotator.Run(ctx, "kubernetes-ca")
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, PhasePromoted, store.rotations["kubernetes-ca"].Phase)
+	assert.Equal(t, []string{"kubernetes-ca/" + newID + " primary=false"}, cluster.verified)
+
+	cluster.nodesError = fmt.Errorf("node a is not up to date")
+	_, err = rotator.Run(ctx, "kubernetes-ca")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "refusing to distrust the previous keypairs: node a is not up to date")
+	}
+	rotation = store.rotations["kubernetes-ca"]
+	assert.Equal(t, PhasePromoted, rotation.Phase)
+	assert.Contains(t, rotation.LastError, "node a is not up to date")
+
+	cluster.nodesError = nil
+	done, err = rotator.Run(ctx, "kubernetes-ca")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Empty(t, store.rotations)
+	assert.Equal(t, []string{
+		"trusted=2 primary=" + previousID,
+		"trusted=2 primary=" + newID,
+		"trusted=1 primary=" + newID,
+	}, cluster.updates)
+
+	keyset, err := keyStore.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	assert.NotNil(t, keyset.Items[previousID].DistrustTimestamp)
+	assert.Nil(t, keyset.Items[newID].DistrustTimestamp)
+	assert.Equal(t, "kubernetes-ca/"+newID+" primary=true", cluster.verified[len(cluster.verified)-1])
+}
+
+func TestRotatorRefusesToPromoteUntrustedKeypair(t *testing.T) {
+	ctx := context.TODO()
+	keyStore, previousID := newTestKeyStore(t)
+	store := &fakeStore{rotations: map[string]Rotation{}}
+	cluster := &fakeCluster{keyStore: keyStore}
+	rotator := &Rotator{
+		KeyStore:        keyStore,
+		Store:           store,
+		Cluster:         cluster,
+		Out:             io.Discard,
+		PauseForClients: true,
+	}
+
+	_, err := rotator.Run(ctx, "kubernetes-ca")
+	assert.NoError(t, err)
+
+	cluster.nodesError = fmt.Errorf("node a is not up to date")
+	_, err = rotator.Run(ctx, "kubernetes-ca")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "refusing to promote the new keypair: node a is not up to date")
+	}
+	assert.Equal(t, PhaseStaged, store.rotations["kubernetes-ca"].Phase)
+
+	keyset, err := keyStore.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	assert.Equal(t, previousID, keyset.Primary.Id)
+}
+
+func TestRotatorRunWithoutPause(t *testing.T) {
+	ctx := context.TODO()
+	keyStore, _ := newTestKeyStore(t)
+	store := &fakeStore{rotations: map[string]Rotation{}}
+	cluster := &fakeCluster{keyStore: keyStore}
+	rotator := &Rotator{KeyStore: keyStore, Store: store, Cluster: cluster, Out: io.Discard}
+
+	done, err := rotator.Run(ctx, "kubernetes-ca")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Len(t, cluster.updates, 3)
+
+	_, err = rotator.Run(ctx, "missing-ca")
+	assert.Error(t, err)
+}
+
+func TestIsCAKeyset(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			EtcdClusters: []kops.EtcdClusterSpec{{Name: "main"}, {Name: "events"}, {Name: "cilium"}},
+		},
+	}
+	for _, name := range []string{"kubernetes-ca", "apiserver-aggregator-ca", "etcd-clients-ca", "etcd-manager-ca-main", "etcd-peers-ca-events", "etcd-clients-ca-cilium"} {
+		assert.True(t, IsCAKeyset(cluster, name), name)
+	}
+	for _, name := range []string{"all", "service-account", "kubelet", "etcd-manager-ca-other", "etcd-clients-ca-main", "my-ca-bundle"} {
+		assert.False(t, IsCAKeyset(cluster, name), name)
+	}
+}
+
+func TestCheckCABundles(t *testing.T) {
+	ctx := context.TODO()
+	keyStore, previousID := newTestKeyStore(t)
+	keyset, err := keyStore.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	item := keyset.Items[previousID]
+	bundle, err := keyset.ToCertificateBytes()
+	if err != nil {
+		t.Fatalf("error encoding keyset: %v", err)
+	}
+
+	configs := map[string]*nodeup.Config{
+		"control-plane": {
+			CAs:        map[string]string{"kubernetes-ca": string(bundle)},
+			KeypairIDs: map[string]string{"kubernetes-ca": previousID},
+		},
+		"nodes": {
+			CAs:        map[string]string{"kubernetes-ca": string(bundle)},
+			KeypairIDs: map[string]string{"kubernetes-ca": "1"},
+		},
+	}
+	assert.NoError(t, CheckCABundles("kubernetes-ca", item, configs, false))
+
+	err = CheckCABundles("kubernetes-ca", item, configs, true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "instance group nodes does not issue certificates with keypair "+previousID)
+	}
+
+	configs["nodes"].CAs["kubernetes-ca"] = ""
+	err = CheckCABundles("kubernetes-ca", item, configs, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "instance group nodes does not trust keypair "+previousID)
+	}
+
+	err = CheckCABundles("etcd-clients-ca", item, configs, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no instance group is given the CA bundle of etcd-clients-ca")
+	}
+}
+
+func TestCheckNodesUpdated(t *testing.T) {
+	node := func(name string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	groups := map[string]*cloudinstances.CloudInstanceGroup{
+		"nodes": {
+			HumanName: "nodes",
+			Ready:     []*cloudinstances.CloudInstance{{ID: "i-a", Node: node("a")}},
+		},
+	}
+	assert.NoError(t, CheckNodesUpdated(groups, []v1.Node{*node("a")}))
+
+	err := CheckNodesUpdated(groups, []v1.Node{*node("a"), *node("b")})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "node b is not an up-to-date instance of an instance group")
+	}
+
+	groups["nodes"].NeedUpdate = []*cloudinstances.CloudInstance{{ID: "i-c", Node: node("c")}}
+	err = CheckNodesUpdated(groups, []v1.Node{*node("a")})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "instance i-c of nodes needs updating")
+	}
+}
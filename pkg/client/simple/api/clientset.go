@@ -28,9 +28,11 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/carotation"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/instancegroups/journal"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
@@ -68,6 +70,82 @@ func (c *RESTClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return nil
 }
 
+// RollingUpdateJournalFor fetches the RollingUpdateJournalClient for the cluster.
+// The journal is not stored in the kubernetes API, so progress is not recorded and rolling updates cannot be resumed.
+func (c *RESTClientset) RollingUpdateJournalFor(cluster *kops.Cluster) simple.RollingUpdateJournalClient {
+	return &noopRollingUpdateJournal{}
+}
+
+// noopRollingUpdateJournal is a RollingUpdateJournalClient which records nothing.
+type noopRollingUpdateJournal struct{}
+
+var _ simple.RollingUpdateJournalClient = &noopRollingUpdateJournal{}
+
+// Get implements RollingUpdateJournalClient::Get
+func (n *noopRollingUpdateJournal) Get(ctx context.Context) (*journal.Journal, error) {
+	return nil, nil
+}
+
+// Put implements RollingUpdateJournalClient::Put
+func (n *noopRollingUpdateJournal) Put(ctx context.Context, j *journal.Journal) error {
+	return nil
+}
+
+// Delete implements RollingUpdateJournalClient::Delete
+func (n *noopRollingUpdateJournal) Delete(ctx context.Context) error {
+	return nil
+}
+
+// InstanceGroupRevisionsFor fetches the InstanceGroupRevisionsClient for the cluster.
+// Revisions are not stored in the kubernetes API, so failed rolling updates cannot be rolled back.
+func (c *RESTClientset) InstanceGroupRevisionsFor(cluster *kops.Cluster) simple.InstanceGroupRevisionsClient {
+	return &noopInstanceGroupRevisions{}
+}
+
+// noopInstanceGroupRevisions is an InstanceGroupRevisionsClient which records no revisions.
+type noopInstanceGroupRevisions struct{}
+
+var _ simple.InstanceGroupRevisionsClient = &noopInstanceGroupRevisions{}
+
+// List implements InstanceGroupRevisionsClient::List
+func (n *noopInstanceGroupRevisions) List(ctx context.Context, name string) ([]*kops.InstanceGroup, error) {
+	return nil, nil
+}
+
+// Record implements InstanceGroupRevisionsClient::Record
+func (n *noopInstanceGroupRevisions) Record(ctx context.Context, ig *kops.InstanceGroup) error {
+	return nil
+}
+
+// CARotationsFor fetches the CARotationClient for the cluster.
+// The progress of CA rotations is not stored in the kubernetes API; as a rotation that cannot be resumed
+// would start again with another keypair, CA rotations are refused rather than run without recording progress.
+func (c *RESTClientset) CARotationsFor(cluster *kops.Cluster) simple.CARotationClient {
+	return &unsupportedCARotations{}
+}
+
+// unsupportedCARotations is a CARotationClient which fails, as the progress of CA rotations cannot be recorded.
+type unsupportedCARotations struct{}
+
+var _ simple.CARotationClient = &unsupportedCARotations{}
+
+var errCARotationsUnsupported = fmt.Errorf("CA rotations are not supported with a kubernetes-API state store")
+
+// Get implements CARotationClient::Get
+func (u *unsupportedCARotations) Get(ctx context.Context, keyset string) (*carotation.Rotation, error) {
+	return nil, errCARotationsUnsupported
+}
+
+// Put implements CARotationClient::Put
+func (u *unsupportedCARotations) Put(ctx context.Context, rotation *carotation.Rotation) error {
+	return errCARotationsUnsupported
+}
+
+// Delete implements CARotationClient::Delete
+func (u *unsupportedCARotations) Delete(ctx context.Context, keyset string) error {
+	return errCARotationsUnsupported
+}
+
 // CreateCluster implements the CreateCluster method of Clientset for a kubernetes-API state store
 func (c *RESTClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
 	namespace := restNamespaceForClusterName(cluster.Name)
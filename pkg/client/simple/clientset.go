@@ -21,7 +21,9 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/carotation"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/pkg/instancegroups/journal"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
@@ -52,6 +54,15 @@ type Clientset interface {
 	// AddonsFor returns the client for addon objects for a particular Cluster
 	AddonsFor(cluster *kops.Cluster) AddonsClient
 
+	// RollingUpdateJournalFor returns the client for the rolling update progress journal of a particular Cluster
+	RollingUpdateJournalFor(cluster *kops.Cluster) RollingUpdateJournalClient
+
+	// InstanceGroupRevisionsFor returns the client for the previously applied revisions of the InstanceGroups of a particular Cluster
+	InstanceGroupRevisionsFor(cluster *kops.Cluster) InstanceGroupRevisionsClient
+
+	// CARotationsFor returns the client for the progress of the CA rotations of a particular Cluster
+	CARotationsFor(cluster *kops.Cluster) CARotationClient
+
 	// SecretStore builds the secret store for the specified cluster
 	SecretStore(cluster *kops.Cluster) (fi.SecretStore, error)
 
@@ -74,3 +85,37 @@ type AddonsClient interface {
 	// List returns all the addon objects
 	List(ctx context.Context) (kubemanifest.ObjectList, error)
 }
+
+// RollingUpdateJournalClient is a client for the progress journal of an in-flight rolling update
+type RollingUpdateJournalClient interface {
+	// Get returns the journal, or nil if no rolling update is in flight
+	Get(ctx context.Context) (*journal.Journal, error)
+
+	// Put writes the journal
+	Put(ctx context.Context, j *journal.Journal) error
+
+	// Delete removes the journal, once the rolling update has completed
+	Delete(ctx context.Context) error
+}
+
+// InstanceGroupRevisionsClient is a client for the previously applied revisions of InstanceGroups
+type InstanceGroupRevisionsClient interface {
+	// List returns the applied revisions of the named InstanceGroup, newest first
+	List(ctx context.Context, name string) ([]*kops.InstanceGroup, error)
+
+	// Record records the InstanceGroup as the newest applied revision, with the time it was applied as its creation timestamp,
+	// if its spec differs from the newest revision
+	Record(ctx context.Context, ig *kops.InstanceGroup) error
+}
+
+// CARotationClient is a client for the progress of in-flight CA rotations
+type CARotationClient interface {
+	// Get returns the progress of the rotation of the keyset, or nil if no rotation is in flight
+	Get(ctx context.Context, keyset string) (*carotation.Rotation, error)
+
+	// Put writes the progress of a rotation
+	Put(ctx context.Context, rotation *carotation.Rotation) error
+
+	// Delete removes the progress of the rotation of the keyset, once the rotation has completed
+	Delete(ctx context.Context, keyset string) error
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/carotation"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+type vfsCARotationClient struct {
+	basePath vfs.Path
+
+	cluster *kops.Cluster
+}
+
+var _ simple.CARotationClient = &vfsCARotationClient{}
+
+func newCARotationVFS(c *VFSClientset, cluster *kops.Cluster) *vfsCARotationClient {
+	if cluster == nil || cluster.Name == "" {
+		klog.Fatalf("cluster / cluster.Name is required")
+	}
+
+	return &vfsCARotationClient{
+		cluster:  cluster,
+		basePath: c.basePath.Join(cluster.Name, "carotation"),
+	}
+}
+
+func (c *vfsCARotationClient) Get(ctx context.Context, keyset string) (*carotation.Rotation, error) {
+	p := c.basePath.Join(keyset)
+
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading CA rotation %s: %w", p, err)
+	}
+
+	r := &carotation.Rotation{}
+	if err := yaml.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("error parsing CA rotation %s: %w", p, err)
+	}
+	return r, nil
+}
+
+func (c *vfsCARotationClient) Put(ctx context.Context, r *carotation.Rotation) error {
+	p := c.basePath.Join(r.Keyset)
+
+	b, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("error serializing CA rotation: %w", err)
+	}
+
+	acl, err := acls.GetACL(ctx, p, c.cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := p.WriteFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return fmt.Errorf("error writing CA rotation %s: %w", p, err)
+	}
+	return nil
+}
+
+func (c *vfsCARotationClient) Delete(ctx context.Context, keyset string) error {
+	p := c.basePath.Join(keyset)
+
+	if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting CA rotation %s: %w", p, err)
+	}
+	return nil
+}
@@ -91,6 +91,18 @@ func (c *VFSClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsVFS(c, cluster)
 }
 
+func (c *VFSClientset) RollingUpdateJournalFor(cluster *kops.Cluster) simple.RollingUpdateJournalClient {
+	return newRollingUpdateJournalVFS(c, cluster)
+}
+
+func (c *VFSClientset) CARotationsFor(cluster *kops.Cluster) simple.CARotationClient {
+	return newCARotationVFS(c, cluster)
+}
+
+func (c *VFSClientset) InstanceGroupRevisionsFor(cluster *kops.Cluster) simple.InstanceGroupRevisionsClient {
+	return newInstanceGroupRevisionsVFS(c, cluster)
+}
+
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	if cluster.Spec.ConfigStore.Secrets == "" {
 		configBase, err := registry.ConfigBase(c.VFSContext(), cluster)
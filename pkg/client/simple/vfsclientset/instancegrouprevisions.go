@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/util/pkg/text"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// maxInstanceGroupRevisions is the number of applied revisions we keep for each InstanceGroup
+const maxInstanceGroupRevisions = 5
+
+type vfsInstanceGroupRevisionsClient struct {
+	basePath vfs.Path
+
+	cluster *kops.Cluster
+}
+
+var _ simple.InstanceGroupRevisionsClient = &vfsInstanceGroupRevisionsClient{}
+
+func newInstanceGroupRevisionsVFS(c *VFSClientset, cluster *kops.Cluster) *vfsInstanceGroupRevisionsClient {
+	if cluster == nil || cluster.Name == "" {
+		klog.Fatalf("cluster / cluster.Name is required")
+	}
+
+	return &vfsInstanceGroupRevisionsClient{
+		cluster:  cluster,
+		basePath: c.basePath.Join(cluster.Name, "instancegroup-revisions"),
+	}
+}
+
+func (c *vfsInstanceGroupRevisionsClient) List(ctx context.Context, name string) ([]*kops.InstanceGroup, error) {
+	sections, err := c.read(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*kops.InstanceGroup
+	for _, section := range sections {
+		ig, err := c.decode(name, section)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, ig)
+	}
+	return revisions, nil
+}
+
+// decode parses a serialized revision of the named InstanceGroup
+func (c *vfsInstanceGroupRevisionsClient) decode(name string, section []byte) (*kops.InstanceGroup, error) {
+	o, _, err := kopscodecs.Decode(section, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing revision of InstanceGroup %q: %w", name, err)
+	}
+	ig, ok := o.(*kops.InstanceGroup)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type in revisions of InstanceGroup %q: %T", name, o)
+	}
+	return ig, nil
+}
+
+// sameRevisionSpec returns true if the InstanceGroups have the same spec once serialized
+func sameRevisionSpec(a, b *kops.InstanceGroup) (bool, error) {
+	var specs [][]byte
+	for _, ig := range []*kops.InstanceGroup{a, b} {
+		data, err := kopscodecs.ToVersionedYaml(&kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: ig.Name},
+			Spec:       ig.Spec,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error serializing InstanceGroup %q: %w", ig.Name, err)
+		}
+		specs = append(specs, data)
+	}
+	return bytes.Equal(specs[0], specs[1]), nil
+}
+
+func (c *vfsInstanceGroupRevisionsClient) Record(ctx context.Context, ig *kops.InstanceGroup) error {
+	sections, err := c.read(ctx, ig.Name)
+	if err != nil {
+		return err
+	}
+	if len(sections) != 0 {
+		newest, err := c.decode(ig.Name, sections[0])
+		if err != nil {
+			return err
+		}
+		same, err := sameRevisionSpec(newest, ig)
+		if err != nil {
+			return err
+		}
+		if same {
+			return nil
+		}
+	}
+
+	// We only keep the spec and the time it was applied, which rolling updates compare with the creation time of Nodes;
+	// the rest of the metadata is owned by the InstanceGroup itself
+	revision := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: ig.Name, CreationTimestamp: metav1.Now()},
+		Spec:       ig.Spec,
+	}
+	data, err := kopscodecs.ToVersionedYaml(revision)
+	if err != nil {
+		return fmt.Errorf("error serializing InstanceGroup %q: %w", ig.Name, err)
+	}
+	data = bytes.TrimSpace(data)
+
+	sections = append([][]byte{data}, sections...)
+	if len(sections) > maxInstanceGroupRevisions {
+		sections = sections[:maxInstanceGroupRevisions]
+	}
+
+	p := c.basePath.Join(ig.Name)
+	acl, err := acls.GetACL(ctx, p, c.cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := p.WriteFile(ctx, bytes.NewReader(bytes.Join(sections, []byte("\n---\n"))), acl); err != nil {
+		return fmt.Errorf("error writing revisions of InstanceGroup %q: %w", ig.Name, err)
+	}
+	return nil
+}
+
+// read returns the serialized revisions of the named InstanceGroup, newest first
+func (c *vfsInstanceGroupRevisionsClient) read(ctx context.Context, name string) ([][]byte, error) {
+	p := c.basePath.Join(name)
+
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading revisions of InstanceGroup %q: %w", name, err)
+	}
+
+	var sections [][]byte
+	for _, section := range text.SplitContentToSections(b) {
+		section = bytes.TrimSpace(section)
+		if len(section) != 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections, nil
+}
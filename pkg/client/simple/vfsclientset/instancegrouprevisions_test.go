@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestInstanceGroupRevisions(t *testing.T) {
+	ctx := context.Background()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building base path: %v", err)
+	}
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test.k8s.local"}}
+	client := NewVFSClientset(vfs.Context, basePath).InstanceGroupRevisionsFor(cluster)
+
+	revisions, err := client.List(ctx, "nodes")
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Fatalf("expected no revisions, got %d", len(revisions))
+	}
+
+	record := func(image string) {
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodes", ResourceVersion: image},
+			Spec: kops.InstanceGroupSpec{
+				Role:  kops.InstanceGroupRoleNode,
+				Image: image,
+			},
+		}
+		if err := client.Record(ctx, ig); err != nil {
+			t.Fatalf("error recording revision: %v", err)
+		}
+	}
+
+	record("image-1")
+	// Recording an unchanged spec does not add a revision
+	record("image-1")
+	record("image-2")
+
+	revisions, err = client.List(ctx, "nodes")
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	var images []string
+	for _, revision := range revisions {
+		images = append(images, revision.Spec.Image)
+		if revision.Name != "nodes" {
+			t.Errorf("unexpected revision name %q", revision.Name)
+		}
+		if revision.ResourceVersion != "" {
+			t.Errorf("unexpected metadata in revision: %v", revision.ObjectMeta)
+		}
+		if revision.CreationTimestamp.IsZero() {
+			t.Errorf("revision has no creation timestamp")
+		}
+	}
+	if fmt.Sprint(images) != "[image-2 image-1]" {
+		t.Errorf("unexpected revisions %v", images)
+	}
+
+	for i := 3; i <= 10; i++ {
+		record(fmt.Sprintf("image-%d", i))
+	}
+	revisions, err = client.List(ctx, "nodes")
+	if err != nil {
+		t.Fatalf("error listing revisions: %v", err)
+	}
+	if len(revisions) != maxInstanceGroupRevisions {
+		t.Errorf("expected %d revisions, got %d", maxInstanceGroupRevisions, len(revisions))
+	}
+	if revisions[0].Spec.Image != "image-10" {
+		t.Errorf("expected newest revision first, got %q", revisions[0].Spec.Image)
+	}
+}
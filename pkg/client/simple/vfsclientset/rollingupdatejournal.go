@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/instancegroups/journal"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+type vfsRollingUpdateJournalClient struct {
+	basePath vfs.Path
+
+	cluster *kops.Cluster
+}
+
+var _ simple.RollingUpdateJournalClient = &vfsRollingUpdateJournalClient{}
+
+func newRollingUpdateJournalVFS(c *VFSClientset, cluster *kops.Cluster) *vfsRollingUpdateJournalClient {
+	if cluster == nil || cluster.Name == "" {
+		klog.Fatalf("cluster / cluster.Name is required")
+	}
+
+	return &vfsRollingUpdateJournalClient{
+		cluster:  cluster,
+		basePath: c.basePath.Join(cluster.Name, "rollingupdate"),
+	}
+}
+
+func (c *vfsRollingUpdateJournalClient) Get(ctx context.Context) (*journal.Journal, error) {
+	p := c.basePath.Join("journal")
+
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update journal %s: %w", p, err)
+	}
+
+	j := &journal.Journal{}
+	if err := yaml.Unmarshal(b, j); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update journal %s: %w", p, err)
+	}
+	return j, nil
+}
+
+func (c *vfsRollingUpdateJournalClient) Put(ctx context.Context, j *journal.Journal) error {
+	p := c.basePath.Join("journal")
+
+	b, err := yaml.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("error serializing rolling update journal: %w", err)
+	}
+
+	acl, err := acls.GetACL(ctx, p, c.cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := p.WriteFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return fmt.Errorf("error writing rolling update journal %s: %w", p, err)
+	}
+	return nil
+}
+
+func (c *vfsRollingUpdateJournalClient) Delete(ctx context.Context) error {
+	p := c.basePath.Join("journal")
+
+	if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting rolling update journal %s: %w", p, err)
+	}
+	return nil
+}
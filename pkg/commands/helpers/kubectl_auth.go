@@ -261,7 +261,8 @@ func buildCredentials(ctx context.Context, f *util.Factory, options *HelperKubec
 
 			Organization: []string{rbac.SystemPrivilegedGroup},
 		},
-		Validity: options.Lifetime,
+		Validity:     options.Lifetime,
+		KeyAlgorithm: pki.KeyAlgorithm(cluster.KeyAlgorithm()),
 	}
 	cert, privateKey, _, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(keyStore))
 	if err != nil {
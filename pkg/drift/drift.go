@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// Kind is the kind of difference between the cloud resources and the model of the cluster.
+type Kind string
+
+const (
+	// KindModified is a resource whose fields differ from the model.
+	KindModified Kind = "Modified"
+	// KindMissing is a resource of the model that was not found.
+	KindMissing Kind = "Missing"
+	// KindExtra is a resource that kOps would delete.
+	KindExtra Kind = "Extra"
+	// KindUnmanaged is a resource tagged as belonging to the cluster that no task of the model manages.
+	KindUnmanaged Kind = "Unmanaged"
+)
+
+// Drift is a difference between a cloud resource and the model of the cluster.
+type Drift struct {
+	Kind Kind   `json:"kind"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// ID is the cloud ID of an unmanaged resource.
+	ID string `json:"id,omitempty"`
+	// Fields are the fields of a modified resource that differ, or the fields of a missing resource.
+	Fields []fi.PlanField `json:"fields,omitempty"`
+}
+
+// unmanagedResourceTypes are the types of resources that belong to the cluster,
+// but are created by cloud groups or by controllers running in the cluster rather than by tasks.
+var unmanagedResourceTypes = map[string]bool{
+	// AWS
+	"instance":          true,
+	"network-interface": true,
+	"route53-record":    true,
+	// GCE
+	"Instance":  true,
+	"Route":     true,
+	"DNSRecord": true,
+}
+
+// Detect returns the differences between the cloud resources and the model of the cluster,
+// from the plan of a dry run of its tasks and from the resources tagged as belonging to the cluster.
+func Detect(plan *fi.Plan, taskMap map[string]fi.CloudupTask, cloudResources map[string]*resources.Resource) []Drift {
+	drifts := planDrifts(plan)
+
+	claimed := make(map[string]bool)
+	for key, task := range taskMap {
+		if i := strings.Index(key, "/"); i != -1 {
+			claimed[key[i+1:]] = true
+		}
+		for _, id := range taskIdentifiers(task) {
+			claimed[id] = true
+		}
+	}
+	for _, r := range cloudResources {
+		if r.Shared || unmanagedResourceTypes[r.Type] {
+			continue
+		}
+		if claimed[r.ID] || (r.Name != "" && claimed[r.Name]) {
+			continue
+		}
+		drifts = append(drifts, Drift{
+			Kind: KindUnmanaged,
+			Type: r.Type,
+			Name: r.Name,
+			ID:   r.ID,
+		})
+	}
+
+	sortDrifts(drifts)
+	return drifts
+}
+
+// FromPlan returns the differences recorded in the plan of a dry run,
+// for example of the nodeup tasks against the host they run on.
+func FromPlan(plan *fi.Plan) []Drift {
+	drifts := planDrifts(plan)
+	sortDrifts(drifts)
+	return drifts
+}
+
+func planDrifts(plan *fi.Plan) []Drift {
+	var drifts []Drift
+
+	for _, change := range plan.Changes {
+		kind := KindModified
+		if change.Action == fi.PlanActionCreate {
+			kind = KindMissing
+		}
+		drifts = append(drifts, Drift{
+			Kind:   kind,
+			Type:   change.Type,
+			Name:   strings.TrimPrefix(change.Key, change.Type+"/"),
+			Fields: change.Fields,
+		})
+	}
+
+	for _, deletion := range plan.Deletions {
+		drifts = append(drifts, Drift{
+			Kind: KindExtra,
+			Type: deletion.Type,
+			Name: deletion.Item,
+		})
+	}
+
+	return drifts
+}
+
+func sortDrifts(drifts []Drift) {
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if drifts[i].Kind != drifts[j].Kind {
+			return drifts[i].Kind < drifts[j].Kind
+		}
+		if drifts[i].Type != drifts[j].Type {
+			return drifts[i].Type < drifts[j].Type
+		}
+		if drifts[i].Name != drifts[j].Name {
+			return drifts[i].Name < drifts[j].Name
+		}
+		return drifts[i].ID < drifts[j].ID
+	})
+}
+
+// taskIdentifiers returns the values of the Name and ID fields of a task.
+// Finding a task usually sets its ID to the ID of the cloud resource.
+func taskIdentifiers(task fi.CloudupTask) []string {
+	v := reflect.ValueOf(task)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var ids []string
+	for _, name := range []string{"Name", "ID"} {
+		f := v.FieldByName(name)
+		if !f.IsValid() {
+			continue
+		}
+		if f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				continue
+			}
+			f = f.Elem()
+		}
+		if f.Kind() == reflect.String && f.String() != "" {
+			ids = append(ids, f.String())
+		}
+	}
+	return ids
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestDetect(t *testing.T) {
+	taskMap := map[string]fi.CloudupTask{
+		"VPC/test.k8s.local":                      &awstasks.VPC{Name: fi.PtrTo("test.k8s.local"), ID: fi.PtrTo("vpc-1")},
+		"SecurityGroup/nodes.test.k8s.local":      &awstasks.SecurityGroup{Name: fi.PtrTo("nodes.test.k8s.local"), ID: fi.PtrTo("sg-1")},
+		"EBSVolume/a.etcd-main.test.k8s.local":    &awstasks.EBSVolume{Name: fi.PtrTo("a.etcd-main.test.k8s.local")},
+		"LaunchTemplate/nodes.test.k8s.local":     &awstasks.LaunchTemplate{Name: fi.PtrTo("nodes.test.k8s.local")},
+		"InternetGateway/test.k8s.local":          &awstasks.InternetGateway{Name: fi.PtrTo("test.k8s.local")},
+		"AutoscalingGroup/nodes.test.k8s.local":   &awstasks.AutoscalingGroup{Name: fi.PtrTo("nodes.test.k8s.local")},
+		"EBSVolume/a.etcd-events.test.k8s.local":  &awstasks.EBSVolume{Name: fi.PtrTo("a.etcd-events.test.k8s.local")},
+		"SecurityGroup/masters.test.k8s.local":    &awstasks.SecurityGroup{Name: fi.PtrTo("masters.test.k8s.local")},
+		"LaunchTemplate/masters.test.k8s.local":   &awstasks.LaunchTemplate{Name: fi.PtrTo("masters.test.k8s.local")},
+		"AutoscalingGroup/masters.test.k8s.local": &awstasks.AutoscalingGroup{Name: fi.PtrTo("masters.test.k8s.local")},
+	}
+	plan := &fi.Plan{
+		Changes: []fi.PlanChange{
+			{
+				Key:    "SecurityGroup/nodes.test.k8s.local",
+				Type:   "SecurityGroup",
+				Action: fi.PlanActionModify,
+				Fields: []fi.PlanField{{Name: "Description", Before: "changed", After: "Security group for nodes"}},
+			},
+			{
+				Key:    "InternetGateway/test.k8s.local",
+				Type:   "InternetGateway",
+				Action: fi.PlanActionCreate,
+			},
+		},
+		Deletions: []fi.PlanDeletion{
+			{Type: "SecurityGroupRule", Item: "sgr-1", Action: fi.PlanActionDelete},
+		},
+	}
+	cloudResources := map[string]*resources.Resource{
+		"vpc:vpc-1":         {Type: "vpc", ID: "vpc-1", Name: "test.k8s.local"},
+		"security-group:1":  {Type: "security-group", ID: "sg-1", Name: "nodes"},
+		"security-group:2":  {Type: "security-group", ID: "sg-2", Name: "masters.test.k8s.local"},
+		"security-group:3":  {Type: "security-group", ID: "sg-3", Name: "debugging"},
+		"volume:1":          {Type: "volume", ID: "vol-1", Name: "a.etcd-main.test.k8s.local"},
+		"volume:2":          {Type: "volume", ID: "vol-2", Name: "backup"},
+		"instance:1":        {Type: "instance", ID: "i-1", Name: "nodes.test.k8s.local"},
+		"subnet:1":          {Type: "subnet", ID: "subnet-1", Name: "shared", Shared: true},
+		"route53-record:1":  {Type: "route53-record", ID: "api.test.k8s.local"},
+		"network-interface": {Type: "network-interface", ID: "eni-1"},
+	}
+
+	assert.Equal(t, []Drift{
+		{Kind: KindExtra, Type: "SecurityGroupRule", Name: "sgr-1"},
+		{Kind: KindMissing, Type: "InternetGateway", Name: "test.k8s.local"},
+		{
+			Kind:   KindModified,
+			Type:   "SecurityGroup",
+			Name:   "nodes.test.k8s.local",
+			Fields: []fi.PlanField{{Name: "Description", Before: "changed", After: "Security group for nodes"}},
+		},
+		{Kind: KindUnmanaged, Type: "security-group", Name: "debugging", ID: "sg-3"},
+		{Kind: KindUnmanaged, Type: "volume", Name: "backup", ID: "vol-2"},
+	}, Detect(plan, taskMap, cloudResources))
+
+	assert.Empty(t, Detect(&fi.Plan{}, taskMap, nil))
+}
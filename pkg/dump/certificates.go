@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// certificateDirs are the directories on nodes where kOps installs certificates.
+var certificateDirs = []string{
+	"/etc/kubernetes",
+	"/srv/kubernetes",
+	"/var/lib/kubelet",
+	"/var/lib/kube-proxy",
+}
+
+// NodeCertificate is a certificate installed on a node.
+type NodeCertificate struct {
+	// Node is the name of the node, or its address if it is not registered.
+	Node string
+	// Path is the path of the file holding the certificate.
+	Path string
+	// Certificate is the parsed certificate.
+	Certificate *x509.Certificate
+}
+
+// CertificateCollector collects the certificates installed on nodes over SSH.
+type CertificateCollector struct {
+	sshClientFactory sshClientFactory
+}
+
+// NewCertificateCollector is the constructor for a CertificateCollector
+func NewCertificateCollector(bastionAddress string, sshConfig *ssh.ClientConfig, keyRing agent.Agent) *CertificateCollector {
+	return &CertificateCollector{
+		sshClientFactory: &sshClientFactoryImplementation{
+			bastion:   bastionAddress,
+			sshConfig: sshConfig,
+			keyRing:   keyRing,
+		},
+	}
+}
+
+// CollectCertificates connects to every node and collects the certificates installed on it.
+// As with DumpAllNodes, instances in additionalIPs and additionalPrivateIPs that are not registered as nodes are also visited.
+// Failures to reach a node are returned as errors, and do not stop the collection.
+func (c *CertificateCollector) CollectCertificates(ctx context.Context, nodes corev1.NodeList, additionalIPs, additionalPrivateIPs []string) ([]*NodeCertificate, []error) {
+	var certificates []*NodeCertificate
+	var errors []error
+
+	var visited []*corev1.Node
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		ip, useBastion := nodeAddress(node)
+		nodeCertificates, err := c.collectNode(ctx, node.Name, ip, useBastion)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("collecting certificates from node %s: %w", node.Name, err))
+			continue
+		}
+		certificates = append(certificates, nodeCertificates...)
+		visited = append(visited, node)
+	}
+
+	for _, ips := range []struct {
+		ips        []string
+		useBastion bool
+	}{
+		{ips: additionalIPs},
+		{ips: additionalPrivateIPs, useBastion: true},
+	} {
+		for _, ip := range findInstancesNotDumped(ips.ips, visited) {
+			nodeCertificates, err := c.collectNode(ctx, ip, ip, ips.useBastion)
+			if err != nil {
+				errors = append(errors, fmt.Errorf("collecting certificates from instance %s: %w", ip, err))
+				continue
+			}
+			certificates = append(certificates, nodeCertificates...)
+		}
+	}
+
+	return certificates, errors
+}
+
+// collectNode connects to a node and collects the certificates installed on it.
+func (c *CertificateCollector) collectNode(ctx context.Context, name string, ip string, useBastion bool) ([]*NodeCertificate, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if ip == "" {
+		return nil, fmt.Errorf("could not find address for %v", name)
+	}
+
+	log.Printf("collecting certificates from node %s", name)
+
+	client, err := c.sshClientFactory.Dial(ctx, ip, useBastion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to SSH to %q: %w", ip, err)
+	}
+	defer client.Close()
+
+	var dirs []string
+	for _, dir := range certificateDirs {
+		dirs = append(dirs, quoteShell(dir))
+	}
+	var stdout, stderr bytes.Buffer
+	command := "for d in " + strings.Join(dirs, " ") + "; do if [ -d \"$d\" ]; then sudo find \"$d\" -type f -name '*.crt' -print0; fi; done"
+	if err := client.ExecPiped(ctx, command, &stdout, &stderr); err != nil {
+		return nil, fmt.Errorf("error listing certificates: %w: %s", err, stderr.String())
+	}
+
+	var certificates []*NodeCertificate
+	for _, b := range bytes.Split(stdout.Bytes(), []byte{0}) {
+		if len(b) == 0 {
+			continue
+		}
+		path := string(b)
+
+		var data, stderr bytes.Buffer
+		if err := client.ExecPiped(ctx, "sudo cat "+quoteShell(path), &data, &stderr); err != nil {
+			return nil, fmt.Errorf("error reading %q: %w: %s", path, err, stderr.String())
+		}
+		for _, certificate := range parseCertificates(data.Bytes()) {
+			certificates = append(certificates, &NodeCertificate{
+				Node:        name,
+				Path:        path,
+				Certificate: certificate,
+			})
+		}
+	}
+	return certificates, nil
+}
+
+// parseCertificates returns the certificates in PEM data, ignoring anything else.
+func parseCertificates(data []byte) []*x509.Certificate {
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certificates
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Printf("ignoring unparseable certificate: %v", err)
+			continue
+		}
+		certificates = append(certificates, certificate)
+	}
+}
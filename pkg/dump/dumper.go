@@ -184,6 +184,13 @@ func (d *logDumper) dumpRegistered(ctx context.Context, node *corev1.Node) error
 		return ctx.Err()
 	}
 
+	ip, useBastion := nodeAddress(node)
+	return d.dumpNode(ctx, node.Name, ip, useBastion)
+}
+
+// nodeAddress returns the address to connect to the node at,
+// and whether the connection must be made through the bastion.
+func nodeAddress(node *corev1.Node) (string, bool) {
 	var publicIP, privateIP string
 	for _, address := range node.Status.Addresses {
 		if address.Type == "ExternalIP" {
@@ -197,10 +204,9 @@ func (d *logDumper) dumpRegistered(ctx context.Context, node *corev1.Node) error
 	}
 
 	if publicIP != "" {
-		return d.dumpNode(ctx, node.Name, publicIP, false)
-	} else {
-		return d.dumpNode(ctx, node.Name, privateIP, true)
+		return publicIP, false
 	}
+	return privateIP, true
 }
 
 func (d *logDumper) dumpNotRegistered(ctx context.Context, ip string, useBastion bool) error {
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+const providerAWSKMS = "aws-kms"
+
+// awsKMS wraps data keys with an AWS KMS key.
+type awsKMS struct {
+	keyARN string
+	region string
+}
+
+var _ KMS = &awsKMS{}
+
+func newAWSKMS(keyARN string) (*awsKMS, error) {
+	parsed, err := arn.Parse(keyARN)
+	if err != nil {
+		return nil, fmt.Errorf("parsing AWS KMS key ARN %q: %w", keyARN, err)
+	}
+	return &awsKMS{keyARN: keyARN, region: parsed.Region}, nil
+}
+
+func (k *awsKMS) Provider() string {
+	return providerAWSKMS
+}
+
+func (k *awsKMS) KeyID() string {
+	return k.keyARN
+}
+
+func (k *awsKMS) client() (*kms.KMS, error) {
+	config := aws.NewConfig().WithCredentialsChainVerboseErrors(true).WithRegion(k.region)
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("starting new AWS session: %w", err)
+	}
+	return kms.New(sess, config), nil
+}
+
+func (k *awsKMS) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	client, err := k.client()
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(k.keyARN),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.CiphertextBlob, nil
+}
+
+func (k *awsKMS) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	client, err := k.client()
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(k.keyARN),
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope implements envelope encryption of the private keys and secrets kOps keeps in the state store.
+//
+// Each value is encrypted with AES-256-GCM under a random data key. The data key is wrapped by a
+// key encryption key held in a KMS, and stored alongside the ciphertext in a PEM block whose headers
+// identify the KMS provider and key. The headers are only used to choose between the configured keys:
+// data recording any other key is not decrypted, because the state store may have been tampered with.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const (
+	pemType = "KOPS ENVELOPE"
+
+	headerProvider   = "Provider"
+	headerKeyID      = "Key-Id"
+	headerWrappedKey = "Wrapped-Key"
+
+	dataKeySize = 32
+)
+
+// KMS wraps and unwraps data keys with a key encryption key.
+type KMS interface {
+	// Provider is the name of the KMS provider, recorded in the encrypted data.
+	Provider() string
+	// KeyID identifies the key encryption key, and is recorded in the encrypted data.
+	KeyID() string
+	// WrapKey encrypts a data key.
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key encrypted by WrapKey.
+	UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// NewKMS returns the KMS configured in the spec.
+func NewKMS(spec *kops.StateStoreEncryptionSpec) (KMS, error) {
+	return newKeyKMS(&kops.StateStoreEncryptionKeySpec{
+		AWSKMSKeyARN:  spec.AWSKMSKeyARN,
+		GCPKMSKeyName: spec.GCPKMSKeyName,
+		Vault:         spec.Vault,
+		KeyFile:       spec.KeyFile,
+	})
+}
+
+// newKeyKMS returns the KMS for a key encryption key.
+func newKeyKMS(key *kops.StateStoreEncryptionKeySpec) (KMS, error) {
+	switch {
+	case key.AWSKMSKeyARN != "":
+		return newAWSKMS(key.AWSKMSKeyARN)
+	case key.GCPKMSKeyName != "":
+		return newGCPKMS(key.GCPKMSKeyName), nil
+	case key.Vault != nil:
+		return newVaultKMS(key.Vault.Address, key.Vault.MountPath, key.Vault.KeyName), nil
+	case key.KeyFile != "":
+		return newFileKMS(key.KeyFile), nil
+	default:
+		return nil, fmt.Errorf("no key encryption key configured")
+	}
+}
+
+// findKMS returns the KMS of the configured or previous key with the provider and key ID.
+func findKMS(spec *kops.StateStoreEncryptionSpec, provider, keyID string) (KMS, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("data is encrypted with %s key %q, but state store encryption is not configured", provider, keyID)
+	}
+	kms, err := NewKMS(spec)
+	if err != nil {
+		return nil, err
+	}
+	if kms.Provider() == provider && kms.KeyID() == keyID {
+		return kms, nil
+	}
+	for i := range spec.PreviousKeys {
+		kms, err := newKeyKMS(&spec.PreviousKeys[i])
+		if err != nil {
+			return nil, err
+		}
+		if kms.Provider() == provider && kms.KeyID() == keyID {
+			return kms, nil
+		}
+	}
+	return nil, fmt.Errorf("data is encrypted with %s key %q, which is not a configured key encryption key", provider, keyID)
+}
+
+// ForCluster returns the KMS the cluster's private keys and secrets are encrypted with, or nil if they are not encrypted.
+func ForCluster(cluster *kops.Cluster) (KMS, error) {
+	if cluster == nil || cluster.Spec.StateStoreEncryption == nil {
+		return nil, nil
+	}
+	return NewKMS(cluster.Spec.StateStoreEncryption)
+}
+
+// IsEncrypted returns true if the data was encrypted by Encrypt.
+func IsEncrypted(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN "+pemType+"-----")) {
+		return false
+	}
+	block, _ := pem.Decode(data)
+	return block != nil && block.Type == pemType
+}
+
+// IsEncryptedWith returns true if the data was encrypted by Encrypt with the configured key of the spec,
+// rather than with one of its previous keys.
+func IsEncryptedWith(spec *kops.StateStoreEncryptionSpec, data []byte) bool {
+	if !IsEncrypted(data) {
+		return false
+	}
+	kms, err := NewKMS(spec)
+	if err != nil {
+		return false
+	}
+	block, _ := pem.Decode(data)
+	return block.Headers[headerProvider] == kms.Provider() && block.Headers[headerKeyID] == kms.KeyID()
+}
+
+// Encrypt encrypts the data under a new data key wrapped by the KMS.
+func Encrypt(ctx context.Context, kms KMS, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("generating data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	wrappedKey, err := kms.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key with %s key %q: %w", kms.Provider(), kms.KeyID(), err)
+	}
+
+	block := &pem.Block{
+		Type: pemType,
+		Headers: map[string]string{
+			headerProvider:   kms.Provider(),
+			headerKeyID:      kms.KeyID(),
+			headerWrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		},
+		Bytes: aead.Seal(nonce, nonce, plaintext, nil),
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt, with the key of the spec recorded in the data.
+// The recorded key must be the configured key or one of its previous keys.
+// Data that is not encrypted is returned unchanged.
+func Decrypt(ctx context.Context, spec *kops.StateStoreEncryptionSpec, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	block, _ := pem.Decode(data)
+
+	kms, err := findKMS(spec, block.Headers[headerProvider], block.Headers[headerKeyID])
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(block.Headers[headerWrappedKey])
+	if err != nil {
+		return nil, fmt.Errorf("decoding wrapped data key: %w", err)
+	}
+	dataKey, err := kms.UnwrapKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key with %s key %q: %w", kms.Provider(), kms.KeyID(), err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Bytes) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted data is truncated")
+	}
+	nonce, ciphertext := block.Bytes[:aead.NonceSize()], block.Bytes[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
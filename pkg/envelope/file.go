@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+)
+
+const providerFile = "file"
+
+// fileKMS wraps data keys with a key read from a local file.
+type fileKMS struct {
+	path string
+}
+
+var _ KMS = &fileKMS{}
+
+func newFileKMS(path string) *fileKMS {
+	return &fileKMS{path: path}
+}
+
+func (k *fileKMS) Provider() string {
+	return providerFile
+}
+
+func (k *fileKMS) KeyID() string {
+	return k.path
+}
+
+func (k *fileKMS) readKey() ([]byte, error) {
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key file %q: %w", k.path, err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key in file %q is %d bytes, expected %d", k.path, len(key), dataKeySize)
+	}
+	return key, nil
+}
+
+func (k *fileKMS) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	key, err := k.readKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+func (k *fileKMS) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	key, err := k.readKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is truncated")
+	}
+	return aead.Open(nil, wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():], nil)
+}
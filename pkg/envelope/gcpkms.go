@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/api/cloudkms/v1"
+)
+
+const providerGCPKMS = "gcp-kms"
+
+// gcpKMS wraps data keys with a GCP Cloud KMS key.
+type gcpKMS struct {
+	keyName string
+}
+
+var _ KMS = &gcpKMS{}
+
+func newGCPKMS(keyName string) *gcpKMS {
+	return &gcpKMS{keyName: keyName}
+}
+
+func (k *gcpKMS) Provider() string {
+	return providerGCPKMS
+}
+
+func (k *gcpKMS) KeyID() string {
+	return k.keyName
+}
+
+func (k *gcpKMS) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("building Cloud KMS client: %w", err)
+	}
+	response, err := service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(k.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Ciphertext)
+}
+
+func (k *gcpKMS) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	service, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("building Cloud KMS client: %w", err)
+	}
+	response, err := service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(k.keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrappedKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Plaintext)
+}
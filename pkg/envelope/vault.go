@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const providerVault = "vault-transit"
+
+// vaultRequestTimeout is the maximum duration of a request to Vault.
+const vaultRequestTimeout = 30 * time.Second
+
+// vaultKMS wraps data keys with a key of a HashiCorp Vault transit secrets engine.
+type vaultKMS struct {
+	address   string
+	mountPath string
+	keyName   string
+	client    *http.Client
+}
+
+var _ KMS = &vaultKMS{}
+
+func newVaultKMS(address, mountPath, keyName string) *vaultKMS {
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+	return &vaultKMS{
+		address:   strings.TrimSuffix(address, "/"),
+		mountPath: strings.Trim(mountPath, "/"),
+		keyName:   keyName,
+		client:    &http.Client{Timeout: vaultRequestTimeout},
+	}
+}
+
+func (k *vaultKMS) Provider() string {
+	return providerVault
+}
+
+func (k *vaultKMS) KeyID() string {
+	return k.address + "/v1/" + k.mountPath + "/keys/" + k.keyName
+}
+
+func (k *vaultKMS) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := k.post(ctx, "encrypt", request, &response); err != nil {
+		return nil, err
+	}
+	return []byte(response.Data.Ciphertext), nil
+}
+
+func (k *vaultKMS) UnwrapKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"ciphertext": string(wrappedKey)}
+	if err := k.post(ctx, "decrypt", request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+// post calls an operation of the transit secrets engine on our key.
+func (k *vaultKMS) post(ctx context.Context, operation string, request any, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	url := k.address + "/v1/" + k.mountPath + "/" + operation + "/" + k.keyName
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+
+	httpResponse, err := k.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("reading response from %s: %w", url, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from %s: %s: %s", url, httpResponse.Status, strings.TrimSpace(string(responseBody)))
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return fmt.Errorf("parsing response from %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// CanaryFailedError represents an error that occurs when the cluster fails to validate
+// after the canary instances of an instance group were replaced.
+type CanaryFailedError struct {
+	// InstanceGroup is the name of the instance group.
+	InstanceGroup string
+	// InstanceIDs are the IDs of the canary instances that were replaced.
+	InstanceIDs []string
+
+	err error
+}
+
+func (e *CanaryFailedError) Error() string {
+	return fmt.Sprintf("canary of InstanceGroup %q failed after replacing instances %s: %v", e.InstanceGroup, strings.Join(e.InstanceIDs, ", "), e.err)
+}
+
+func (e *CanaryFailedError) Unwrap() error {
+	return e.err
+}
+
+// rollCanaries replaces the canary instances of the group, one at a time,
+// then validates the cluster continuously for the soak duration.
+func (c *RollingUpdateCluster) rollCanaries(group *cloudinstances.CloudInstanceGroup, canaries []*cloudinstances.CloudInstance, soakDuration time.Duration, sleepAfterTerminate time.Duration) error {
+	var ids []string
+	for _, u := range canaries {
+		ids = append(ids, u.ID)
+	}
+	canaryFailed := func(err error) error {
+		return &CanaryFailedError{
+			InstanceGroup: group.InstanceGroup.Name,
+			InstanceIDs:   ids,
+			err:           err,
+		}
+	}
+
+	klog.Infof("Replacing canary instances %s of InstanceGroup %q.", strings.Join(ids, ", "), group.InstanceGroup.Name)
+
+	for _, u := range canaries {
+		if err := c.drainTerminateAndWait(u, sleepAfterTerminate); err != nil {
+			return err
+		}
+
+		if err := c.maybeValidate(" after terminating canary instance", c.ValidateCount, group); err != nil {
+			return canaryFailed(err)
+		}
+
+		if err := c.runAfterValidateHooks(group); err != nil {
+			return err
+		}
+	}
+
+	if err := c.soakCanaries(group, soakDuration); err != nil {
+		return canaryFailed(err)
+	}
+
+	klog.Infof("Canary instances of InstanceGroup %q passed the soak period.", group.InstanceGroup.Name)
+	return nil
+}
+
+// soakCanaries validates the cluster continuously for the soak duration, failing on the first validation failure.
+func (c *RollingUpdateCluster) soakCanaries(group *cloudinstances.CloudInstanceGroup, soakDuration time.Duration) error {
+	if c.CloudOnly {
+		klog.Warningf("Not soaking canary instances of InstanceGroup %q as 'cloudonly' flag is set.", group.InstanceGroup.Name)
+		return nil
+	}
+
+	klog.Infof("Validating the cluster for %v to soak the canary instances of InstanceGroup %q.", soakDuration, group.InstanceGroup.Name)
+
+	deadline := time.Now().Add(soakDuration)
+	for {
+		result, err := c.ClusterValidator.Validate(c.Ctx)
+		if err == nil && hasFailureRelevantToGroup(result.Failures, group) {
+			var messages []string
+			for _, failure := range result.Failures {
+				messages = append(messages, failure.Message)
+			}
+			err = fmt.Errorf("cluster failed validation during soak: %s", strings.Join(messages, ", "))
+		}
+		if err != nil {
+			if c.FailOnValidate {
+				return err
+			}
+			klog.Warningf("Cluster validation failed while soaking canary instances, proceeding since fail-on-validate is set to false: %v", err)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(min(remaining, c.ValidateTickDuration))
+		select {
+		case <-c.Ctx.Done():
+			timer.Stop()
+			return c.Ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
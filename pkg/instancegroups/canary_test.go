@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/validation"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// failAfterCallsClusterValidator passes validation for a number of calls, then fails every validation.
+type failAfterCallsClusterValidator struct {
+	PassingCalls int
+	calls        int
+}
+
+func (v *failAfterCallsClusterValidator) Validate(ctx context.Context) (*validation.ValidationCluster, error) {
+	v.calls++
+	if v.calls <= v.PassingCalls {
+		return &validation.ValidationCluster{}, nil
+	}
+	return &validation.ValidationCluster{
+		Failures: []*validation.ValidationError{
+			{
+				Kind:    "testing",
+				Name:    "testingfailure",
+				Message: "testing failure",
+			},
+		},
+	}, nil
+}
+
+func canaryRollingUpdate(count intstr.IntOrString) *kopsapi.RollingUpdate {
+	return &kopsapi.RollingUpdate{
+		Strategy: kopsapi.RollingUpdateStrategyCanary,
+		Canary: &kopsapi.RollingUpdateCanary{
+			Count:        &count,
+			SoakDuration: &metav1.Duration{Duration: 20 * time.Millisecond},
+		},
+	}
+}
+
+func TestRollingUpdateCanary(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(1))
+	validator := &failAfterCallsClusterValidator{PassingCalls: 1000}
+	c.ClusterValidator = validator
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	// 1 initial validation, 2 after the canary, at least 2 while soaking, 2 for each of the remaining instances
+	assert.GreaterOrEqual(t, validator.calls, 1+2+2+2*2, "validation calls")
+}
+
+func TestRollingUpdateCanaryFailsDuringSoak(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(1))
+	// Passes the initial validation and the validations after replacing the canary
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 3}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+
+	var canaryErr *CanaryFailedError
+	if assert.True(t, errors.As(err, &canaryErr), "canary error, got %v", err) {
+		assert.Equal(t, "node-1", canaryErr.InstanceGroup)
+		assert.Equal(t, []string{"node-1a"}, canaryErr.InstanceIDs)
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+}
+
+func TestRollingUpdateCanaryPercentage(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromString("50%"))
+	// Passes the initial validation and the validations after replacing the first of the two canaries
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 3}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 4, 4)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+
+	var canaryErr *CanaryFailedError
+	if assert.True(t, errors.As(err, &canaryErr), "canary error, got %v", err) {
+		assert.Equal(t, []string{"node-1a", "node-1b"}, canaryErr.InstanceIDs)
+	}
+
+	// No instances were replaced after the cluster failed validation after the second canary
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateCanaryCoversGroup(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(3))
+	validator := &failAfterCallsClusterValidator{PassingCalls: 1000}
+	c.ClusterValidator = validator
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	// 1 initial validation, 2 after each of the 2 canaries, at least 2 while soaking
+	assert.GreaterOrEqual(t, validator.calls, 1+2*2+2, "validation calls")
+}
+
+func TestRollingUpdateCanaryFailsDuringSoakOfGroup(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(3))
+	// Passes the initial validation and the validations after replacing both canaries
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 5}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+
+	var canaryErr *CanaryFailedError
+	if assert.True(t, errors.As(err, &canaryErr), "canary error, got %v", err) {
+		assert.Equal(t, []string{"node-1a", "node-1b"}, canaryErr.InstanceIDs)
+	}
+}
+
+func TestRollingUpdateCanarySoakCancelled(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(1))
+	c.Cluster.Spec.RollingUpdate.Canary.SoakDuration = &metav1.Duration{Duration: time.Hour}
+	c.ValidateTickDuration = time.Hour
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 1000}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Ctx = ctx
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Only the canary was replaced
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestCanarySettings(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		Spec: kopsapi.ClusterSpec{
+			RollingUpdate: &kopsapi.RollingUpdate{
+				Strategy: kopsapi.RollingUpdateStrategyCanary,
+			},
+		},
+	}
+	group := &kopsapi.InstanceGroup{}
+
+	resolved := resolveSettings(cluster, group, 10)
+	assert.Equal(t, kopsapi.RollingUpdateStrategyCanary, resolved.Strategy)
+	assert.Equal(t, intstr.FromInt(1), *resolved.Canary.Count)
+	assert.Equal(t, 10*time.Minute, resolved.Canary.SoakDuration.Duration)
+
+	group.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Canary: &kopsapi.RollingUpdateCanary{
+			Count: fi.PtrTo(intstr.FromString("25%")),
+		},
+	}
+	resolved = resolveSettings(cluster, group, 10)
+	assert.Equal(t, intstr.FromInt(3), *resolved.Canary.Count)
+	assert.Nil(t, group.Spec.RollingUpdate.Canary.SoakDuration, "group not modified")
+
+	resolved = resolveSettings(&kopsapi.Cluster{}, &kopsapi.InstanceGroup{}, 10)
+	assert.Equal(t, kopsapi.RollingUpdateStrategyRolling, resolved.Strategy)
+	assert.Nil(t, resolved.Canary)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+// statefulSetReadyPollInterval is the interval at which we check whether a StatefulSet has recovered after a drain.
+var statefulSetReadyPollInterval = 5 * time.Second
+
+// rollingUpdateNodeGroups rolls the node instance groups, running up to Options.MaxConcurrentNodeGroups of them at once.
+// Groups are started in name order; when Options.OneGroupPerZone is set, a group is not started while
+// another group sharing one of its zones is still being rolled.
+// The results map is updated with the outcome of every group that was started.
+func (c *RollingUpdateCluster) rollingUpdateNodeGroups(nodeGroups map[string]*cloudinstances.CloudInstanceGroup, results map[string]error) error {
+	maxConcurrent := c.Options.MaxConcurrentNodeGroups
+	if maxConcurrent < 1 || c.Interactive {
+		maxConcurrent = 1
+	}
+
+	zones := make(map[string][]string)
+	if c.Options.OneGroupPerZone {
+		for k, group := range nodeGroups {
+			groupZones, err := model.FindZonesForInstanceGroup(c.Cluster, group.InstanceGroup)
+			if err != nil {
+				return err
+			}
+			zones[k] = groupZones
+		}
+	}
+
+	if maxConcurrent > 1 && !c.CloudOnly {
+		// Pods of the same StatefulSet could otherwise be evicted from nodes in different groups at the same time.
+		c.statefulSetGuard = newStatefulSetGuard()
+		defer func() {
+			c.statefulSetGuard = nil
+		}()
+	}
+
+	type groupResult struct {
+		name string
+		err  error
+	}
+	done := make(chan groupResult)
+
+	pending := sortGroups(nodeGroups)
+	running := make(map[string]bool)
+	var exitErr error
+	for {
+		if exitErr == nil {
+			for i := 0; i < len(pending) && len(running) < maxConcurrent; {
+				k := pending[i]
+				if zonesInUse(zones, running, k) {
+					i++
+					continue
+				}
+				pending = append(pending[:i], pending[i+1:]...)
+				running[k] = true
+
+				klog.Infof("Starting rolling update of InstanceGroup %q (%d of at most %d concurrently).", k, len(running), maxConcurrent)
+				go func(k string) {
+					done <- groupResult{name: k, err: c.rollingUpdateInstanceGroup(nodeGroups[k], c.NodeInterval)}
+				}(k)
+			}
+		}
+
+		if len(running) == 0 {
+			break
+		}
+
+		r := <-done
+		delete(running, r.name)
+		results[r.name] = r.err
+		if r.err != nil {
+			klog.Errorf("failed to roll InstanceGroup %q: %v", r.name, r.err)
+		}
+
+		// We stop starting new groups, but let the in-progress groups finish.
+		if isExitableError(r.err) && exitErr == nil {
+			exitErr = r.err
+		}
+	}
+
+	return exitErr
+}
+
+// zonesInUse returns true if the group k shares a zone with any of the running groups.
+func zonesInUse(zones map[string][]string, running map[string]bool, k string) bool {
+	for other := range running {
+		for _, a := range zones[k] {
+			for _, b := range zones[other] {
+				if a == b {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// statefulSetGuard serializes the draining of nodes that host pods of the same StatefulSet.
+// PodDisruptionBudgets are enforced by the eviction API for every drain, but StatefulSets
+// without a PDB would otherwise lose several replicas when groups are rolled concurrently.
+type statefulSetGuard struct {
+	mutex    sync.Mutex
+	draining map[types.NamespacedName]bool
+	// released is closed (and replaced) whenever StatefulSets are released, waking up waiting drains.
+	released chan struct{}
+}
+
+func newStatefulSetGuard() *statefulSetGuard {
+	return &statefulSetGuard{
+		draining: make(map[types.NamespacedName]bool),
+		released: make(chan struct{}),
+	}
+}
+
+// acquire blocks until no other drain is disrupting any of the StatefulSets with pods on the node,
+// or until the context is cancelled.
+// It returns the StatefulSets that must be passed to release once the drain is complete.
+func (g *statefulSetGuard) acquire(ctx context.Context, client kubernetes.Interface, node *corev1.Node) ([]types.NamespacedName, error) {
+	statefulSets, err := statefulSetsOnNode(ctx, client, node.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(statefulSets) == 0 {
+		return nil, nil
+	}
+
+	// We take all the StatefulSets at once, so concurrent drains cannot deadlock
+	for {
+		g.mutex.Lock()
+		if !g.anyDraining(statefulSets) {
+			for _, sts := range statefulSets {
+				g.draining[sts] = true
+			}
+			g.mutex.Unlock()
+			return statefulSets, nil
+		}
+		released := g.released
+		g.mutex.Unlock()
+
+		klog.Infof("Waiting for other drains to complete before draining node %q, which hosts StatefulSet pods.", node.Name)
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for other drains before draining node %q: %w", node.Name, ctx.Err())
+		}
+	}
+}
+
+func (g *statefulSetGuard) anyDraining(statefulSets []types.NamespacedName) bool {
+	for _, sts := range statefulSets {
+		if g.draining[sts] {
+			return true
+		}
+	}
+	return false
+}
+
+// release waits (up to timeout) for the StatefulSets to have all their replicas ready again,
+// and then allows other drains to disrupt them.
+func (g *statefulSetGuard) release(ctx context.Context, client kubernetes.Interface, statefulSets []types.NamespacedName, timeout time.Duration) error {
+	if len(statefulSets) == 0 {
+		return nil
+	}
+
+	defer func() {
+		g.mutex.Lock()
+		for _, sts := range statefulSets {
+			delete(g.draining, sts)
+		}
+		close(g.released)
+		g.released = make(chan struct{})
+		g.mutex.Unlock()
+	}()
+
+	for _, sts := range statefulSets {
+		err := wait.PollUntilContextTimeout(ctx, statefulSetReadyPollInterval, timeout, false, func(ctx context.Context) (bool, error) {
+			return isStatefulSetReady(ctx, client, sts)
+		})
+		if err != nil {
+			return fmt.Errorf("StatefulSet %s did not become ready after drain: %w", sts, err)
+		}
+	}
+	return nil
+}
+
+// statefulSetsOnNode returns the StatefulSets owning pods scheduled to the named node.
+func statefulSetsOnNode(ctx context.Context, client kubernetes.Interface, nodeName string) ([]types.NamespacedName, error) {
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods on node %q: %w", nodeName, err)
+	}
+
+	found := make(map[types.NamespacedName]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || owner.Kind != "StatefulSet" {
+			continue
+		}
+		found[types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}] = true
+	}
+
+	var statefulSets []types.NamespacedName
+	for sts := range found {
+		statefulSets = append(statefulSets, sts)
+	}
+	sort.Slice(statefulSets, func(i, j int) bool {
+		return statefulSets[i].String() < statefulSets[j].String()
+	})
+	return statefulSets, nil
+}
+
+func isStatefulSetReady(ctx context.Context, client kubernetes.Interface, name types.NamespacedName) (bool, error) {
+	sts, err := client.AppsV1().StatefulSets(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		klog.Warningf("error getting StatefulSet %s: %v", name, err)
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.Status.ReadyReplicas >= replicas, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestRollingUpdateNodeGroupsConcurrently(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.MaxConcurrentNodeGroups = 2
+	c.Options.OneGroupPerZone = true
+	c.Cluster.Spec.Networking.Subnets = []kopsapi.ClusterSubnetSpec{
+		{Name: "subnet-a", Zone: "us-east-1a"},
+		{Name: "subnet-b", Zone: "us-east-1b"},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-3", kopsapi.InstanceGroupRoleNode, 3, 3)
+	groups["node-1"].InstanceGroup.Spec.Subnets = []string{"subnet-a"}
+	groups["node-2"].InstanceGroup.Spec.Subnets = []string{"subnet-a"}
+	groups["node-3"].InstanceGroup.Spec.Subnets = []string{"subnet-b"}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+	assertGroupInstanceCount(t, cloud, "node-2", 0)
+	assertGroupInstanceCount(t, cloud, "node-3", 0)
+	assert.Nil(t, c.statefulSetGuard, "guard is cleared after rolling")
+}
+
+func TestRollingUpdateNodeGroupsUnknownSubnet(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.MaxConcurrentNodeGroups = 2
+	c.Options.OneGroupPerZone = true
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	groups["node-1"].InstanceGroup.Spec.Subnets = []string{"subnet-missing"}
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestZonesInUse(t *testing.T) {
+	zones := map[string][]string{
+		"a":  {"us-east-1a"},
+		"ab": {"us-east-1a", "us-east-1b"},
+		"b":  {"us-east-1b"},
+		"c":  {"us-east-1c"},
+	}
+
+	assert.False(t, zonesInUse(zones, map[string]bool{}, "a"))
+	assert.True(t, zonesInUse(zones, map[string]bool{"ab": true}, "a"))
+	assert.True(t, zonesInUse(zones, map[string]bool{"a": true}, "ab"))
+	assert.False(t, zonesInUse(zones, map[string]bool{"a": true, "b": true}, "c"))
+	assert.False(t, zonesInUse(map[string][]string{}, map[string]bool{"a": true}, "b"), "zones are ignored when not computed")
+}
+
+func TestStatefulSetGuardSerializesDrains(t *testing.T) {
+	statefulSetReadyPollInterval = time.Millisecond
+
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: fi.PtrTo(int32(2))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+	}
+	client := fake.NewSimpleClientset(sts,
+		makeStatefulSetPod("db-0", "node-a", sts),
+		makeStatefulSetPod("db-1", "node-b", sts),
+	)
+
+	guard := newStatefulSetGuard()
+	first, err := guard.acquire(ctx, client, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.NamespacedName{{Namespace: "default", Name: "db"}}, first)
+
+	acquired := make(chan []types.NamespacedName)
+	go func() {
+		second, err := guard.acquire(ctx, client, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}})
+		assert.NoError(t, err)
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second drain was not blocked by the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.NoError(t, guard.release(ctx, client, first, time.Second))
+
+	select {
+	case second := <-acquired:
+		assert.NoError(t, guard.release(ctx, client, second, time.Second))
+	case <-time.After(5 * time.Second):
+		t.Fatal("second drain was not unblocked after release")
+	}
+}
+
+func TestStatefulSetGuardAcquireCancelled(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: fi.PtrTo(int32(2))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+	}
+	client := fake.NewSimpleClientset(sts,
+		makeStatefulSetPod("db-0", "node-a", sts),
+		makeStatefulSetPod("db-1", "node-b", sts),
+	)
+
+	guard := newStatefulSetGuard()
+	_, err := guard.acquire(context.Background(), client, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	acquired := make(chan error)
+	go func() {
+		_, err := guard.acquire(ctx, client, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}})
+		acquired <- err
+	}()
+
+	cancel()
+	select {
+	case err := <-acquired:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting drain was not stopped by cancelling the context")
+	}
+	assert.Equal(t, map[types.NamespacedName]bool{{Namespace: "default", Name: "db"}: true}, guard.draining, "only the first drain holds the StatefulSet")
+}
+
+func TestStatefulSetGuardReleaseTimeout(t *testing.T) {
+	statefulSetReadyPollInterval = time.Millisecond
+
+	ctx := context.Background()
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: fi.PtrTo(int32(2))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	client := fake.NewSimpleClientset(sts, makeStatefulSetPod("db-0", "node-a", sts))
+
+	guard := newStatefulSetGuard()
+	held, err := guard.acquire(ctx, client, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	assert.NoError(t, err)
+
+	err = guard.release(ctx, client, held, 20*time.Millisecond)
+	assert.Error(t, err, "StatefulSet never became ready")
+	assert.Empty(t, guard.draining, "StatefulSets are released even on error")
+}
+
+func makeStatefulSetPod(name string, nodeName string, sts *appsv1.StatefulSet) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sts.Namespace,
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(sts, appsv1.SchemeGroupVersion.WithKind("StatefulSet")),
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+	}
+}
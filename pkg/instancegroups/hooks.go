@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+var (
+	// hookRetryInterval is the time to wait before retrying a hook that did not succeed.
+	hookRetryInterval = 10 * time.Second
+	// hookJobPollInterval is the time between checks of the status of a hook Job.
+	hookJobPollInterval = 2 * time.Second
+	// httpHookClient makes the requests of HTTP hooks; a request that does not complete in time is retried.
+	httpHookClient = &http.Client{Timeout: httpHookRequestTimeout}
+)
+
+// httpHookRequestTimeout is the maximum duration of a request of an HTTP hook.
+const httpHookRequestTimeout = 30 * time.Second
+
+// defaultHookTimeout is the time a hook may take to succeed if the hook does not specify a timeout.
+const defaultHookTimeout = 5 * time.Minute
+
+// hooksFor returns the rolling update hooks of the instance group that run in the phase.
+func (c *RollingUpdateCluster) hooksFor(group *cloudinstances.CloudInstanceGroup, phase api.RollingUpdateHookPhase) []api.RollingUpdateHook {
+	if group == nil || group.InstanceGroup == nil {
+		return nil
+	}
+
+	var hooks []api.RollingUpdateHook
+	for _, hook := range resolveSettings(c.Cluster, group.InstanceGroup, 0).Hooks {
+		if hook.Phase == phase {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// checkExecHooks returns an error if exec hooks are not allowed, and would run for instances of the groups.
+func (c *RollingUpdateCluster) checkExecHooks(groups map[string]*cloudinstances.CloudInstanceGroup) error {
+	if c.Options.AllowExecHooks {
+		return nil
+	}
+	for _, k := range sortGroups(groups) {
+		group := groups[k]
+		if len(group.NeedUpdate) == 0 && !c.Force {
+			continue
+		}
+		for _, phase := range []api.RollingUpdateHookPhase{api.RollingUpdateHookPhaseBeforeDrain, api.RollingUpdateHookPhaseAfterValidate} {
+			for _, hook := range c.hooksFor(group, phase) {
+				if hook.Exec != nil {
+					return execHooksNotAllowedError(&hook, group.InstanceGroup.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func execHooksNotAllowedError(hook *api.RollingUpdateHook, instanceGroup string) error {
+	return fmt.Errorf("%s hook %q of instance group %q runs a command on this machine; specify --allow-exec-hooks to allow it", hook.Phase, hook.Name, instanceGroup)
+}
+
+// runBeforeDrainHooks runs the BeforeDrain hooks for an instance about to be drained.
+func (c *RollingUpdateCluster) runBeforeDrainHooks(u *cloudinstances.CloudInstance) error {
+	for _, hook := range c.hooksFor(u.CloudInstanceGroup, api.RollingUpdateHookPhaseBeforeDrain) {
+		if err := c.runHook(&hook, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPendingAfterValidate records that an instance was replaced,
+// so that the AfterValidate hooks are run for it once the cluster next validates.
+func (c *RollingUpdateCluster) addPendingAfterValidate(u *cloudinstances.CloudInstance) {
+	if len(c.hooksFor(u.CloudInstanceGroup, api.RollingUpdateHookPhaseAfterValidate)) == 0 {
+		return
+	}
+
+	c.hookMutex.Lock()
+	defer c.hookMutex.Unlock()
+
+	if c.pendingAfterValidate == nil {
+		c.pendingAfterValidate = make(map[string][]*cloudinstances.CloudInstance)
+	}
+	name := u.CloudInstanceGroup.InstanceGroup.Name
+	c.pendingAfterValidate[name] = append(c.pendingAfterValidate[name], u)
+}
+
+// runAfterValidateHooks runs the AfterValidate hooks for the instances of the group replaced since the cluster last validated.
+func (c *RollingUpdateCluster) runAfterValidateHooks(group *cloudinstances.CloudInstanceGroup) error {
+	c.hookMutex.Lock()
+	pending := c.pendingAfterValidate[group.InstanceGroup.Name]
+	delete(c.pendingAfterValidate, group.InstanceGroup.Name)
+	c.hookMutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	hooks := c.hooksFor(group, api.RollingUpdateHookPhaseAfterValidate)
+	for _, u := range pending {
+		for _, hook := range hooks {
+			if err := c.runHook(&hook, u); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runHook runs the hook for the instance, retrying until it succeeds or its timeout expires.
+// Failures are only returned if the failure policy of the hook is Fail.
+// Exec hooks which are not allowed always fail, as they are refused rather than run.
+func (c *RollingUpdateCluster) runHook(hook *api.RollingUpdateHook, u *cloudinstances.CloudInstance) error {
+	if hook.Exec != nil && !c.Options.AllowExecHooks {
+		return execHooksNotAllowedError(hook, u.CloudInstanceGroup.InstanceGroup.Name)
+	}
+
+	timeout := defaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+
+	ctx, cancel := context.WithTimeout(c.Ctx, timeout)
+	defer cancel()
+
+	env := c.hookEnv(hook, u)
+
+	klog.Infof("Running %s hook %q for instance %q.", hook.Phase, hook.Name, u.ID)
+
+	var lastErr error
+	err := wait.PollUntilContextCancel(ctx, hookRetryInterval, true, func(ctx context.Context) (bool, error) {
+		lastErr = c.runHookOnce(ctx, hook, env)
+		if lastErr != nil {
+			klog.Warningf("%s hook %q for instance %q did not succeed: %v", hook.Phase, hook.Name, u.ID, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if lastErr == nil {
+		lastErr = err
+	}
+
+	if hook.FailurePolicy == api.RollingUpdateHookFailurePolicyIgnore {
+		klog.Warningf("Ignoring failure of %s hook %q for instance %q: %v", hook.Phase, hook.Name, u.ID, lastErr)
+		return nil
+	}
+	return fmt.Errorf("%s hook %q for instance %q did not succeed within %v: %w", hook.Phase, hook.Name, u.ID, timeout, lastErr)
+}
+
+func (c *RollingUpdateCluster) runHookOnce(ctx context.Context, hook *api.RollingUpdateHook, env map[string]string) error {
+	switch {
+	case hook.Exec != nil:
+		return runExecHook(ctx, hook.Exec, env)
+	case hook.HTTP != nil:
+		return runHTTPHook(ctx, hook.HTTP, env)
+	case hook.Job != nil:
+		if c.CloudOnly || c.K8sClient == nil {
+			klog.Warningf("Not running job hook %q as 'cloudonly' flag is set.", hook.Name)
+			return nil
+		}
+		return c.runJobHook(ctx, hook, env)
+	default:
+		return fmt.Errorf("hook %q has no action", hook.Name)
+	}
+}
+
+// hookEnv returns the variables describing the instance being replaced.
+func (c *RollingUpdateCluster) hookEnv(hook *api.RollingUpdateHook, u *cloudinstances.CloudInstance) map[string]string {
+	env := map[string]string{
+		"KOPS_CLUSTER_NAME": c.Cluster.Name,
+		"KOPS_INSTANCE_ID":  u.ID,
+		"KOPS_HOOK_PHASE":   string(hook.Phase),
+	}
+	if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
+		env["KOPS_INSTANCE_GROUP"] = u.CloudInstanceGroup.InstanceGroup.Name
+	}
+	if u.Node != nil {
+		env["KOPS_NODE_NAME"] = u.Node.Name
+	}
+	return env
+}
+
+func sortedEnv(env map[string]string) []string {
+	var vars []string
+	for k, v := range env {
+		vars = append(vars, k+"="+v)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+func runExecHook(ctx context.Context, hook *api.RollingUpdateExecHook, env map[string]string) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(os.Environ(), sortedEnv(env)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command %q failed: %w: %s", strings.Join(hook.Command, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func runHTTPHook(ctx context.Context, hook *api.RollingUpdateHTTPHook, env map[string]string) error {
+	url := os.Expand(hook.URL, func(k string) string {
+		return env[k]
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("building request for %q: %w", url, err)
+	}
+	resp, err := httpHookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %q from %q", resp.Status, url)
+	}
+	return nil
+}
+
+func (c *RollingUpdateCluster) runJobHook(ctx context.Context, hook *api.RollingUpdateHook, env map[string]string) error {
+	job := &batchv1.Job{}
+	if err := yaml.UnmarshalStrict([]byte(hook.Job.Manifest), job); err != nil {
+		return fmt.Errorf("parsing job manifest: %w", err)
+	}
+	// Each run creates a Job with a new name, as the Job of the previous run may still be being deleted
+	if job.Name != "" {
+		job.GenerateName = job.Name + "-"
+		job.Name = ""
+	}
+	if job.GenerateName == "" {
+		job.GenerateName = hook.Name + "-"
+	}
+	if job.Namespace == "" {
+		job.Namespace = metav1.NamespaceSystem
+	}
+
+	var vars []corev1.EnvVar
+	for _, v := range sortedEnv(env) {
+		k, v, _ := strings.Cut(v, "=")
+		vars = append(vars, corev1.EnvVar{Name: k, Value: v})
+	}
+	for i := range job.Spec.Template.Spec.InitContainers {
+		job.Spec.Template.Spec.InitContainers[i].Env = append(job.Spec.Template.Spec.InitContainers[i].Env, vars...)
+	}
+	for i := range job.Spec.Template.Spec.Containers {
+		job.Spec.Template.Spec.Containers[i].Env = append(job.Spec.Template.Spec.Containers[i].Env, vars...)
+	}
+
+	jobs := c.K8sClient.BatchV1().Jobs(job.Namespace)
+	created, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("creating job: %w", err)
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		// Use the parent context, as the job must be cleaned up even if the hook timed out.
+		if err := jobs.Delete(c.Ctx, created.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			klog.Warningf("failed to delete job %s/%s: %v", created.Namespace, created.Name, err)
+		}
+	}()
+
+	var jobErr error
+	err = wait.PollUntilContextCancel(ctx, hookJobPollInterval, true, func(ctx context.Context) (bool, error) {
+		current, err := jobs.Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("failed to get job %s/%s: %v", created.Namespace, created.Name, err)
+			return false, nil
+		}
+		if current.Status.Succeeded > 0 {
+			return true, nil
+		}
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				jobErr = fmt.Errorf("job %s/%s failed: %s", created.Namespace, created.Name, condition.Message)
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for job %s/%s: %w", created.Namespace, created.Name, err)
+	}
+	return jobErr
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func init() {
+	hookRetryInterval = time.Millisecond
+	hookJobPollInterval = time.Millisecond
+}
+
+func TestRollingUpdateAfterValidateHookRunsForEachInstance(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.AllowExecHooks = true
+
+	output := filepath.Join(t.TempDir(), "hook.log")
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:  "record",
+				Phase: kopsapi.RollingUpdateHookPhaseAfterValidate,
+				Exec: &kopsapi.RollingUpdateExecHook{
+					Command: []string{"sh", "-c", `echo "$KOPS_CLUSTER_NAME $KOPS_INSTANCE_GROUP $KOPS_INSTANCE_ID $KOPS_NODE_NAME $KOPS_HOOK_PHASE" >> ` + output},
+				},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	b, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		"test.k8s.local node-1 node-1a node-1a.local AfterValidate",
+		"test.k8s.local node-1 node-1b node-1b.local AfterValidate",
+	}, lines)
+}
+
+func TestRollingUpdateBeforeDrainHookFailureStopsRollingUpdate(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.AllowExecHooks = true
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:    "fail",
+				Phase:   kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				Exec:    &kopsapi.RollingUpdateExecHook{Command: []string{"false"}},
+				Timeout: &metav1.Duration{Duration: 10 * time.Millisecond},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), `BeforeDrain hook "fail"`)
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestRollingUpdateHookFailureIgnored(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.AllowExecHooks = true
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:          "fail",
+				Phase:         kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				Exec:          &kopsapi.RollingUpdateExecHook{Command: []string{"false"}},
+				Timeout:       &metav1.Duration{Duration: 10 * time.Millisecond},
+				FailurePolicy: kopsapi.RollingUpdateHookFailurePolicyIgnore,
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateInstanceGroupHooksOverrideCluster(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:    "fail",
+				Phase:   kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				Exec:    &kopsapi.RollingUpdateExecHook{Command: []string{"false"}},
+				Timeout: &metav1.Duration{Duration: 10 * time.Millisecond},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	groups["node-1"].InstanceGroup.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{},
+	}
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateExecHookNotAllowed(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	output := filepath.Join(t.TempDir(), "hook.log")
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:          "record",
+				Phase:         kopsapi.RollingUpdateHookPhaseAfterValidate,
+				Exec:          &kopsapi.RollingUpdateExecHook{Command: []string{"touch", output}},
+				FailurePolicy: kopsapi.RollingUpdateHookFailurePolicyIgnore,
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "specify --allow-exec-hooks")
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assert.NoFileExists(t, output)
+
+	// Instances are not replaced, so the hook is not refused
+	groups = make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+}
+
+func TestRollingUpdateHTTPHook(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	var mutex sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requested = append(requested, r.URL.Query().Get("node"))
+		// Fail the first request, to exercise retries
+		if len(requested) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:  "check",
+				Phase: kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				HTTP:  &kopsapi.RollingUpdateHTTPHook{URL: server.URL + "/check?node=${KOPS_NODE_NAME}"},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 1)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, []string{"node-1a.local", "node-1a.local"}, requested)
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateHTTPHookRequestTimeout(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	client := httpHookClient
+	httpHookClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { httpHookClient = client }()
+
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		first := requests == 1
+		mutex.Unlock()
+		// Hang on the first request, until the client gives up
+		if first {
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:    "check",
+				Phase:   kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				HTTP:    &kopsapi.RollingUpdateHTTPHook{URL: server.URL + "/check"},
+				Timeout: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 1)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assert.Equal(t, 2, requests, "requests")
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateJobHook(t *testing.T) {
+	c, cloud := getTestSetup()
+	k8sClient := c.K8sClient.(*fake.Clientset)
+
+	var created []*batchv1.Job
+	k8sClient.PrependReactor("create", "jobs", func(action testingclient.Action) (bool, runtime.Object, error) {
+		job := action.(testingclient.CreateAction).GetObject().(*batchv1.Job)
+		if job.Name == "" {
+			job.Name = job.GenerateName + "abcde"
+		}
+		created = append(created, job)
+		return false, nil, nil
+	})
+	k8sClient.PrependReactor("get", "jobs", func(action testingclient.Action) (bool, runtime.Object, error) {
+		job := created[len(created)-1].DeepCopy()
+		job.Status.Succeeded = 1
+		return true, job, nil
+	})
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:  "smoke-test",
+				Phase: kopsapi.RollingUpdateHookPhaseAfterValidate,
+				Job: &kopsapi.RollingUpdateJobHook{
+					Manifest: `
+apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: test
+        image: busybox
+        command: ["true"]
+`,
+				},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 1)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	if assert.Len(t, created, 1) {
+		job := created[0]
+		assert.Equal(t, "smoke-test-", job.GenerateName)
+		assert.Equal(t, "kube-system", job.Namespace)
+		env := map[string]string{}
+		for _, v := range job.Spec.Template.Spec.Containers[0].Env {
+			env[v.Name] = v.Value
+		}
+		assert.Equal(t, "node-1a", env["KOPS_INSTANCE_ID"])
+		assert.Equal(t, "AfterValidate", env["KOPS_HOOK_PHASE"])
+	}
+
+	jobs, err := k8sClient.BatchV1().Jobs("kube-system").List(c.Ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, jobs.Items, "job is deleted")
+}
+
+func TestRollingUpdateNamedJobHook(t *testing.T) {
+	c, cloud := getTestSetup()
+	k8sClient := c.K8sClient.(*fake.Clientset)
+
+	var created []*batchv1.Job
+	k8sClient.PrependReactor("create", "jobs", func(action testingclient.Action) (bool, runtime.Object, error) {
+		job := action.(testingclient.CreateAction).GetObject().(*batchv1.Job)
+		created = append(created, job.DeepCopy())
+		if job.Name == "" {
+			job.Name = fmt.Sprintf("%s%d", job.GenerateName, len(created))
+		}
+		return false, nil, nil
+	})
+	k8sClient.PrependReactor("get", "jobs", func(action testingclient.Action) (bool, runtime.Object, error) {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: action.(testingclient.GetAction).GetName()}}
+		job.Status.Succeeded = 1
+		return true, job, nil
+	})
+
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Hooks: []kopsapi.RollingUpdateHook{
+			{
+				Name:  "smoke-test",
+				Phase: kopsapi.RollingUpdateHookPhaseBeforeDrain,
+				Job: &kopsapi.RollingUpdateJobHook{
+					Manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: check-node
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: test
+        image: busybox
+        command: ["true"]
+`,
+				},
+			},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	if assert.Len(t, created, 2) {
+		for _, job := range created {
+			assert.Empty(t, job.Name)
+			assert.Equal(t, "check-node-", job.GenerateName)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups/journal"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// defaultReconfigureTimeout is the maximum time to wait for nodeup to reconfigure a node, if ReconfigureTimeout is not set.
+const defaultReconfigureTimeout = 15 * time.Minute
+
+// needsUpdateAnnotation marks a Node as needing update, even if its instance is up to date.
+const needsUpdateAnnotation = "kops.k8s.io/needs-update"
+
+// reconfigureInPlace reconfigures the instances that can be updated in place, by draining them
+// and requesting that nodeup runs again on them, and returns the instances that must be replaced instead.
+func (c *RollingUpdateCluster) reconfigureInPlace(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstance, settings api.RollingUpdate, force bool) ([]*cloudinstances.CloudInstance, error) {
+	ig := group.InstanceGroup
+
+	readHash := c.readNodeupConfigHash
+	if c.nodeupConfigHash != nil {
+		readHash = c.nodeupConfigHash
+	}
+	hash, err := readHash(ig)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*api.InstanceGroup
+	if c.Clientset != nil {
+		revisions, err = c.Clientset.InstanceGroupRevisionsFor(c.Cluster).List(c.Ctx, ig.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var inPlace, replace []*cloudinstances.CloudInstance
+	for _, u := range update {
+		switch {
+		case u.Node == nil:
+			klog.Infof("Instance %q is not registered in kubernetes; replacing it.", u.ID)
+			replace = append(replace, u)
+		case !machineTypeMatches(ig, u.MachineType):
+			klog.Infof("Instance %q has machine type %q, which is not a machine type of InstanceGroup %q; replacing it.", u.ID, u.MachineType, ig.Name)
+			replace = append(replace, u)
+		case launchSpecChanged(ig, revisions, u.Node):
+			klog.Infof("The image or machine type of InstanceGroup %q changed since instance %q was launched; replacing it.", ig.Name, u.ID)
+			replace = append(replace, u)
+		case !force && isReconfigured(u.Node, hash):
+			klog.V(2).Infof("Node %q was already reconfigured with the current configuration.", u.Node.Name)
+		default:
+			inPlace = append(inPlace, u)
+		}
+	}
+
+	if len(inPlace) == 0 {
+		return replace, nil
+	}
+
+	// Nodes can't surge when reconfigured in place, so we reconfigure as many nodes as may be unavailable
+	maxConcurrency := settings.MaxUnavailable.IntValue()
+	if maxConcurrency < 1 || c.Interactive {
+		maxConcurrency = 1
+	}
+
+	klog.Infof("Reconfiguring %d instance(s) of InstanceGroup %q in place.", len(inPlace), ig.Name)
+	for start := 0; start < len(inPlace); start += maxConcurrency {
+		batch := inPlace[start:min(start+maxConcurrency, len(inPlace))]
+
+		reconfigureChan := make(chan error, len(batch))
+		for _, u := range batch {
+			go func(m *cloudinstances.CloudInstance) {
+				reconfigureChan <- c.reconfigureInstance(m, hash)
+			}(u)
+		}
+		var reconfigureErr error
+		for range batch {
+			if err := <-reconfigureChan; err != nil && reconfigureErr == nil {
+				reconfigureErr = err
+			}
+		}
+		if reconfigureErr != nil {
+			return nil, reconfigureErr
+		}
+
+		if err := c.maybeValidate(" after reconfiguring instance", c.ValidateCount, group); err != nil {
+			return nil, err
+		}
+
+		if err := c.runAfterValidateHooks(group); err != nil {
+			return nil, err
+		}
+
+		if c.Interactive {
+			stopPrompting, err := promptInteractive(batch[0].ID, batch[0].Node.Name)
+			if err != nil {
+				return nil, err
+			}
+			if stopPrompting {
+				// Is a pointer to a struct, changes here push back into the original
+				c.Interactive = false
+			}
+		}
+	}
+
+	return replace, nil
+}
+
+// reconfigureInstance drains the node, requests that nodeup applies the nodeup configuration with the given hash,
+// waits for nodeup to record that it did, then uncordons the node.
+func (c *RollingUpdateCluster) reconfigureInstance(u *cloudinstances.CloudInstance, hash string) error {
+	nodeName := u.Node.Name
+
+	if err := c.runBeforeDrainHooks(u); err != nil {
+		return err
+	}
+
+	if u.Node.Spec.Unschedulable && c.progress.phase(u) == journal.PhaseDrained {
+		klog.Infof("Node %q was drained by an interrupted rolling update; not draining again.", nodeName)
+	} else {
+		klog.Infof("Draining the node: %q.", nodeName)
+
+		// The instance keeps running, so we don't deregister it from the cloud load balancers
+		if err := c.guardedDrainNode(u, false); err != nil {
+			if c.FailOnDrainError {
+				return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
+			}
+			klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+		} else {
+			c.progress.record(c.Ctx, u, journal.PhaseDrained)
+		}
+	}
+
+	klog.Infof("Requesting that nodeup reconfigures node %q.", nodeName)
+	if err := c.requestReconfigure(nodeName, hash); err != nil {
+		return err
+	}
+
+	if err := c.waitForReconfigure(nodeName, hash); err != nil {
+		return err
+	}
+
+	klog.Infof("Node %q was reconfigured; uncordoning it.", nodeName)
+	if err := c.uncordonReconfigured(nodeName); err != nil {
+		return err
+	}
+	c.progress.record(c.Ctx, u, journal.PhaseReconfigured)
+	c.addPendingAfterValidate(u)
+
+	return nil
+}
+
+// isReconfigured returns true if nodeup has applied the nodeup configuration with the given hash to the node,
+// and the node has not since been marked as needing update.
+func isReconfigured(node *corev1.Node, hash string) bool {
+	if _, ok := node.Annotations[needsUpdateAnnotation]; ok {
+		return false
+	}
+	return node.Annotations[nodeup.AnnotationReconfigured] == hash
+}
+
+// requestReconfigure records on the Node the hash of the nodeup configuration that nodeup must apply.
+// The node must be cordoned, for kops-controller to allow it to bootstrap again.
+func (c *RollingUpdateCluster) requestReconfigure(nodeName string, hash string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				nodeup.AnnotationReconfigureRequested: hash,
+			},
+		},
+		"spec": map[string]interface{}{
+			"unschedulable": true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.K8sClient.CoreV1().Nodes().Patch(c.Ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error requesting reconfiguration of node %q: %w", nodeName, err)
+	}
+	return nil
+}
+
+// waitForReconfigure waits until nodeup records on the Node that it applied the nodeup configuration with the given hash.
+func (c *RollingUpdateCluster) waitForReconfigure(nodeName string, hash string) error {
+	timeout := c.ReconfigureTimeout
+	if timeout == 0 {
+		timeout = defaultReconfigureTimeout
+	}
+
+	err := wait.PollUntilContextTimeout(c.Ctx, c.ValidateTickDuration, timeout, true, func(ctx context.Context) (bool, error) {
+		node, err := c.K8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("node %q was deleted while being reconfigured", nodeName)
+			}
+			klog.Warningf("error getting node %q: %v", nodeName, err)
+			return false, nil
+		}
+		return node.Annotations[nodeup.AnnotationReconfigured] == hash, nil
+	})
+	if err != nil && wait.Interrupted(err) && c.Ctx.Err() == nil {
+		return fmt.Errorf("node %q was not reconfigured within %s; check the kops-reconfigure service on the node", nodeName, timeout)
+	}
+	return err
+}
+
+// uncordonReconfigured reverts the changes made to the Node to drain it and request its reconfiguration.
+func (c *RollingUpdateCluster) uncordonReconfigured(nodeName string) error {
+	node, err := c.K8sClient.CoreV1().Nodes().Get(c.Ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %w", nodeName, err)
+	}
+
+	oldData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	node.Spec.Unschedulable = false
+	var taints []corev1.Taint
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != rollingUpdateTaintKey {
+			taints = append(taints, taint)
+		}
+	}
+	node.Spec.Taints = taints
+	delete(node.Labels, corev1.LabelNodeExcludeBalancers)
+	delete(node.Annotations, nodeup.AnnotationReconfigureRequested)
+	delete(node.Annotations, needsUpdateAnnotation)
+
+	newData, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, node)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.K8sClient.CoreV1().Nodes().Patch(c.Ctx, nodeName, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error uncordoning node %q: %w", nodeName, err)
+	}
+	return nil
+}
+
+// readNodeupConfigHash returns the hash of the nodeup configuration of the InstanceGroup in the state store,
+// which nodeup verifies before applying it.
+func (c *RollingUpdateCluster) readNodeupConfigHash(ig *api.InstanceGroup) (string, error) {
+	configBase, err := vfs.Context.BuildVfsPath(c.Cluster.Spec.ConfigStore.Base)
+	if err != nil {
+		return "", fmt.Errorf("error parsing configStore.base %q: %w", c.Cluster.Spec.ConfigStore.Base, err)
+	}
+	p := configBase.Join("igconfig", ig.Spec.Role.ToLowerString(), ig.Name, "nodeupconfig.yaml")
+
+	b, err := p.ReadFile(c.Ctx)
+	if err != nil {
+		return "", fmt.Errorf("error reading nodeup config of InstanceGroup %q: %w", ig.Name, err)
+	}
+	return nodeup.HashConfig(b), nil
+}
+
+// launchSpecChanged returns true if the image or machine type of the InstanceGroup changed
+// since the instance of the Node was launched, in which case the instance must be replaced.
+// The applied revisions are checked, newest first, down to the revision the instance was launched with:
+// the newest one applied before the Node was created. Revisions are checked down to the oldest
+// if none is known to be older than the Node, for example because they were recorded without a timestamp.
+func launchSpecChanged(ig *api.InstanceGroup, revisions []*api.InstanceGroup, node *corev1.Node) bool {
+	for _, revision := range revisions {
+		if revision.Spec.Image != ig.Spec.Image || revision.Spec.MachineType != ig.Spec.MachineType {
+			return true
+		}
+		if !revision.CreationTimestamp.IsZero() && revision.CreationTimestamp.Before(&node.CreationTimestamp) {
+			return false
+		}
+	}
+	return false
+}
+
+// machineTypeMatches returns true if the machine type is one of the machine types of the InstanceGroup,
+// or is not known.
+func machineTypeMatches(ig *api.InstanceGroup, machineType string) bool {
+	if machineType == "" {
+		return true
+	}
+
+	machineTypes := strings.Split(ig.Spec.MachineType, ",")
+	if policy := ig.Spec.MixedInstancesPolicy; policy != nil {
+		machineTypes = append(machineTypes, policy.Instances...)
+	}
+	for _, t := range machineTypes {
+		if strings.TrimSpace(t) == machineType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclient "k8s.io/client-go/testing"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// fakeNodeup simulates nodeup on the nodes, recording the requested reconfiguration when the Node is next read.
+type fakeNodeup struct {
+	mutex        sync.Mutex
+	reconfigured []string
+}
+
+func (f *fakeNodeup) install(k8sClient *fake.Clientset) {
+	k8sClient.PrependReactor("get", "nodes", func(action testingclient.Action) (bool, runtime.Object, error) {
+		name := action.(testingclient.GetAction).GetName()
+		obj, err := k8sClient.Tracker().Get(v1.SchemeGroupVersion.WithResource("nodes"), "", name)
+		if err != nil {
+			return false, nil, nil
+		}
+		node := obj.(*v1.Node)
+		requested := node.Annotations[nodeup.AnnotationReconfigureRequested]
+		if requested == "" || node.Annotations[nodeup.AnnotationReconfigured] == requested {
+			return false, nil, nil
+		}
+
+		node = node.DeepCopy()
+		node.Annotations[nodeup.AnnotationReconfigured] = requested
+		if err := k8sClient.Tracker().Update(v1.SchemeGroupVersion.WithResource("nodes"), node, ""); err != nil {
+			return true, nil, err
+		}
+
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.reconfigured = append(f.reconfigured, name)
+		return false, nil, nil
+	})
+}
+
+func getInPlaceTestSetup() (*RollingUpdateCluster, *awsup.MockAWSCloud, *fakeNodeup, map[string]*cloudinstances.CloudInstanceGroup) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Strategy: kopsapi.RollingUpdateStrategyInPlace,
+	}
+	c.nodeupConfigHash = func(ig *kopsapi.InstanceGroup) (string, error) {
+		return "hash-1", nil
+	}
+
+	f := &fakeNodeup{}
+	f.install(c.K8sClient.(*fake.Clientset))
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 2)
+	groups["node-1"].InstanceGroup.Spec.MachineType = "t3.medium"
+
+	return c, cloud, f, groups
+}
+
+func TestRollingUpdateInPlace(t *testing.T) {
+	c, cloud, fakeNodeup, groups := getInPlaceTestSetup()
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assert.ElementsMatch(t, []string{"node-1a.local", "node-1b.local"}, fakeNodeup.reconfigured)
+
+	for _, name := range []string{"node-1a.local", "node-1b.local"} {
+		node, err := c.K8sClient.CoreV1().Nodes().Get(c.Ctx, name, v1meta.GetOptions{})
+		if assert.NoError(t, err, "getting node") {
+			assert.False(t, node.Spec.Unschedulable, "%s unschedulable", name)
+			assert.Empty(t, node.Spec.Taints, "%s taints", name)
+			assert.NotContains(t, node.Labels, v1.LabelNodeExcludeBalancers, "%s labels", name)
+			assert.NotContains(t, node.Annotations, nodeup.AnnotationReconfigureRequested, "%s annotations", name)
+			assert.Equal(t, "hash-1", node.Annotations[nodeup.AnnotationReconfigured], "%s annotations", name)
+		}
+	}
+}
+
+func TestRollingUpdateInPlaceSkipsReconfiguredNodes(t *testing.T) {
+	c, cloud, fakeNodeup, groups := getInPlaceTestSetup()
+
+	for _, u := range groups["node-1"].NeedUpdate {
+		u.Node.Annotations = map[string]string{nodeup.AnnotationReconfigured: "hash-1"}
+	}
+	addNeedsUpdateAnnotation(groups["node-1"], "node-1b")
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+	assert.Equal(t, []string{"node-1b.local"}, fakeNodeup.reconfigured)
+}
+
+func TestRollingUpdateInPlaceReplacesChangedMachineType(t *testing.T) {
+	c, cloud, fakeNodeup, groups := getInPlaceTestSetup()
+
+	groups["node-1"].NeedUpdate[0].MachineType = "t3.large"
+	groups["node-1"].NeedUpdate[1].MachineType = "t3.medium"
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	assert.Equal(t, []string{"node-1b.local"}, fakeNodeup.reconfigured)
+}
+
+func TestRollingUpdateInPlaceTimeout(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Strategy: kopsapi.RollingUpdateStrategyInPlace,
+	}
+	c.nodeupConfigHash = func(ig *kopsapi.InstanceGroup) (string, error) {
+		return "hash-1", nil
+	}
+	c.ReconfigureTimeout = 10 * time.Millisecond
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 1)
+
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), `node "node-1a.local" was not reconfigured`)
+	}
+
+	node, err := c.K8sClient.CoreV1().Nodes().Get(c.Ctx, "node-1a.local", v1meta.GetOptions{})
+	if assert.NoError(t, err, "getting node") {
+		assert.True(t, node.Spec.Unschedulable, "node left cordoned")
+		assert.Equal(t, "hash-1", node.Annotations[nodeup.AnnotationReconfigureRequested])
+	}
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestRollingUpdateInPlaceCancelled(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		Strategy: kopsapi.RollingUpdateStrategyInPlace,
+	}
+	c.nodeupConfigHash = func(ig *kopsapi.InstanceGroup) (string, error) {
+		return "hash-1", nil
+	}
+	c.ReconfigureTimeout = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Ctx = ctx
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 1)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestMachineTypeMatches(t *testing.T) {
+	ig := &kopsapi.InstanceGroup{
+		Spec: kopsapi.InstanceGroupSpec{
+			MachineType: "t3.medium,t3.large",
+			MixedInstancesPolicy: &kopsapi.MixedInstancesPolicySpec{
+				Instances: []string{"m5.large"},
+			},
+		},
+	}
+
+	assert.True(t, machineTypeMatches(ig, ""), "unknown machine type")
+	assert.True(t, machineTypeMatches(ig, "t3.large"), "machine type")
+	assert.True(t, machineTypeMatches(ig, "m5.large"), "mixed instances policy")
+	assert.False(t, machineTypeMatches(ig, "m5.xlarge"), "other machine type")
+}
+
+func TestLaunchSpecChanged(t *testing.T) {
+	day := func(d int) v1meta.Time {
+		return v1meta.NewTime(time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+	revision := func(created v1meta.Time, image string) *kopsapi.InstanceGroup {
+		return &kopsapi.InstanceGroup{
+			ObjectMeta: v1meta.ObjectMeta{Name: "nodes", CreationTimestamp: created},
+			Spec:       kopsapi.InstanceGroupSpec{Image: image, MachineType: "t3.medium"},
+		}
+	}
+	ig := revision(day(5), "image-2")
+	node := func(created v1meta.Time) *v1.Node {
+		return &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-1", CreationTimestamp: created}}
+	}
+
+	// The image changed two revisions ago, before the last rolling update
+	revisions := []*kopsapi.InstanceGroup{
+		revision(day(5), "image-2"),
+		revision(day(3), "image-2"),
+		revision(day(1), "image-1"),
+	}
+	assert.True(t, launchSpecChanged(ig, revisions, node(day(2))), "node launched with the old image")
+	assert.False(t, launchSpecChanged(ig, revisions, node(day(4))), "node launched with the new image")
+	assert.False(t, launchSpecChanged(ig, revisions, node(day(6))), "node launched after the newest revision")
+	assert.False(t, launchSpecChanged(ig, nil, node(day(2))), "no revisions")
+
+	// Without timestamps, any retained revision with another image counts
+	legacy := []*kopsapi.InstanceGroup{
+		revision(v1meta.Time{}, "image-2"),
+		revision(v1meta.Time{}, "image-1"),
+	}
+	assert.True(t, launchSpecChanged(ig, legacy, node(day(4))), "revisions without timestamps")
+}
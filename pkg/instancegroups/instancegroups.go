@@ -38,6 +38,7 @@ import (
 
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups/journal"
 	"k8s.io/kops/pkg/validation"
 )
 
@@ -97,8 +98,9 @@ func promptInteractive(upgradedHostID, upgradedHostName string) (stopPrompting b
 	return stopPrompting, err
 }
 
-// RollingUpdate performs a rolling update on a list of instances.
-func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration) (err error) {
+// updateInstanceGroup performs a rolling update on a list of instances.
+// If force is set, instances that do not need updating are also replaced.
+func (c *RollingUpdateCluster) updateInstanceGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration, force bool) (err error) {
 	isBastion := group.InstanceGroup.IsBastion()
 	// Do not need a k8s client if you are doing cloudonly.
 	if c.K8sClient == nil && !c.CloudOnly {
@@ -108,7 +110,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 	noneReady := len(group.Ready) == 0
 	numInstances := len(group.Ready) + len(group.NeedUpdate)
 	update := group.NeedUpdate
-	if c.Force {
+	if force {
 		update = append(update, group.Ready...)
 	}
 
@@ -138,6 +140,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			if err != nil {
 				return fmt.Errorf("failed to delete warm pool instance %q: %w", instance.ID, err)
 			}
+			c.progress.record(c.Ctx, instance, journal.PhaseTerminated)
 		} else {
 			nonWarmPool = append(nonWarmPool, instance)
 		}
@@ -146,6 +149,17 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	settings := resolveSettings(c.Cluster, group.InstanceGroup, numInstances)
 
+	if settings.Strategy == api.RollingUpdateStrategyInPlace && *settings.DrainAndTerminate && !isBastion && !c.CloudOnly {
+		update, err = c.reconfigureInPlace(group, update, settings, force)
+		if err != nil {
+			return err
+		}
+		if len(update) == 0 {
+			return nil
+		}
+		noneReady = false
+	}
+
 	runningDrains := 0
 	maxSurge := settings.MaxSurge.IntValue()
 
@@ -180,6 +194,22 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 
 	update = prioritizeUpdate(update)
 
+	if settings.Strategy == api.RollingUpdateStrategyCanary && *settings.DrainAndTerminate && len(update) != 0 {
+		canaryCount := settings.Canary.Count.IntValue()
+		if canaryCount >= len(update) {
+			klog.Infof("Canary count %d of InstanceGroup %q covers all %d instances to update; replacing them all as canaries.", canaryCount, group.InstanceGroup.Name, len(update))
+			canaryCount = len(update)
+		}
+		if err := c.rollCanaries(group, update[:canaryCount], settings.Canary.SoakDuration.Duration, sleepAfterTerminate); err != nil {
+			return err
+		}
+		update = update[canaryCount:]
+		noneReady = false
+		if maxSurge > len(update) {
+			maxSurge = len(update)
+		}
+	}
+
 	if maxSurge > 0 && !c.CloudOnly {
 		skippedNodes := 0
 		for numSurge := 1; numSurge <= maxSurge; numSurge++ {
@@ -242,6 +272,11 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
+		err = c.runAfterValidateHooks(group)
+		if err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
 		if c.Interactive {
 			nodeName := ""
 			if u.Node != nil {
@@ -287,6 +322,11 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.
 		if err != nil {
 			return err
 		}
+
+		err = c.runAfterValidateHooks(group)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -422,19 +462,27 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 
 	isBastion := u.CloudInstanceGroup.InstanceGroup.IsBastion()
 
+	if err := c.runBeforeDrainHooks(u); err != nil {
+		return err
+	}
+
 	if isBastion {
 		// We don't want to validate for bastions - they aren't part of the cluster
 	} else if c.CloudOnly {
 		klog.Warning("Not draining cluster nodes as 'cloudonly' flag is set.")
 	} else {
-		if u.Node != nil {
+		if u.Node != nil && u.Node.Spec.Unschedulable && c.progress.phase(u) == journal.PhaseDrained {
+			klog.Infof("Node %q was drained by an interrupted rolling update; not draining again.", nodeName)
+		} else if u.Node != nil {
 			klog.Infof("Draining the node: %q.", nodeName)
 
-			if err := c.drainNode(u); err != nil {
+			if err := c.guardedDrainNode(u, true); err != nil {
 				if c.FailOnDrainError {
 					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
 				}
 				klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+			} else {
+				c.progress.record(c.Ctx, u, journal.PhaseDrained)
 			}
 		} else {
 			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceID)
@@ -459,6 +507,8 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(u *cloudinstances.CloudInst
 		klog.Errorf("error deleting instance %q, node %q: %v", instanceID, nodeName, err)
 		return err
 	}
+	c.progress.record(c.Ctx, u, journal.PhaseTerminated)
+	c.addPendingAfterValidate(u)
 
 	if err := c.reconcileInstanceGroup(); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
@@ -516,6 +566,8 @@ func (c *RollingUpdateCluster) maybeValidate(operation string, validateCount int
 			}
 
 			klog.Warningf("Cluster validation failed%s, proceeding since fail-on-validate is set to false: %v", operation, err)
+		} else {
+			c.progress.recordValidated(c.Ctx, group)
 		}
 	}
 	return nil
@@ -535,7 +587,7 @@ func (c *RollingUpdateCluster) validateClusterWithTimeout(validateCount int, gro
 
 	for {
 		// Note that we validate at least once before checking the timeout, in case the cluster is healthy with a short timeout
-		result, err := c.ClusterValidator.Validate()
+		result, err := c.ClusterValidator.Validate(c.Ctx)
 		if err == nil && !hasFailureRelevantToGroup(result.Failures, group) {
 			successCount++
 			if successCount >= validateCount {
@@ -581,6 +633,16 @@ func (c *RollingUpdateCluster) validateClusterWithTimeout(validateCount int, gro
 func hasFailureRelevantToGroup(failures []*validation.ValidationError, group *cloudinstances.CloudInstanceGroup) bool {
 	// Ignore non critical validation errors in other instance groups like below target size errors
 	for _, failure := range failures {
+		// Failures of checks gated to some instance groups only block the rolling updates of those groups
+		if len(failure.InstanceGroups) != 0 {
+			for _, ig := range failure.InstanceGroups {
+				if ig.Name == group.InstanceGroup.Name {
+					return true
+				}
+			}
+			continue
+		}
+
 		// Certain failures like a system-critical-pod failure and dns server related failures
 		// set their InstanceGroup to nil, since we cannot associate the failure to any one group
 		if failure.InstanceGroup == nil {
@@ -615,6 +677,7 @@ func (c *RollingUpdateCluster) detachInstance(u *cloudinstances.CloudInstance) e
 		}
 		return fmt.Errorf("error detaching instance %q: %v", id, err)
 	}
+	c.progress.record(c.Ctx, u, journal.PhaseDetached)
 
 	return nil
 }
@@ -642,8 +705,28 @@ func (c *RollingUpdateCluster) deleteInstance(u *cloudinstances.CloudInstance) e
 	return nil
 }
 
+// guardedDrainNode drains a K8s node, first waiting for any concurrent drain disrupting the same StatefulSets.
+func (c *RollingUpdateCluster) guardedDrainNode(u *cloudinstances.CloudInstance, deregister bool) error {
+	guard := c.statefulSetGuard
+	if guard == nil || c.K8sClient == nil {
+		return c.drainNode(u, deregister)
+	}
+
+	statefulSets, err := guard.acquire(c.Ctx, c.K8sClient, u.Node)
+	if err != nil {
+		return err
+	}
+
+	drainErr := c.drainNode(u, deregister)
+	if err := guard.release(c.Ctx, c.K8sClient, statefulSets, c.DrainTimeout); err != nil && drainErr == nil {
+		return err
+	}
+	return drainErr
+}
+
 // drainNode drains a K8s node.
-func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error {
+// If deregister is set, the instance is also deregistered from the cloud load balancers, as it is about to be terminated.
+func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance, deregister bool) error {
 	if c.K8sClient == nil {
 		return fmt.Errorf("K8sClient not set")
 	}
@@ -684,8 +767,8 @@ func (c *RollingUpdateCluster) drainNode(u *cloudinstances.CloudInstance) error
 		return fmt.Errorf("error excluding node from load balancer: %v", err)
 	}
 
-	shouldDeregister := true
-	if !c.Options.DeregisterControlPlaneNodes {
+	shouldDeregister := deregister
+	if shouldDeregister && !c.Options.DeregisterControlPlaneNodes {
 		if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
 			role := u.CloudInstanceGroup.InstanceGroup.Spec.Role
 			switch role {
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phase is the progress of an instance through a rolling update.
+type Phase string
+
+const (
+	// PhasePending means the instance is to be replaced, but no action has been taken yet.
+	PhasePending Phase = "Pending"
+	// PhaseDetached means the instance has been detached from its group, so that a replacement is surged.
+	PhaseDetached Phase = "Detached"
+	// PhaseDrained means the node has been cordoned and drained.
+	PhaseDrained Phase = "Drained"
+	// PhaseTerminated means the instance has been terminated.
+	PhaseTerminated Phase = "Terminated"
+	// PhaseReconfigured means nodeup has reconfigured the instance in place, and the node has been uncordoned.
+	PhaseReconfigured Phase = "Reconfigured"
+	// PhaseValidated means the cluster validated after the instance was terminated or reconfigured.
+	PhaseValidated Phase = "Validated"
+)
+
+// IsComplete returns true if the instance no longer needs any action.
+func (p Phase) IsComplete() bool {
+	return p == PhaseTerminated || p == PhaseReconfigured || p == PhaseValidated
+}
+
+// Journal records the progress of a rolling update, so that an interrupted rolling update can be resumed
+// and so that other operators can see the rolling update is in flight.
+type Journal struct {
+	// ClusterName is the name of the cluster being updated.
+	ClusterName string `json:"clusterName"`
+	// Owner identifies who is running the rolling update.
+	Owner string `json:"owner,omitempty"`
+	// StartedAt is when the rolling update was first started.
+	StartedAt metav1.Time `json:"startedAt"`
+	// UpdatedAt is when the journal was last written.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+	// LastError is the error that stopped the rolling update, if any.
+	LastError string `json:"lastError,omitempty"`
+	// Instances are the instances selected for replacement when the rolling update was started.
+	Instances []*Instance `json:"instances,omitempty"`
+}
+
+// Instance records the progress of a single instance.
+type Instance struct {
+	// ID is the cloud identifier of the instance.
+	ID string `json:"id"`
+	// InstanceGroup is the name of the InstanceGroup the instance belongs to.
+	InstanceGroup string `json:"instanceGroup"`
+	// NodeName is the name of the kubernetes Node, if the instance was registered.
+	NodeName string `json:"nodeName,omitempty"`
+	// Phase is the last completed step for the instance.
+	Phase Phase `json:"phase"`
+	// UpdatedAt is when the phase was recorded.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// FindInstance returns the instance with the specified ID, or nil if it is not in the journal.
+func (j *Journal) FindInstance(id string) *Instance {
+	for _, instance := range j.Instances {
+		if instance.ID == id {
+			return instance
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups/journal"
+)
+
+// progressJournal records the progress of a rolling update into the state store.
+// A nil progressJournal records nothing.
+type progressJournal struct {
+	mutex   sync.Mutex
+	client  simple.RollingUpdateJournalClient
+	journal *journal.Journal
+}
+
+// ResumeFromJournal reads the journal of an interrupted rolling update, if there is one,
+// and adjusts the groups so that only the instances selected by the interrupted rolling update,
+// and any instances that have since been marked as needing update, are replaced.
+// It returns the journal being resumed, or nil if there is no interrupted rolling update.
+func (c *RollingUpdateCluster) ResumeFromJournal(ctx context.Context, groups map[string]*cloudinstances.CloudInstanceGroup) (*journal.Journal, error) {
+	if c.Journal == nil {
+		return nil, nil
+	}
+
+	j, err := c.Journal.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if j == nil {
+		return nil, nil
+	}
+
+	for _, group := range groups {
+		var ready []*cloudinstances.CloudInstance
+		for _, u := range group.Ready {
+			instance := j.FindInstance(u.ID)
+			if instance != nil && !instance.Phase.IsComplete() {
+				group.NeedUpdate = append(group.NeedUpdate, u)
+				u.Status = cloudinstances.CloudInstanceStatusNeedsUpdate
+			} else {
+				ready = append(ready, u)
+			}
+		}
+		group.Ready = ready
+	}
+
+	// The instances selected by --force were recorded in the journal; we must not replace their replacements.
+	if c.Force {
+		klog.Warningf("Ignoring --force: only the instances selected by the rolling update started at %s are forced", j.StartedAt.Format(time.RFC3339))
+		c.Force = false
+	}
+	c.resumed = j
+
+	return j, nil
+}
+
+// startJournal writes the journal for the instances selected for replacement.
+func (c *RollingUpdateCluster) startJournal(groups map[string]*cloudinstances.CloudInstanceGroup) error {
+	now := metav1.Now()
+
+	j := c.resumed
+	if j == nil {
+		j = &journal.Journal{
+			ClusterName: c.Cluster.Name,
+			Owner:       journalOwner(),
+			StartedAt:   now,
+		}
+	}
+	j.LastError = ""
+
+	for _, k := range sortGroups(groups) {
+		group := groups[k]
+		update := group.NeedUpdate
+		if c.Force {
+			update = append(update, group.Ready...)
+		}
+		for _, u := range update {
+			if j.FindInstance(u.ID) != nil {
+				continue
+			}
+			instance := &journal.Instance{
+				ID:            u.ID,
+				InstanceGroup: group.InstanceGroup.Name,
+				Phase:         journal.PhasePending,
+				UpdatedAt:     now,
+			}
+			if u.Node != nil {
+				instance.NodeName = u.Node.Name
+			}
+			j.Instances = append(j.Instances, instance)
+		}
+	}
+
+	c.progress = &progressJournal{
+		client:  c.Journal,
+		journal: j,
+	}
+	return c.progress.write(c.Ctx)
+}
+
+// finishJournal records the error if the rolling update failed.
+// Otherwise it removes the instances of the updated groups from the journal, so that the progress of groups
+// excluded with --instance-group or --instance-group-roles is kept, and removes the journal once it is empty.
+func (c *RollingUpdateCluster) finishJournal(groups map[string]*cloudinstances.CloudInstanceGroup, rollingUpdateErr error) {
+	p := c.progress
+	if p == nil {
+		return
+	}
+	c.progress = nil
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if rollingUpdateErr == nil {
+		updated := make(map[string]bool)
+		for _, group := range groups {
+			if group.InstanceGroup != nil {
+				updated[group.InstanceGroup.Name] = true
+			}
+		}
+		var remaining []*journal.Instance
+		for _, instance := range p.journal.Instances {
+			if !updated[instance.InstanceGroup] {
+				remaining = append(remaining, instance)
+			}
+		}
+
+		if len(remaining) == 0 {
+			if err := p.client.Delete(c.Ctx); err != nil {
+				klog.Warningf("failed to delete rolling update journal: %v", err)
+			}
+			return
+		}
+
+		p.journal.Instances = remaining
+		if err := p.writeLocked(c.Ctx); err != nil {
+			klog.Warningf("failed to remove updated instance groups from rolling update journal: %v", err)
+		}
+		return
+	}
+
+	p.journal.LastError = rollingUpdateErr.Error()
+	if err := p.writeLocked(c.Ctx); err != nil {
+		klog.Warningf("failed to record error in rolling update journal: %v", err)
+	}
+}
+
+// phase returns the recorded phase for the instance, or "" if it is not recorded.
+func (p *progressJournal) phase(u *cloudinstances.CloudInstance) journal.Phase {
+	if p == nil {
+		return ""
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	instance := p.journal.FindInstance(u.ID)
+	if instance == nil {
+		return ""
+	}
+	return instance.Phase
+}
+
+// record records that the instance has reached the phase.
+// Failures to write the journal are logged, but do not stop the rolling update.
+func (p *progressJournal) record(ctx context.Context, u *cloudinstances.CloudInstance, phase journal.Phase) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	instance := p.journal.FindInstance(u.ID)
+	if instance == nil {
+		instance = &journal.Instance{
+			ID: u.ID,
+		}
+		if u.CloudInstanceGroup != nil && u.CloudInstanceGroup.InstanceGroup != nil {
+			instance.InstanceGroup = u.CloudInstanceGroup.InstanceGroup.Name
+		}
+		if u.Node != nil {
+			instance.NodeName = u.Node.Name
+		}
+		p.journal.Instances = append(p.journal.Instances, instance)
+	}
+	instance.Phase = phase
+	instance.UpdatedAt = metav1.Now()
+
+	if err := p.writeLocked(ctx); err != nil {
+		klog.Warningf("failed to record instance %q as %s in rolling update journal: %v", u.ID, phase, err)
+	}
+}
+
+// recordValidated records that the cluster validated after the terminated instances in the group were replaced,
+// or the reconfigured instances were uncordoned.
+func (p *progressJournal) recordValidated(ctx context.Context, group *cloudinstances.CloudInstanceGroup) {
+	if p == nil || group == nil || group.InstanceGroup == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	changed := false
+	for _, instance := range p.journal.Instances {
+		if instance.InstanceGroup == group.InstanceGroup.Name && (instance.Phase == journal.PhaseTerminated || instance.Phase == journal.PhaseReconfigured) {
+			instance.Phase = journal.PhaseValidated
+			instance.UpdatedAt = metav1.Now()
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := p.writeLocked(ctx); err != nil {
+		klog.Warningf("failed to record validation in rolling update journal: %v", err)
+	}
+}
+
+func (p *progressJournal) write(ctx context.Context) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.writeLocked(ctx)
+}
+
+func (p *progressJournal) writeLocked(ctx context.Context) error {
+	p.journal.UpdatedAt = metav1.Now()
+	return p.client.Put(ctx, p.journal)
+}
+
+// journalOwner identifies the user and host running the rolling update.
+func journalOwner() string {
+	owner := "unknown"
+	if u, err := user.Current(); err == nil {
+		owner = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		owner = fmt.Sprintf("%s@%s", owner, hostname)
+	}
+	return owner
+}
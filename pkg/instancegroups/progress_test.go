@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups/journal"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func getTestJournal(t *testing.T, cluster *kopsapi.Cluster) simple.RollingUpdateJournalClient {
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building base path: %v", err)
+	}
+	return vfsclientset.NewVFSClientset(vfs.Context, basePath).RollingUpdateJournalFor(cluster)
+}
+
+func TestRollingUpdateJournalDeletedOnSuccess(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Journal = getTestJournal(t, c.Cluster)
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	j, err := c.Journal.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, j, "journal is removed after a successful rolling update")
+}
+
+func TestRollingUpdateJournalRecordsProgressOnFailure(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Journal = getTestJournal(t, c.Cluster)
+	c.ClusterValidator = &failAfterOneNodeClusterValidator{
+		Cloud:       cloud,
+		Group:       "node-1",
+		ReturnError: false,
+	}
+
+	groups := getGroupsAllNeedUpdate(c.K8sClient, cloud)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.Error(t, err, "rolling update")
+
+	j, err := c.Journal.Get(context.Background())
+	assert.NoError(t, err)
+	if !assert.NotNil(t, j, "journal is kept after a failed rolling update") {
+		return
+	}
+	assert.Equal(t, "test.k8s.local", j.ClusterName)
+	assert.NotEmpty(t, j.LastError)
+	assert.Len(t, j.Instances, 9, "all selected instances are recorded")
+
+	phases := map[string]journal.Phase{}
+	for _, instance := range j.Instances {
+		phases[instance.ID] = instance.Phase
+	}
+	assert.Equal(t, journal.PhaseValidated, phases["master-1a"])
+	assert.Equal(t, journal.PhaseValidated, phases["master-1b"])
+	assert.Equal(t, journal.PhaseTerminated, phases["node-1a"])
+	assert.Equal(t, journal.PhasePending, phases["node-2a"])
+}
+
+func TestRollingUpdateResumeFromJournal(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Journal = getTestJournal(t, c.Cluster)
+	c.Force = true
+
+	err := c.Journal.Put(context.Background(), &journal.Journal{
+		ClusterName: "test.k8s.local",
+		Instances: []*journal.Instance{
+			{ID: "node-1a", InstanceGroup: "node-1", Phase: journal.PhaseTerminated},
+			{ID: "node-1b", InstanceGroup: "node-1", Phase: journal.PhaseDrained},
+			{ID: "node-1c", InstanceGroup: "node-1", Phase: journal.PhasePending},
+		},
+	})
+	assert.NoError(t, err)
+
+	// node-1a is the replacement of the terminated instance, and so has the same id in our mocks
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 0)
+
+	resumed, err := c.ResumeFromJournal(context.Background(), groups)
+	assert.NoError(t, err)
+	assert.NotNil(t, resumed)
+	assert.False(t, c.Force, "force is disabled when resuming")
+
+	var needUpdate []string
+	for _, u := range groups["node-1"].NeedUpdate {
+		needUpdate = append(needUpdate, u.ID)
+	}
+	assert.ElementsMatch(t, []string{"node-1b", "node-1c"}, needUpdate)
+	assert.Len(t, groups["node-1"].Ready, 1)
+	assert.Empty(t, groups["node-2"].NeedUpdate)
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+}
+
+func TestRollingUpdateJournalKeepsOtherGroups(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Journal = getTestJournal(t, c.Cluster)
+
+	err := c.Journal.Put(context.Background(), &journal.Journal{
+		ClusterName: "test.k8s.local",
+		Instances: []*journal.Instance{
+			{ID: "node-1a", InstanceGroup: "node-1", Phase: journal.PhasePending},
+			{ID: "node-2a", InstanceGroup: "node-2", Phase: journal.PhasePending},
+		},
+	})
+	assert.NoError(t, err)
+
+	// As with --instance-group node-1
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 0)
+
+	_, err = c.ResumeFromJournal(context.Background(), groups)
+	assert.NoError(t, err)
+
+	err = c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	assert.NoError(t, err, "rolling update")
+
+	j, err := c.Journal.Get(context.Background())
+	assert.NoError(t, err)
+	if !assert.NotNil(t, j, "journal is kept while other groups have recorded progress") {
+		return
+	}
+	if assert.Len(t, j.Instances, 1) {
+		assert.Equal(t, "node-2a", j.Instances[0].ID)
+	}
+}
+
+func TestRollingUpdateResumeWithoutJournal(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Journal = getTestJournal(t, c.Cluster)
+	c.Force = true
+
+	groups := getGroups(c.K8sClient, cloud)
+	resumed, err := c.ResumeFromJournal(context.Background(), groups)
+	assert.NoError(t, err)
+	assert.Nil(t, resumed)
+	assert.True(t, c.Force, "force is kept when not resuming")
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+)
+
+// rollingUpdateInstanceGroup performs a rolling update on a list of instances,
+// rolling back the instance group if requested and the new instances fail validation.
+func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration) error {
+	err := c.updateInstanceGroup(group, sleepAfterTerminate, c.Force)
+	if err == nil || !c.shouldRollBack(group, err) {
+		return err
+	}
+	return c.rollBackInstanceGroup(group, sleepAfterTerminate, err)
+}
+
+// shouldRollBack returns true if the error shows that the cluster failed to validate
+// after instances of the group were replaced, and the group should be rolled back.
+func (c *RollingUpdateCluster) shouldRollBack(group *cloudinstances.CloudInstanceGroup, err error) bool {
+	if !c.Options.RollbackOnFailure || c.CloudOnly || group.InstanceGroup.Spec.Role != api.InstanceGroupRoleNode {
+		return false
+	}
+
+	var canaryErr *CanaryFailedError
+	if errors.As(err, &canaryErr) {
+		return true
+	}
+	// A validation failure before any instance was replaced has no operation
+	var validationErr *ValidationTimeoutError
+	return errors.As(err, &validationErr) && validationErr.operation != ""
+}
+
+// rollBackInstanceGroup reverts the group to its previously applied spec,
+// then replaces the instances that no longer match it.
+// The returned error always wraps the error that caused the rollback.
+func (c *RollingUpdateCluster) rollBackInstanceGroup(group *cloudinstances.CloudInstanceGroup, sleepAfterTerminate time.Duration, cause error) error {
+	name := group.InstanceGroup.Name
+	klog.Warningf("Rolling back InstanceGroup %q after failed rolling update: %v", name, cause)
+
+	revert := c.revertInstanceGroup
+	if revert == nil {
+		revert = c.revertInstanceGroupSpec
+	}
+	reverted, err := revert(group.InstanceGroup)
+	if err != nil {
+		return fmt.Errorf("rolling update of InstanceGroup %q failed: %w; reverting its spec failed: %v", name, cause, err)
+	}
+
+	klog.Infof("Replacing %d instance(s) of InstanceGroup %q not matching its previous spec.", len(reverted.NeedUpdate), name)
+	if err := c.updateInstanceGroup(reverted, sleepAfterTerminate, false); err != nil {
+		return fmt.Errorf("rolling update of InstanceGroup %q failed: %w; rolling back its instances failed: %v", name, cause, err)
+	}
+
+	return fmt.Errorf("rolled back InstanceGroup %q after failed rolling update: %w", name, cause)
+}
+
+// revertInstanceGroupSpec writes the previously applied revision of the InstanceGroup to the state store,
+// applies the launch template or instance template of the InstanceGroup,
+// and returns the cloud group with the instances that need updating to match it.
+func (c *RollingUpdateCluster) revertInstanceGroupSpec(ig *api.InstanceGroup) (*cloudinstances.CloudInstanceGroup, error) {
+	if c.Clientset == nil {
+		return nil, fmt.Errorf("rolling back requires a clientset")
+	}
+
+	revisions, err := c.Clientset.InstanceGroupRevisionsFor(c.Cluster).List(c.Ctx, ig.Name)
+	if err != nil {
+		return nil, err
+	}
+	// The newest revision is the spec we are rolling back from
+	if len(revisions) < 2 {
+		return nil, fmt.Errorf("no previously applied revision of InstanceGroup %q found", ig.Name)
+	}
+	previous := revisions[1]
+
+	current, err := c.Clientset.InstanceGroupsFor(c.Cluster).Get(c.Ctx, ig.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error reading InstanceGroup %q: %w", ig.Name, err)
+	}
+	current.Spec = previous.Spec
+	current, err = c.Clientset.InstanceGroupsFor(c.Cluster).Update(c.Ctx, current, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error writing InstanceGroup %q: %w", ig.Name, err)
+	}
+
+	rto := fi.RunTasksOptions{}
+	rto.InitDefaults()
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Cloud:              c.Cloud,
+		Clientset:          c.Clientset,
+		Cluster:            c.Cluster,
+		AllowKopsDowngrade: true,
+		RunTasksOptions:    &rto,
+		TargetName:         cloudup.TargetDirect,
+		LifecycleOverrides: map[string]fi.Lifecycle{},
+
+		DeletionProcessing:        fi.DeletionProcessingModeIgnore,
+		OnlyInstanceGroupTemplate: ig.Name,
+	}
+	if err := applyCmd.Run(c.Ctx); err != nil {
+		return nil, fmt.Errorf("error applying the previous template of InstanceGroup %q: %w", ig.Name, err)
+	}
+
+	nodes, err := c.K8sClient.CoreV1().Nodes().List(c.Ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %w", err)
+	}
+	groups, err := c.Cloud.GetCloudGroups(c.Cluster, []*api.InstanceGroup{current}, false, nodes.Items)
+	if err != nil {
+		return nil, err
+	}
+	group := groups[ig.Name]
+	if group == nil {
+		return nil, fmt.Errorf("cloud group of InstanceGroup %q not found", ig.Name)
+	}
+	group.AdjustNeedUpdate()
+	return group, nil
+}
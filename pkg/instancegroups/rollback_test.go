@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// fakeRevert replaces the cloud group with one where the given number of instances do not match the reverted spec,
+// and makes the cluster validate from then on.
+func fakeRevert(c *RollingUpdateCluster, cloud *awsup.MockAWSCloud, needUpdate int, reverted *[]string) func(ig *kopsapi.InstanceGroup) (*cloudinstances.CloudInstanceGroup, error) {
+	return func(ig *kopsapi.InstanceGroup) (*cloudinstances.CloudInstanceGroup, error) {
+		*reverted = append(*reverted, ig.Name)
+		c.ClusterValidator = &successfulClusterValidator{}
+
+		groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+		makeGroup(groups, c.K8sClient, cloud, ig.Name, ig.Spec.Role, 3, needUpdate)
+		return groups[ig.Name], nil
+	}
+}
+
+func TestRollingUpdateRollbackOnFailure(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.RollbackOnFailure = true
+	// Passes the initial validation, then fails after the first instance is replaced
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 1}
+	var reverted []string
+	c.revertInstanceGroup = fakeRevert(c, cloud, 1, &reverted)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	makeGroup(groups, c.K8sClient, cloud, "node-2", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), `rolled back InstanceGroup "node-1" after failed rolling update`)
+		assert.True(t, errors.Is(err, &ValidationTimeoutError{}), "wraps the validation error")
+	}
+
+	assert.Equal(t, []string{"node-1"}, reverted)
+	// The instance not matching the reverted spec was replaced
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+	// The rolling update stops after the rollback
+	assertGroupInstanceCount(t, cloud, "node-2", 3)
+}
+
+func TestRollingUpdateRollbackOnCanaryFailure(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.RollbackOnFailure = true
+	c.Cluster.Spec.RollingUpdate = canaryRollingUpdate(intstr.FromInt(1))
+	// Passes the initial validation and the validations after replacing the canary
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 3}
+	var reverted []string
+	c.revertInstanceGroup = fakeRevert(c, cloud, 1, &reverted)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+
+	var canaryErr *CanaryFailedError
+	assert.True(t, errors.As(err, &canaryErr), "canary error, got %v", err)
+	assert.Equal(t, []string{"node-1"}, reverted)
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
+
+func TestRollingUpdateNoRollback(t *testing.T) {
+	for _, test := range []struct {
+		name              string
+		rollbackOnFailure bool
+		role              kopsapi.InstanceGroupRole
+		passingCalls      int
+	}{
+		{
+			name:         "option not set",
+			role:         kopsapi.InstanceGroupRoleNode,
+			passingCalls: 1,
+		},
+		{
+			name:              "initial validation failed",
+			rollbackOnFailure: true,
+			role:              kopsapi.InstanceGroupRoleNode,
+		},
+		{
+			name:              "control plane",
+			rollbackOnFailure: true,
+			role:              kopsapi.InstanceGroupRoleControlPlane,
+			passingCalls:      1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c, cloud := getTestSetup()
+			c.Options.RollbackOnFailure = test.rollbackOnFailure
+			c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: test.passingCalls}
+			var reverted []string
+			c.revertInstanceGroup = fakeRevert(c, cloud, 1, &reverted)
+
+			groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+			makeGroup(groups, c.K8sClient, cloud, "group-1", test.role, 3, 3)
+			err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+			if assert.Error(t, err, "rolling update") {
+				assert.NotContains(t, err.Error(), "rolled back")
+			}
+			assert.Empty(t, reverted)
+		})
+	}
+}
+
+func TestRollingUpdateRollbackRevertFailure(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.Options.RollbackOnFailure = true
+	c.ClusterValidator = &failAfterCallsClusterValidator{PassingCalls: 1}
+	c.revertInstanceGroup = func(ig *kopsapi.InstanceGroup) (*cloudinstances.CloudInstanceGroup, error) {
+		return nil, errors.New("no previous revision")
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	err := c.RollingUpdate(groups, &kopsapi.InstanceGroupList{})
+	if assert.Error(t, err, "rolling update") {
+		assert.Contains(t, err.Error(), "reverting its spec failed: no previous revision")
+		assert.True(t, errors.Is(err, &ValidationTimeoutError{}), "wraps the validation error")
+	}
+
+	assertGroupInstanceCount(t, cloud, "node-1", 2)
+}
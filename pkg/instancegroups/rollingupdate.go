@@ -31,6 +31,7 @@ import (
 	"k8s.io/klog/v2"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/instancegroups/journal"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -83,18 +84,64 @@ type RollingUpdateCluster struct {
 	// DrainTimeout is the maximum amount of time to wait while draining a node.
 	DrainTimeout time.Duration
 
+	// ReconfigureTimeout is the maximum amount of time to wait for nodeup to reconfigure a node in place.
+	// Defaults to 15 minutes.
+	ReconfigureTimeout time.Duration
+
 	// Options holds user-specified options
 	Options RollingUpdateOptions
+
+	// Journal persists the progress of the rolling update, so that an interrupted rolling update can be resumed.
+	// Optional; if nil, progress is not recorded.
+	Journal simple.RollingUpdateJournalClient
+
+	// resumed is the journal of the interrupted rolling update we are resuming, if any
+	resumed *journal.Journal
+	// progress records the progress of the running rolling update
+	progress *progressJournal
+
+	// statefulSetGuard serializes drains of nodes hosting pods of the same StatefulSet.
+	// It is only set while node instance groups are being rolled concurrently.
+	statefulSetGuard *statefulSetGuard
+
+	// hookMutex guards pendingAfterValidate
+	hookMutex sync.Mutex
+	// pendingAfterValidate holds, by instance group name, the instances replaced since the cluster last validated
+	pendingAfterValidate map[string][]*cloudinstances.CloudInstance
+
+	// revertInstanceGroup, if set, replaces revertInstanceGroupSpec; used for testing
+	revertInstanceGroup func(ig *api.InstanceGroup) (*cloudinstances.CloudInstanceGroup, error)
+
+	// nodeupConfigHash, if set, replaces readNodeupConfigHash; used for testing
+	nodeupConfigHash func(ig *api.InstanceGroup) (string, error)
 }
 
 type RollingUpdateOptions struct {
 	// DeregisterControlPlaneNodes controls if we deregister control plane instances from load balacners etc before draining/terminating.
 	// When a cluster only has a single apiserver, we don't want to do this, as we can't drain after deregistering it.
 	DeregisterControlPlaneNodes bool
+
+	// MaxConcurrentNodeGroups is the maximum number of node instance groups that are rolled at the same time.
+	// Values less than 1 are treated as 1, which rolls node instance groups one after another.
+	MaxConcurrentNodeGroups int
+
+	// OneGroupPerZone prevents two node instance groups that share a zone from being rolled at the same time.
+	OneGroupPerZone bool
+
+	// RollbackOnFailure reverts a node instance group to its previously applied spec, and replaces the
+	// instances created from the failed spec, when the cluster fails to validate after replacing instances.
+	RollbackOnFailure bool
+
+	// AllowExecHooks allows the rolling update hooks of the cluster spec to run commands on this machine.
+	// Without it, rolling updates of instance groups with exec hooks are refused.
+	AllowExecHooks bool
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
 	o.DeregisterControlPlaneNodes = true
+	o.MaxConcurrentNodeGroups = 1
+	o.OneGroupPerZone = false
+	o.RollbackOnFailure = false
 }
 
 // AdjustNeedUpdate adjusts the set of instances that need updating, using factors outside those known by the cloud implementation
@@ -112,6 +159,22 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 		return nil
 	}
 
+	if err := c.checkExecHooks(groups); err != nil {
+		return err
+	}
+
+	if c.Journal != nil {
+		if err := c.startJournal(groups); err != nil {
+			return fmt.Errorf("error writing rolling update journal: %w", err)
+		}
+	}
+
+	err := c.rollingUpdate(groups)
+	c.finishJournal(groups, err)
+	return err
+}
+
+func (c *RollingUpdateCluster) rollingUpdate(groups map[string]*cloudinstances.CloudInstanceGroup) error {
 	var resultsMutex sync.Mutex
 	results := make(map[string]error)
 
@@ -201,26 +264,18 @@ func (c *RollingUpdateCluster) RollingUpdate(groups map[string]*cloudinstances.C
 
 	// Upgrade nodes
 	{
-		// We run nodes in series, even if they are in separate instance groups
-		// typically they will not being separate instance groups. If you roll the nodes in parallel
-		// you can get into a scenario where you can evict multiple statefulset pods from the same
-		// statefulset at the same time. Further improvements needs to be made to protect from this as
-		// well.
+		// By default we run nodes in series, even if they are in separate instance groups.
+		// If you roll the nodes in parallel you can get into a scenario where you can evict multiple
+		// statefulset pods from the same statefulset at the same time. When concurrent rolling is enabled,
+		// drains are therefore serialized per statefulset (see statefulSetGuard); PodDisruptionBudgets are
+		// enforced by the eviction API across all groups being rolled.
 
 		for k := range nodeGroups {
 			results[k] = fmt.Errorf("function panic nodes")
 		}
 
-		for _, k := range sortGroups(nodeGroups) {
-			err := c.rollingUpdateInstanceGroup(nodeGroups[k], c.NodeInterval)
-			results[k] = err
-			if err != nil {
-				klog.Errorf("failed to roll InstanceGroup %q: %v", k, err)
-			}
-
-			if isExitableError(err) {
-				return err
-			}
+		if err := c.rollingUpdateNodeGroups(nodeGroups, results); err != nil {
+			return err
 		}
 	}
 
@@ -249,7 +304,9 @@ func sortGroups(groupMap map[string]*cloudinstances.CloudInstanceGroup) []string
 //
 // For example, if a cluster is unable to be validated by the deadline, then it
 // is unlikely that it will validate on the next instance roll, so an early exit as a
-// warning to the user is more appropriate.
+// warning to the user is more appropriate. Likewise, a failed canary is expected to
+// be investigated before any more instances are replaced.
 func isExitableError(err error) bool {
-	return stderrors.Is(err, &ValidationTimeoutError{})
+	var canaryErr *CanaryFailedError
+	return stderrors.Is(err, &ValidationTimeoutError{}) || stderrors.As(err, &canaryErr)
 }
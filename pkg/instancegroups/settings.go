@@ -17,6 +17,9 @@ limitations under the License.
 package instancegroups
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"k8s.io/kops/pkg/apis/kops"
@@ -40,12 +43,46 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxSurge == nil {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.Hooks == nil {
+			rollingUpdate.Hooks = def.Hooks
+		}
+		if rollingUpdate.Strategy == "" {
+			rollingUpdate.Strategy = def.Strategy
+		}
+		if rollingUpdate.Canary == nil {
+			rollingUpdate.Canary = def.Canary
+		}
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
 		rollingUpdate.DrainAndTerminate = fi.PtrTo(true)
 	}
 
+	if rollingUpdate.Strategy == "" {
+		rollingUpdate.Strategy = kops.RollingUpdateStrategyRolling
+	}
+
+	if rollingUpdate.Strategy == kops.RollingUpdateStrategyCanary {
+		canary := kops.RollingUpdateCanary{}
+		if rollingUpdate.Canary != nil {
+			canary = *rollingUpdate.Canary
+		}
+		if canary.Count == nil {
+			canary.Count = fi.PtrTo(intstr.FromInt(1))
+		}
+		if canary.Count.Type == intstr.String {
+			count, _ := intstr.GetScaledValueFromIntOrPercent(canary.Count, numInstances, true)
+			if count <= 0 {
+				count = 1
+			}
+			canary.Count = fi.PtrTo(intstr.FromInt(count))
+		}
+		if canary.SoakDuration == nil {
+			canary.SoakDuration = &metav1.Duration{Duration: 10 * time.Minute}
+		}
+		rollingUpdate.Canary = &canary
+	}
+
 	if rollingUpdate.MaxSurge == nil {
 		val := intstr.FromInt(0)
 		if cluster.Spec.GetCloudProvider() == kops.CloudProviderAWS && !featureflag.Spotinst.Enabled() && group.Spec.Manager != kops.InstanceManagerKarpenter {
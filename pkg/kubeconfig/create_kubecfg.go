@@ -131,7 +131,8 @@ func BuildKubecfg(ctx context.Context, cluster *kops.Cluster, keyStore fi.Keysto
 				CommonName:   cn,
 				Organization: []string{rbac.SystemPrivilegedGroup},
 			},
-			Validity: admin,
+			Validity:     admin,
+			KeyAlgorithm: pki.KeyAlgorithm(cluster.KeyAlgorithm()),
 		}
 		cert, privateKey, _, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(keyStore))
 		if err != nil {
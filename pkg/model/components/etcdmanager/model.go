@@ -33,6 +33,7 @@ import (
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -116,34 +117,38 @@ func (b *EtcdManagerBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 
 		// We create a CA keypair to enable secure communication
 		c.AddTask(&fitasks.Keypair{
-			Name:      fi.PtrTo("etcd-manager-ca-" + etcdCluster.Name),
-			Lifecycle: b.Lifecycle,
-			Subject:   "cn=etcd-manager-ca-" + etcdCluster.Name,
-			Type:      "ca",
+			Name:         fi.PtrTo("etcd-manager-ca-" + etcdCluster.Name),
+			Lifecycle:    b.Lifecycle,
+			Subject:      "cn=etcd-manager-ca-" + etcdCluster.Name,
+			Type:         "ca",
+			KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 		})
 
 		// We create a CA for etcd peers and a separate one for clients
 		c.AddTask(&fitasks.Keypair{
-			Name:      fi.PtrTo("etcd-peers-ca-" + etcdCluster.Name),
-			Lifecycle: b.Lifecycle,
-			Subject:   "cn=etcd-peers-ca-" + etcdCluster.Name,
-			Type:      "ca",
+			Name:         fi.PtrTo("etcd-peers-ca-" + etcdCluster.Name),
+			Lifecycle:    b.Lifecycle,
+			Subject:      "cn=etcd-peers-ca-" + etcdCluster.Name,
+			Type:         "ca",
+			KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 		})
 
 		// Because API server can only have a single client-cert, we need to share a client CA
 		c.EnsureTask(&fitasks.Keypair{
-			Name:      fi.PtrTo("etcd-clients-ca"),
-			Lifecycle: b.Lifecycle,
-			Subject:   "cn=etcd-clients-ca",
-			Type:      "ca",
+			Name:         fi.PtrTo("etcd-clients-ca"),
+			Lifecycle:    b.Lifecycle,
+			Subject:      "cn=etcd-clients-ca",
+			Type:         "ca",
+			KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 		})
 
 		if etcdCluster.Name == "cilium" {
 			clientsCaCilium := &fitasks.Keypair{
-				Name:      fi.PtrTo("etcd-clients-ca-cilium"),
-				Lifecycle: b.Lifecycle,
-				Subject:   "cn=etcd-clients-ca-cilium",
-				Type:      "ca",
+				Name:         fi.PtrTo("etcd-clients-ca-cilium"),
+				Lifecycle:    b.Lifecycle,
+				Subject:      "cn=etcd-clients-ca-cilium",
+				Type:         "ca",
+				KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 			}
 			c.AddTask(clientsCaCilium)
 		}
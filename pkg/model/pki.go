@@ -17,6 +17,7 @@ limitations under the License.
 package model
 
 import (
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/tokens"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
@@ -35,19 +36,21 @@ var _ fi.CloudupModelBuilder = &PKIModelBuilder{}
 func (b *PKIModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	// TODO: Only create the CA via this task
 	defaultCA := &fitasks.Keypair{
-		Name:      fi.PtrTo(fi.CertificateIDCA),
-		Lifecycle: b.Lifecycle,
-		Subject:   "cn=kubernetes-ca",
-		Type:      "ca",
+		Name:         fi.PtrTo(fi.CertificateIDCA),
+		Lifecycle:    b.Lifecycle,
+		Subject:      "cn=kubernetes-ca",
+		Type:         "ca",
+		KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 	}
 	c.AddTask(defaultCA)
 
 	{
 		aggregatorCA := &fitasks.Keypair{
-			Name:      fi.PtrTo("apiserver-aggregator-ca"),
-			Lifecycle: b.Lifecycle,
-			Subject:   "cn=apiserver-aggregator-ca",
-			Type:      "ca",
+			Name:         fi.PtrTo("apiserver-aggregator-ca"),
+			Lifecycle:    b.Lifecycle,
+			Subject:      "cn=apiserver-aggregator-ca",
+			Type:         "ca",
+			KeyAlgorithm: pki.KeyAlgorithm(b.Cluster.KeyAlgorithm()),
 		}
 		c.AddTask(aggregatorCA)
 	}
@@ -55,6 +58,7 @@ func (b *PKIModelBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	{
 		serviceAccount := &fitasks.Keypair{
 			// We only need the private key, but it's easier to create a certificate as well.
+			// It always uses RSA, as the service account issuer discovery documents only advertise RS256.
 			Name:      fi.PtrTo("service-account"),
 			Lifecycle: b.Lifecycle,
 			Subject:   "cn=service-account",
@@ -19,7 +19,9 @@ package aws
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -55,6 +57,11 @@ type nodeIdentifier struct {
 	cache expirationcache.Store
 	// cacheEnabled indicates if caching should be used
 	cacheEnabled bool
+
+	// instanceTypesMutex guards instanceTypes
+	instanceTypesMutex sync.Mutex
+	// instanceTypes caches the attributes of instance types, which do not change
+	instanceTypes map[string]map[string]string
 }
 
 // New creates and returns a nodeidentity.Identifier for Nodes running on AWS
@@ -81,9 +88,10 @@ func New(CacheNodeidentityInfo bool) (nodeidentity.Identifier, error) {
 	ec2Client := ec2.New(s, config.WithRegion(region))
 
 	return &nodeIdentifier{
-		ec2Client:    ec2Client,
-		cache:        expirationcache.NewTTLStore(stringKeyFunc, cacheTTL),
-		cacheEnabled: CacheNodeidentityInfo,
+		ec2Client:     ec2Client,
+		cache:         expirationcache.NewTTLStore(stringKeyFunc, cacheTTL),
+		cacheEnabled:  CacheNodeidentityInfo,
+		instanceTypes: make(map[string]map[string]string),
 	}, nil
 }
 
@@ -142,9 +150,16 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		labels[fmt.Sprintf("node-role.kubernetes.io/%s-worker", *instance.InstanceLifecycle)] = "true"
 	}
 
+	attributes, err := i.getInstanceTypeAttributes(aws.StringValue(instance.InstanceType))
+	if err != nil {
+		return nil, err
+	}
+
 	info := &nodeidentity.Info{
 		InstanceID: instanceID,
 		Labels:     labels,
+		Tags:       map[string]string{},
+		Attributes: attributes,
 	}
 
 	for _, tag := range instance.Tags {
@@ -152,6 +167,7 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		if strings.HasPrefix(key, ClusterAutoscalerNodeTemplateLabel) {
 			info.Labels[strings.TrimPrefix(aws.StringValue(tag.Key), ClusterAutoscalerNodeTemplateLabel)] = aws.StringValue(tag.Value)
 		}
+		info.Tags[key] = aws.StringValue(tag.Value)
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
@@ -186,3 +202,58 @@ func (i *nodeIdentifier) getInstance(instanceID string) (*ec2.Instance, error) {
 	instance := resp.Reservations[0].Instances[0]
 	return instance, nil
 }
+
+// getInstanceTypeAttributes returns the attributes of the instance type, querying EC2 the first time we see the type
+func (i *nodeIdentifier) getInstanceTypeAttributes(instanceType string) (map[string]string, error) {
+	i.instanceTypesMutex.Lock()
+	defer i.instanceTypesMutex.Unlock()
+
+	if attributes, found := i.instanceTypes[instanceType]; found {
+		return attributes, nil
+	}
+
+	resp, err := i.ec2Client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice([]string{instanceType}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error from ec2 DescribeInstanceTypes request: %v", err)
+	}
+	if len(resp.InstanceTypes) != 1 {
+		return nil, fmt.Errorf("found %d instance types for %q", len(resp.InstanceTypes), instanceType)
+	}
+
+	attributes := buildInstanceTypeAttributes(resp.InstanceTypes[0])
+	i.instanceTypes[instanceType] = attributes
+	return attributes, nil
+}
+
+// buildInstanceTypeAttributes returns the nodeidentity attributes of the instance type
+func buildInstanceTypeAttributes(info *ec2.InstanceTypeInfo) map[string]string {
+	attributes := map[string]string{
+		nodeidentity.AttributeInstanceType: aws.StringValue(info.InstanceType),
+	}
+	if info.ProcessorInfo != nil {
+		for _, arch := range aws.StringValueSlice(info.ProcessorInfo.SupportedArchitectures) {
+			switch arch {
+			case ec2.ArchitectureTypeX8664:
+				attributes[nodeidentity.AttributeArchitecture] = "amd64"
+			case ec2.ArchitectureTypeArm64:
+				attributes[nodeidentity.AttributeArchitecture] = "arm64"
+			}
+		}
+	}
+	if info.VCpuInfo != nil && info.VCpuInfo.DefaultVCpus != nil {
+		attributes[nodeidentity.AttributeVCPUs] = strconv.FormatInt(aws.Int64Value(info.VCpuInfo.DefaultVCpus), 10)
+	}
+	if info.MemoryInfo != nil && info.MemoryInfo.SizeInMiB != nil {
+		attributes[nodeidentity.AttributeMemoryMiB] = strconv.FormatInt(aws.Int64Value(info.MemoryInfo.SizeInMiB), 10)
+	}
+	gpus := int64(0)
+	if info.GpuInfo != nil {
+		for _, gpu := range info.GpuInfo.Gpus {
+			gpus += aws.Int64Value(gpu.Count)
+		}
+	}
+	attributes[nodeidentity.AttributeGPUs] = strconv.FormatInt(gpus, 10)
+	return attributes
+}
@@ -133,9 +133,23 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		}
 	}
 
+	tags := map[string]string{}
+	for k, v := range vmss.Tags {
+		if v != nil {
+			tags[k] = *v
+		}
+	}
+
+	attributes := map[string]string{}
+	if vmss.SKU != nil && vmss.SKU.Name != nil {
+		attributes[nodeidentity.AttributeInstanceType] = *vmss.SKU.Name
+	}
+
 	info := &nodeidentity.Info{
 		InstanceID: vmssName,
 		Labels:     labels,
+		Tags:       tags,
+		Attributes: attributes,
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
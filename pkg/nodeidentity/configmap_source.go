@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeidentity
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigMapSource looks up the labels and taints of nodes in a ConfigMap.
+// Each key of the ConfigMap is the name of a node, with a YAML value holding a SourceResult.
+type ConfigMapSource struct {
+	client    corev1client.ConfigMapsGetter
+	namespace string
+	name      string
+}
+
+var _ Source = &ConfigMapSource{}
+
+// NewConfigMapSource builds a ConfigMapSource for the ConfigMap of the options.
+func NewConfigMapSource(client corev1client.ConfigMapsGetter, options *ConfigMapSourceOptions) *ConfigMapSource {
+	return &ConfigMapSource{
+		client:    client,
+		namespace: options.Namespace,
+		name:      options.Name,
+	}
+}
+
+// Lookup implements Source::Lookup
+func (s *ConfigMapSource) Lookup(ctx context.Context, node *corev1.Node, info *Info) (*SourceResult, error) {
+	configMap, err := s.client.ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(2).Infof("ConfigMap %s/%s not found", s.namespace, s.name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting ConfigMap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	data, found := configMap.Data[node.Name]
+	if !found {
+		return nil, nil
+	}
+
+	result := &SourceResult{}
+	if err := yaml.UnmarshalStrict([]byte(data), result); err != nil {
+		return nil, fmt.Errorf("error parsing key %q of ConfigMap %s/%s: %w", node.Name, s.namespace, s.name, err)
+	}
+	return result, nil
+}
@@ -145,6 +145,10 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 
 	info := &nodeidentity.LegacyInfo{}
 	info.InstanceGroup = igName
+	info.Tags = instance.Labels
+	info.Attributes = map[string]string{
+		nodeidentity.AttributeInstanceType: lastComponent(instance.MachineType),
+	}
 	return info, nil
 }
 
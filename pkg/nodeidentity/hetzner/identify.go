@@ -111,9 +111,24 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		}
 	}
 
+	attributes := map[string]string{}
+	if server.ServerType != nil {
+		attributes[nodeidentity.AttributeInstanceType] = server.ServerType.Name
+		attributes[nodeidentity.AttributeVCPUs] = strconv.Itoa(server.ServerType.Cores)
+		attributes[nodeidentity.AttributeMemoryMiB] = strconv.Itoa(int(server.ServerType.Memory * 1024))
+		switch server.ServerType.Architecture {
+		case hcloud.ArchitectureX86:
+			attributes[nodeidentity.AttributeArchitecture] = "amd64"
+		case hcloud.ArchitectureARM:
+			attributes[nodeidentity.AttributeArchitecture] = "arm64"
+		}
+	}
+
 	info := &nodeidentity.Info{
 		InstanceID: serverID,
 		Labels:     labels,
+		Tags:       server.Labels,
+		Attributes: attributes,
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
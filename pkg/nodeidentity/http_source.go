@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeidentity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultHTTPSourceTimeout is the maximum duration of each request of an HTTPSource, unless configured.
+const defaultHTTPSourceTimeout = 10 * time.Second
+
+// HTTPSource looks up the labels and taints of nodes by calling an HTTP endpoint.
+// The endpoint is sent a POST request with an HTTPSourceRequest, and responds with a SourceResult,
+// or with 404 Not Found if it has no labels or taints for the node.
+type HTTPSource struct {
+	url    string
+	client *http.Client
+}
+
+var _ Source = &HTTPSource{}
+
+// HTTPSourceRequest is the body of the requests sent by an HTTPSource.
+type HTTPSourceRequest struct {
+	NodeName   string               `json:"nodeName"`
+	ProviderID string               `json:"providerID,omitempty"`
+	InstanceID string               `json:"instanceID,omitempty"`
+	Addresses  []corev1.NodeAddress `json:"addresses,omitempty"`
+	Tags       map[string]string    `json:"tags,omitempty"`
+	Attributes map[string]string    `json:"attributes,omitempty"`
+}
+
+// NewHTTPSource builds an HTTPSource for the endpoint of the options.
+func NewHTTPSource(options *HTTPSourceOptions) *HTTPSource {
+	timeout := defaultHTTPSourceTimeout
+	if options.Timeout != nil {
+		timeout = options.Timeout.Duration
+	}
+	return &HTTPSource{
+		url:    options.URL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Lookup implements Source::Lookup
+func (s *HTTPSource) Lookup(ctx context.Context, node *corev1.Node, info *Info) (*SourceResult, error) {
+	request := &HTTPSourceRequest{
+		NodeName:   node.Name,
+		ProviderID: node.Spec.ProviderID,
+		Addresses:  node.Status.Addresses,
+	}
+	if info != nil {
+		request.InstanceID = info.InstanceID
+		request.Tags = info.Tags
+		request.Attributes = info.Attributes
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %q from %s: %s", resp.Status, s.url, string(message))
+	}
+
+	result := &SourceResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("error parsing response from %s: %w", s.url, err)
+	}
+	return result, nil
+}
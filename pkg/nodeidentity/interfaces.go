@@ -29,6 +29,13 @@ type Identifier interface {
 type Info struct {
 	InstanceID string
 	Labels     map[string]string
+	// Taints are the taints to be added to the node.
+	Taints []corev1.Taint
+
+	// Tags are the cloud tags of the instance, which can be mapped to labels and taints.
+	Tags map[string]string
+	// Attributes are attributes of the instance, such as its instance type, which can be mapped to labels.
+	Attributes map[string]string
 }
 
 type LegacyIdentifier interface {
@@ -39,4 +46,22 @@ type LegacyInfo struct {
 	InstanceID        string
 	InstanceGroup     string
 	InstanceLifecycle string
+
+	// Tags are the cloud tags of the instance, which can be mapped to labels and taints.
+	Tags map[string]string
+	// Attributes are attributes of the instance, such as its instance type, which can be mapped to labels.
+	Attributes map[string]string
+}
+
+// Source is an external source of labels and taints for nodes.
+type Source interface {
+	// Lookup returns the labels and taints of the node, or nil if the source has none.
+	// The info is nil for nodes which are not cloud instances, such as bare-metal hosts.
+	Lookup(ctx context.Context, node *corev1.Node, info *Info) (*SourceResult, error)
+}
+
+// SourceResult holds the labels and taints of a node returned by a Source.
+type SourceResult struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Taints []corev1.Taint    `json:"taints,omitempty"`
 }
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeidentity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/nodelabels"
+)
+
+const (
+	// AttributeInstanceType is the instance type (or machine type) of the instance.
+	AttributeInstanceType = "instanceType"
+	// AttributeArchitecture is the CPU architecture of the instance.
+	AttributeArchitecture = "architecture"
+	// AttributeVCPUs is the number of virtual CPUs of the instance type.
+	AttributeVCPUs = "vcpus"
+	// AttributeMemoryMiB is the memory of the instance type, in MiB.
+	AttributeMemoryMiB = "memoryMiB"
+	// AttributeGPUs is the number of GPUs of the instance type.
+	AttributeGPUs = "gpus"
+)
+
+// Options configures the labels and taints which are added to nodes, in addition to those of the cloud identifier.
+type Options struct {
+	// Labels set node labels from the cloud tags or the attributes of the instance.
+	Labels []LabelMapping `json:"labels,omitempty"`
+	// Taints set node taints from the cloud tags of the instance.
+	Taints []TaintMapping `json:"taints,omitempty"`
+	// ConfigMap looks up labels and taints for nodes in a ConfigMap.
+	ConfigMap *ConfigMapSourceOptions `json:"configMap,omitempty"`
+	// HTTP looks up labels and taints for nodes by calling an HTTP endpoint.
+	HTTP *HTTPSourceOptions `json:"http,omitempty"`
+}
+
+// LabelMapping sets a node label from a cloud tag or an attribute of the instance.
+type LabelMapping struct {
+	Label     string `json:"label"`
+	Tag       string `json:"tag,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// TaintMapping sets a node taint when the instance has a cloud tag.
+type TaintMapping struct {
+	Tag string `json:"tag"`
+	Key string `json:"key"`
+	// Value is the value of the taint; if nil the value of the tag is used.
+	Value  *string            `json:"value,omitempty"`
+	Effect corev1.TaintEffect `json:"effect"`
+}
+
+// ConfigMapSourceOptions configures a ConfigMapSource.
+type ConfigMapSourceOptions struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// HTTPSourceOptions configures an HTTPSource.
+type HTTPSourceOptions struct {
+	URL     string           `json:"url"`
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Mapper adds labels and taints to the identity of nodes,
+// from the cloud tags and attributes of their instances and from external sources.
+type Mapper struct {
+	labels  []LabelMapping
+	taints  []TaintMapping
+	sources []Source
+}
+
+// NewMapper builds a Mapper for the label and taint mappings of the options, and the external sources.
+func NewMapper(options *Options, sources ...Source) *Mapper {
+	return &Mapper{
+		labels:  options.Labels,
+		taints:  options.Taints,
+		sources: sources,
+	}
+}
+
+// HasSources returns true if the mapper has external sources,
+// which can also provide labels and taints for nodes which are not cloud instances.
+func (m *Mapper) HasSources() bool {
+	return len(m.sources) != 0
+}
+
+// Map returns a copy of the info with the labels and taints of the mappings and external sources added.
+// The info is not modified, because identifiers may cache it; it is nil for nodes which are not cloud instances.
+// Labels already set by the identifier are not changed, nor are the node role labels which kops-controller manages.
+// If a source fails, the labels and taints of the mappings and the other sources are returned along with the error.
+func (m *Mapper) Map(ctx context.Context, node *corev1.Node, info *Info) (*Info, error) {
+	mapped := &Info{
+		Labels: make(map[string]string),
+	}
+	if info != nil {
+		mapped.InstanceID = info.InstanceID
+		mapped.Tags = info.Tags
+		mapped.Attributes = info.Attributes
+		for k, v := range info.Labels {
+			mapped.Labels[k] = v
+		}
+		mapped.Taints = append(mapped.Taints, info.Taints...)
+	}
+
+	setLabel := func(key, value string) {
+		if isManagedLabel(key) {
+			klog.Warningf("not setting label %q on node %q, because it is managed by kops-controller", key, node.Name)
+			return
+		}
+		if info != nil {
+			if _, found := info.Labels[key]; found {
+				klog.V(2).Infof("not setting label %q on node %q, because it is set by the cloud identifier", key, node.Name)
+				return
+			}
+		}
+		mapped.Labels[key] = value
+	}
+
+	for _, mapping := range m.labels {
+		var value string
+		var found bool
+		if mapping.Tag != "" {
+			value, found = mapped.Tags[mapping.Tag]
+		} else {
+			value, found = mapped.Attributes[mapping.Attribute]
+		}
+		if !found {
+			continue
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			klog.Warningf("not setting label %q on node %q to invalid value %q: %s", mapping.Label, node.Name, value, strings.Join(errs, "; "))
+			continue
+		}
+		setLabel(mapping.Label, value)
+	}
+
+	for _, mapping := range m.taints {
+		value, found := mapped.Tags[mapping.Tag]
+		if !found {
+			continue
+		}
+		if mapping.Value != nil {
+			value = *mapping.Value
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			klog.Warningf("not setting taint %q on node %q to invalid value %q: %s", mapping.Key, node.Name, value, strings.Join(errs, "; "))
+			continue
+		}
+		mapped.Taints = setTaint(mapped.Taints, corev1.Taint{
+			Key:    mapping.Key,
+			Value:  value,
+			Effect: mapping.Effect,
+		})
+	}
+
+	var errs []error
+	for _, source := range m.sources {
+		result, err := source.Lookup(ctx, node, info)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error looking up labels and taints of node %q: %w", node.Name, err))
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		if err := result.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid labels or taints for node %q: %w", node.Name, err))
+			continue
+		}
+		for k, v := range result.Labels {
+			setLabel(k, v)
+		}
+		for _, taint := range result.Taints {
+			mapped.Taints = setTaint(mapped.Taints, taint)
+		}
+	}
+
+	return mapped, errors.Join(errs...)
+}
+
+// validate checks that the labels and taints of the result can be set on a node.
+func (r *SourceResult) validate() error {
+	for k, v := range r.Labels {
+		if errs := validation.IsQualifiedName(k); len(errs) != 0 {
+			return fmt.Errorf("invalid label key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) != 0 {
+			return fmt.Errorf("invalid value %q for label %q: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+	for _, taint := range r.Taints {
+		if errs := validation.IsQualifiedName(taint.Key); len(errs) != 0 {
+			return fmt.Errorf("invalid taint key %q: %s", taint.Key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(taint.Value); len(errs) != 0 {
+			return fmt.Errorf("invalid value %q for taint %q: %s", taint.Value, taint.Key, strings.Join(errs, "; "))
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("invalid effect %q for taint %q", taint.Effect, taint.Key)
+		}
+	}
+	return nil
+}
+
+// isManagedLabel returns true for the node role labels which kops-controller sets from the instance group.
+func isManagedLabel(key string) bool {
+	switch key {
+	case nodelabels.RoleLabelAPIServer16, nodelabels.RoleLabelNode16, nodelabels.RoleLabelControlPlane20:
+		return true
+	}
+	return false
+}
+
+// setTaint adds the taint, replacing any taint with the same key and effect.
+func setTaint(taints []corev1.Taint, taint corev1.Taint) []corev1.Taint {
+	for i := range taints {
+		if taints[i].MatchTaint(&taint) {
+			taints[i] = taint
+			return taints
+		}
+	}
+	return append(taints, taint)
+}
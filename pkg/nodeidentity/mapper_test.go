@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeidentity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type staticSource struct {
+	result *SourceResult
+}
+
+func (s *staticSource) Lookup(ctx context.Context, node *corev1.Node, info *Info) (*SourceResult, error) {
+	return s.result, nil
+}
+
+func TestMapper(t *testing.T) {
+	dedicated := "gpu"
+	options := &Options{
+		Labels: []LabelMapping{
+			{Label: "topology.example.com/rack", Tag: "rack"},
+			{Label: "node.example.com/instance-type", Attribute: AttributeInstanceType},
+			{Label: "node.example.com/gpus", Attribute: AttributeGPUs},
+			{Label: "node.example.com/owner", Tag: "owner"},
+			{Label: "node.example.com/team", Tag: "team"},
+		},
+		Taints: []TaintMapping{
+			{Tag: "pool", Key: "pool", Effect: corev1.TaintEffectNoSchedule},
+			{Tag: "accelerator", Key: "dedicated", Value: &dedicated, Effect: corev1.TaintEffectNoExecute},
+			{Tag: "spot", Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		},
+	}
+	source := &staticSource{
+		result: &SourceResult{
+			Labels: map[string]string{
+				"topology.example.com/row":              "b",
+				"kops.k8s.io/instancegroup":             "other",
+				"node-role.kubernetes.io/control-plane": "",
+			},
+			Taints: []corev1.Taint{
+				{Key: "pool", Value: "batch", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	mapper := NewMapper(options, source)
+
+	info := &Info{
+		InstanceID: "i-0123456789",
+		Labels: map[string]string{
+			"kops.k8s.io/instancegroup": "nodes",
+			"node.example.com/team":     "storage",
+		},
+		Tags: map[string]string{
+			"rack":        "r12",
+			"owner":       "not a valid label value",
+			"team":        "compute",
+			"pool":        "general",
+			"accelerator": "nvidia",
+		},
+		Attributes: map[string]string{
+			AttributeInstanceType: "g5.xlarge",
+			AttributeGPUs:         "1",
+		},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	mapped, err := mapper.Map(context.Background(), node, info)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedLabels := map[string]string{
+		"kops.k8s.io/instancegroup":      "nodes",
+		"node.example.com/team":          "storage",
+		"topology.example.com/rack":      "r12",
+		"node.example.com/instance-type": "g5.xlarge",
+		"node.example.com/gpus":          "1",
+		"topology.example.com/row":       "b",
+	}
+	if !reflect.DeepEqual(mapped.Labels, expectedLabels) {
+		t.Errorf("unexpected labels: got %v, expected %v", mapped.Labels, expectedLabels)
+	}
+
+	expectedTaints := []corev1.Taint{
+		{Key: "pool", Value: "batch", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoExecute},
+	}
+	if !reflect.DeepEqual(mapped.Taints, expectedTaints) {
+		t.Errorf("unexpected taints: got %v, expected %v", mapped.Taints, expectedTaints)
+	}
+
+	if len(info.Labels) != 2 {
+		t.Errorf("info was modified: %v", info.Labels)
+	}
+}
+
+func TestMapperWithoutInfo(t *testing.T) {
+	source := &staticSource{
+		result: &SourceResult{
+			Labels: map[string]string{"topology.kubernetes.io/zone": "dc1-room2"},
+		},
+	}
+	mapper := NewMapper(&Options{
+		Labels: []LabelMapping{{Label: "topology.example.com/rack", Tag: "rack"}},
+	}, source)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "metal-1"}}
+	mapped, err := mapper.Map(context.Background(), node, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedLabels := map[string]string{"topology.kubernetes.io/zone": "dc1-room2"}
+	if !reflect.DeepEqual(mapped.Labels, expectedLabels) {
+		t.Errorf("unexpected labels: got %v, expected %v", mapped.Labels, expectedLabels)
+	}
+}
+
+func TestMapperInvalidSourceResult(t *testing.T) {
+	grid := []*SourceResult{
+		{Labels: map[string]string{"-rack": "r1"}},
+		{Labels: map[string]string{"rack": "r 1"}},
+		{Taints: []corev1.Taint{{Key: "pool", Effect: "Never"}}},
+	}
+	for _, result := range grid {
+		mapper := NewMapper(&Options{}, &staticSource{result: result})
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+		if _, err := mapper.Map(context.Background(), node, &Info{}); err == nil {
+			t.Errorf("expected error for %v", result)
+		}
+	}
+}
+
+type failingSource struct{}
+
+func (s *failingSource) Lookup(ctx context.Context, node *corev1.Node, info *Info) (*SourceResult, error) {
+	return nil, errors.New("inventory unavailable")
+}
+
+func TestMapperSourceError(t *testing.T) {
+	mapper := NewMapper(&Options{
+		Taints: []TaintMapping{{Tag: "pool", Key: "pool", Effect: corev1.TaintEffectNoSchedule}},
+	}, &failingSource{}, &staticSource{result: &SourceResult{Labels: map[string]string{"topology.example.com/rack": "r12"}}})
+
+	info := &Info{
+		Labels: map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+		Tags:   map[string]string{"pool": "general"},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	mapped, err := mapper.Map(context.Background(), node, info)
+	if err == nil {
+		t.Errorf("expected error from failing source")
+	}
+	if mapped == nil {
+		t.Fatalf("expected labels and taints of the other sources")
+	}
+
+	expectedLabels := map[string]string{
+		"kops.k8s.io/instancegroup": "nodes",
+		"topology.example.com/rack": "r12",
+	}
+	if !reflect.DeepEqual(mapped.Labels, expectedLabels) {
+		t.Errorf("unexpected labels: got %v, expected %v", mapped.Labels, expectedLabels)
+	}
+	expectedTaints := []corev1.Taint{{Key: "pool", Value: "general", Effect: corev1.TaintEffectNoSchedule}}
+	if !reflect.DeepEqual(mapped.Taints, expectedTaints) {
+		t.Errorf("unexpected taints: got %v, expected %v", mapped.Taints, expectedTaints)
+	}
+}
+
+func TestConfigMapSource(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "node-identity"},
+		Data: map[string]string{
+			"metal-1": "labels:\n  topology.kubernetes.io/zone: dc1-room2\ntaints:\n- key: dedicated\n  value: storage\n  effect: NoSchedule\n",
+			"metal-2": "lables: {}\n",
+		},
+	})
+
+	source := NewConfigMapSource(client.CoreV1(), &ConfigMapSourceOptions{Namespace: "kube-system", Name: "node-identity"})
+
+	result, err := source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "metal-1"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &SourceResult{
+		Labels: map[string]string{"topology.kubernetes.io/zone": "dc1-room2"},
+		Taints: []corev1.Taint{{Key: "dedicated", Value: "storage", Effect: corev1.TaintEffectNoSchedule}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result: got %v, expected %v", result, expected)
+	}
+
+	if _, err := source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "metal-2"}}, nil); err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+
+	result, err = source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "metal-3"}}, nil)
+	if err != nil || result != nil {
+		t.Errorf("expected no result for missing key, got %v, %v", result, err)
+	}
+
+	missing := NewConfigMapSource(client.CoreV1(), &ConfigMapSourceOptions{Namespace: "kube-system", Name: "missing"})
+	result, err = missing.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "metal-1"}}, nil)
+	if err != nil || result != nil {
+		t.Errorf("expected no result for missing ConfigMap, got %v, %v", result, err)
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		request := &HTTPSourceRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch request.NodeName {
+		case "node-1":
+			json.NewEncoder(w).Encode(&SourceResult{
+				Labels: map[string]string{"topology.example.com/rack": request.Tags["rack"]},
+			})
+		case "node-2":
+			http.Error(w, "inventory unavailable", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	source := NewHTTPSource(&HTTPSourceOptions{URL: server.URL})
+	info := &Info{Tags: map[string]string{"rack": "r12"}}
+
+	result, err := source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}, info)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &SourceResult{Labels: map[string]string{"topology.example.com/rack": "r12"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result: got %v, expected %v", result, expected)
+	}
+
+	if _, err := source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}, info); err == nil {
+		t.Errorf("expected error for unavailable endpoint")
+	}
+
+	result, err = source.Lookup(context.Background(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}}, nil)
+	if err != nil || result != nil {
+		t.Errorf("expected no result for unknown node, got %v, %v", result, err)
+	}
+}
@@ -148,6 +148,7 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 	info := &nodeidentity.Info{
 		InstanceID: instanceID,
 		Labels:     labels,
+		Tags:       server.Metadata,
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
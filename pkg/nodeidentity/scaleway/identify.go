@@ -112,9 +112,27 @@ func (i *nodeIdentifier) IdentifyNode(ctx context.Context, node *corev1.Node) (*
 		klog.Warningf("Unknown node role %q for server %s(%s)", role, server.Name, server.ID)
 	}
 
+	tags := map[string]string{}
+	for _, tag := range server.Tags {
+		k, v, _ := strings.Cut(tag, "=")
+		tags[k] = v
+	}
+
+	attributes := map[string]string{
+		nodeidentity.AttributeInstanceType: server.CommercialType,
+	}
+	switch server.Arch {
+	case instance.ArchX86_64:
+		attributes[nodeidentity.AttributeArchitecture] = "amd64"
+	case instance.ArchArm64:
+		attributes[nodeidentity.AttributeArchitecture] = "arm64"
+	}
+
 	info := &nodeidentity.Info{
 		InstanceID: serverID,
 		Labels:     labels,
+		Tags:       tags,
+		Attributes: attributes,
 	}
 
 	// If caching is enabled add the nodeidentity.Info to cache.
@@ -19,6 +19,7 @@ package pki
 import (
 	"context"
 	"crypto"
+	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
@@ -49,6 +50,8 @@ type IssueCertRequest struct {
 	PublicKey crypto.PublicKey
 	// PrivateKey is the private key for this certificate. If both this and PublicKey are nil, a new private key will be generated.
 	PrivateKey *PrivateKey
+	// KeyAlgorithm is the algorithm of the private key, if one is generated. The default is RSA.
+	KeyAlgorithm KeyAlgorithm
 	// Validity is the certificate validity. The default is 10 years.
 	Validity time.Duration
 
@@ -136,12 +139,21 @@ func IssueCert(ctx context.Context, request *IssueCertRequest, keystore Keystore
 		template.PublicKey = request.PublicKey
 	} else if privateKey == nil {
 		var err error
-		privateKey, err = GeneratePrivateKey()
+		privateKey, err = GeneratePrivateKeyWithAlgorithm(request.KeyAlgorithm)
 		if err != nil {
 			return nil, nil, nil, err
 		}
 	}
 
+	// Only RSA keys can be used for key encipherment
+	publicKey := template.PublicKey
+	if publicKey == nil {
+		publicKey = privateKey.Key.Public()
+	}
+	if _, ok := publicKey.(*rsa.PublicKey); !ok {
+		template.KeyUsage &^= x509.KeyUsageKeyEncipherment
+	}
+
 	if request.Validity != 0 {
 		template.NotAfter = time.Now().Add(request.Validity).UTC()
 	}
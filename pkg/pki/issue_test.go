@@ -224,3 +224,32 @@ func TestIssueCert(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueCertKeyAlgorithm(t *testing.T) {
+	for _, algorithm := range []KeyAlgorithm{KeyAlgorithmECDSAP256, KeyAlgorithmEd25519} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			ctx := context.TODO()
+			caCertificate, caPrivateKey, _, err := IssueCert(ctx, &IssueCertRequest{
+				Type:         "ca",
+				Subject:      pkix.Name{CommonName: "Test CA"},
+				KeyAlgorithm: algorithm,
+			}, nil)
+			require.NoError(t, err)
+
+			keystore := &mockKeystore{t: t, signer: "ca", cert: caCertificate, key: caPrivateKey}
+			certificate, privateKey, _, err := IssueCert(ctx, &IssueCertRequest{
+				Signer:       "ca",
+				Type:         "server",
+				Subject:      pkix.Name{CommonName: "server"},
+				KeyAlgorithm: algorithm,
+			}, keystore)
+			require.NoError(t, err)
+
+			actual, err := KeyAlgorithmOf(privateKey.Key.Public())
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, actual)
+			assert.Equal(t, x509.KeyUsageDigitalSignature, certificate.Certificate.KeyUsage, "key usage")
+			assert.NoError(t, certificate.Certificate.CheckSignatureFrom(caCertificate.Certificate))
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	crypto_rand "crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -50,7 +52,49 @@ func ParsePEMPrivateKey(data []byte) (*PrivateKey, error) {
 	return &PrivateKey{Key: k}, nil
 }
 
+// KeyAlgorithm is the algorithm of a private key.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA is RSA, with the key size of DefaultPrivateKeySize.
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSAP256 is ECDSA on the NIST P-256 curve.
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ECDSA-P256"
+	// KeyAlgorithmEd25519 is Ed25519.
+	KeyAlgorithmEd25519 KeyAlgorithm = "Ed25519"
+)
+
+// KeyAlgorithms are the supported key algorithms.
+var KeyAlgorithms = []KeyAlgorithm{KeyAlgorithmRSA, KeyAlgorithmECDSAP256, KeyAlgorithmEd25519}
+
+// GeneratePrivateKey generates an RSA private key.
 func GeneratePrivateKey() (*PrivateKey, error) {
+	return GeneratePrivateKeyWithAlgorithm(KeyAlgorithmRSA)
+}
+
+// GeneratePrivateKeyWithAlgorithm generates a private key using the algorithm, or RSA if the algorithm is empty.
+func GeneratePrivateKeyWithAlgorithm(algorithm KeyAlgorithm) (*PrivateKey, error) {
+	switch algorithm {
+	case "", KeyAlgorithmRSA:
+		return generateRSAPrivateKey()
+	case KeyAlgorithmECDSAP256:
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), crypto_rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("error generating ECDSA private key: %v", err)
+		}
+		return &PrivateKey{Key: ecdsaKey}, nil
+	case KeyAlgorithmEd25519:
+		_, ed25519Key, err := ed25519.GenerateKey(crypto_rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("error generating Ed25519 private key: %v", err)
+		}
+		return &PrivateKey{Key: ed25519Key}, nil
+	default:
+		return nil, fmt.Errorf("unknown key algorithm %q", algorithm)
+	}
+}
+
+func generateRSAPrivateKey() (*PrivateKey, error) {
 	rsaKeySize := DefaultPrivateKeySize
 
 	if os.Getenv("KOPS_RSA_PRIVATE_KEY_SIZE") != "" {
@@ -72,6 +116,23 @@ func GeneratePrivateKey() (*PrivateKey, error) {
 	return privateKey, nil
 }
 
+// KeyAlgorithmOf returns the algorithm of a public key.
+func KeyAlgorithmOf(publicKey crypto.PublicKey) (KeyAlgorithm, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		if k.Curve == elliptic.P256() {
+			return KeyAlgorithmECDSAP256, nil
+		}
+		return "", fmt.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return KeyAlgorithmEd25519, nil
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
 type PrivateKey struct {
 	Key crypto.Signer
 }
@@ -164,6 +225,14 @@ func (k *PrivateKey) WriteTo(w io.Writer) (int64, error) {
 		if err := pem.Encode(w, &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}); err != nil {
 			return 0, fmt.Errorf("error encoding ECDSA private key: %w", err)
 		}
+	case ed25519.PrivateKey:
+		b, err := x509.MarshalPKCS8PrivateKey(pk)
+		if err != nil {
+			return 0, fmt.Errorf("error encoding Ed25519 private key: %w", err)
+		}
+		if err := pem.Encode(w, &pem.Block{Type: "PRIVATE KEY", Bytes: b}); err != nil {
+			return 0, fmt.Errorf("error encoding Ed25519 private key: %w", err)
+		}
 	default:
 		return 0, fmt.Errorf("unknown private key type: %T", k.Key)
 	}
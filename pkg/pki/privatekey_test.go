@@ -60,3 +60,37 @@ pTejuKor1QB7wzbfGuANjE9mgHuhfQLPGw==
 		})
 	}
 }
+
+func TestGeneratePrivateKeyWithAlgorithm(t *testing.T) {
+	t.Setenv("KOPS_RSA_PRIVATE_KEY_SIZE", "1024")
+
+	for _, algorithm := range KeyAlgorithms {
+		t.Run(string(algorithm), func(t *testing.T) {
+			key, err := GeneratePrivateKeyWithAlgorithm(algorithm)
+			if err != nil {
+				t.Fatalf("error from GeneratePrivateKeyWithAlgorithm: %v", err)
+			}
+
+			var b bytes.Buffer
+			if _, err := key.WriteTo(&b); err != nil {
+				t.Fatalf("error from PrivateKey WriteTo: %v", err)
+			}
+			parsed, err := ParsePEMPrivateKey(b.Bytes())
+			if err != nil {
+				t.Fatalf("error from ParsePEMPrivateKey: %v", err)
+			}
+
+			actual, err := KeyAlgorithmOf(parsed.Key.Public())
+			if err != nil {
+				t.Fatalf("error from KeyAlgorithmOf: %v", err)
+			}
+			if actual != algorithm {
+				t.Fatalf("unexpected key algorithm %q", actual)
+			}
+		})
+	}
+
+	if _, err := GeneratePrivateKeyWithAlgorithm("DSA"); err == nil {
+		t.Fatalf("expected error for unknown key algorithm")
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// prometheusCheckTimeout is the maximum duration of the query of a prometheus check.
+const prometheusCheckTimeout = 30 * time.Second
+
+// Check is an additional check performed when validating the cluster.
+type Check interface {
+	// Name identifies the check. It is used as the Kind of the validation failures of the check.
+	Name() string
+	// Check returns the validation failures found by the check.
+	Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error)
+}
+
+// CheckContext holds the state of the cluster available to checks.
+type CheckContext struct {
+	Cluster   *kops.Cluster
+	K8sClient kubernetes.Interface
+	// Nodes are all the nodes of the cluster.
+	Nodes []v1.Node
+	// NodeInstanceGroups maps the names of the nodes to their instance groups.
+	NodeInstanceGroups map[string]*kops.InstanceGroup
+}
+
+// buildChecks builds the checks configured in the cluster spec.
+func buildChecks(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup) ([]Check, error) {
+	if cluster.Spec.ClusterValidation == nil {
+		return nil, nil
+	}
+
+	var checks []Check
+	for _, spec := range cluster.Spec.ClusterValidation.Checks {
+		var gatedGroups []*kops.InstanceGroup
+		for _, name := range spec.InstanceGroups {
+			found := false
+			for _, ig := range instanceGroups {
+				if ig.Name == name {
+					gatedGroups = append(gatedGroups, ig)
+					found = true
+				}
+			}
+			if !found {
+				// The failures of a check gated to no known group would block the rolling updates of all groups
+				return nil, fmt.Errorf("validation check %q refers to unknown InstanceGroup %q", spec.Name, name)
+			}
+		}
+
+		var check Check
+		switch {
+		case spec.Deployments != nil:
+			check = &deploymentsCheck{name: spec.Name, spec: spec.Deployments}
+		case spec.Prometheus != nil:
+			check = &prometheusCheck{name: spec.Name, spec: spec.Prometheus, client: &http.Client{Timeout: prometheusCheckTimeout}}
+		case spec.NodeConditions != nil:
+			check = &nodeConditionsCheck{name: spec.Name, spec: spec.NodeConditions, instanceGroups: spec.InstanceGroups}
+		default:
+			return nil, fmt.Errorf("validation check %q has no action", spec.Name)
+		}
+
+		if len(spec.InstanceGroups) != 0 {
+			check = &gatedCheck{check: check, instanceGroups: gatedGroups}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// runChecks runs the checks, recording their failures.
+// A check that cannot be run is recorded as a failure of the check.
+func (v *ValidationCluster) runChecks(ctx context.Context, checks []Check, checkContext *CheckContext) {
+	for _, check := range checks {
+		failures, err := check.Check(ctx, checkContext)
+		if err != nil {
+			failure := &ValidationError{
+				Kind:    check.Name(),
+				Name:    check.Name(),
+				Message: fmt.Sprintf("validation check %q failed: %v", check.Name(), err),
+			}
+			if gated, ok := check.(*gatedCheck); ok {
+				failure.InstanceGroups = gated.instanceGroups
+			}
+			v.addError(failure)
+			continue
+		}
+		for _, failure := range failures {
+			v.addError(failure)
+		}
+	}
+}
+
+// gatedCheck limits the rolling updates blocked by a check to those of the given instance groups.
+type gatedCheck struct {
+	check          Check
+	instanceGroups []*kops.InstanceGroup
+}
+
+func (c *gatedCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *gatedCheck) Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error) {
+	failures, err := c.check.Check(ctx, checkContext)
+	if err != nil {
+		return nil, err
+	}
+	for _, failure := range failures {
+		if failure.InstanceGroup == nil && len(failure.InstanceGroups) == 0 {
+			failure.InstanceGroups = c.instanceGroups
+		}
+	}
+	return failures, nil
+}
+
+// deploymentsCheck checks that deployments have all their replicas available.
+type deploymentsCheck struct {
+	name string
+	spec *kops.DeploymentsValidationCheck
+}
+
+func (c *deploymentsCheck) Name() string {
+	return c.name
+}
+
+func (c *deploymentsCheck) Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+	for _, namespace := range c.spec.Namespaces {
+		deployments, err := checkContext.K8sClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: c.spec.Selector})
+		if err != nil {
+			return nil, fmt.Errorf("listing deployments in namespace %q: %w", namespace, err)
+		}
+		for _, deployment := range deployments.Items {
+			replicas := int32(1)
+			if deployment.Spec.Replicas != nil {
+				replicas = *deployment.Spec.Replicas
+			}
+			available := deployment.Status.AvailableReplicas
+			if available < replicas {
+				failures = append(failures, &ValidationError{
+					Kind:    c.name,
+					Name:    deployment.Namespace + "/" + deployment.Name,
+					Message: fmt.Sprintf("deployment %q has %d of %d replicas available", deployment.Name, available, replicas),
+				})
+			}
+		}
+	}
+	return failures, nil
+}
+
+// prometheusCheck checks that a Prometheus query returns no results.
+type prometheusCheck struct {
+	name   string
+	spec   *kops.PrometheusValidationCheck
+	client *http.Client
+}
+
+func (c *prometheusCheck) Name() string {
+	return c.name
+}
+
+// prometheusQueryResponse is the response of the Prometheus instant query API.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (c *prometheusCheck) Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error) {
+	u := strings.TrimSuffix(c.spec.URL, "/") + "/api/v1/query?query=" + url.QueryEscape(c.spec.Query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %q: %w", u, err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response := &prometheusQueryResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, fmt.Errorf("parsing prometheus response (status %q): %w", resp.Status, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+
+	var failures []*ValidationError
+	for _, result := range response.Data.Result {
+		name := result.Metric["alertname"]
+		if name == "" {
+			name = result.Metric["__name__"]
+		}
+		var labels []string
+		for k, v := range result.Metric {
+			labels = append(labels, fmt.Sprintf("%s=%q", k, v))
+		}
+		sort.Strings(labels)
+		failures = append(failures, &ValidationError{
+			Kind:    c.name,
+			Name:    name,
+			Message: fmt.Sprintf("prometheus query %q returned {%s}", c.spec.Query, strings.Join(labels, ", ")),
+		})
+	}
+	return failures, nil
+}
+
+// nodeConditionsCheck checks the conditions of the nodes.
+type nodeConditionsCheck struct {
+	name string
+	spec *kops.NodeConditionsValidationCheck
+	// instanceGroups, if set, are the names of the instance groups whose nodes are checked.
+	instanceGroups []string
+}
+
+func (c *nodeConditionsCheck) Name() string {
+	return c.name
+}
+
+func (c *nodeConditionsCheck) Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+	for i := range checkContext.Nodes {
+		node := &checkContext.Nodes[i]
+		ig := checkContext.NodeInstanceGroups[node.Name]
+		if len(c.instanceGroups) != 0 && (ig == nil || !slices.Contains(c.instanceGroups, ig.Name)) {
+			continue
+		}
+
+		for _, requirement := range c.spec.Conditions {
+			condition := findNodeCondition(node, v1.NodeConditionType(requirement.Type))
+			if condition == nil {
+				if !requirement.AllowMissing {
+					failures = append(failures, &ValidationError{
+						Kind:          c.name,
+						Name:          node.Name,
+						Message:       fmt.Sprintf("node %q does not report condition %s, expected %s", node.Name, requirement.Type, requirement.Status),
+						InstanceGroup: ig,
+					})
+				}
+				continue
+			}
+			if string(condition.Status) == requirement.Status {
+				continue
+			}
+			failures = append(failures, &ValidationError{
+				Kind:          c.name,
+				Name:          node.Name,
+				Message:       fmt.Sprintf("node %q has condition %s=%s, expected %s", node.Name, requirement.Type, condition.Status, requirement.Status),
+				InstanceGroup: ig,
+			})
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func testValidateWithChecks(t *testing.T, checks []kopsapi.ValidationCheck, objects []runtime.Object, extraChecks ...Check) *ValidationCluster {
+	cluster := &kopsapi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"},
+		Spec: kopsapi.ClusterSpec{
+			ClusterValidation: &kopsapi.ClusterValidationSpec{
+				Checks: checks,
+			},
+		},
+	}
+
+	instanceGroups := []kopsapi.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+			Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for i := range instanceGroups {
+		ig := &instanceGroups[i]
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: ig.Name + "a"},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{Type: "Ready", Status: v1.ConditionTrue},
+					{Type: "KernelDeadlock", Status: v1.ConditionTrue},
+				},
+			},
+		}
+		objects = append(objects, node)
+		groups[ig.Name] = &cloudinstances.CloudInstanceGroup{
+			InstanceGroup: ig,
+			MinSize:       1,
+			TargetSize:    1,
+			Ready:         []*cloudinstances.CloudInstance{{ID: "i-" + ig.Name, Node: node}},
+		}
+	}
+
+	mockcloud := BuildMockCloud(t, groups, cluster, instanceGroups)
+	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset(objects...), extraChecks...)
+	require.NoError(t, err)
+	v, err := validator.Validate(context.Background())
+	require.NoError(t, err)
+	return v
+}
+
+func Test_ValidateDeploymentsCheck(t *testing.T) {
+	objects := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "ingress", Labels: map[string]string{"app": "ingress"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(2))},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 2},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "unavailable", Namespace: "ingress", Labels: map[string]string{"app": "ingress"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(2))},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "unselected", Namespace: "ingress"},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(2))},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "default", Labels: map[string]string{"app": "ingress"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: fi.PtrTo(int32(2))},
+		},
+	}
+
+	v := testValidateWithChecks(t, []kopsapi.ValidationCheck{
+		{
+			Name:           "ingress",
+			InstanceGroups: []string{"node-2"},
+			Deployments: &kopsapi.DeploymentsValidationCheck{
+				Namespaces: []string{"ingress"},
+				Selector:   "app=ingress",
+			},
+		},
+	}, objects)
+
+	if assert.Len(t, v.Failures, 1) {
+		failure := v.Failures[0]
+		assert.Equal(t, "ingress", failure.Kind)
+		assert.Equal(t, "ingress/unavailable", failure.Name)
+		assert.Equal(t, `deployment "unavailable" has 1 of 2 replicas available`, failure.Message)
+		assert.Nil(t, failure.InstanceGroup)
+		if assert.Len(t, failure.InstanceGroups, 1) {
+			assert.Equal(t, "node-2", failure.InstanceGroups[0].Name)
+		}
+	}
+}
+
+func Test_ValidateNodeConditionsCheck(t *testing.T) {
+	v := testValidateWithChecks(t, []kopsapi.ValidationCheck{
+		{
+			Name:           "kernel",
+			InstanceGroups: []string{"node-1"},
+			NodeConditions: &kopsapi.NodeConditionsValidationCheck{
+				Conditions: []kopsapi.NodeConditionRequirement{
+					{Type: "KernelDeadlock", Status: "False"},
+					{Type: "FrequentKubeletRestart", Status: "False", AllowMissing: true},
+				},
+			},
+		},
+	}, nil)
+
+	if assert.Len(t, v.Failures, 1) {
+		failure := v.Failures[0]
+		assert.Equal(t, "kernel", failure.Kind)
+		assert.Equal(t, "node-1a", failure.Name)
+		assert.Equal(t, `node "node-1a" has condition KernelDeadlock=True, expected False`, failure.Message)
+		assert.Equal(t, "node-1", failure.InstanceGroup.Name)
+	}
+}
+
+func Test_ValidateNodeConditionsCheckMissing(t *testing.T) {
+	v := testValidateWithChecks(t, []kopsapi.ValidationCheck{
+		{
+			Name:           "kubelet",
+			InstanceGroups: []string{"node-2"},
+			NodeConditions: &kopsapi.NodeConditionsValidationCheck{
+				Conditions: []kopsapi.NodeConditionRequirement{
+					{Type: "FrequentKubeletRestart", Status: "False"},
+				},
+			},
+		},
+	}, nil)
+
+	if assert.Len(t, v.Failures, 1) {
+		failure := v.Failures[0]
+		assert.Equal(t, "kubelet", failure.Kind)
+		assert.Equal(t, "node-2a", failure.Name)
+		assert.Equal(t, `node "node-2a" does not report condition FrequentKubeletRestart, expected False`, failure.Message)
+		assert.Equal(t, "node-2", failure.InstanceGroup.Name)
+	}
+}
+
+func Test_ValidatePrometheusCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, `ALERTS{alertstate="firing"}`, r.URL.Query().Get("query"))
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"ALERTS","alertname":"HighErrorRate","alertstate":"firing"},"value":[1700000000,"1"]}]}}`))
+	}))
+	defer server.Close()
+
+	v := testValidateWithChecks(t, []kopsapi.ValidationCheck{
+		{
+			Name: "alerts",
+			Prometheus: &kopsapi.PrometheusValidationCheck{
+				URL:   server.URL + "/",
+				Query: `ALERTS{alertstate="firing"}`,
+			},
+		},
+	}, nil)
+
+	if assert.Len(t, v.Failures, 1) {
+		failure := v.Failures[0]
+		assert.Equal(t, "alerts", failure.Kind)
+		assert.Equal(t, "HighErrorRate", failure.Name)
+		assert.Equal(t, `prometheus query "ALERTS{alertstate=\"firing\"}" returned {__name__="ALERTS", alertname="HighErrorRate", alertstate="firing"}`, failure.Message)
+		assert.Nil(t, failure.InstanceGroup)
+		assert.Empty(t, failure.InstanceGroups)
+	}
+}
+
+func Test_ValidatePrometheusCheckUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	v := testValidateWithChecks(t, []kopsapi.ValidationCheck{
+		{
+			Name:           "alerts",
+			InstanceGroups: []string{"node-1"},
+			Prometheus: &kopsapi.PrometheusValidationCheck{
+				URL:   server.URL,
+				Query: "up == 0",
+			},
+		},
+	}, nil)
+
+	if assert.Len(t, v.Failures, 1) {
+		failure := v.Failures[0]
+		assert.Equal(t, "alerts", failure.Kind)
+		assert.Contains(t, failure.Message, `validation check "alerts" failed`)
+		if assert.Len(t, failure.InstanceGroups, 1) {
+			assert.Equal(t, "node-1", failure.InstanceGroups[0].Name)
+		}
+	}
+}
+
+type testCheck struct{}
+
+func (testCheck) Name() string {
+	return "test"
+}
+
+func (testCheck) Check(ctx context.Context, checkContext *CheckContext) ([]*ValidationError, error) {
+	var failures []*ValidationError
+	for _, node := range checkContext.Nodes {
+		failures = append(failures, &ValidationError{
+			Kind:          "test",
+			Name:          node.Name,
+			Message:       "test failure",
+			InstanceGroup: checkContext.NodeInstanceGroups[node.Name],
+		})
+	}
+	return failures, nil
+}
+
+func Test_ValidateExtraCheck(t *testing.T) {
+	v := testValidateWithChecks(t, nil, nil, testCheck{})
+
+	if assert.Len(t, v.Failures, 2) {
+		assert.ElementsMatch(t, []string{"node-1a", "node-2a"}, []string{v.Failures[0].Name, v.Failures[1].Name})
+		assert.Equal(t, v.Failures[0].Name, v.Failures[0].InstanceGroup.Name+"a")
+	}
+}
+
+func Test_ValidateCheckUnknownInstanceGroup(t *testing.T) {
+	cluster := &kopsapi.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "testcluster.k8s.local"},
+		Spec: kopsapi.ClusterSpec{
+			ClusterValidation: &kopsapi.ClusterValidationSpec{
+				Checks: []kopsapi.ValidationCheck{
+					{
+						Name:           "ingress",
+						InstanceGroups: []string{"removed"},
+						Deployments:    &kopsapi.DeploymentsValidationCheck{Namespaces: []string{"ingress"}},
+					},
+				},
+			},
+		},
+	}
+	instanceGroups := []*kopsapi.InstanceGroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       kopsapi.InstanceGroupSpec{Role: kopsapi.InstanceGroupRoleNode},
+		},
+	}
+
+	_, err := buildChecks(cluster, instanceGroups)
+	if assert.Error(t, err) {
+		assert.Equal(t, `validation check "ingress" refers to unknown InstanceGroup "removed"`, err.Error())
+	}
+}
@@ -51,11 +51,14 @@ type ValidationError struct {
 	Message string `json:"message,omitempty"`
 	// The InstanceGroup field is used to indicate which instance group this validation error is coming from
 	InstanceGroup *kops.InstanceGroup `json:"instanceGroup,omitempty"`
+	// InstanceGroups, if set, are the instance groups whose rolling updates are blocked by a failure
+	// not coming from any one instance group, such as a failure of a check gated to those instance groups.
+	InstanceGroups []*kops.InstanceGroup `json:"instanceGroups,omitempty"`
 }
 
 type ClusterValidator interface {
 	// Validate validates a k8s cluster
-	Validate() (*ValidationCluster, error)
+	Validate(ctx context.Context) (*ValidationCluster, error)
 }
 
 type clusterValidatorImpl struct {
@@ -64,6 +67,7 @@ type clusterValidatorImpl struct {
 	instanceGroups []*kops.InstanceGroup
 	host           string
 	k8sClient      kubernetes.Interface
+	checks         []Check
 }
 
 func (v *ValidationCluster) addError(failure *ValidationError) {
@@ -100,7 +104,9 @@ func hasPlaceHolderIP(host string) (string, error) {
 	return "", nil
 }
 
-func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface) (ClusterValidator, error) {
+// NewClusterValidator builds a ClusterValidator, which performs the checks configured in the cluster spec and any extraChecks
+// in addition to the built-in checks.
+func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupList *kops.InstanceGroupList, host string, k8sClient kubernetes.Interface, extraChecks ...Check) (ClusterValidator, error) {
 	var instanceGroups []*kops.InstanceGroup
 
 	for i := range instanceGroupList.Items {
@@ -112,18 +118,23 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		return nil, fmt.Errorf("no InstanceGroup objects found")
 	}
 
+	checks, err := buildChecks(cluster, instanceGroups)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, extraChecks...)
+
 	return &clusterValidatorImpl{
 		cluster:        cluster,
 		cloud:          cloud,
 		instanceGroups: instanceGroups,
 		host:           host,
 		k8sClient:      k8sClient,
+		checks:         checks,
 	}, nil
 }
 
-func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
-	ctx := context.TODO()
-
+func (v *clusterValidatorImpl) Validate(ctx context.Context) (*ValidationCluster, error) {
 	validation := &ValidationCluster{}
 
 	// Do not use if we are running gossip or without dns
@@ -170,6 +181,13 @@ func (v *clusterValidatorImpl) Validate() (*ValidationCluster, error) {
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", v.cluster.Name, err)
 	}
 
+	validation.runChecks(ctx, v.checks, &CheckContext{
+		Cluster:            v.cluster,
+		K8sClient:          v.k8sClient,
+		Nodes:              nodeList.Items,
+		NodeInstanceGroups: nodeInstanceGroupMapping,
+	})
+
 	return validation, nil
 }
 
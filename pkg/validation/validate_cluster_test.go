@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -130,7 +131,7 @@ func testValidate(t *testing.T, groups map[string]*cloudinstances.CloudInstanceG
 	if err != nil {
 		return nil, err
 	}
-	return validator.Validate()
+	return validator.Validate(context.Background())
 }
 
 func Test_ValidateCloudGroupMissing(t *testing.T) {
@@ -158,7 +159,7 @@ func Test_ValidateCloudGroupMissing(t *testing.T) {
 
 	validator, err := NewClusterValidator(cluster, mockcloud, &kopsapi.InstanceGroupList{Items: instanceGroups}, "https://api.testcluster.k8s.local", fake.NewSimpleClientset())
 	require.NoError(t, err)
-	v, err := validator.Validate()
+	v, err := validator.Validate(context.Background())
 	require.NoError(t, err)
 	if !assert.Len(t, v.Failures, 1) ||
 		!assert.Equal(t, &ValidationError{
@@ -118,7 +118,8 @@ func equalFieldValues(a, e reflect.Value) bool {
 			if ok {
 				if hasIsReady, ok := eResource.(HasIsReady); ok {
 					if !hasIsReady.IsReady() {
-						return false
+						// A resource which is not ready is only equal to itself
+						return aResource == eResource
 					}
 				}
 				same, err := ResourcesMatch(aResource, eResource)
@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -145,6 +146,16 @@ type ApplyClusterCmd struct {
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// PlanFormat, if set, makes a dry run print a machine-readable plan in this format instead of a report.
+	PlanFormat fi.PlanFormat
+
+	// DryRunOutput, if set, is where a dry run prints its report or plan; it defaults to stdout.
+	DryRunOutput io.Writer
+
+	// MessageOutput, if set, is where warnings and banners for the operator are printed.
+	// It defaults to stderr when PlanFormat is set, and to stdout otherwise.
+	MessageOutput io.Writer
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.CloudupTask
 
@@ -158,6 +169,23 @@ type ApplyClusterCmd struct {
 
 	// DeletionProcessing controls whether we process deletions.
 	DeletionProcessing fi.DeletionProcessingMode
+
+	// OnlyInstanceGroupTemplate, if set, is the name of the InstanceGroup whose cloud groups and
+	// launch templates or instance templates are the only resources changed; other resources are only checked.
+	OnlyInstanceGroupTemplate string
+
+	// ChangePolicyPaths are the locations of change policies checked in addition to the one in the state store.
+	ChangePolicyPaths []string
+	// AllowedChangePolicyRules are the names of change policy rules whose forbidden changes are allowed.
+	AllowedChangePolicyRules []string
+	// SkipChangePolicy is true if the changes are not checked against the change policy.
+	SkipChangePolicy bool
+
+	// SavedPlan, if set, is the plan the update applies: the task map must not differ from the one it was
+	// computed from, and each change is checked against it before it is made.
+	SavedPlan *SavedPlan
+	// Tasks describes the task map before the tasks ran, as returned by fi.DescribeTasks (output).
+	Tasks map[string]map[string]string
 }
 
 func (c *ApplyClusterCmd) Run(ctx context.Context) error {
@@ -187,6 +215,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 		c.InstanceGroups = instanceGroups
 	}
+	// The InstanceGroups are replaced by their fully populated specs; we record the specs as written by the user
+	appliedInstanceGroups := slices.Clone(c.InstanceGroups)
 
 	if c.AdditionalObjects == nil {
 		additionalObjects, err := c.Clientset.AddonsFor(c.Cluster).List(ctx)
@@ -269,6 +299,8 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	c.warnUnsupportedBootstrapAddons()
+
 	cluster := c.Cluster
 
 	configBase, err := c.Clientset.VFSContext().BuildVfsPath(cluster.Spec.ConfigStore.Base)
@@ -285,14 +317,15 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 				return fmt.Errorf("error parsing last kops version updated: %v", err)
 			}
 			if version.GT(semver.MustParse(kopsbase.Version)) {
-				fmt.Printf("\n")
-				fmt.Printf("%s\n", starline)
-				fmt.Printf("\n")
-				fmt.Printf("The cluster was last updated by kops version %s\n", kopsVersionUpdated)
-				fmt.Printf("To permit updating by the older version %s, run with the --allow-kops-downgrade flag\n", kopsbase.Version)
-				fmt.Printf("\n")
-				fmt.Printf("%s\n", starline)
-				fmt.Printf("\n")
+				out := c.messageOutput()
+				fmt.Fprintf(out, "\n")
+				fmt.Fprintf(out, "%s\n", starline)
+				fmt.Fprintf(out, "\n")
+				fmt.Fprintf(out, "The cluster was last updated by kops version %s\n", kopsVersionUpdated)
+				fmt.Fprintf(out, "To permit updating by the older version %s, run with the --allow-kops-downgrade flag\n", kopsbase.Version)
+				fmt.Fprintf(out, "\n")
+				fmt.Fprintf(out, "%s\n", starline)
+				fmt.Fprintf(out, "\n")
 				return fmt.Errorf("kops version older than last used to update the cluster")
 			}
 		} else if err != os.ErrNotExist {
@@ -357,16 +390,17 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 
 		if warn {
-			fmt.Println("")
-			fmt.Printf("%s\n", starline)
-			fmt.Println("")
-			fmt.Println("Kubelet anonymousAuth is currently turned on. This allows RBAC escalation and remote code execution possibilities.")
-			fmt.Println("It is highly recommended you turn it off by setting 'spec.kubelet.anonymousAuth' to 'false' via 'kops edit cluster'")
-			fmt.Println("")
-			fmt.Println("See https://kops.sigs.k8s.io/security/#kubelet-api")
-			fmt.Println("")
-			fmt.Printf("%s\n", starline)
-			fmt.Println("")
+			out := c.messageOutput()
+			fmt.Fprintln(out, "")
+			fmt.Fprintf(out, "%s\n", starline)
+			fmt.Fprintln(out, "")
+			fmt.Fprintln(out, "Kubelet anonymousAuth is currently turned on. This allows RBAC escalation and remote code execution possibilities.")
+			fmt.Fprintln(out, "It is highly recommended you turn it off by setting 'spec.kubelet.anonymousAuth' to 'false' via 'kops edit cluster'")
+			fmt.Fprintln(out, "")
+			fmt.Fprintln(out, "See https://kops.sigs.k8s.io/security/#kubelet-api")
+			fmt.Fprintln(out, "")
+			fmt.Fprintf(out, "%s\n", starline)
+			fmt.Fprintln(out, "")
 		}
 	}
 
@@ -377,9 +411,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			return fmt.Errorf("could not load encryptionconfig secret: %v", err)
 		}
 		if secret == nil {
-			fmt.Println("")
-			fmt.Println("You have encryptionConfig enabled, but no encryptionconfig secret has been set.")
-			fmt.Println("See `kops create secret encryptionconfig -h` and https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/")
+			out := c.messageOutput()
+			fmt.Fprintln(out, "")
+			fmt.Fprintln(out, "You have encryptionConfig enabled, but no encryptionconfig secret has been set.")
+			fmt.Fprintln(out, "See `kops create secret encryptionconfig -h` and https://kubernetes.io/docs/tasks/administer-cluster/encrypt-data/")
 			return fmt.Errorf("could not find encryptionconfig secret")
 		}
 		hashBytes := sha256.Sum256(secret.Data)
@@ -393,9 +428,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 			return fmt.Errorf("could not load the ciliumpassword secret: %w", err)
 		}
 		if secret == nil {
-			fmt.Println("")
-			fmt.Println("You have cilium encryption enabled, but no ciliumpassword secret has been set.")
-			fmt.Println("See `kops create secret ciliumpassword -h`")
+			out := c.messageOutput()
+			fmt.Fprintln(out, "")
+			fmt.Fprintln(out, "You have cilium encryption enabled, but no ciliumpassword secret has been set.")
+			fmt.Fprintln(out, "See `kops create secret ciliumpassword -h`")
 			return fmt.Errorf("could not find ciliumpassword secret")
 		}
 	}
@@ -714,6 +750,38 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("error building tasks: %v", err)
 	}
 
+	if c.OnlyInstanceGroupTemplate != "" {
+		var ig *kops.InstanceGroup
+		for _, g := range c.InstanceGroups {
+			if g.Name == c.OnlyInstanceGroupTemplate {
+				ig = g
+			}
+		}
+		if ig == nil {
+			return fmt.Errorf("InstanceGroup %q not found", c.OnlyInstanceGroupTemplate)
+		}
+		if err := restrictToInstanceGroupTemplate(modelContext, ig, c.TaskMap); err != nil {
+			return err
+		}
+	}
+
+	// All the images the cluster uses have now been remapped; verify them before anything that runs them is created
+	if !c.GetAssets {
+		verifier, err := assets.NewImageVerifier(ctx, cluster.Spec.Assets)
+		if err != nil {
+			return err
+		}
+		if verifier != nil {
+			var images []string
+			for _, imageAsset := range assetBuilder.ImageAssets {
+				images = append(images, imageAsset.RunLocation)
+			}
+			if err := verifier.VerifyImages(images); err != nil {
+				return err
+			}
+		}
+	}
+
 	var target fi.CloudupTarget
 	shouldPrecreateDNS := true
 
@@ -773,10 +841,17 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		if c.GetAssets {
 			out = io.Discard
 		}
-		target = fi.NewCloudupDryRunTarget(assetBuilder, out)
+		dryRunTarget := fi.NewCloudupDryRunTarget(assetBuilder, out)
+		if c.PlanFormat != "" {
+			dryRunTarget.SetPlanFormat(c.PlanFormat)
+		}
+		target = dryRunTarget
 
 		// Avoid making changes on a dry-run
 		shouldPrecreateDNS = false
@@ -793,10 +868,36 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		}
 	}
 
+	var policy *ChangePolicy
+	if !c.GetAssets && !c.SkipChangePolicy {
+		policy, err = LoadChangePolicy(ctx, c.Clientset.VFSContext(), configBase, c.ChangePolicyPaths)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Tasks = fi.DescribeTasks(c.TaskMap)
+	if c.SavedPlan != nil {
+		if err := c.SavedPlan.VerifyTasks(c.Tasks); err != nil {
+			return fmt.Errorf("cannot apply plan: %w", err)
+		}
+	}
+
 	context, err := fi.NewCloudupContext(ctx, deletionProcessingMode, target, cluster, cloud, keyStore, secretStore, configBase, c.TaskMap)
 	if err != nil {
 		return fmt.Errorf("error building context: %v", err)
 	}
+	if policy != nil && c.TargetName == TargetDirect {
+		// The changes are refused before they are made; a dry run checks all of them once it finishes
+		context.AddChangeChecker(&changePolicyChecker{
+			policy:       policy,
+			taskMap:      c.TaskMap,
+			allowedRules: c.AllowedChangePolicyRules,
+		})
+	}
+	if c.SavedPlan != nil {
+		context.AddChangeChecker(c.SavedPlan)
+	}
 
 	var options fi.RunTasksOptions
 	if c.RunTasksOptions != nil {
@@ -807,7 +908,7 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 
 	err = context.RunTasks(options)
 	if err != nil {
-		return fmt.Errorf("error running tasks: %v", err)
+		return fmt.Errorf("error running tasks: %w", err)
 	}
 
 	if !cluster.PublishesDNSRecords() {
@@ -825,9 +926,24 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) error {
 		return fmt.Errorf("error closing target: %v", err)
 	}
 
+	if dryRunTarget, ok := target.(*fi.CloudupDryRunTarget); ok && policy != nil {
+		plan, err := dryRunTarget.Plan(c.TaskMap)
+		if err != nil {
+			return err
+		}
+		includeDeferred := c.DeletionProcessing == fi.DeletionProcessingModeDeleteIncludingDeferred
+		if violations := policy.Evaluate(plan, c.TaskMap, c.AllowedChangePolicyRules, includeDeferred); len(violations) != 0 {
+			return &ChangePolicyError{Violations: violations}
+		}
+	}
+
 	c.ImageAssets = assetBuilder.ImageAssets
 	c.FileAssets = assetBuilder.FileAssets
 
+	if c.TargetName == TargetDirect && !c.GetAssets && c.Phase == "" {
+		c.recordInstanceGroupRevisions(ctx, appliedInstanceGroups)
+	}
+
 	return nil
 }
 
@@ -852,6 +968,8 @@ func (c *ApplyClusterCmd) upgradeSpecs(ctx context.Context, assetBuilder *assets
 
 // validateKopsVersion ensures that kops meet the version requirements / recommendations in the channel
 func (c *ApplyClusterCmd) validateKopsVersion() error {
+	out := c.messageOutput()
+
 	kopsVersion, err := semver.ParseTolerant(kopsbase.Version)
 	if err != nil {
 		klog.Warningf("unable to parse kops version %q", kopsbase.Version)
@@ -882,31 +1000,31 @@ func (c *ApplyClusterCmd) validateKopsVersion() error {
 	}
 
 	if recommended != nil && !required && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("A new kops version is available: %s", recommended)
-		fmt.Printf("\n")
-		fmt.Printf("Upgrading is recommended\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "A new kops version is available: %s", recommended)
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "Upgrading is recommended\n")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 	} else if required {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 		if recommended != nil {
-			fmt.Printf("a new kops version is available: %s\n", recommended)
+			fmt.Fprintf(out, "a new kops version is available: %s\n", recommended)
 		}
-		fmt.Println("")
-		fmt.Printf("This version of kops (%s) is no longer supported; upgrading is required\n", kopsbase.Version)
-		fmt.Printf("(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
-		fmt.Println("")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintln(out, "")
+		fmt.Fprintf(out, "This version of kops (%s) is no longer supported; upgrading is required\n", kopsbase.Version)
+		fmt.Fprintf(out, "(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
+		fmt.Fprintln(out, "")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_kops", recommended.String()))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 	}
 
 	if required {
@@ -920,6 +1038,8 @@ func (c *ApplyClusterCmd) validateKopsVersion() error {
 
 // validateKubernetesVersion ensures that kubernetes meet the version requirements / recommendations in the channel
 func (c *ApplyClusterCmd) validateKubernetesVersion() error {
+	out := c.messageOutput()
+
 	parsed, err := util.ParseKubernetesVersion(c.Cluster.Spec.KubernetesVersion)
 	if err != nil {
 		klog.Warningf("unable to parse kubernetes version %q", c.Cluster.Spec.KubernetesVersion)
@@ -936,14 +1056,14 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 		tooNewVersion.Pre = nil
 		tooNewVersion.Build = nil
 		if util.IsKubernetesGTE(tooNewVersion.String(), *parsed) {
-			fmt.Printf("\n")
-			fmt.Printf("%s\n", starline)
-			fmt.Printf("\n")
-			fmt.Printf("This version of kubernetes is not yet supported; upgrading kops is required\n")
-			fmt.Printf("(you can bypass this check by exporting KOPS_RUN_TOO_NEW_VERSION)\n")
-			fmt.Printf("\n")
-			fmt.Printf("%s\n", starline)
-			fmt.Printf("\n")
+			fmt.Fprintf(out, "\n")
+			fmt.Fprintf(out, "%s\n", starline)
+			fmt.Fprintf(out, "\n")
+			fmt.Fprintf(out, "This version of kubernetes is not yet supported; upgrading kops is required\n")
+			fmt.Fprintf(out, "(you can bypass this check by exporting KOPS_RUN_TOO_NEW_VERSION)\n")
+			fmt.Fprintf(out, "\n")
+			fmt.Fprintf(out, "%s\n", starline)
+			fmt.Fprintf(out, "\n")
 			if os.Getenv("KOPS_RUN_TOO_NEW_VERSION") == "" {
 				return fmt.Errorf("kops upgrade is required")
 			}
@@ -951,25 +1071,25 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 	}
 
 	if !util.IsKubernetesGTE(OldestSupportedKubernetesVersion, *parsed) {
-		fmt.Printf("This version of Kubernetes is no longer supported; upgrading Kubernetes is required\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "This version of Kubernetes is no longer supported; upgrading Kubernetes is required\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 		return fmt.Errorf("kubernetes upgrade is required")
 	}
 	if !util.IsKubernetesGTE(OldestRecommendedKubernetesVersion, *parsed) && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("Kops support for this Kubernetes version is deprecated and will be removed in a future release.\n")
-		fmt.Printf("\n")
-		fmt.Printf("Upgrading Kubernetes is recommended\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "Kops support for this Kubernetes version is deprecated and will be removed in a future release.\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "Upgrading Kubernetes is recommended\n")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_k8s", OldestRecommendedKubernetesVersion))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 
 	}
 
@@ -999,31 +1119,31 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 	}
 
 	if recommended != nil && !required && !c.GetAssets {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
-		fmt.Printf("A new kubernetes version is available: %s\n", recommended)
-		fmt.Printf("Upgrading is recommended (try kops upgrade cluster)\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "A new kubernetes version is available: %s\n", recommended)
+		fmt.Fprintf(out, "Upgrading is recommended (try kops upgrade cluster)\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 	} else if required {
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 		if recommended != nil {
-			fmt.Printf("A new kubernetes version is available: %s\n", recommended)
+			fmt.Fprintf(out, "A new kubernetes version is available: %s\n", recommended)
 		}
-		fmt.Printf("\n")
-		fmt.Printf("This version of kubernetes is no longer supported; upgrading is required\n")
-		fmt.Printf("(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
-		fmt.Printf("\n")
-		fmt.Printf("More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
-		fmt.Printf("\n")
-		fmt.Printf("%s\n", starline)
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "This version of kubernetes is no longer supported; upgrading is required\n")
+		fmt.Fprintf(out, "(you can bypass this check by exporting KOPS_RUN_OBSOLETE_VERSION)\n")
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "More information: %s\n", buildPermalink("upgrade_k8s", recommended.String()))
+		fmt.Fprintf(out, "\n")
+		fmt.Fprintf(out, "%s\n", starline)
+		fmt.Fprintf(out, "\n")
 	}
 
 	if required {
@@ -1035,6 +1155,72 @@ func (c *ApplyClusterCmd) validateKubernetesVersion() error {
 	return nil
 }
 
+// warnUnsupportedBootstrapAddons warns about held or pinned bootstrap addons that don't support the kubernetes version
+func (c *ApplyClusterCmd) warnUnsupportedBootstrapAddons() {
+	unsupported := unsupportedBootstrapAddons(c.Cluster)
+	if len(unsupported) == 0 {
+		return
+	}
+
+	out := c.messageOutput()
+
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "%s\n", starline)
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "Kubernetes version %s is outside the range supported by these held or pinned bootstrap addons:\n", c.Cluster.Spec.KubernetesVersion)
+	fmt.Fprintf(out, "\n")
+	for _, s := range unsupported {
+		fmt.Fprintf(out, "  %s\n", s)
+	}
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "The addons may not work; update or remove their entries in spec.bootstrapAddons\n")
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "%s\n", starline)
+	fmt.Fprintf(out, "\n")
+}
+
+// messageOutput returns where warnings and banners for the operator are printed.
+// They are printed to stderr when printing a plan, so that they don't corrupt the plan.
+func (c *ApplyClusterCmd) messageOutput() io.Writer {
+	if c.MessageOutput != nil {
+		return c.MessageOutput
+	}
+	if c.PlanFormat != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// unsupportedBootstrapAddons returns a description of each held or pinned bootstrap addon
+// whose kubernetesVersion range doesn't include the cluster's kubernetes version.
+func unsupportedBootstrapAddons(cluster *kops.Cluster) []string {
+	if len(cluster.Spec.BootstrapAddons) == 0 {
+		return nil
+	}
+
+	kubernetesVersion, err := util.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
+	if err != nil {
+		klog.Warningf("unable to parse kubernetes version %q", cluster.Spec.KubernetesVersion)
+		return nil
+	}
+
+	var unsupported []string
+	for _, addon := range cluster.Spec.BootstrapAddons {
+		if addon.KubernetesVersion == "" {
+			continue
+		}
+		versionRange, err := semver.ParseRange(addon.KubernetesVersion)
+		if err != nil {
+			klog.Warningf("unable to parse kubernetesVersion %q of bootstrap addon %q", addon.KubernetesVersion, addon.Name)
+			continue
+		}
+		if !versionRange(*kubernetesVersion) {
+			unsupported = append(unsupported, fmt.Sprintf("%s (supports %s)", addon.Name, addon.KubernetesVersion))
+		}
+	}
+	return unsupported
+}
+
 // addFileAssets adds the file assets within the assetBuilder
 func (c *ApplyClusterCmd) addFileAssets(assetBuilder *assets.AssetBuilder) error {
 	var baseURL string
@@ -1215,7 +1401,10 @@ func NewNodeUpConfigBuilder(cluster *kops.Cluster, assetBuilder *assets.AssetBui
 	}
 
 	for i := range cluster.Spec.Addons {
-		channels = append(channels, cluster.Spec.Addons[i].Manifest)
+		// Chart addons are rendered into the bootstrap channel
+		if cluster.Spec.Addons[i].Manifest != "" {
+			channels = append(channels, cluster.Spec.Addons[i].Manifest)
+		}
 	}
 
 	etcdManifests := map[string][]string{}
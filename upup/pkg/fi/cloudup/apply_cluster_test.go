@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestUnsupportedBootstrapAddons(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.29.2",
+			BootstrapAddons: []kops.BootstrapAddonSpec{
+				{Name: "coredns.addons.k8s.io", Hold: true},
+				{Name: "networking.cilium.io", Hold: true, KubernetesVersion: ">=1.27.0 <1.30.0"},
+				{Name: "aws-ebs-csi-driver.addons.k8s.io", Manifest: "s3://bucket/ebs.yaml", KubernetesVersion: "<1.29.0"},
+			},
+		},
+	}
+
+	actual := unsupportedBootstrapAddons(cluster)
+	expected := []string{"aws-ebs-csi-driver.addons.k8s.io (supports <1.29.0)"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
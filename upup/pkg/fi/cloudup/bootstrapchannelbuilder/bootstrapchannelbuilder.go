@@ -56,6 +56,9 @@ type BootstrapChannelBuilder struct {
 	Lifecycle     fi.Lifecycle
 	templates     *templates.Templates
 	assetBuilder  *assets.AssetBuilder
+
+	// existingChannel is the bootstrap channel last written to the state store, loaded when an addon is held.
+	existingChannel *channelsapi.Addons
 }
 
 var _ fi.CloudupModelBuilder = &BootstrapChannelBuilder{}
@@ -105,29 +108,51 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		return err
 	}
 
+	overrides := b.bootstrapAddonOverrides()
 	for _, a := range addons.Items {
 		// Older versions of channels that may be running on the upgrading cluster requires Version to be set
 		// We hardcode version to a high version to ensure an update is triggered on first run, and from then on
 		// only a hash change will trigger an addon update.
 		a.Spec.Version = "9.99.0"
 
+		override := overrides[*a.Spec.Name]
+		delete(overrides, *a.Spec.Name)
+		if override != nil && override.Hold {
+			held, err := b.holdAddon(c.Context(), a)
+			if err != nil {
+				return err
+			}
+			if held {
+				continue
+			}
+		}
+
 		key := *a.Spec.Name
 		if a.Spec.Id != "" {
 			key = key + "-" + a.Spec.Id
 		}
 		name := b.Cluster.ObjectMeta.Name + "-addons-" + key
-		manifestPath := "addons/" + *a.Spec.Manifest
-		klog.V(4).Infof("Addon %q", name)
 
-		manifestResource := b.templates.Find(manifestPath)
-		if manifestResource == nil {
-			return fmt.Errorf("unable to find manifest %s", manifestPath)
-		}
+		var manifestBytes []byte
+		if override != nil && override.Manifest != "" {
+			a.Spec.Manifest = fi.PtrTo(*a.Spec.Name + "/pinned.yaml")
+			manifestBytes, err = readPinnedManifest(*a.Spec.Name, override)
+			if err != nil {
+				return err
+			}
+		} else {
+			manifestResource := b.templates.Find("addons/" + *a.Spec.Manifest)
+			if manifestResource == nil {
+				return fmt.Errorf("unable to find manifest addons/%s", *a.Spec.Manifest)
+			}
 
-		manifestBytes, err := fi.ResourceAsBytes(manifestResource)
-		if err != nil {
-			return fmt.Errorf("error reading manifest %s: %v", manifestPath, err)
+			manifestBytes, err = fi.ResourceAsBytes(manifestResource)
+			if err != nil {
+				return fmt.Errorf("error reading manifest addons/%s: %v", *a.Spec.Manifest, err)
+			}
 		}
+		manifestPath := "addons/" + *a.Spec.Manifest
+		klog.V(4).Infof("Addon %q", name)
 
 		// Go through any transforms that are best expressed as code
 		remapped, err := addonmanifests.RemapAddonManifest(a.Spec, b.KopsModelContext, b.assetBuilder, manifestBytes, serviceAccounts)
@@ -160,6 +185,10 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		})
 	}
 
+	for name := range overrides {
+		klog.Warningf("spec.bootstrapAddons refers to addon %q, which is not in the bootstrap channel", name)
+	}
+
 	if featureflag.UseAddonOperators.Enabled() {
 		ob := &wellknownoperators.Builder{
 			VFSContext: vfs.Context,
@@ -212,6 +241,10 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		}
 	}
 
+	if err := b.buildChartAddons(c, addons, serviceAccounts); err != nil {
+		return err
+	}
+
 	// Not all objects in ClusterAddons should be applied to the cluster - although most should.
 	// However, there are a handful of well-known exceptions:
 	// e.g. configuration objects which are instead configured via files on the nodes.
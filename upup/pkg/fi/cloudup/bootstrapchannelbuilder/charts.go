@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	channelsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/model/components/addonmanifests"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// buildChartAddons renders the Helm charts referenced in spec.addons into the bootstrap channel,
+// so that they are remapped, pruned and applied in the same way as the addons built into kOps.
+func (b *BootstrapChannelBuilder) buildChartAddons(c *fi.CloudupModelBuilderContext, addons *AddonList, serviceAccounts map[string]iam.Subject) error {
+	for _, clusterAddon := range b.Cluster.Spec.Addons {
+		chart := clusterAddon.Chart
+		if chart == nil {
+			continue
+		}
+
+		values := make(map[string]interface{})
+		if chart.Values != "" {
+			if err := utils.YamlUnmarshal([]byte(chart.Values), &values); err != nil {
+				return fmt.Errorf("error parsing values for addon %q: %w", chart.Name, err)
+			}
+		}
+
+		namespace := chart.Namespace
+		if namespace == "" {
+			namespace = "kube-system"
+		}
+
+		a := &channelsapi.AddonSpec{
+			Name:               fi.PtrTo(chart.Name),
+			Selector:           map[string]string{"k8s-addon": chart.Name},
+			Manifest:           fi.PtrTo(chart.Name + "/" + chart.Chart + "-" + chart.Version + ".yaml"),
+			NeedsRollingUpdate: channelsapi.NeedsRollingUpdate(chart.NeedsRollingUpdate),
+			NeedsPKI:           chart.NeedsPKI,
+			Version:            "9.99.0",
+		}
+
+		name := b.Cluster.ObjectMeta.Name + "-addons-" + chart.Name
+		manifestPath := "addons/" + *a.Manifest
+		klog.V(4).Infof("Addon %q", name)
+
+		chartSpec := &channelsapi.ChartSpec{
+			Repository: chart.Repository,
+			Name:       chart.Chart,
+			Version:    chart.Version,
+			Values:     values,
+		}
+		manifestBytes, err := channels.RenderChart(c.Context(), vfs.Context, chartSpec, chart.Name, namespace)
+		if err != nil {
+			return fmt.Errorf("error rendering chart for addon %q: %w", chart.Name, err)
+		}
+
+		manifestBytes, err = ensureNamespace(manifestBytes, namespace)
+		if err != nil {
+			return fmt.Errorf("error rendering chart for addon %q: %w", chart.Name, err)
+		}
+
+		// Go through any transforms that are best expressed as code
+		manifestBytes, err = addonmanifests.RemapAddonManifest(a, b.KopsModelContext, b.assetBuilder, manifestBytes, serviceAccounts)
+		if err != nil {
+			return fmt.Errorf("error remapping manifest %s: %v", manifestPath, err)
+		}
+
+		// Trim whitespace
+		manifestBytes = []byte(strings.TrimSpace(string(manifestBytes)))
+
+		manifestHash, err := utils.HashString(string(manifestBytes))
+		if err != nil {
+			return fmt.Errorf("error hashing manifest: %v", err)
+		}
+		a.ManifestHash = manifestHash
+
+		c.AddTask(&fitasks.ManagedFile{
+			Contents:  fi.NewBytesResource(manifestBytes),
+			Lifecycle: b.Lifecycle,
+			Location:  fi.PtrTo(manifestPath),
+			Name:      fi.PtrTo(name),
+		})
+
+		addon := addons.Add(a)
+		addon.ManifestData = manifestBytes
+		addon.BuildPrune = true
+	}
+
+	return nil
+}
+
+// ensureNamespace adds the release namespace to the manifest, unless the chart already creates it,
+// in the same way as `helm install --create-namespace`.
+func ensureNamespace(manifest []byte, namespace string) ([]byte, error) {
+	if namespace == "kube-system" {
+		return manifest, nil
+	}
+
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		if object.Kind() == "Namespace" && object.GetName() == namespace {
+			return manifest, nil
+		}
+	}
+
+	ns := kubemanifest.NewObject(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name": namespace,
+		},
+	})
+	return append(kubemanifest.ObjectList{ns}, objects...).ToYAML()
+}
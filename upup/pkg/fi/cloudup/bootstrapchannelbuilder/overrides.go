@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	channelsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// bootstrapAddonOverrides returns the entries of spec.bootstrapAddons, by addon name.
+func (b *BootstrapChannelBuilder) bootstrapAddonOverrides() map[string]*kops.BootstrapAddonSpec {
+	overrides := make(map[string]*kops.BootstrapAddonSpec)
+	for i := range b.Cluster.Spec.BootstrapAddons {
+		override := &b.Cluster.Spec.BootstrapAddons[i]
+		overrides[override.Name] = override
+	}
+	return overrides
+}
+
+// findExistingAddon returns the spec of the named addon in the bootstrap channel that was last written
+// to the state store, or nil if there is no such addon.
+// We match on the name only, so that an addon is held even if kOps has moved it to a manifest with a different id.
+func (b *BootstrapChannelBuilder) findExistingAddon(ctx context.Context, name string) (*channelsapi.AddonSpec, error) {
+	if b.existingChannel == nil {
+		configBase, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.ConfigStore.Base)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing configStore.base %q: %w", b.Cluster.Spec.ConfigStore.Base, err)
+		}
+		p := configBase.Join("addons", "bootstrap-channel.yaml")
+
+		existing := &channelsapi.Addons{}
+		data, err := p.ReadFile(ctx)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error reading bootstrap channel %s: %w", p, err)
+			}
+		} else if err := utils.YamlUnmarshal(data, existing); err != nil {
+			return nil, fmt.Errorf("error parsing bootstrap channel %s: %w", p, err)
+		}
+		b.existingChannel = existing
+	}
+
+	for _, addon := range b.existingChannel.Spec.Addons {
+		if addon.Name != nil && *addon.Name == name {
+			return addon, nil
+		}
+	}
+	return nil, nil
+}
+
+// holdAddon replaces the spec of the addon with its spec in the existing bootstrap channel,
+// so that channels keeps the installed version. The manifest it references is already in the state store.
+// It returns false if the addon is not in the existing channel, in which case kOps installs the version it renders.
+func (b *BootstrapChannelBuilder) holdAddon(ctx context.Context, addon *Addon) (bool, error) {
+	name := *addon.Spec.Name
+
+	existing, err := b.findExistingAddon(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if existing == nil {
+		klog.Warningf("bootstrap addon %q is held but has not been installed; installing the version from this version of kOps", name)
+		return false, nil
+	}
+
+	klog.Infof("holding bootstrap addon %q at manifest %s (hash %s)", name, fi.ValueOf(existing.Manifest), existing.ManifestHash)
+	addon.Spec = existing
+	// The existing spec already has the prune directives for the held manifest
+	addon.BuildPrune = false
+	return true, nil
+}
+
+// readPinnedManifest reads the manifest that an addon is pinned to.
+func readPinnedManifest(name string, override *kops.BootstrapAddonSpec) ([]byte, error) {
+	klog.Infof("pinning bootstrap addon %q to manifest %s", name, override.Manifest)
+
+	manifestBytes, err := vfs.Context.ReadFile(override.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pinned manifest %s for bootstrap addon %q: %w", override.Manifest, name, err)
+	}
+	return manifestBytes, nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	kopsapi "k8s.io/kops/pkg/apis/kops"
@@ -80,7 +81,67 @@ func TestBootstrapChannelBuilder_AWSCloudController(t *testing.T) {
 	runChannelBuilderTest(t, "awscloudcontroller", []string{"aws-cloud-controller.addons.k8s.io-k8s-1.18"})
 }
 
+func TestBootstrapChannelBuilder_BootstrapAddons(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	setup := func(t *testing.T) {
+		ctx := context.TODO()
+
+		// The bootstrap channel written by a previous version of kOps, with the version of coredns we hold
+		existingChannel := `kind: Addons
+metadata:
+  name: bootstrap
+spec:
+  addons:
+  - id: k8s-1.12
+    manifest: coredns.addons.k8s.io/k8s-1.12.yaml
+    manifestHash: 1c5f1a3e9b2d4c6e8f0a1b3c5d7e9f1a3b5c7d9e1f3a5b7c9d1e3f5a7b9c1d3e
+    name: coredns.addons.k8s.io
+    selector:
+      k8s-addon: coredns.addons.k8s.io
+    version: 9.99.0
+`
+		pinnedManifest := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kops:system:kubelet-api-admin
+  annotations:
+    example.com/pinned: "true"
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:kubelet-api-admin
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: kubelet-api
+`
+		for p, contents := range map[string]string{
+			"memfs://clusters.example.com/minimal.example.com/addons/bootstrap-channel.yaml": existingChannel,
+			"memfs://pinned/kubelet-api.yaml":                                                pinnedManifest,
+		} {
+			vfsPath, err := vfs.Context.BuildVfsPath(p)
+			if err != nil {
+				t.Fatalf("error building vfspath: %v", err)
+			}
+			if err := vfsPath.WriteFile(ctx, strings.NewReader(contents), nil); err != nil {
+				t.Fatalf("error writing %s: %v", p, err)
+			}
+		}
+	}
+
+	runChannelBuilderTestWithSetup(t, "bootstrapaddons", []string{"kubelet-api.rbac.addons.k8s.io-k8s-1.9"}, setup)
+}
+
 func runChannelBuilderTest(t *testing.T, key string, addonManifests []string) {
+	runChannelBuilderTestWithSetup(t, key, addonManifests, nil)
+}
+
+// runChannelBuilderTestWithSetup runs the test, calling setup (if not nil) after the state store has been reset.
+func runChannelBuilderTestWithSetup(t *testing.T, key string, addonManifests []string, setup func(t *testing.T)) {
 	ctx := context.TODO()
 
 	basedir := path.Join("tests/bootstrapchannelbuilder/", key)
@@ -117,6 +178,9 @@ func runChannelBuilderTest(t *testing.T, key string, addonManifests []string) {
 	}
 
 	vfs.Context.ResetMemfsContext(true)
+	if setup != nil {
+		setup(t)
+	}
 
 	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
 	if err != nil {
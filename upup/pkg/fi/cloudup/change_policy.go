@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// ChangePolicy holds rules forbidding changes that cluster updates would otherwise make.
+type ChangePolicy struct {
+	Rules []ChangePolicyRule `json:"rules"`
+}
+
+// ChangePolicyRule forbids the changes it matches.
+// A change matches if it matches each of the set fields of the rule;
+// a rule without any of them set forbids all changes.
+type ChangePolicyRule struct {
+	// Name identifies the rule; the changes it forbids are allowed if the update allows the rule by name.
+	Name string `json:"name"`
+	// Message explains why the changes are forbidden.
+	Message string `json:"message,omitempty"`
+	// Types matches the changes of tasks of any of these types, such as EBSVolume.
+	Types []string `json:"types,omitempty"`
+	// Actions matches the changes taking any of these actions: create, modify or delete.
+	Actions []fi.PlanAction `json:"actions,omitempty"`
+	// Lifecycles matches the changes of tasks with any of these lifecycles; it never matches deletions.
+	Lifecycles []fi.Lifecycle `json:"lifecycles,omitempty"`
+	// Fields matches the changes setting any of these fields; it never matches deletions.
+	Fields []string `json:"fields,omitempty"`
+	// Names matches the changes of resources with names matching any of these glob patterns.
+	Names []string `json:"names,omitempty"`
+}
+
+// ChangePolicyViolation is a change forbidden by a rule.
+type ChangePolicyViolation struct {
+	Rule   *ChangePolicyRule
+	Action fi.PlanAction
+	Type   string
+	Name   string
+}
+
+// ChangePolicyError is returned when an update would make changes forbidden by the change policy.
+type ChangePolicyError struct {
+	Violations []ChangePolicyViolation
+}
+
+func (e *ChangePolicyError) Error() string {
+	var b strings.Builder
+	b.WriteString("changes forbidden by the change policy:")
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  %s %s %q: forbidden by rule %q", v.Action, v.Type, v.Name, v.Rule.Name)
+		if v.Rule.Message != "" {
+			fmt.Fprintf(&b, ": %s", v.Rule.Message)
+		}
+	}
+	return b.String()
+}
+
+// LoadChangePolicy reads the change policy from the state store, if present, and from the given paths.
+// It returns nil if no rules are found.
+func LoadChangePolicy(ctx context.Context, vfsContext *vfs.VFSContext, configBase vfs.Path, paths []string) (*ChangePolicy, error) {
+	policy := &ChangePolicy{}
+
+	if configBase != nil {
+		p := configBase.Join(registry.PathChangePolicy)
+		b, err := p.ReadFile(ctx)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error reading change policy %q: %w", p, err)
+		}
+		if err == nil {
+			if err := policy.add(p.Path(), b); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, location := range paths {
+		p, err := vfsContext.BuildVfsPath(location)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing change policy path %q: %w", location, err)
+		}
+		b, err := p.ReadFile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading change policy %q: %w", location, err)
+		}
+		if err := policy.add(location, b); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(policy.Rules) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// add parses and validates the rules of a change policy file, and adds them to the policy.
+func (p *ChangePolicy) add(location string, b []byte) error {
+	parsed := &ChangePolicy{}
+	if err := yaml.UnmarshalStrict(b, parsed); err != nil {
+		return fmt.Errorf("error parsing change policy %q: %w", location, err)
+	}
+	for i := range parsed.Rules {
+		rule := &parsed.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("change policy %q: rule %d has no name", location, i)
+		}
+		for _, existing := range p.Rules {
+			if existing.Name == rule.Name {
+				return fmt.Errorf("change policy %q: duplicate rule %q", location, rule.Name)
+			}
+		}
+		for _, action := range rule.Actions {
+			switch action {
+			case fi.PlanActionCreate, fi.PlanActionModify, fi.PlanActionDelete:
+			default:
+				return fmt.Errorf("change policy %q: rule %q has unknown action %q", location, rule.Name, action)
+			}
+		}
+		for _, lifecycle := range rule.Lifecycles {
+			if !fi.Lifecycles.Has(string(lifecycle)) {
+				return fmt.Errorf("change policy %q: rule %q has unknown lifecycle %q", location, rule.Name, lifecycle)
+			}
+		}
+		for _, pattern := range rule.Names {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("change policy %q: rule %q has invalid name pattern %q", location, rule.Name, pattern)
+			}
+		}
+		p.Rules = append(p.Rules, *rule)
+	}
+	return nil
+}
+
+// Evaluate returns the changes of the plan forbidden by rules not in allowedRules.
+// The lifecycles of the changes are looked up in the taskMap.
+// Deferred deletions are only evaluated if they will be made.
+func (p *ChangePolicy) Evaluate(plan *fi.Plan, taskMap map[string]fi.CloudupTask, allowedRules []string, includeDeferred bool) []ChangePolicyViolation {
+	var violations []ChangePolicyViolation
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if slices.Contains(allowedRules, rule.Name) {
+			continue
+		}
+
+		for _, change := range plan.Changes {
+			var lifecycle fi.Lifecycle
+			if hasLifecycle, ok := taskMap[change.Key].(fi.HasLifecycle); ok {
+				lifecycle = hasLifecycle.GetLifecycle()
+			}
+			var fields []string
+			for _, field := range change.Fields {
+				fields = append(fields, field.Name)
+			}
+			name := strings.TrimPrefix(change.Key, change.Type+"/")
+			if rule.matches(change.Type, change.Action, name, lifecycle, fields) {
+				violations = append(violations, ChangePolicyViolation{Rule: rule, Action: change.Action, Type: change.Type, Name: name})
+			}
+		}
+
+		for _, deletion := range plan.Deletions {
+			if deletion.Deferred && !includeDeferred {
+				continue
+			}
+			if rule.matches(deletion.Type, deletion.Action, deletion.Item, "", nil) {
+				violations = append(violations, ChangePolicyViolation{Rule: rule, Action: deletion.Action, Type: deletion.Type, Name: deletion.Item})
+			}
+		}
+	}
+	return violations
+}
+
+// changePolicyChecker refuses each change forbidden by the change policy before it is made.
+type changePolicyChecker struct {
+	policy       *ChangePolicy
+	taskMap      map[string]fi.CloudupTask
+	allowedRules []string
+}
+
+var _ fi.ChangeChecker = &changePolicyChecker{}
+
+func (c *changePolicyChecker) CheckChange(change *fi.PlanChange) error {
+	return c.check(&fi.Plan{Changes: []fi.PlanChange{*change}})
+}
+
+// CheckDeletion is only called for deletions that will be made, so deferred deletions are evaluated.
+func (c *changePolicyChecker) CheckDeletion(deletion *fi.PlanDeletion) error {
+	return c.check(&fi.Plan{Deletions: []fi.PlanDeletion{*deletion}})
+}
+
+func (c *changePolicyChecker) check(plan *fi.Plan) error {
+	if violations := c.policy.Evaluate(plan, c.taskMap, c.allowedRules, true); len(violations) != 0 {
+		return &ChangePolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (r *ChangePolicyRule) matches(taskType string, action fi.PlanAction, name string, lifecycle fi.Lifecycle, fields []string) bool {
+	if len(r.Types) != 0 && !slices.Contains(r.Types, taskType) {
+		return false
+	}
+	if len(r.Actions) != 0 && !slices.Contains(r.Actions, action) {
+		return false
+	}
+	if len(r.Lifecycles) != 0 && !slices.Contains(r.Lifecycles, lifecycle) {
+		return false
+	}
+	if len(r.Fields) != 0 && !slices.ContainsFunc(fields, func(field string) bool { return slices.Contains(r.Fields, field) }) {
+		return false
+	}
+	if len(r.Names) != 0 && !slices.ContainsFunc(r.Names, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}) {
+		return false
+	}
+	return true
+}
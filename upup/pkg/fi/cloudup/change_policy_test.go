@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestLoadChangePolicy(t *testing.T) {
+	ctx := context.TODO()
+	vfs.Context.ResetMemfsContext(true)
+
+	configBase, err := vfs.Context.BuildVfsPath("memfs://tests/test.k8s.local")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	policy, err := LoadChangePolicy(ctx, vfs.Context, configBase, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Nil(t, policy, "policy without rules")
+
+	stateStorePolicy := `
+rules:
+- name: etcd-volumes
+  message: etcd volumes must never be deleted
+  types: [EBSVolume]
+  actions: [delete]
+`
+	if err := configBase.Join("change-policy.yaml").WriteFile(ctx, bytes.NewReader([]byte(stateStorePolicy)), nil); err != nil {
+		t.Fatalf("error writing policy: %v", err)
+	}
+	localPolicy := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(localPolicy, []byte("rules:\n- name: iam\n  types: [IAMRolePolicy]\n"), 0o644); err != nil {
+		t.Fatalf("error writing policy: %v", err)
+	}
+
+	policy, err = LoadChangePolicy(ctx, vfs.Context, configBase, []string{localPolicy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assert.NotNil(t, policy) && assert.Len(t, policy.Rules, 2) {
+		assert.Equal(t, "etcd-volumes", policy.Rules[0].Name)
+		assert.Equal(t, "iam", policy.Rules[1].Name)
+	}
+
+	for _, invalid := range []struct {
+		policy   string
+		expected string
+	}{
+		{policy: "rules:\n- types: [VPC]\n", expected: "rule 0 has no name"},
+		{policy: "rules:\n- name: etcd-volumes\n", expected: `duplicate rule "etcd-volumes"`},
+		{policy: "rules:\n- name: vpc\n  actions: [replace]\n", expected: `unknown action "replace"`},
+		{policy: "rules:\n- name: vpc\n  lifecycles: [Never]\n", expected: `unknown lifecycle "Never"`},
+		{policy: "rules:\n- name: vpc\n  type: [VPC]\n", expected: "error parsing change policy"},
+	} {
+		if err := os.WriteFile(localPolicy, []byte(invalid.policy), 0o644); err != nil {
+			t.Fatalf("error writing policy: %v", err)
+		}
+		_, err := LoadChangePolicy(ctx, vfs.Context, configBase, []string{localPolicy})
+		if assert.Error(t, err, invalid.policy) {
+			assert.Contains(t, err.Error(), invalid.expected)
+		}
+	}
+}
+
+func TestChangePolicyEvaluate(t *testing.T) {
+	policy := &ChangePolicy{}
+	err := policy.add("policy.yaml", []byte(`
+rules:
+- name: etcd-volumes
+  message: etcd volumes must never be deleted
+  types: [EBSVolume]
+  actions: [delete]
+  names: ["*.etcd-*"]
+- name: vpc-cidr
+  types: [VPC]
+  fields: [CIDR]
+- name: iam
+  types: [IAMRolePolicy]
+  lifecycles: [Sync]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	taskMap := map[string]fi.CloudupTask{
+		"IAMRolePolicy/nodes.test.k8s.local":   &awstasks.IAMRolePolicy{Name: fi.PtrTo("nodes.test.k8s.local"), Lifecycle: fi.LifecycleSync},
+		"IAMRolePolicy/masters.test.k8s.local": &awstasks.IAMRolePolicy{Name: fi.PtrTo("masters.test.k8s.local"), Lifecycle: fi.LifecycleExistsAndWarnIfChanges},
+	}
+	plan := &fi.Plan{
+		Changes: []fi.PlanChange{
+			{Key: "IAMRolePolicy/masters.test.k8s.local", Type: "IAMRolePolicy", Action: fi.PlanActionModify},
+			{Key: "IAMRolePolicy/nodes.test.k8s.local", Type: "IAMRolePolicy", Action: fi.PlanActionModify},
+			{Key: "VPC/test.k8s.local", Type: "VPC", Action: fi.PlanActionModify, Fields: []fi.PlanField{{Name: "Tags"}}},
+		},
+		Deletions: []fi.PlanDeletion{
+			{Type: "EBSVolume", Item: "a.etcd-events.test.k8s.local", Action: fi.PlanActionDelete},
+			{Type: "EBSVolume", Item: "a.etcd-main.test.k8s.local", Action: fi.PlanActionDelete, Deferred: true},
+			{Type: "EBSVolume", Item: "data.test.k8s.local", Action: fi.PlanActionDelete},
+		},
+	}
+
+	violations := policy.Evaluate(plan, taskMap, nil, false)
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule.Name+" "+string(v.Action)+" "+v.Type+"/"+v.Name)
+	}
+	assert.Equal(t, []string{
+		"etcd-volumes delete EBSVolume/a.etcd-events.test.k8s.local",
+		"iam modify IAMRolePolicy/nodes.test.k8s.local",
+	}, names)
+
+	err = &ChangePolicyError{Violations: violations}
+	assert.Equal(t, `changes forbidden by the change policy:
+  delete EBSVolume "a.etcd-events.test.k8s.local": forbidden by rule "etcd-volumes": etcd volumes must never be deleted
+  modify IAMRolePolicy "nodes.test.k8s.local": forbidden by rule "iam"`, err.Error())
+
+	violations = policy.Evaluate(plan, taskMap, []string{"iam"}, true)
+	names = nil
+	for _, v := range violations {
+		names = append(names, v.Rule.Name+" "+string(v.Action)+" "+v.Type+"/"+v.Name)
+	}
+	assert.Equal(t, []string{
+		"etcd-volumes delete EBSVolume/a.etcd-events.test.k8s.local",
+		"etcd-volumes delete EBSVolume/a.etcd-main.test.k8s.local",
+	}, names)
+
+	plan.Changes[2].Fields = append(plan.Changes[2].Fields, fi.PlanField{Name: "CIDR"})
+	violations = policy.Evaluate(plan, taskMap, []string{"iam", "etcd-volumes"}, true)
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, "vpc-cidr", violations[0].Rule.Name)
+	}
+}
+
+func TestChangePolicyChecker(t *testing.T) {
+	policy := &ChangePolicy{}
+	err := policy.add("policy.yaml", []byte(`
+rules:
+- name: etcd-volumes
+  types: [EBSVolume]
+  actions: [delete]
+- name: vpc-cidr
+  types: [VPC]
+  fields: [CIDR]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checker := &changePolicyChecker{policy: policy, allowedRules: []string{"vpc-cidr"}}
+
+	assert.NoError(t, checker.CheckChange(&fi.PlanChange{Key: "VPC/test.k8s.local", Type: "VPC", Action: fi.PlanActionModify, Fields: []fi.PlanField{{Name: "CIDR"}}}))
+	assert.NoError(t, checker.CheckChange(&fi.PlanChange{Key: "EBSVolume/a.etcd-main.test.k8s.local", Type: "EBSVolume", Action: fi.PlanActionModify}))
+
+	err = checker.CheckDeletion(&fi.PlanDeletion{Type: "EBSVolume", Item: "a.etcd-main.test.k8s.local", Action: fi.PlanActionDelete, Deferred: true})
+	var policyErr *ChangePolicyError
+	if assert.ErrorAs(t, err, &policyErr) && assert.Len(t, policyErr.Violations, 1) {
+		assert.Equal(t, "etcd-volumes", policyErr.Violations[0].Rule.Name)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
+)
+
+// restrictToInstanceGroupTemplate changes the lifecycle of all tasks, other than those for the
+// cloud groups of the InstanceGroup and their launch templates or instance templates, so that
+// they are only checked for changes.
+func restrictToInstanceGroupTemplate(modelContext *model.KopsModelContext, ig *kops.InstanceGroup, tasks map[string]fi.CloudupTask) error {
+	cluster := modelContext.Cluster
+	keep := make(map[fi.CloudupTask]bool)
+	switch cluster.Spec.GetCloudProvider() {
+	case kops.CloudProviderAWS:
+		name := modelContext.AutoscalingGroupName(ig)
+		for _, task := range tasks {
+			if asg, ok := task.(*awstasks.AutoscalingGroup); ok && fi.ValueOf(asg.Name) == name && asg.LaunchTemplate != nil {
+				keep[asg] = true
+				keep[asg.LaunchTemplate] = true
+			}
+		}
+	case kops.CloudProviderGCE:
+		for _, task := range tasks {
+			if igm, ok := task.(*gcetasks.InstanceGroupManager); ok && fi.ValueOf(igm.BaseInstanceName) == ig.Name && igm.InstanceTemplate != nil {
+				keep[igm] = true
+				keep[igm.InstanceTemplate] = true
+			}
+		}
+	default:
+		return fmt.Errorf("applying only the template of an InstanceGroup is not supported for cloud provider %q", cluster.Spec.GetCloudProvider())
+	}
+
+	if len(keep) == 0 {
+		return fmt.Errorf("unable to find the template of InstanceGroup %q", ig.Name)
+	}
+
+	for key, task := range tasks {
+		if keep[task] {
+			klog.V(2).Infof("applying task %q for the template of InstanceGroup %q", key, ig.Name)
+			continue
+		}
+		hl, ok := task.(fi.HasLifecycle)
+		if !ok {
+			klog.Warningf("task %T does not implement HasLifecycle", task)
+			continue
+		}
+		if hl.GetLifecycle() != fi.LifecycleIgnore {
+			hl.SetLifecycle(fi.LifecycleExistsAndWarnIfChanges)
+		}
+	}
+	return nil
+}
+
+// recordInstanceGroupRevisions records the applied specs of the InstanceGroups, so that a
+// failed rolling update can revert an InstanceGroup to its previous revision.
+func (c *ApplyClusterCmd) recordInstanceGroupRevisions(ctx context.Context, instanceGroups []*kops.InstanceGroup) {
+	revisions := c.Clientset.InstanceGroupRevisionsFor(c.Cluster)
+	for _, ig := range instanceGroups {
+		if c.OnlyInstanceGroupTemplate != "" && ig.Name != c.OnlyInstanceGroupTemplate {
+			continue
+		}
+		if err := revisions.Record(ctx, ig); err != nil {
+			klog.Warningf("unable to record the applied revision of InstanceGroup %q: %v", ig.Name, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestRestrictToInstanceGroupTemplate(t *testing.T) {
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test.k8s.local"},
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+		},
+	}
+	modelContext := &model.KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+	}
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+	}
+
+	newGroup := func(name string) (*awstasks.AutoscalingGroup, *awstasks.LaunchTemplate) {
+		lt := &awstasks.LaunchTemplate{Name: fi.PtrTo(name), Lifecycle: fi.LifecycleSync}
+		asg := &awstasks.AutoscalingGroup{Name: fi.PtrTo(name), Lifecycle: fi.LifecycleSync, LaunchTemplate: lt}
+		return asg, lt
+	}
+	asg, lt := newGroup("nodes.test.k8s.local")
+	otherASG, otherLT := newGroup("other.test.k8s.local")
+	vpc := &awstasks.VPC{Name: fi.PtrTo("test.k8s.local"), Lifecycle: fi.LifecycleSync}
+	ignored := &awstasks.VPC{Name: fi.PtrTo("ignored"), Lifecycle: fi.LifecycleIgnore}
+
+	tasks := map[string]fi.CloudupTask{
+		"AutoscalingGroup/nodes.test.k8s.local": asg,
+		"LaunchTemplate/nodes.test.k8s.local":   lt,
+		"AutoscalingGroup/other.test.k8s.local": otherASG,
+		"LaunchTemplate/other.test.k8s.local":   otherLT,
+		"VPC/test.k8s.local":                    vpc,
+		"VPC/ignored":                           ignored,
+	}
+	if err := restrictToInstanceGroupTemplate(modelContext, ig, tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]fi.Lifecycle{
+		"AutoscalingGroup/nodes.test.k8s.local": fi.LifecycleSync,
+		"LaunchTemplate/nodes.test.k8s.local":   fi.LifecycleSync,
+		"AutoscalingGroup/other.test.k8s.local": fi.LifecycleExistsAndWarnIfChanges,
+		"LaunchTemplate/other.test.k8s.local":   fi.LifecycleExistsAndWarnIfChanges,
+		"VPC/test.k8s.local":                    fi.LifecycleExistsAndWarnIfChanges,
+		"VPC/ignored":                           fi.LifecycleIgnore,
+	}
+	for key, lifecycle := range expected {
+		if actual := tasks[key].(fi.HasLifecycle).GetLifecycle(); actual != lifecycle {
+			t.Errorf("task %q: expected lifecycle %q, got %q", key, lifecycle, actual)
+		}
+	}
+
+	missing := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing"},
+		Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode},
+	}
+	if err := restrictToInstanceGroupTemplate(modelContext, missing, tasks); err == nil {
+		t.Errorf("expected an error for an InstanceGroup without a template")
+	}
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// SavedPlan is a plan computed by a dry run, that is applied later only if
+// the state it was computed from has not changed.
+type SavedPlan struct {
+	// KopsVersion is the version of kOps that computed the plan.
+	KopsVersion string `json:"kopsVersion"`
+	// ClusterName is the name of the cluster the plan applies to.
+	ClusterName string `json:"clusterName"`
+	// Fingerprint identifies the state store contents the plan was computed from.
+	Fingerprint *PlanFingerprint `json:"fingerprint"`
+	// Options are the options of the update that affect the plan.
+	Options SavedPlanOptions `json:"options"`
+	// Tasks is the task map the plan was computed from: the values of the fields of each task, by task key.
+	Tasks map[string]map[string]string `json:"tasks"`
+	// Plan holds the changes computed from the task map.
+	Plan *fi.Plan `json:"plan"`
+}
+
+// SavedPlanOptions are the options of an update that affect its plan.
+type SavedPlanOptions struct {
+	Phase              string            `json:"phase,omitempty"`
+	LifecycleOverrides map[string]string `json:"lifecycleOverrides,omitempty"`
+	Prune              bool              `json:"prune,omitempty"`
+}
+
+// PlanFingerprint identifies the state store contents a plan was computed from.
+type PlanFingerprint struct {
+	// ClusterGeneration is the generation of the cluster in the state store.
+	ClusterGeneration int64 `json:"clusterGeneration"`
+	// ClusterSpec is the hash of the cluster spec.
+	ClusterSpec string `json:"clusterSpec"`
+	// InstanceGroups maps the names of the instance groups to the hashes of their specs.
+	InstanceGroups map[string]string `json:"instanceGroups"`
+	// Keystore is the hash of the keysets in the keystore.
+	Keystore string `json:"keystore"`
+}
+
+// BuildPlanFingerprint computes the fingerprint of the cluster, its instance groups and its keystore.
+func BuildPlanFingerprint(cluster *kops.Cluster, instanceGroups []*kops.InstanceGroup, keyStore fi.CAStore) (*PlanFingerprint, error) {
+	fingerprint := &PlanFingerprint{
+		ClusterGeneration: cluster.Generation,
+		InstanceGroups:    make(map[string]string),
+	}
+
+	var err error
+	fingerprint.ClusterSpec, err = hashJSON(cluster.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing cluster spec: %w", err)
+	}
+
+	for _, ig := range instanceGroups {
+		fingerprint.InstanceGroups[ig.Name], err = hashJSON(ig.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing spec of InstanceGroup %q: %w", ig.Name, err)
+		}
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %w", err)
+	}
+	var names []string
+	for name := range keysets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hasher := sha256.New()
+	for _, name := range names {
+		keyset := keysets[name]
+		fmt.Fprintf(hasher, "keyset %s\n", name)
+		if keyset.Primary != nil {
+			fmt.Fprintf(hasher, "primary %s\n", keyset.Primary.Id)
+		}
+		var ids []string
+		for id := range keyset.Items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			item := keyset.Items[id]
+			fmt.Fprintf(hasher, "item %s\n", id)
+			if item.DistrustTimestamp != nil {
+				fmt.Fprintf(hasher, "distrusted %s\n", item.DistrustTimestamp.UTC())
+			}
+			if item.Certificate != nil {
+				b, err := item.Certificate.AsBytes()
+				if err != nil {
+					return nil, fmt.Errorf("error serializing certificate %q of keyset %q: %w", id, name, err)
+				}
+				hasher.Write(b)
+			}
+		}
+	}
+	fingerprint.Keystore = hex.EncodeToString(hasher.Sum(nil))
+
+	return fingerprint, nil
+}
+
+func hashJSON(o interface{}) (string, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Changes returns descriptions of the differences between the fingerprint and a later one.
+func (f *PlanFingerprint) Changes(later *PlanFingerprint) []string {
+	var changes []string
+	if f.ClusterGeneration != later.ClusterGeneration || f.ClusterSpec != later.ClusterSpec {
+		changes = append(changes, "the cluster spec changed")
+	}
+
+	var names []string
+	for name := range f.InstanceGroups {
+		names = append(names, name)
+	}
+	for name := range later.InstanceGroups {
+		if _, found := f.InstanceGroups[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hash, found := f.InstanceGroups[name]
+		laterHash, laterFound := later.InstanceGroups[name]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("InstanceGroup %q was created", name))
+		case !laterFound:
+			changes = append(changes, fmt.Sprintf("InstanceGroup %q was deleted", name))
+		case hash != laterHash:
+			changes = append(changes, fmt.Sprintf("InstanceGroup %q changed", name))
+		}
+	}
+
+	if f.Keystore != later.Keystore {
+		changes = append(changes, "the keystore changed")
+	}
+	return changes
+}
+
+// Verify returns an error if the saved plan cannot be applied to the cluster in its current state.
+func (p *SavedPlan) Verify(clusterName string, fingerprint *PlanFingerprint, options SavedPlanOptions) error {
+	if p.KopsVersion != kopsbase.Version {
+		return fmt.Errorf("plan was made by kOps %s, not %s", p.KopsVersion, kopsbase.Version)
+	}
+	if p.ClusterName != clusterName {
+		return fmt.Errorf("plan was made for cluster %q, not %q", p.ClusterName, clusterName)
+	}
+	if changes := p.Fingerprint.Changes(fingerprint); len(changes) != 0 {
+		return fmt.Errorf("cluster changed since the plan was made: %s", joinChanges(changes))
+	}
+	if !equalJSON(p.Options, options) {
+		return fmt.Errorf("plan was made with different options: %+v", p.Options)
+	}
+	return nil
+}
+
+// VerifyTasks returns an error if the task map now computed, as described by fi.DescribeTasks, differs from the saved one.
+func (p *SavedPlan) VerifyTasks(tasks map[string]map[string]string) error {
+	var keys []string
+	for key := range p.Tasks {
+		keys = append(keys, key)
+	}
+	for key := range tasks {
+		if _, found := p.Tasks[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, key := range keys {
+		saved, found := p.Tasks[key]
+		current, currentFound := tasks[key]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("task %q was added", key))
+		case !currentFound:
+			changes = append(changes, fmt.Sprintf("task %q was removed", key))
+		case !equalJSON(saved, current):
+			changes = append(changes, fmt.Sprintf("task %q changed", key))
+		}
+	}
+	if len(changes) != 0 {
+		return fmt.Errorf("tasks changed since the plan was made: %s", joinChanges(changes))
+	}
+	return nil
+}
+
+// CheckChange returns an error if the change is not one of the changes of the plan.
+// It is called before each change is made, so that only the planned changes are applied.
+func (p *SavedPlan) CheckChange(change *fi.PlanChange) error {
+	var planned *fi.PlanChange
+	for i := range p.Plan.Changes {
+		if p.Plan.Changes[i].Key == change.Key {
+			planned = &p.Plan.Changes[i]
+			break
+		}
+	}
+	if planned == nil {
+		return fmt.Errorf("refusing to %s %s: the change is not in the plan", change.Action, change.Key)
+	}
+	if planned.Action != change.Action {
+		return fmt.Errorf("refusing to %s %s: the plan is to %s it", change.Action, change.Key, planned.Action)
+	}
+
+	plannedFields := make(map[string]fi.PlanField)
+	for _, field := range planned.Fields {
+		plannedFields[field.Name] = field
+	}
+	for _, field := range change.Fields {
+		plannedField, found := plannedFields[field.Name]
+		delete(plannedFields, field.Name)
+		if !found {
+			// A reference to a task created by this run is only printed once its ID is known
+			if change.Action == fi.PlanActionCreate && unplannedTaskID.MatchString(field.After) {
+				continue
+			}
+			return fmt.Errorf("refusing to %s %s: field %s is not changed by the plan", change.Action, change.Key, field.Name)
+		}
+		if !planValueMatches(plannedField.Before, field.Before) || !planValueMatches(plannedField.After, field.After) {
+			return fmt.Errorf("refusing to %s %s: field %s differs from the plan", change.Action, change.Key, field.Name)
+		}
+	}
+	if len(plannedFields) != 0 {
+		var names []string
+		for name := range plannedFields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("refusing to %s %s: field %s is changed by the plan, but no longer differs", change.Action, change.Key, names[0])
+	}
+	return nil
+}
+
+// CheckDeletion returns an error if the deletion is not one of the deletions of the plan.
+func (p *SavedPlan) CheckDeletion(deletion *fi.PlanDeletion) error {
+	for _, planned := range p.Plan.Deletions {
+		if planned.Type == deletion.Type && planned.Item == deletion.Item {
+			return nil
+		}
+	}
+	return fmt.Errorf("refusing to delete %s %s: the deletion is not in the plan", deletion.Type, deletion.Item)
+}
+
+var (
+	// unplannedTaskID matches the value of a field referencing a task without a name, once the task has an ID.
+	unplannedTaskID = regexp.MustCompile(`^id:\S+$`)
+	// taskNameAndID matches a reference to a task with a name and an ID.
+	taskNameAndID = regexp.MustCompile(`name:([^\s,\]}]+) id:[^\s,\]}]+`)
+)
+
+// planValueMatches returns true if a value computed when applying a plan matches the value in the plan.
+// Tasks created while applying the plan have IDs they did not have when the plan was made,
+// so references to tasks are compared by name, and references to tasks without a name or ID match any ID.
+func planValueMatches(planned, value string) bool {
+	planned = taskNameAndID.ReplaceAllString(planned, "name:$1")
+	value = taskNameAndID.ReplaceAllString(value, "name:$1")
+	if planned == value {
+		return true
+	}
+	if !strings.Contains(planned, "id:<nil>") {
+		return false
+	}
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(planned), regexp.QuoteMeta("id:<nil>"), `id:[^\s,\]}]+`)
+	return regexp.MustCompile("^" + pattern + "$").MatchString(value)
+}
+
+func joinChanges(changes []string) string {
+	var b bytes.Buffer
+	for i, change := range changes {
+		if i != 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(change)
+	}
+	return b.String()
+}
+
+func equalJSON(a, b interface{}) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// WriteSavedPlan writes the plan to a file.
+// The file is only readable by the user, as the plan can hold sensitive values.
+func WriteSavedPlan(path string, plan *SavedPlan) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing plan: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("error writing plan to %q: %w", path, err)
+	}
+	return nil
+}
+
+// ReadSavedPlan reads a plan written by WriteSavedPlan.
+func ReadSavedPlan(path string) (*SavedPlan, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan from %q: %w", path, err)
+	}
+	plan := &SavedPlan{}
+	if err := json.Unmarshal(b, plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan from %q: %w", path, err)
+	}
+	if plan.Fingerprint == nil || plan.Tasks == nil || plan.Plan == nil {
+		return nil, fmt.Errorf("plan in %q is incomplete", path)
+	}
+	return plan, nil
+}
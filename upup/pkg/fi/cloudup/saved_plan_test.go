@@ -0,0 +1,298 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// fakePlanKeystore is a keystore that only lists its keysets.
+type fakePlanKeystore struct {
+	fi.CAStore
+	keysets map[string]*fi.Keyset
+}
+
+func (k *fakePlanKeystore) ListKeysets() (map[string]*fi.Keyset, error) {
+	return k.keysets, nil
+}
+
+func TestSavedPlanVerify(t *testing.T) {
+	newCluster := func() *kops.Cluster {
+		return &kops.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test.k8s.local", Generation: 1},
+			Spec:       kops.ClusterSpec{KubernetesVersion: "1.29.0"},
+		}
+	}
+	newInstanceGroups := func() []*kops.InstanceGroup {
+		return []*kops.InstanceGroup{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+				Spec:       kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode, MinSize: fi.PtrTo(int32(2))},
+			},
+		}
+	}
+	newKeystore := func() *fakePlanKeystore {
+		item := &fi.KeysetItem{Id: "1"}
+		return &fakePlanKeystore{keysets: map[string]*fi.Keyset{
+			"kubernetes-ca": {Items: map[string]*fi.KeysetItem{"1": item}, Primary: item},
+		}}
+	}
+	options := SavedPlanOptions{Prune: true}
+
+	fingerprint, err := BuildPlanFingerprint(newCluster(), newInstanceGroups(), newKeystore())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan := &SavedPlan{
+		KopsVersion: kopsbase.Version,
+		ClusterName: "test.k8s.local",
+		Fingerprint: fingerprint,
+		Options:     options,
+		Tasks:       map[string]map[string]string{},
+		Plan:        &fi.Plan{Changes: []fi.PlanChange{}, Deletions: []fi.PlanDeletion{}},
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := WriteSavedPlan(path, plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan, err = ReadSavedPlan(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grid := []struct {
+		name        string
+		mutate      func(cluster *kops.Cluster, instanceGroups *[]*kops.InstanceGroup, keystore *fakePlanKeystore)
+		options     *SavedPlanOptions
+		clusterName string
+		expected    string
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name: "cluster spec changed",
+			mutate: func(cluster *kops.Cluster, instanceGroups *[]*kops.InstanceGroup, keystore *fakePlanKeystore) {
+				cluster.Spec.KubernetesVersion = "1.29.1"
+			},
+			expected: "cluster changed since the plan was made: the cluster spec changed",
+		},
+		{
+			name: "instance groups changed",
+			mutate: func(cluster *kops.Cluster, instanceGroups *[]*kops.InstanceGroup, keystore *fakePlanKeystore) {
+				(*instanceGroups)[0].Spec.MinSize = fi.PtrTo(int32(3))
+				*instanceGroups = append(*instanceGroups, &kops.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "extra"}})
+			},
+			expected: `cluster changed since the plan was made: InstanceGroup "extra" was created, InstanceGroup "nodes" changed`,
+		},
+		{
+			name: "keystore changed",
+			mutate: func(cluster *kops.Cluster, instanceGroups *[]*kops.InstanceGroup, keystore *fakePlanKeystore) {
+				distrusted := time.Unix(1700000000, 0)
+				keystore.keysets["kubernetes-ca"].Items["1"].DistrustTimestamp = &distrusted
+			},
+			expected: "cluster changed since the plan was made: the keystore changed",
+		},
+		{
+			name:     "options changed",
+			options:  &SavedPlanOptions{},
+			expected: "plan was made with different options",
+		},
+		{
+			name:        "other cluster",
+			clusterName: "other.k8s.local",
+			expected:    `plan was made for cluster "test.k8s.local", not "other.k8s.local"`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			cluster := newCluster()
+			instanceGroups := newInstanceGroups()
+			keystore := newKeystore()
+			if g.mutate != nil {
+				g.mutate(cluster, &instanceGroups, keystore)
+			}
+			current, err := BuildPlanFingerprint(cluster, instanceGroups, keystore)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			verifyOptions := options
+			if g.options != nil {
+				verifyOptions = *g.options
+			}
+			clusterName := "test.k8s.local"
+			if g.clusterName != "" {
+				clusterName = g.clusterName
+			}
+
+			err = plan.Verify(clusterName, current, verifyOptions)
+			if g.expected == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), g.expected)
+			}
+		})
+	}
+}
+
+func TestSavedPlanVerifyTasks(t *testing.T) {
+	plan := &SavedPlan{
+		Tasks: map[string]map[string]string{
+			"VPC/test.k8s.local":      {"CIDR": "172.20.0.0/16"},
+			"Subnet/a.test.k8s.local": {"CIDR": "172.20.32.0/19", "VPC": "name:test.k8s.local"},
+		},
+	}
+
+	assert.NoError(t, plan.VerifyTasks(map[string]map[string]string{
+		"VPC/test.k8s.local":      {"CIDR": "172.20.0.0/16"},
+		"Subnet/a.test.k8s.local": {"CIDR": "172.20.32.0/19", "VPC": "name:test.k8s.local"},
+	}))
+
+	err := plan.VerifyTasks(map[string]map[string]string{
+		"VPC/test.k8s.local":      {"CIDR": "10.0.0.0/16"},
+		"Subnet/b.test.k8s.local": {"CIDR": "172.20.32.0/19", "VPC": "name:test.k8s.local"},
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, `tasks changed since the plan was made: task "Subnet/a.test.k8s.local" was removed, task "Subnet/b.test.k8s.local" was added, task "VPC/test.k8s.local" changed`, err.Error())
+	}
+}
+
+func TestSavedPlanCheckChange(t *testing.T) {
+	plan := &SavedPlan{
+		Plan: &fi.Plan{
+			Changes: []fi.PlanChange{
+				{
+					Key:    "Subnet/a.test.k8s.local",
+					Type:   "Subnet",
+					Action: fi.PlanActionCreate,
+					Fields: []fi.PlanField{
+						{Name: "CIDR", After: "172.20.32.0/19"},
+						{Name: "VPC", After: "name:test.k8s.local"},
+					},
+				},
+				{
+					Key:    "SecurityGroup/nodes.test.k8s.local",
+					Type:   "SecurityGroup",
+					Action: fi.PlanActionModify,
+					Fields: []fi.PlanField{
+						{Name: "Tags", Before: "{Name: nodes}", After: "{Name: nodes, owner: kops}"},
+					},
+				},
+			},
+			Deletions: []fi.PlanDeletion{
+				{Type: "SecurityGroupRule", Item: "sg-1:ingress", Action: fi.PlanActionDelete},
+			},
+		},
+	}
+
+	grid := []struct {
+		name     string
+		change   fi.PlanChange
+		expected string
+	}{
+		{
+			name: "planned create of subnet in created vpc",
+			change: fi.PlanChange{
+				Key:    "Subnet/a.test.k8s.local",
+				Type:   "Subnet",
+				Action: fi.PlanActionCreate,
+				Fields: []fi.PlanField{
+					{Name: "CIDR", After: "172.20.32.0/19"},
+					{Name: "VPC", After: "name:test.k8s.local id:vpc-1"},
+				},
+			},
+		},
+		{
+			name: "planned modify",
+			change: fi.PlanChange{
+				Key:    "SecurityGroup/nodes.test.k8s.local",
+				Type:   "SecurityGroup",
+				Action: fi.PlanActionModify,
+				Fields: []fi.PlanField{
+					{Name: "Tags", Before: "{Name: nodes}", After: "{Name: nodes, owner: kops}"},
+				},
+			},
+		},
+		{
+			name: "unplanned change",
+			change: fi.PlanChange{
+				Key:    "VPC/test.k8s.local",
+				Type:   "VPC",
+				Action: fi.PlanActionModify,
+			},
+			expected: "refusing to modify VPC/test.k8s.local: the change is not in the plan",
+		},
+		{
+			name: "other action",
+			change: fi.PlanChange{
+				Key:    "SecurityGroup/nodes.test.k8s.local",
+				Type:   "SecurityGroup",
+				Action: fi.PlanActionCreate,
+			},
+			expected: "refusing to create SecurityGroup/nodes.test.k8s.local: the plan is to modify it",
+		},
+		{
+			name: "resource changed since the plan",
+			change: fi.PlanChange{
+				Key:    "SecurityGroup/nodes.test.k8s.local",
+				Type:   "SecurityGroup",
+				Action: fi.PlanActionModify,
+				Fields: []fi.PlanField{
+					{Name: "Tags", Before: "{Name: other}", After: "{Name: nodes, owner: kops}"},
+				},
+			},
+			expected: "refusing to modify SecurityGroup/nodes.test.k8s.local: field Tags differs from the plan",
+		},
+		{
+			name: "unplanned field",
+			change: fi.PlanChange{
+				Key:    "Subnet/a.test.k8s.local",
+				Type:   "Subnet",
+				Action: fi.PlanActionCreate,
+				Fields: []fi.PlanField{
+					{Name: "CIDR", After: "172.20.32.0/19"},
+					{Name: "VPC", After: "name:test.k8s.local id:vpc-1"},
+					{Name: "Shared", After: "true"},
+				},
+			},
+			expected: "refusing to create Subnet/a.test.k8s.local: field Shared is not changed by the plan",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			err := plan.CheckChange(&g.change)
+			if g.expected == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Equal(t, g.expected, err.Error())
+			}
+		})
+	}
+
+	assert.NoError(t, plan.CheckDeletion(&fi.PlanDeletion{Type: "SecurityGroupRule", Item: "sg-1:ingress", Action: fi.PlanActionDelete}))
+	assert.Error(t, plan.CheckDeletion(&fi.PlanDeletion{Type: "SecurityGroupRule", Item: "sg-1:egress", Action: fi.PlanActionDelete}))
+}
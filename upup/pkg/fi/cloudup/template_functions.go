@@ -57,6 +57,7 @@ import (
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/components/kopscontroller"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/nodeidentity"
 	"k8s.io/kops/pkg/resources/spotinst"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
@@ -657,6 +658,8 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		Cloud:       string(cluster.Spec.GetCloudProvider()),
 		ConfigBase:  cluster.Spec.ConfigStore.Base,
 		SecretStore: cluster.Spec.ConfigStore.Secrets,
+
+		StateStoreEncryption: cluster.Spec.StateStoreEncryption,
 	}
 
 	if featureflag.CacheNodeidentityInfo.Enabled() {
@@ -685,6 +688,7 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 			CABasePath:            pkiDir,
 			SigningCAs:            signingCAs,
 			CertNames:             certNames,
+			KeyAlgorithm:          cluster.KeyAlgorithm(),
 		}
 
 		if featureflag.Metal.Enabled() {
@@ -765,6 +769,38 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		}
 	}
 
+	if spec := cluster.Spec.NodeIdentity; spec != nil {
+		options := &nodeidentity.Options{}
+		for _, label := range spec.Labels {
+			options.Labels = append(options.Labels, nodeidentity.LabelMapping{
+				Label:     label.Label,
+				Tag:       label.Tag,
+				Attribute: string(label.Attribute),
+			})
+		}
+		for _, taint := range spec.Taints {
+			options.Taints = append(options.Taints, nodeidentity.TaintMapping{
+				Tag:    taint.Tag,
+				Key:    taint.Key,
+				Value:  taint.Value,
+				Effect: corev1.TaintEffect(taint.Effect),
+			})
+		}
+		if spec.ConfigMap != nil {
+			options.ConfigMap = &nodeidentity.ConfigMapSourceOptions{
+				Namespace: "kube-system",
+				Name:      spec.ConfigMap.Name,
+			}
+		}
+		if spec.HTTP != nil {
+			options.HTTP = &nodeidentity.HTTPSourceOptions{
+				URL:     spec.HTTP.URL,
+				Timeout: spec.HTTP.Timeout,
+			}
+		}
+		config.NodeIdentity = options
+	}
+
 	// To avoid indentation problems, we marshal as json.  json is a subset of yaml
 	b, err := json.Marshal(config)
 	if err != nil {
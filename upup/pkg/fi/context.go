@@ -23,6 +23,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
@@ -38,8 +39,18 @@ type Context[T SubContext] struct {
 	tasks    map[string]Task[T]
 	warnings []*Warning[T]
 
+	// changedMutex guards changed
+	changedMutex sync.Mutex
+	// changed records the tasks which were rendered, because they differed from the actual state
+	changed map[Task[T]]bool
+	// taskResults records the outcome of the tasks run by RunTasks
+	taskResults []TaskResult
+
 	deletionProcessingMode DeletionProcessingMode
 
+	// changeCheckers check the changes before they are made
+	changeCheckers []ChangeChecker
+
 	T T
 }
 
@@ -132,6 +143,68 @@ func (c *Context[T]) RunTasks(options RunTasksOptions) error {
 	return e.RunTasks(c.ctx, c.tasks)
 }
 
+// TaskResults returns the outcome of each task run by RunTasks, ordered by the time the task was first run.
+// Tasks which never ran are listed last.
+func (c *Context[T]) TaskResults() []TaskResult {
+	return c.taskResults
+}
+
+// AddChangeChecker adds a check of each change before it is made.
+// A change refused by a check fails the run; the checks do not apply to the DryRunTarget.
+func (c *Context[T]) AddChangeChecker(checker ChangeChecker) {
+	c.changeCheckers = append(c.changeCheckers, checker)
+}
+
+// checkChange returns an error if any ChangeChecker refuses the change that rendering the task makes.
+func (c *Context[T]) checkChange(a, e, changes Task[T]) error {
+	if len(c.changeCheckers) == 0 {
+		return nil
+	}
+	change, err := buildPlanChange(c.tasks, a, reflect.ValueOf(a).IsNil(), e, changes)
+	if err != nil {
+		return err
+	}
+	for _, checker := range c.changeCheckers {
+		if err := checker.CheckChange(change); err != nil {
+			return NewPermanentError(err)
+		}
+	}
+	return nil
+}
+
+// checkDeletion returns an error if any ChangeChecker refuses the deletion.
+func (c *Context[T]) checkDeletion(deletion Deletion[T]) error {
+	for _, checker := range c.changeCheckers {
+		if err := checker.CheckDeletion(buildPlanDeletion(deletion)); err != nil {
+			return NewPermanentError(err)
+		}
+	}
+	return nil
+}
+
+// recordChanged records that a task was rendered.
+func (c *Context[T]) recordChanged(task Task[T]) {
+	if !reflect.TypeOf(task).Comparable() {
+		return
+	}
+	c.changedMutex.Lock()
+	defer c.changedMutex.Unlock()
+	if c.changed == nil {
+		c.changed = make(map[Task[T]]bool)
+	}
+	c.changed[task] = true
+}
+
+// hasChanged returns true if the task was rendered.
+func (c *Context[T]) hasChanged(task Task[T]) bool {
+	if !reflect.TypeOf(task).Comparable() {
+		return false
+	}
+	c.changedMutex.Lock()
+	defer c.changedMutex.Unlock()
+	return c.changed[task]
+}
+
 // Render dispatches the creation of an object to the appropriate handler defined on the Task,
 // it is typically called after we have checked the existing state of the Task and determined that is different
 // from the desired state.
@@ -188,9 +261,15 @@ func (c *Context[T]) Render(a, e, changes Task[T]) error {
 	}
 
 	if _, ok := c.Target.(*DryRunTarget[T]); ok {
+		c.recordChanged(e)
 		return c.Target.(*DryRunTarget[T]).Render(a, e, changes)
 	}
 
+	if err := c.checkChange(a, e, changes); err != nil {
+		return err
+	}
+	c.recordChanged(e)
+
 	v := reflect.ValueOf(e)
 	vType := v.Type()
 
@@ -309,3 +388,20 @@ func (e *TryAgainLaterError) Error() string {
 }
 
 func (e *TryAgainLaterError) Unwrap() error { return e.inner }
+
+// PermanentError is returned by a task which failed and must not be run again; it stops the execution of all tasks.
+type PermanentError struct {
+	inner error
+}
+
+// NewPermanentError is a builder for PermanentError.
+func NewPermanentError(err error) *PermanentError {
+	return &PermanentError{
+		inner: err,
+	}
+}
+
+// PermanentError implementation of the error interface.
+func (e *PermanentError) Error() string { return e.inner.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.inner }
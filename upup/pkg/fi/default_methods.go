@@ -127,6 +127,9 @@ func defaultDeltaRunMethod[T SubContext](e Task[T], c *Context[T]) error {
 						klog.Fatalf("unhandled deletionProcessingMode %v", c.deletionProcessingMode)
 					}
 				}
+				if err := c.checkDeletion(deletion); err != nil {
+					return err
+				}
 				if err := deletion.Delete(c.Target); err != nil {
 					return err
 				}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"k8s.io/kops/util/pkg/reflectutils"
+	"sigs.k8s.io/yaml"
+)
+
+// PlanFormat is a machine-readable format in which a DryRunTarget can print its changes.
+type PlanFormat string
+
+const (
+	PlanFormatJSON PlanFormat = "json"
+	PlanFormatYAML PlanFormat = "yaml"
+)
+
+// PlanAction is the action a plan will take on a resource.
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionModify PlanAction = "modify"
+	PlanActionDelete PlanAction = "delete"
+)
+
+// Plan is a machine-readable form of the changes recorded by a DryRunTarget.
+type Plan struct {
+	// Changes are the resources that will be created or modified, ordered by key.
+	Changes []PlanChange `json:"changes"`
+	// Deletions are the resources that will be deleted, ordered by type and item.
+	Deletions []PlanDeletion `json:"deletions"`
+}
+
+// PlanChange is a resource that will be created or modified.
+type PlanChange struct {
+	// Key identifies the task, as <type>/<name>.
+	Key string `json:"key"`
+	// Type is the type of the task.
+	Type   string     `json:"type"`
+	Action PlanAction `json:"action"`
+	// Fields are the fields of a created resource, or the changed fields of a modified resource.
+	Fields []PlanField `json:"fields,omitempty"`
+}
+
+// PlanField is a field of a resource that will be created or modified.
+type PlanField struct {
+	Name string `json:"name"`
+	// Before is the current value; it is not set for created resources.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// PlanDeletion is a resource that will be deleted.
+type PlanDeletion struct {
+	Type   string     `json:"type"`
+	Item   string     `json:"item"`
+	Action PlanAction `json:"action"`
+	// Deferred is true if the resource is only deleted when pruning.
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// SetPlanFormat makes Finish print the changes as a plan in the given format, instead of a report.
+func (t *DryRunTarget[T]) SetPlanFormat(format PlanFormat) {
+	t.planFormat = format
+}
+
+// Plan builds the plan of the recorded changes.
+func (t *DryRunTarget[T]) Plan(taskMap map[string]Task[T]) (*Plan, error) {
+	plan := &Plan{
+		Changes:   []PlanChange{},
+		Deletions: []PlanDeletion{},
+	}
+
+	for _, r := range t.changes {
+		planChange, err := buildPlanChange(taskMap, r.a, r.aIsNil, r.e, r.changes)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, *planChange)
+	}
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Key < plan.Changes[j].Key
+	})
+
+	for _, d := range t.deletions {
+		plan.Deletions = append(plan.Deletions, *buildPlanDeletion(d))
+	}
+	sort.Slice(plan.Deletions, func(i, j int) bool {
+		if plan.Deletions[i].Type != plan.Deletions[j].Type {
+			return plan.Deletions[i].Type < plan.Deletions[j].Type
+		}
+		return plan.Deletions[i].Item < plan.Deletions[j].Item
+	})
+
+	return plan, nil
+}
+
+// buildPlanChange builds the change that rendering the task makes.
+func buildPlanChange[T SubContext](taskMap map[string]Task[T], a Task[T], aIsNil bool, e, changes Task[T]) (*PlanChange, error) {
+	taskName := getTaskName(changes)
+	planChange := &PlanChange{
+		Key:  taskName + "/" + idForTask(taskMap, e),
+		Type: taskName,
+	}
+
+	var fields []change
+	if aIsNil {
+		planChange.Action = PlanActionCreate
+		fields = buildCreateFieldList(changes)
+	} else {
+		planChange.Action = PlanActionModify
+		changeList, err := buildChangeList(a, e, changes)
+		if err != nil {
+			return nil, err
+		}
+		fields = changeList
+	}
+	for _, field := range fields {
+		planChange.Fields = append(planChange.Fields, PlanField{
+			Name:   field.FieldName,
+			Before: field.Before,
+			After:  field.After,
+		})
+	}
+	return planChange, nil
+}
+
+func buildPlanDeletion[T SubContext](deletion Deletion[T]) *PlanDeletion {
+	return &PlanDeletion{
+		Type:     deletion.TaskName(),
+		Item:     deletion.Item(),
+		Action:   PlanActionDelete,
+		Deferred: deletion.DeferDeletion(),
+	}
+}
+
+// ChangeChecker checks the changes of a run against a target other than the DryRunTarget, before they are made.
+type ChangeChecker interface {
+	// CheckChange returns an error if the change must not be made.
+	CheckChange(change *PlanChange) error
+	// CheckDeletion returns an error if the deletion must not be made.
+	CheckDeletion(deletion *PlanDeletion) error
+}
+
+// DescribeTasks returns the values of the exported fields of each task, by task key and field name.
+// Before the tasks run, this describes the desired state computed by the model.
+func DescribeTasks[T SubContext](taskMap map[string]Task[T]) map[string]map[string]string {
+	descriptions := make(map[string]map[string]string)
+	for key, task := range taskMap {
+		v := reflect.ValueOf(task)
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		fields := make(map[string]string)
+		if v.Kind() == reflect.Struct {
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).PkgPath != "" {
+					// Not exported
+					continue
+				}
+				if v.Type().Field(i).Name == "Lifecycle" {
+					// The lifecycle of some phases differs between a dry run and an apply
+					continue
+				}
+				fields[v.Type().Field(i).Name] = reflectutils.ValueAsString(v.Field(i))
+			}
+		}
+		descriptions[key] = fields
+	}
+	return descriptions
+}
+
+// PrintPlan prints the plan of the recorded changes in the given format.
+func (t *DryRunTarget[T]) PrintPlan(taskMap map[string]Task[T], format PlanFormat, out io.Writer) error {
+	plan, err := t.Plan(taskMap)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	switch format {
+	case PlanFormatJSON:
+		b, err = json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling plan to json: %w", err)
+		}
+		b = append(b, '\n')
+	case PlanFormatYAML:
+		b, err = yaml.Marshal(plan)
+		if err != nil {
+			return fmt.Errorf("error marshaling plan to yaml: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown plan format %q", format)
+	}
+
+	_, err = out.Write(b)
+	return err
+}
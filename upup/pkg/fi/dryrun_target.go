@@ -45,6 +45,9 @@ type DryRunTarget[T SubContext] struct {
 
 	// assetBuilder records all assets used
 	assetBuilder *assets.AssetBuilder
+
+	// planFormat, if set, is the format of the plan printed instead of the report on Finish()
+	planFormat PlanFormat
 }
 
 type NodeupDryRunTarget = DryRunTarget[NodeupSubContext]
@@ -161,56 +164,8 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = ValueOf(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
-					}
+				for _, change := range buildCreateFieldList(r.changes) {
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", change.FieldName, change.Description)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -306,6 +261,68 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 type change struct {
 	FieldName   string
 	Description string
+	// Before and After are the values of the field before and after the change
+	Before string
+	After  string
+}
+
+// buildCreateFieldList returns the informative fields of a task that will be created
+func buildCreateFieldList[T SubContext](task Task[T]) []change {
+	var fields []change
+
+	changes := reflect.ValueOf(task)
+	if changes.Kind() == reflect.Ptr && !changes.IsNil() {
+		changes = changes.Elem()
+	}
+
+	if changes.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < changes.NumField(); i++ {
+		field := changes.Field(i)
+
+		fieldName := changes.Type().Field(i).Name
+		if changes.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		fieldValue := reflectutils.ValueAsString(field)
+
+		shouldPrint := true
+		if fieldName == "Name" {
+			// The field name is already printed above, no need to repeat it.
+			shouldPrint = false
+		}
+		if fieldName == "Lifecycle" {
+			// Lifecycle is a "system" field; no need to show it
+			shouldPrint = false
+		}
+		if fieldValue == "<nil>" || fieldValue == "<resource>" {
+			// Uninformative
+			shouldPrint = false
+		}
+		if fieldValue == "id:<nil>" {
+			// Uninformative, but we can often print the name instead
+			name := ""
+			if field.CanInterface() {
+				hasName, ok := field.Interface().(HasName)
+				if ok {
+					name = ValueOf(hasName.GetName())
+				}
+			}
+			if name != "" {
+				fieldValue = "name:" + name
+			} else {
+				shouldPrint = false
+			}
+		}
+		if shouldPrint {
+			fields = append(fields, change{FieldName: fieldName, Description: fieldValue, After: fieldValue})
+		}
+	}
+	return fields
 }
 
 func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
@@ -353,6 +370,8 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 			}
 
 			description := ""
+			before := ""
+			after := ""
 			ignored := false
 			if fieldValE.CanInterface() {
 
@@ -364,17 +383,21 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 					resE, okE := tryResourceAsString(fieldValE)
 					if okA && okE {
 						description = diff.FormatDiff(resA, resE)
+						before = resA
+						after = resE
 					}
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					before = reflectutils.ValueAsString(fieldValA)
+					after = reflectutils.ValueAsString(fieldValE)
+					description = fmt.Sprintf(" %v -> %v", before, after)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description, Before: before, After: after})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
@@ -422,6 +445,9 @@ func getTaskName[T SubContext](t Task[T]) string {
 
 // Finish is called at the end of a run, and prints a list of changes to the configured Writer
 func (t *DryRunTarget[T]) Finish(taskMap map[string]Task[T]) error {
+	if t.planFormat != "" {
+		return t.PrintPlan(taskMap, t.planFormat, t.out)
+	}
 	return t.PrintReport(taskMap, t.out)
 }
 
@@ -18,6 +18,7 @@ package fi
 
 import (
 	"bytes"
+	"io"
 	"reflect"
 	"testing"
 
@@ -91,3 +92,88 @@ func Test_DryrunTarget_PrintReport(t *testing.T) {
 	err = target.PrintReport(tasks, &out)
 	assert.NoError(t, err, "target.PrintReport()")
 }
+
+type testDeletion struct {
+	item     string
+	deferred bool
+}
+
+var _ CloudupDeletion = &testDeletion{}
+
+func (d *testDeletion) Delete(_ CloudupTarget) error {
+	panic("not implemented")
+}
+
+func (d *testDeletion) TaskName() string {
+	return "testTask"
+}
+
+func (d *testDeletion) Item() string {
+	return d.item
+}
+
+func (d *testDeletion) DeferDeletion() bool {
+	return d.deferred
+}
+
+func Test_DryrunTarget_PrintPlan(t *testing.T) {
+	builder := assets.NewAssetBuilder(vfs.Context, nil, "1.17.3", false)
+	target := newDryRunTarget[CloudupSubContext](builder, io.Discard)
+	tasks := map[string]CloudupTask{}
+
+	created := &testTask{
+		Name:      PtrTo("created"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "value"},
+	}
+	tasks["testTask/created"] = created
+	var none *testTask
+	assert.NoError(t, target.Render(none, created, created), "target.Render()")
+
+	a := &testTask{
+		Name:      PtrTo("modified"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "old"},
+	}
+	e := &testTask{
+		Name:      PtrTo("modified"),
+		Lifecycle: LifecycleSync,
+		Tags:      map[string]string{"key": "new"},
+	}
+	tasks["testTask/modified"] = e
+	changes := reflect.New(reflect.TypeOf(e).Elem()).Interface().(CloudupTask)
+	_ = BuildChanges(a, e, changes)
+	assert.NoError(t, target.Render(a, e, changes), "target.Render()")
+
+	assert.NoError(t, target.RecordDeletion(&testDeletion{item: "pruned", deferred: true}), "target.RecordDeletion()")
+	assert.NoError(t, target.RecordDeletion(&testDeletion{item: "deleted"}), "target.RecordDeletion()")
+
+	var out bytes.Buffer
+	err := target.PrintPlan(tasks, PlanFormatYAML, &out)
+	assert.NoError(t, err, "target.PrintPlan()")
+
+	expected := `changes:
+- action: create
+  fields:
+  - after: '{key: value}'
+    name: Tags
+  key: testTask/created
+  type: testTask
+- action: modify
+  fields:
+  - after: '{key: new}'
+    before: '{key: old}'
+    name: Tags
+  key: testTask/modified
+  type: testTask
+deletions:
+- action: delete
+  item: deleted
+  type: testTask
+- action: delete
+  deferred: true
+  item: pruned
+  type: testTask
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -20,10 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"k8s.io/klog/v2"
 )
 
@@ -40,6 +43,31 @@ type taskState[T SubContext] struct {
 	deadline     time.Time
 	lastError    error
 	dependencies []*taskState[T]
+
+	// started is when the task was first run
+	started time.Time
+	// duration is the time spent running the task, over all attempts
+	duration time.Duration
+	// attempts is the number of times the task was run
+	attempts int
+}
+
+// TaskResult is the outcome of running a task.
+type TaskResult struct {
+	// Key is the key of the task.
+	Key string
+	// Started is when the task was first run; it is zero if the task never ran.
+	Started time.Time
+	// Duration is the time spent running the task, over all attempts.
+	Duration time.Duration
+	// Attempts is the number of times the task was run.
+	Attempts int
+	// Done is true if the task succeeded.
+	Done bool
+	// Changed is true if the task found differences from the actual state, and rendered them.
+	Changed bool
+	// LastError is the error returned by the last attempt, if it did not succeed.
+	LastError error
 }
 
 type RunTasksOptions struct {
@@ -74,6 +102,7 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 		}
 		taskStates[k] = ts
 	}
+	defer e.recordTaskResults(taskStates)
 
 	for k, ts := range taskStates {
 		for _, dep := range dependencies[k] {
@@ -134,6 +163,12 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 					continue
 				}
 
+				var permanentError *PermanentError
+				if errors.As(err, &permanentError) {
+					ts.lastError = err
+					return fmt.Errorf("error running task %q: %w", ts.key, err)
+				}
+
 				remaining := time.Second * time.Duration(int(time.Until(ts.deadline).Seconds()))
 				if _, ok := err.(*TryAgainLaterError); ok {
 					klog.V(2).Infof("Task %q not ready: %v", ts.key, err)
@@ -207,6 +242,25 @@ func (e *executor[T]) forkJoin(ctx context.Context, tasks []*taskState[T]) []err
 			_, span := tracer.Start(ctx, "task-"+ts.key)
 			defer span.End()
 
+			start := time.Now()
+			if ts.started.IsZero() {
+				ts.started = start
+			}
+			ts.attempts++
+			span.SetAttributes(attribute.String("kops.task.key", ts.key), attribute.Int("kops.task.attempt", ts.attempts))
+			defer func() {
+				ts.duration += time.Since(start)
+
+				resultsMutex.Lock()
+				err := results[index]
+				resultsMutex.Unlock()
+				span.SetAttributes(attribute.Bool("kops.task.changed", e.context.hasChanged(ts.task)))
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+			}()
+
 			resultsMutex.Lock()
 			results[index] = fmt.Errorf("function panic")
 			resultsMutex.Unlock()
@@ -232,3 +286,30 @@ func (e *executor[T]) forkJoin(ctx context.Context, tasks []*taskState[T]) []err
 
 	return results
 }
+
+// recordTaskResults records the outcome of the tasks on the context.
+func (e *executor[T]) recordTaskResults(taskStates map[string]*taskState[T]) {
+	var results []TaskResult
+	for _, ts := range taskStates {
+		results = append(results, TaskResult{
+			Key:       ts.key,
+			Started:   ts.started,
+			Duration:  ts.duration,
+			Attempts:  ts.attempts,
+			Done:      ts.done,
+			Changed:   e.context.hasChanged(ts.task),
+			LastError: ts.lastError,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		// Tasks which never ran are listed last
+		if results[i].Started.IsZero() != results[j].Started.IsZero() {
+			return !results[i].Started.IsZero()
+		}
+		if !results[i].Started.Equal(results[j].Started) {
+			return results[i].Started.Before(results[j].Started)
+		}
+		return results[i].Key < results[j].Key
+	})
+	e.context.taskResults = results
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// changingTask is a task which never exists, so it is always rendered.
+type changingTask struct {
+	Name      *string
+	Lifecycle Lifecycle
+}
+
+var _ InstallTask = &changingTask{}
+
+func (_ *changingTask) Find(_ *InstallContext) (*changingTask, error) {
+	return nil, nil
+}
+
+func (_ *changingTask) CheckChanges(a, e, changes *changingTask) error {
+	return nil
+}
+
+func (e *changingTask) Run(c *InstallContext) error {
+	return InstallDefaultDeltaRunMethod(e, c)
+}
+
+// retryingTask is a task which fails until it has been run a number of times.
+type retryingTask struct {
+	Failures int
+
+	runs int
+}
+
+var _ InstallTask = &retryingTask{}
+
+func (e *retryingTask) Run(_ *InstallContext) error {
+	e.runs++
+	if e.runs <= e.Failures {
+		return NewTryAgainLaterError("not ready")
+	}
+	return nil
+}
+
+// failingTask is a task which fails permanently.
+type failingTask struct {
+	runs int
+}
+
+var _ InstallTask = &failingTask{}
+
+func (e *failingTask) Run(_ *InstallContext) error {
+	e.runs++
+	return NewPermanentError(errors.New("failed"))
+}
+
+func TestRunTasksStopsOnPermanentError(t *testing.T) {
+	ctx := context.Background()
+	builder := assets.NewAssetBuilder(vfs.Context, nil, "1.29.0", false)
+	target := newDryRunTarget[InstallSubContext](builder, io.Discard)
+
+	failing := &failingTask{}
+	tasks := map[string]InstallTask{
+		"failing": failing,
+	}
+	c, err := NewInstallContext(ctx, target, tasks)
+	if err != nil {
+		t.Fatalf("building context: %v", err)
+	}
+
+	options := RunTasksOptions{
+		MaxTaskDuration:         time.Hour,
+		WaitAfterAllTasksFailed: time.Millisecond,
+	}
+	err = c.RunTasks(options)
+	var permanentError *PermanentError
+	if !errors.As(err, &permanentError) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	assert.Equal(t, 1, failing.runs, "runs")
+}
+
+func TestRunTasksRecordsResults(t *testing.T) {
+	ctx := context.Background()
+	builder := assets.NewAssetBuilder(vfs.Context, nil, "1.29.0", false)
+	target := newDryRunTarget[InstallSubContext](builder, io.Discard)
+
+	tasks := map[string]InstallTask{
+		"changing": &changingTask{Name: PtrTo("changing"), Lifecycle: LifecycleSync},
+		"retrying": &retryingTask{Failures: 2},
+	}
+	c, err := NewInstallContext(ctx, target, tasks)
+	if err != nil {
+		t.Fatalf("building context: %v", err)
+	}
+
+	options := RunTasksOptions{
+		MaxTaskDuration:         time.Minute,
+		WaitAfterAllTasksFailed: time.Millisecond,
+	}
+	if err := c.RunTasks(options); err != nil {
+		t.Fatalf("running tasks: %v", err)
+	}
+
+	results := c.TaskResults()
+	if assert.Len(t, results, 2) {
+		byKey := make(map[string]TaskResult)
+		for _, result := range results {
+			assert.False(t, result.Started.IsZero(), "%s started", result.Key)
+			assert.True(t, result.Done, "%s done", result.Key)
+			assert.NoError(t, result.LastError, "%s last error", result.Key)
+			byKey[result.Key] = result
+		}
+
+		assert.Equal(t, 1, byKey["changing"].Attempts, "changing attempts")
+		assert.True(t, byKey["changing"].Changed, "changing changed")
+		assert.Equal(t, 3, byKey["retrying"].Attempts, "retrying attempts")
+		assert.False(t, byKey["retrying"].Changed, "retrying changed")
+	}
+}
+
+// renderingTarget is a target which records the tasks rendered to it.
+type renderingTarget struct {
+	mutex    sync.Mutex
+	rendered []string
+}
+
+var _ InstallTarget = &renderingTarget{}
+
+func (t *renderingTarget) Finish(taskMap map[string]InstallTask) error {
+	return nil
+}
+
+func (t *renderingTarget) DefaultCheckExisting() bool {
+	return true
+}
+
+func (_ *changingTask) Render(t *renderingTarget, a, e, changes *changingTask) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rendered = append(t.rendered, *e.Name)
+	return nil
+}
+
+// refusingChecker is a ChangeChecker which refuses the changes of the given keys.
+type refusingChecker struct {
+	refused string
+
+	mutex   sync.Mutex
+	checked []string
+}
+
+func (c *refusingChecker) CheckChange(change *PlanChange) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checked = append(c.checked, change.Key)
+	if change.Key == c.refused {
+		return errors.New("refused")
+	}
+	return nil
+}
+
+func (c *refusingChecker) CheckDeletion(deletion *PlanDeletion) error {
+	return nil
+}
+
+func TestRunTasksChecksChanges(t *testing.T) {
+	ctx := context.Background()
+	target := &renderingTarget{}
+
+	allowed := &changingTask{Name: PtrTo("allowed"), Lifecycle: LifecycleSync}
+	refused := &changingTask{Name: PtrTo("refused"), Lifecycle: LifecycleSync}
+	tasks := map[string]InstallTask{
+		"changingTask/allowed": allowed,
+		"changingTask/refused": refused,
+	}
+	c, err := NewInstallContext(ctx, target, tasks)
+	if err != nil {
+		t.Fatalf("building context: %v", err)
+	}
+	checker := &refusingChecker{refused: "changingTask/refused"}
+	c.AddChangeChecker(checker)
+
+	options := RunTasksOptions{
+		MaxTaskDuration:         time.Minute,
+		WaitAfterAllTasksFailed: time.Millisecond,
+	}
+	err = c.RunTasks(options)
+	var permanentError *PermanentError
+	if !errors.As(err, &permanentError) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	assert.ElementsMatch(t, []string{"changingTask/allowed", "changingTask/refused"}, checker.checked, "checked")
+	assert.Equal(t, []string{"allowed"}, target.rendered, "rendered")
+}
@@ -46,6 +46,8 @@ type Keypair struct {
 	Type string `json:"type"`
 	// LegacyFormat is whether the keypair is stored in a legacy format.
 	LegacyFormat bool `json:"oldFormat"`
+	// KeyAlgorithm is the algorithm of the private key, if set. Existing CA keypairs are not reissued when it changes.
+	KeyAlgorithm pki.KeyAlgorithm `json:"keyAlgorithm,omitempty"`
 
 	certificates *fi.CloudupTaskDependentResource
 	keyset       *fi.Keyset
@@ -107,6 +109,14 @@ func (e *Keypair) Find(c *fi.CloudupContext) (*Keypair, error) {
 
 	actual.Signer = &Keypair{Subject: pki.PkixNameToString(&cert.Certificate.Issuer)}
 
+	// Only compare the key algorithm if one is expected, so that existing keys are kept by default
+	if e.KeyAlgorithm != "" {
+		actual.KeyAlgorithm, err = pki.KeyAlgorithmOf(keyset.Primary.PrivateKey.Key.Public())
+		if err != nil {
+			return nil, fmt.Errorf("keypair %q: %w", name, err)
+		}
+	}
+
 	// Avoid spurious changes
 	actual.Lifecycle = e.Lifecycle
 
@@ -151,9 +161,11 @@ func (_ *Keypair) CheckChanges(a, e, changes *Keypair) error {
 }
 
 func (_ *Keypair) ShouldCreate(a, e, changes *Keypair) (bool, error) {
-	// Don't reissue a CA just because the Subject or AlternateNames changed
+	// Don't reissue a CA just because the Subject, AlternateNames or KeyAlgorithm changed;
+	// CAs are changed to a new key algorithm by rotating them.
 	if a != nil && e.Type == "ca" && changes.Type == "" && !a.LegacyFormat {
 		e.Subject = a.Subject
+		e.KeyAlgorithm = a.KeyAlgorithm
 		return false, nil
 	}
 
@@ -181,6 +193,9 @@ func (_ *Keypair) Render(c *fi.CloudupContext, a, e, changes *Keypair) error {
 		} else if changes.Subject != "" && e.Type != "ca" {
 			createCertificate = true
 			klog.V(8).Infof("creating certificate new Subject")
+		} else if changes.KeyAlgorithm != "" && e.Type != "ca" {
+			createCertificate = true
+			klog.V(8).Infof("creating certificate new KeyAlgorithm")
 		} else if changes.Issuer != "" {
 			createCertificate = true
 			klog.V(8).Infof("creating certificate new Issuer")
@@ -214,6 +229,11 @@ func (_ *Keypair) Render(c *fi.CloudupContext, a, e, changes *Keypair) error {
 		if keyset.Primary != nil {
 			privateKey = keyset.Primary.PrivateKey
 		}
+		if privateKey != nil && e.KeyAlgorithm != "" {
+			if algorithm, err := pki.KeyAlgorithmOf(privateKey.Key.Public()); err != nil || algorithm != e.KeyAlgorithm {
+				privateKey = nil
+			}
+		}
 		if privateKey == nil {
 			klog.V(2).Infof("Creating privateKey %q", name)
 		}
@@ -242,6 +262,7 @@ func (_ *Keypair) Render(c *fi.CloudupContext, a, e, changes *Keypair) error {
 			Subject:        *subjectPkix,
 			AlternateNames: e.AlternateNames,
 			PrivateKey:     privateKey,
+			KeyAlgorithm:   e.KeyAlgorithm,
 			Serial:         serial,
 		}
 		cert, privateKey, _, err := pki.IssueCert(ctx, &req, fi.NewPKIKeystoreAdapter(c.T.Keystore))
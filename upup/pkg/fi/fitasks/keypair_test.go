@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
@@ -46,3 +47,20 @@ func TestKeypairDeps(t *testing.T) {
 		t.Errorf("unexpected dependencies for cert: %v", deps["cert"])
 	}
 }
+
+func TestKeypairShouldCreate(t *testing.T) {
+	actual := &Keypair{Name: fi.PtrTo("ca"), Subject: "cn=ca", Type: "ca", KeyAlgorithm: pki.KeyAlgorithmRSA}
+	expected := &Keypair{Name: fi.PtrTo("ca"), Subject: "cn=ca", Type: "ca", KeyAlgorithm: pki.KeyAlgorithmECDSAP256}
+	changes := &Keypair{KeyAlgorithm: pki.KeyAlgorithmECDSAP256}
+
+	create, err := (&Keypair{}).ShouldCreate(actual, expected, changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if create {
+		t.Errorf("CA keypair reissued because its key algorithm changed")
+	}
+	if expected.KeyAlgorithm != pki.KeyAlgorithmRSA {
+		t.Errorf("unexpected key algorithm %q", expected.KeyAlgorithm)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -37,8 +38,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/multierr"
 	"k8s.io/klog/v2"
+	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/model"
 	"k8s.io/kops/nodeup/pkg/model/networking"
 	api "k8s.io/kops/pkg/apis/kops"
@@ -75,11 +79,25 @@ type NodeUpCommand struct {
 	CacheDir       string
 	ConfigLocation string
 	Target         string
+	// ReportFormat, if set with the dryrun target, prints a drift report in this format instead of the dry run changes,
+	// and records the drift in a condition on the Node.
+	ReportFormat string
+	// Reconfigure runs nodeup only if a rolling update requested that the node is reconfigured in place,
+	// applying the nodeup configuration with the hash requested on the Node.
+	Reconfigure bool
+	// RunLogPath, if set, is where a structured record of the run is written when running with the direct target.
+	RunLogPath string
 }
 
 // Run is responsible for perform the nodeup process
 func (c *NodeUpCommand) Run(out io.Writer) error {
-	ctx := context.Background()
+	ctx, span := tracer.Start(context.Background(), "NodeUpCommand::Run")
+	defer span.End()
+
+	runRecord := &RunRecord{
+		Version: kops.Version,
+		Started: time.Now(),
+	}
 
 	var bootConfig nodeup.BootConfig
 	if c.ConfigLocation != "" {
@@ -100,6 +118,34 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("CacheDir is required")
 	}
 
+	if c.ReportFormat != "" {
+		if c.Target != "dryrun" {
+			return fmt.Errorf("a report can only be printed with the dryrun target")
+		}
+		if c.ReportFormat != ReportFormatJSON {
+			return fmt.Errorf("unsupported report format %q", c.ReportFormat)
+		}
+	}
+
+	var reconfigure *reconfigureRequest
+	if c.Reconfigure {
+		if c.Target != "direct" {
+			return fmt.Errorf("a node can only be reconfigured with the direct target")
+		}
+		var err error
+		reconfigure, err = getReconfigureRequest(ctx, bootConfig.CloudProvider)
+		if err != nil {
+			return err
+		}
+		if reconfigure == nil {
+			klog.V(2).Infof("no reconfiguration requested for this node")
+			return nil
+		}
+		klog.Infof("reconfiguring node %q with nodeup config %s", reconfigure.nodeName, reconfigure.hash)
+		// The rolling update read the hash from the nodeup config it expects us to apply
+		bootConfig.NodeupConfigHash = reconfigure.hash
+	}
+
 	region, err := getRegion(ctx, &bootConfig)
 	if err != nil {
 		return err
@@ -114,11 +160,25 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	var nodeConfig *nodeup.NodeConfig
 
 	if bootConfig.ConfigServer != nil && len(bootConfig.ConfigServer.Servers) > 0 {
-		response, err := getNodeConfigFromServers(ctx, &bootConfig, region)
-		if err != nil {
-			return fmt.Errorf("failed to get node config from server: %w", err)
+		if c.ReportFormat != "" {
+			// kops-controller only answers the first request of each node, so we report drift
+			// from the configuration the node was last configured with.
+			nodeConfig, err = readCachedNodeConfig(c.CacheDir)
+			if err != nil {
+				return err
+			}
+		} else {
+			response, err := getNodeConfigFromServers(ctx, &bootConfig, region)
+			if err != nil {
+				return fmt.Errorf("failed to get node config from server: %w", err)
+			}
+			nodeConfig = response.NodeConfig
+			if c.Target == "direct" {
+				if err := writeCachedNodeConfig(c.CacheDir, nodeConfig); err != nil {
+					klog.Warningf("unable to cache node config: %v", err)
+				}
+			}
 		}
-		nodeConfig = response.NodeConfig
 	} else if fi.ValueOf(bootConfig.ConfigBase) != "" {
 		var err error
 		configBase, err = vfs.Context.BuildVfsPath(*bootConfig.ConfigBase)
@@ -155,10 +215,27 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	if want := bootConfig.NodeupConfigHash; want != "" {
 		if got := base64.StdEncoding.EncodeToString(nodeupConfigHash[:]); got != want {
-			return fmt.Errorf("nodeup config hash mismatch (was %q, expected %q)", got, want)
+			if c.ReportFormat == "" {
+				return fmt.Errorf("nodeup config hash mismatch (was %q, expected %q)", got, want)
+			}
+			// The configuration was updated since the node was created; the report shows what a rolling update would change
+			klog.Warningf("nodeup config hash mismatch (was %q, expected %q); reporting drift from the updated configuration", got, want)
+		}
+	}
+
+	if reconfigure != nil {
+		if err := reconfigure.updateBootConfig(c.ConfigLocation); err != nil {
+			return err
 		}
 	}
 
+	runRecord.NodeupConfigHash = base64.StdEncoding.EncodeToString(nodeupConfigHash[:])
+	span.SetAttributes(
+		attribute.String("kops.nodeup.target", c.Target),
+		attribute.String("kops.nodeup.instance_group", bootConfig.InstanceGroupName),
+		attribute.String("kops.nodeup.config_hash", runRecord.NodeupConfigHash),
+	)
+
 	err = evaluateSpec(&nodeupConfig, bootConfig.CloudProvider)
 	if err != nil {
 		return err
@@ -214,7 +291,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building secret store path: %v", err)
 		}
 
-		secretStore = secrets.NewVFSSecretStoreReader(p)
+		secretStore = secrets.NewVFSSecretStoreReader(p, nodeupConfig.StateStoreEncryption)
 		modelContext.SecretStore = secretStore
 	} else {
 		return fmt.Errorf("SecretStore not set")
@@ -229,7 +306,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			return fmt.Errorf("error building key store path: %v", err)
 		}
 
-		modelContext.KeyStore = fi.NewVFSKeystoreReader(p)
+		modelContext.KeyStore = fi.NewVFSKeystoreReader(p, nodeupConfig.StateStoreEncryption)
 		keyStore = modelContext.KeyStore
 	} else {
 		return fmt.Errorf("KeyStore not set")
@@ -322,6 +399,14 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &networking.KuberouterBuilder{NodeupModelContext: modelContext})
 
 	loader.Builders = append(loader.Builders, &model.BootstrapClientBuilder{NodeupModelContext: modelContext})
+	{
+		nodeupCommand, err := c.nodeupCommand()
+		if err != nil {
+			return err
+		}
+		loader.Builders = append(loader.Builders, &model.DriftCheckBuilder{NodeupModelContext: modelContext, NodeupCommand: nodeupCommand})
+		loader.Builders = append(loader.Builders, &model.ReconfigureBuilder{NodeupModelContext: modelContext, NodeupCommand: nodeupCommand})
+	}
 	taskMap, err := loader.Build()
 	if err != nil {
 		return fmt.Errorf("error building loader: %v", err)
@@ -336,6 +421,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	// Protokube load image task is in ProtokubeBuilder
 
 	var target fi.NodeupTarget
+	var dryRunTarget *fi.NodeupDryRunTarget
 
 	switch c.Target {
 	case "direct":
@@ -345,7 +431,13 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		}
 	case "dryrun":
 		assetBuilder := assets.NewAssetBuilder(vfs.Context, nil, nodeupConfig.KubernetesVersion, false)
-		target = fi.NewNodeupDryRunTarget(assetBuilder, out)
+		if c.ReportFormat != "" {
+			// We print only the report
+			dryRunTarget = fi.NewNodeupDryRunTarget(assetBuilder, io.Discard)
+		} else {
+			dryRunTarget = fi.NewNodeupDryRunTarget(assetBuilder, out)
+		}
+		target = dryRunTarget
 	default:
 		return fmt.Errorf("unsupported target type %q", c.Target)
 	}
@@ -359,7 +451,17 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	options.InitDefaults()
 
 	err = context.RunTasks(options)
+	if c.Target == "direct" && c.RunLogPath != "" {
+		buildRunRecord(runRecord, context.TaskResults(), time.Now(), err)
+		if err := writeRunLog(c.RunLogPath, runRecord); err != nil {
+			klog.Warningf("unable to write run log: %v", err)
+		}
+	}
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		flushTraces(ctx)
 		klog.Exitf("error running tasks: %v", err)
 	}
 
@@ -368,6 +470,18 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Exitf("error closing target: %v", err)
 	}
 
+	if reconfigure != nil {
+		return reconfigure.complete(ctx)
+	}
+
+	if c.ReportFormat != "" {
+		nodeName, err := modelContext.NodeName()
+		if err != nil {
+			return err
+		}
+		return reportDrift(ctx, out, dryRunTarget, taskMap, nodeName, modelContext.KubeletKubeConfig())
+	}
+
 	if nodeupConfig.EnableLifecycleHook {
 		if bootConfig.CloudProvider == api.CloudProviderAWS {
 			err := completeWarmingLifecycleAction(ctx, cloud.(awsup.AWSCloud), modelContext)
@@ -379,6 +493,22 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	return nil
 }
 
+// nodeupCommand returns the command to run this nodeup binary with the same configuration.
+func (c *NodeUpCommand) nodeupCommand() ([]string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error finding nodeup executable: %w", err)
+	}
+	configLocation := c.ConfigLocation
+	if !strings.Contains(configLocation, "://") {
+		configLocation, err = filepath.Abs(configLocation)
+		if err != nil {
+			return nil, fmt.Errorf("error finding absolute path of %q: %w", c.ConfigLocation, err)
+		}
+	}
+	return []string{executable, "--conf=" + configLocation}, nil
+}
+
 func getMachineType() (string, error) {
 	config := aws.NewConfig()
 	config = config.WithCredentialsChainVerboseErrors(true)
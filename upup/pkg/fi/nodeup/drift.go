@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/drift"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// ReportFormatJSON prints the drift report of a dry run as JSON.
+	ReportFormatJSON = "json"
+
+	// NodeConditionConfigurationDrift is the condition set on the Node by a dry run with a report,
+	// which is true if the host differs from the configuration nodeup would apply.
+	NodeConditionConfigurationDrift corev1.NodeConditionType = "KopsConfigurationDrift"
+
+	// maxConditionDrifts is the number of differences listed in the message of the Node condition.
+	maxConditionDrifts = 10
+
+	// nodeConfigCacheFile is the file in the cache directory where a direct run keeps the node configuration
+	// it got from kops-controller, for dry runs with a report.
+	nodeConfigCacheFile = "node-config.json"
+
+	// redactedValue replaces the contents of files in drift reports, which may be private keys or other secrets.
+	redactedValue = "<redacted>"
+)
+
+// NodeDriftReport is the report printed by a dry run of nodeup with a report format.
+type NodeDriftReport struct {
+	// Node is the name of the Node.
+	Node string `json:"node"`
+	// Drift are the differences between the host and the tasks nodeup would run.
+	Drift []drift.Drift `json:"drift"`
+}
+
+// reportDrift prints the differences recorded by the dry run, and records them in a condition on the Node.
+func reportDrift(ctx context.Context, out io.Writer, target *fi.NodeupDryRunTarget, taskMap map[string]fi.NodeupTask, nodeName string, kubeconfig string) error {
+	plan, err := target.Plan(taskMap)
+	if err != nil {
+		return err
+	}
+
+	report := &NodeDriftReport{
+		Node:  nodeName,
+		Drift: redactDrift(drift.FromPlan(plan)),
+	}
+	if report.Drift == nil {
+		report.Drift = []drift.Drift{}
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling drift report to json: %w", err)
+	}
+	b = append(b, '\n')
+	if _, err := out.Write(b); err != nil {
+		return err
+	}
+
+	// The report has been printed, so we only warn if the Node can't be updated,
+	// for example because the node has not yet joined the cluster.
+	k8sClient, err := buildKubeletClient(kubeconfig)
+	if err != nil {
+		klog.Warningf("unable to record drift on node: %v", err)
+		return nil
+	}
+	if err := setDriftCondition(ctx, k8sClient, report, time.Now()); err != nil {
+		klog.Warningf("unable to record drift on node: %v", err)
+	}
+	return nil
+}
+
+// redactDrift removes the contents of files from the differences,
+// because the report is printed to the journal and may be collected from there.
+func redactDrift(drifts []drift.Drift) []drift.Drift {
+	for i := range drifts {
+		for j := range drifts[i].Fields {
+			field := &drifts[i].Fields[j]
+			if field.Name != "Contents" {
+				continue
+			}
+			if field.Before != "" {
+				field.Before = redactedValue
+			}
+			if field.After != "" {
+				field.After = redactedValue
+			}
+		}
+	}
+	return drifts
+}
+
+// writeCachedNodeConfig saves the node configuration from kops-controller in the cache directory.
+// It holds the node's secrets, so it is only readable by root.
+func writeCachedNodeConfig(cacheDir string, nodeConfig *nodeup.NodeConfig) error {
+	b, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return fmt.Errorf("error marshaling node config: %w", err)
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return fmt.Errorf("error creating cache directory %q: %w", cacheDir, err)
+	}
+	p := filepath.Join(cacheDir, nodeConfigCacheFile)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("error writing node config %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return fmt.Errorf("error replacing node config %q: %w", p, err)
+	}
+	return nil
+}
+
+// readCachedNodeConfig loads the node configuration saved by the last direct run.
+func readCachedNodeConfig(cacheDir string) (*nodeup.NodeConfig, error) {
+	p := filepath.Join(cacheDir, nodeConfigCacheFile)
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("node config %q not found; the node has not yet been configured by nodeup", p)
+		}
+		return nil, fmt.Errorf("error reading node config %q: %w", p, err)
+	}
+	nodeConfig := &nodeup.NodeConfig{}
+	if err := json.Unmarshal(b, nodeConfig); err != nil {
+		return nil, fmt.Errorf("error parsing node config %q: %w", p, err)
+	}
+	return nodeConfig, nil
+}
+
+// buildKubeletClient builds a client for the API server that authenticates as the node.
+func buildKubeletClient(kubeconfig string) (kubernetes.Interface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig %q: %w", kubeconfig, err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %w", err)
+	}
+	return k8sClient, nil
+}
+
+// setDriftCondition sets the drift condition on the Node, keeping its transition time if its status is unchanged.
+func setDriftCondition(ctx context.Context, k8sClient kubernetes.Interface, report *NodeDriftReport, now time.Time) error {
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, report.Node, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %w", report.Node, err)
+	}
+
+	condition := buildDriftCondition(report, metav1.NewTime(now))
+	for _, existing := range node.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.NodeCondition{condition},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch for node %q: %w", report.Node, err)
+	}
+	if _, err := k8sClient.CoreV1().Nodes().PatchStatus(ctx, report.Node, patch); err != nil {
+		return fmt.Errorf("error patching status of node %q: %w", report.Node, err)
+	}
+	return nil
+}
+
+func buildDriftCondition(report *NodeDriftReport, now metav1.Time) corev1.NodeCondition {
+	condition := corev1.NodeCondition{
+		Type:               NodeConditionConfigurationDrift,
+		Status:             corev1.ConditionFalse,
+		Reason:             "NoDrift",
+		Message:            "node matches its kOps configuration",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	if len(report.Drift) == 0 {
+		return condition
+	}
+
+	var items []string
+	for i, d := range report.Drift {
+		if i == maxConditionDrifts {
+			items = append(items, fmt.Sprintf("and %d more", len(report.Drift)-maxConditionDrifts))
+			break
+		}
+		items = append(items, fmt.Sprintf("%s %s/%s", d.Kind, d.Type, d.Name))
+	}
+
+	condition.Status = corev1.ConditionTrue
+	condition.Reason = "ConfigurationDrift"
+	condition.Message = fmt.Sprintf("node differs from its kOps configuration: %s", strings.Join(items, ", "))
+	return condition
+}
@@ -17,11 +17,13 @@ limitations under the License.
 package nodetasks
 
 import (
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"strconv"
 
+	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/bootstrap"
 	"k8s.io/kops/pkg/kopscontrollerclient"
@@ -45,6 +47,9 @@ type BootstrapClientTask struct {
 	// ClusterName is the name of the cluster
 	ClusterName string
 
+	// KeyAlgorithm is the algorithm of the private keys to generate.
+	KeyAlgorithm string
+
 	keys map[string]*pki.PrivateKey
 }
 
@@ -83,6 +88,13 @@ func (b *BootstrapClientTask) String() string {
 func (b *BootstrapClientTask) Run(c *fi.NodeupContext) error {
 	ctx := c.Context()
 
+	if _, ok := c.Target.(*fi.NodeupDryRunTarget); ok {
+		// Requesting certificates would generate new keys, which would always differ from the installed ones,
+		// and kops-controller rejects requests from nodes which are already registered.
+		klog.V(2).Infof("not requesting certificates from kops-controller in a dry run")
+		return nil
+	}
+
 	req := nodeup.BootstrapRequest{
 		APIVersion: nodeup.BootstrapAPIVersion,
 		Certs:      map[string]string{},
@@ -115,7 +127,7 @@ func (b *BootstrapClientTask) Run(c *fi.NodeupContext) error {
 		key, ok := b.keys[name]
 		if !ok {
 			var err error
-			key, err = pki.GeneratePrivateKey()
+			key, err = pki.GeneratePrivateKeyWithAlgorithm(pki.KeyAlgorithm(b.KeyAlgorithm))
 			if err != nil {
 				return fmt.Errorf("generating private key: %v", err)
 			}
@@ -128,8 +140,13 @@ func (b *BootstrapClientTask) Run(c *fi.NodeupContext) error {
 		if err != nil {
 			return fmt.Errorf("marshalling public key: %v", err)
 		}
+		// Older kops-controllers only accept the "RSA PUBLIC KEY" type, even though the data is PKIX.
+		pemType := "PUBLIC KEY"
+		if _, isRSA := key.Key.Public().(*rsa.PublicKey); isRSA {
+			pemType = "RSA PUBLIC KEY"
+		}
 		// TODO perhaps send a CSR instead to prove we own the private key?
-		req.Certs[name] = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkData}))
+		req.Certs[name] = string(pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: pkData}))
 	}
 
 	var resp nodeup.BootstrapResponse
@@ -198,6 +198,10 @@ func (e *File) Find(_ *fi.NodeupContext) (*File, error) {
 	if e.IfNotExists {
 		actual.Contents = e.Contents
 	}
+	// Contents issued by a task which did not run, as in a dry run, can't be compared
+	if hasIsReady, ok := e.Contents.(fi.HasIsReady); ok && !hasIsReady.IsReady() {
+		actual.Contents = e.Contents
+	}
 	actual.OnChangeExecute = e.OnChangeExecute
 
 	return actual, nil
@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"syscall"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+)
+
+// maxHookScriptOutput is the amount of output of a failed hook script included in the error.
+const maxHookScriptOutput = 2048
+
+// HookScript runs a script at a phase of the node configuration, blocking services of later phases until it succeeds.
+type HookScript struct {
+	Name string `json:"name"`
+
+	// Path is the location of the script.
+	Path string `json:"path"`
+	// Interpreter is the program which runs the script.
+	Interpreter string `json:"interpreter"`
+	// Environment are the environment variables set for the script.
+	Environment map[string]string `json:"environment,omitempty"`
+
+	// BeforeServices are the services which are not started until the script succeeds.
+	BeforeServices []string `json:"beforeServices,omitempty"`
+	// AfterServices are the services which are started before the script runs.
+	AfterServices []string `json:"afterServices,omitempty"`
+	// WaitForHealthy is a URL which must return a successful response before the script runs.
+	WaitForHealthy string `json:"waitForHealthy,omitempty"`
+
+	// Timeout is the maximum duration of each run of the script.
+	Timeout metav1.Duration `json:"timeout"`
+	// Retries is the number of times the script is run again after failing.
+	Retries int `json:"retries"`
+	// SuccessExitCodes are the exit codes of the script which indicate success.
+	SuccessExitCodes []int `json:"successExitCodes"`
+	// Creates is a path which the script creates when it succeeds; the script is not run if the path exists.
+	Creates string `json:"creates,omitempty"`
+
+	// attempts is the number of times the script was run
+	attempts int
+	// lastError is the error of the last run of the script
+	lastError error
+}
+
+var _ fi.NodeupTask = &HookScript{}
+
+func (e *HookScript) String() string {
+	return fmt.Sprintf("HookScript: %s", e.Name)
+}
+
+var _ fi.HasName = &HookScript{}
+
+func (e *HookScript) GetName() *string {
+	return &e.Name
+}
+
+var _ fi.NodeupHasDependencies = &HookScript{}
+
+// GetDependencies implements HasDependencies::GetDependencies
+func (e *HookScript) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTask {
+	var deps []fi.NodeupTask
+	for _, v := range tasks {
+		switch v := v.(type) {
+		case *Package, *UpdatePackages, *UserTask, *GroupTask, *Archive, *Prefix, *UpdateEtcHostsTask, *File:
+			// The script is a file asset, and may use the files and packages installed by nodeup
+			deps = append(deps, v)
+		case *Service:
+			if slices.Contains(e.AfterServices, v.Name) {
+				deps = append(deps, v)
+			}
+		}
+	}
+	return deps
+}
+
+func (e *HookScript) Find(c *fi.NodeupContext) (*HookScript, error) {
+	if e.Creates == "" {
+		if _, ok := c.Target.(*fi.NodeupDryRunTarget); ok {
+			// The script is run on every pass of nodeup, so running it again is not drift
+			actual := *e
+			return &actual, nil
+		}
+		// We run the script every time
+		return nil, nil
+	}
+	if _, err := os.Stat(e.Creates); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error checking whether hook %q created %q: %w", e.Name, e.Creates, err)
+	}
+
+	// The script already succeeded
+	actual := *e
+	return &actual, nil
+}
+
+func (e *HookScript) Run(c *fi.NodeupContext) error {
+	return fi.NodeupDefaultDeltaRunMethod(e, c)
+}
+
+func (_ *HookScript) CheckChanges(a, e, changes *HookScript) error {
+	if e.Path == "" {
+		return fi.RequiredField("Path")
+	}
+	if e.Interpreter == "" {
+		return fi.RequiredField("Interpreter")
+	}
+	return nil
+}
+
+func (_ *HookScript) RenderLocal(t *local.LocalTarget, a, e, changes *HookScript) error {
+	if e.WaitForHealthy != "" {
+		if err := checkHealthy(e.WaitForHealthy); err != nil {
+			return fi.NewTryAgainLaterError(fmt.Sprintf("waiting for %s before running hook %q", e.WaitForHealthy, e.Name)).WithError(err)
+		}
+	}
+
+	e.attempts++
+	e.lastError = e.execute()
+	if e.lastError != nil && e.attempts > e.Retries {
+		// nodeup retries tasks for a very long time, so we stop it ourselves
+		return fi.NewPermanentError(fmt.Errorf("hook %q failed %d times; not retrying: %w", e.Name, e.attempts, e.lastError))
+	}
+	return e.lastError
+}
+
+// execute runs the script once, and checks that it succeeded.
+func (e *HookScript) execute() error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.Timeout.Duration)
+	defer cancel()
+
+	var env []string
+	for k, v := range e.Environment {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+
+	klog.Infof("running hook %q: %s %s", e.Name, e.Interpreter, e.Path)
+	cmd := exec.CommandContext(ctx, e.Interpreter, e.Path)
+	cmd.Env = append(os.Environ(), env...)
+	// Run the script in its own process group, so that we also stop the processes it started when it times out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 10 * time.Second
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("hook %q did not complete within %s: %s", e.Name, e.Timeout.Duration, outputTail(output))
+	}
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("error running hook %q: %w", e.Name, err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	if !slices.Contains(e.SuccessExitCodes, exitCode) {
+		return fmt.Errorf("hook %q exited with code %d: %s", e.Name, exitCode, outputTail(output))
+	}
+
+	if e.Creates != "" {
+		if _, err := os.Stat(e.Creates); err != nil {
+			return fmt.Errorf("hook %q did not create %q: %w", e.Name, e.Creates, err)
+		}
+	}
+
+	klog.Infof("hook %q succeeded", e.Name)
+	return nil
+}
+
+// checkHealthy returns an error unless the URL returns a successful response.
+func checkHealthy(url string) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return nil
+}
+
+// outputTail returns the end of the output of a script.
+func outputTail(output []byte) string {
+	if len(output) > maxHookScriptOutput {
+		output = output[len(output)-maxHookScriptOutput:]
+	}
+	return string(output)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetasks
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestHookScriptTask_Deps(t *testing.T) {
+	containerd := &Service{Name: "containerd.service"}
+	kubelet := &Service{Name: "kubelet.service"}
+	file := &File{Path: "/srv/kubernetes/assets/script"}
+	preKubelet := &HookScript{Name: "pre-kubelet", BeforeServices: []string{"kubelet.service"}}
+	postKubelet := &HookScript{Name: "post-kubelet", AfterServices: []string{"kubelet.service"}}
+
+	tasks := map[string]fi.NodeupTask{
+		"Service/containerd.service": containerd,
+		"Service/kubelet.service":    kubelet,
+		"File/script":                file,
+		"HookScript/pre-kubelet":     preKubelet,
+		"HookScript/post-kubelet":    postKubelet,
+	}
+
+	grid := []struct {
+		Task     fi.NodeupHasDependencies
+		Expected []fi.NodeupTask
+	}{
+		{Task: containerd, Expected: []fi.NodeupTask{file}},
+		{Task: kubelet, Expected: []fi.NodeupTask{file, preKubelet}},
+		{Task: preKubelet, Expected: []fi.NodeupTask{file}},
+		{Task: postKubelet, Expected: []fi.NodeupTask{file, kubelet}},
+	}
+	for _, g := range grid {
+		deps := g.Task.GetDependencies(tasks)
+		if !sameTasks(g.Expected, deps) {
+			t.Errorf("unexpected deps of %v.  expected=%v, actual=%v", g.Task, g.Expected, deps)
+		}
+	}
+}
+
+func sameTasks(expected, actual []fi.NodeupTask) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for _, e := range expected {
+		found := false
+		for _, a := range actual {
+			if reflect.DeepEqual(e, a) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHookScriptTask_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeScript := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o440); err != nil {
+			t.Fatalf("writing script: %v", err)
+		}
+		return p
+	}
+
+	grid := []struct {
+		Name          string
+		Script        string
+		Retries       int
+		ExitCodes     []int
+		Creates       string
+		ExpectedError string
+	}{
+		{
+			Name:   "success",
+			Script: "test \"$GREETING\" = hello",
+		},
+		{
+			Name:          "failure",
+			Script:        "echo formatting failed; exit 3",
+			ExpectedError: `hook "failure" exited with code 3: formatting failed`,
+		},
+		{
+			Name:      "other exit code",
+			Script:    "exit 3",
+			ExitCodes: []int{0, 3},
+		},
+		{
+			Name:          "timeout",
+			Script:        "sleep 10",
+			ExpectedError: `hook "timeout" did not complete within 100ms`,
+		},
+		{
+			Name:    "creates",
+			Script:  "touch " + filepath.Join(dir, "created"),
+			Creates: filepath.Join(dir, "created"),
+		},
+		{
+			Name:          "does not create",
+			Script:        "true",
+			Creates:       filepath.Join(dir, "missing"),
+			ExpectedError: `hook "does not create" did not create`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			e := &HookScript{
+				Name:             g.Name,
+				Path:             writeScript(strings.ReplaceAll(g.Name, " ", "-")+".sh", g.Script),
+				Interpreter:      "/bin/sh",
+				Environment:      map[string]string{"GREETING": "hello"},
+				Timeout:          metav1.Duration{Duration: time.Second},
+				SuccessExitCodes: []int{0},
+				Creates:          g.Creates,
+			}
+			if g.Name == "timeout" {
+				e.Timeout.Duration = 100 * time.Millisecond
+			}
+			if g.ExitCodes != nil {
+				e.SuccessExitCodes = g.ExitCodes
+			}
+
+			err := e.RenderLocal(nil, nil, e, nil)
+			if g.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+				t.Fatalf("expected error containing %q, got %v", g.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestHookScriptTask_Retries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "script.sh")
+	counter := filepath.Join(dir, "runs")
+	if err := os.WriteFile(path, []byte("echo run >> "+counter+"; exit 1"), 0o440); err != nil {
+		t.Fatalf("writing script: %v", err)
+	}
+
+	e := &HookScript{
+		Name:             "retries",
+		Path:             path,
+		Interpreter:      "/bin/sh",
+		Timeout:          metav1.Duration{Duration: time.Second},
+		Retries:          1,
+		SuccessExitCodes: []int{0},
+	}
+	var permanentError *fi.PermanentError
+	err := e.RenderLocal(nil, nil, e, nil)
+	if err == nil || errors.As(err, &permanentError) {
+		t.Fatalf("expected first run to fail and be retried, got %v", err)
+	}
+	err = e.RenderLocal(nil, nil, e, nil)
+	if !errors.As(err, &permanentError) || !strings.Contains(err.Error(), `hook "retries" failed 2 times; not retrying`) {
+		t.Fatalf("expected hook to fail permanently, got %v", err)
+	}
+
+	b, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("reading runs: %v", err)
+	}
+	if runs := strings.Count(string(b), "run"); runs != 2 {
+		t.Errorf("expected the script to run 2 times, ran %d times", runs)
+	}
+}
+
+func TestHookScriptTask_FindCreates(t *testing.T) {
+	created := filepath.Join(t.TempDir(), "created")
+	e := &HookScript{Name: "creates", Path: "/srv/kubernetes/assets/script", Interpreter: "/bin/bash", Creates: created}
+
+	actual, err := e.Find(nil)
+	if err != nil || actual != nil {
+		t.Fatalf("expected hook to run before %q exists, got %v (%v)", created, actual, err)
+	}
+
+	if err := os.WriteFile(created, nil, 0o644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+	actual, err = e.Find(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual == nil || fi.BuildChanges(actual, e, &HookScript{}) {
+		t.Errorf("expected hook not to run once %q exists, got %v", created, actual)
+	}
+}
+
+func TestHookScriptTask_FindDryRun(t *testing.T) {
+	e := &HookScript{Name: "always", Path: "/srv/kubernetes/assets/script", Interpreter: "/bin/bash"}
+
+	actual, err := e.Find(&fi.NodeupContext{})
+	if err != nil || actual != nil {
+		t.Fatalf("expected hook without creates to run, got %v (%v)", actual, err)
+	}
+
+	c := &fi.NodeupContext{Target: fi.NewNodeupDryRunTarget(assets.NewAssetBuilder(vfs.Context, nil, "1.29.0", false), io.Discard)}
+	actual, err = e.Find(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual == nil || fi.BuildChanges(actual, e, &HookScript{}) {
+		t.Errorf("expected hook without creates not to be reported as drift, got %v", actual)
+	}
+}
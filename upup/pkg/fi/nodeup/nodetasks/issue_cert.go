@@ -55,6 +55,8 @@ type IssueCert struct {
 	Type           string   `json:"type"`
 	Subject        PKIXName `json:"subject"`
 	AlternateNames []string `json:"alternateNames,omitempty"`
+	// KeyAlgorithm is the algorithm of the generated private key; the default is RSA.
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
 
 	// IncludeRootCertificate will force the certificate data to include the full chain, not just the leaf
 	IncludeRootCertificate bool `json:"includeRootCertificate,omitempty"`
@@ -127,6 +129,13 @@ func (i *IssueCert) AddFileTasks(c *fi.NodeupModelBuilderContext, dir string, na
 func (e *IssueCert) Run(c *fi.NodeupContext) error {
 	ctx := c.Context()
 
+	if _, ok := c.Target.(*fi.NodeupDryRunTarget); ok {
+		// A new certificate would have a new key, so it would always differ from the installed one.
+		// The resources are left unset, so that the files containing them are not compared.
+		klog.V(2).Infof("not signing certificate for %q in a dry run", e.Name)
+		return nil
+	}
+
 	// Skew the certificate lifetime by up to 30 days based on information about the generating node.
 	// This is so that different nodes created at the same time have the certificates they generated
 	// expire at different times, but all certificates on a given node expire around the same time.
@@ -149,6 +158,7 @@ func (e *IssueCert) Run(c *fi.NodeupContext) error {
 		Type:           e.Type,
 		Subject:        e.Subject.toPKIXName(),
 		AlternateNames: e.AlternateNames,
+		KeyAlgorithm:   pki.KeyAlgorithm(e.KeyAlgorithm),
 		Validity:       time.Hour * time.Duration(validHours),
 	}
 
@@ -73,6 +73,13 @@ func (k *KubeConfig) GetConfig() *fi.NodeupTaskDependentResource {
 }
 
 func (k *KubeConfig) Run(_ *fi.NodeupContext) error {
+	for _, r := range []fi.Resource{k.Cert, k.Key, k.CA} {
+		if hasIsReady, ok := r.(fi.HasIsReady); ok && !hasIsReady.IsReady() {
+			// The certificate was not issued, as in a dry run, so the kubeconfig can't be built either
+			return nil
+		}
+	}
+
 	cert, err := fi.ResourceAsBytes(k.Cert)
 	if err != nil {
 		return err
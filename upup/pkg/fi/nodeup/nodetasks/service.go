@@ -21,6 +21,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -96,6 +97,11 @@ func (s *Service) GetDependencies(tasks map[string]fi.NodeupTask) []fi.NodeupTas
 			if s.Name == kubeletService {
 				deps = append(deps, v)
 			}
+		case *HookScript:
+			// Hook scripts only block the services of later phases
+			if slices.Contains(v.BeforeServices, s.Name) {
+				deps = append(deps, v)
+			}
 		case *File:
 			if len(v.BeforeServices) > 0 {
 				for _, b := range v.BeforeServices {
@@ -244,7 +250,7 @@ func (e *Service) Find(_ *fi.NodeupContext) (*Service, error) {
 		actual.Enabled = fi.PtrTo(false)
 
 	// TODO: Can probably do better here!
-	case "multi-user.target", "graphical.target multi-user.target":
+	case "multi-user.target", "graphical.target multi-user.target", "timers.target":
 		actual.Enabled = fi.PtrTo(true)
 
 	default:
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/nodeup/pkg/model"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+// reconfigureRequest is a request from a rolling update to reconfigure the node in place.
+type reconfigureRequest struct {
+	k8sClient kubernetes.Interface
+	nodeName  string
+	// hash is the hash of the nodeup configuration to apply.
+	hash string
+}
+
+// getReconfigureRequest returns the reconfiguration requested on the Node, or nil if there is none pending.
+func getReconfigureRequest(ctx context.Context, cloudProvider api.CloudProviderID) (*reconfigureRequest, error) {
+	// This mirrors NodeupModelContext.NodeName, which we can't use before the nodeup configuration is loaded
+	nodeName, err := evaluateHostnameOverride(cloudProvider)
+	if err != nil {
+		return nil, err
+	}
+	if nodeName == "" {
+		nodeName, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("error determining hostname: %w", err)
+		}
+	}
+	nodeName = strings.ToLower(strings.TrimSpace(nodeName))
+
+	k8sClient, err := buildKubeletClient((&model.NodeupModelContext{}).KubeletKubeConfig())
+	if err != nil {
+		return nil, err
+	}
+	return findReconfigureRequest(ctx, k8sClient, nodeName)
+}
+
+// findReconfigureRequest returns the reconfiguration requested on the Node, if it has not yet been applied.
+func findReconfigureRequest(ctx context.Context, k8sClient kubernetes.Interface, nodeName string) (*reconfigureRequest, error) {
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting node %q: %w", nodeName, err)
+	}
+
+	requested := node.Annotations[nodeup.AnnotationReconfigureRequested]
+	if requested == "" || requested == node.Annotations[nodeup.AnnotationReconfigured] {
+		return nil, nil
+	}
+	return &reconfigureRequest{
+		k8sClient: k8sClient,
+		nodeName:  nodeName,
+		hash:      requested,
+	}, nil
+}
+
+// updateBootConfig records the hash of the requested configuration in the boot configuration of the node,
+// so that nodeup applies the same configuration when the node reboots.
+func (r *reconfigureRequest) updateBootConfig(configLocation string) error {
+	if strings.Contains(configLocation, "://") {
+		klog.Warningf("not updating remote boot configuration %q with the new nodeup config hash", configLocation)
+		return nil
+	}
+
+	stat, err := os.Stat(configLocation)
+	if err != nil {
+		return fmt.Errorf("error reading boot configuration %q: %w", configLocation, err)
+	}
+	b, err := os.ReadFile(configLocation)
+	if err != nil {
+		return fmt.Errorf("error reading boot configuration %q: %w", configLocation, err)
+	}
+	var bootConfig nodeup.BootConfig
+	if err := utils.YamlUnmarshal(b, &bootConfig); err != nil {
+		return fmt.Errorf("error parsing boot configuration %q: %w", configLocation, err)
+	}
+	if bootConfig.NodeupConfigHash == r.hash {
+		return nil
+	}
+
+	bootConfig.NodeupConfigHash = r.hash
+	b, err = utils.YamlMarshal(&bootConfig)
+	if err != nil {
+		return fmt.Errorf("error serializing boot configuration: %w", err)
+	}
+	if err := os.WriteFile(configLocation, b, stat.Mode().Perm()); err != nil {
+		return fmt.Errorf("error writing boot configuration %q: %w", configLocation, err)
+	}
+	return nil
+}
+
+// complete records on the Node that the requested configuration was applied, so that the rolling update can proceed.
+func (r *reconfigureRequest) complete(ctx context.Context) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				nodeup.AnnotationReconfigured: r.hash,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch for node %q: %w", r.nodeName, err)
+	}
+	if _, err := r.k8sClient.CoreV1().Nodes().Patch(ctx, r.nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error recording reconfiguration of node %q: %w", r.nodeName, err)
+	}
+	klog.Infof("reconfigured node %q with nodeup config %s", r.nodeName, r.hash)
+	return nil
+}
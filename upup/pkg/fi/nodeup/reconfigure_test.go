@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekubernetes "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/upup/pkg/fi/utils"
+)
+
+func TestFindReconfigureRequest(t *testing.T) {
+	ctx := context.Background()
+
+	grid := []struct {
+		Annotations map[string]string
+		Expected    string
+	}{
+		{},
+		{
+			Annotations: map[string]string{nodeup.AnnotationReconfigureRequested: "hash-2"},
+			Expected:    "hash-2",
+		},
+		{
+			Annotations: map[string]string{
+				nodeup.AnnotationReconfigureRequested: "hash-2",
+				nodeup.AnnotationReconfigured:         "hash-1",
+			},
+			Expected: "hash-2",
+		},
+		{
+			Annotations: map[string]string{
+				nodeup.AnnotationReconfigureRequested: "hash-2",
+				nodeup.AnnotationReconfigured:         "hash-2",
+			},
+		},
+	}
+	for _, g := range grid {
+		k8sClient := fakekubernetes.NewSimpleClientset(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: g.Annotations},
+		})
+		request, err := findReconfigureRequest(ctx, k8sClient, "node-1")
+		if err != nil {
+			t.Fatalf("findReconfigureRequest: %v", err)
+		}
+		hash := ""
+		if request != nil {
+			hash = request.hash
+		}
+		if hash != g.Expected {
+			t.Errorf("annotations %v: expected request for %q, got %q", g.Annotations, g.Expected, hash)
+		}
+	}
+}
+
+func TestReconfigureRequest(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fakekubernetes.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Annotations: map[string]string{nodeup.AnnotationReconfigureRequested: "hash-2"},
+		},
+	})
+	request := &reconfigureRequest{k8sClient: k8sClient, nodeName: "node-1", hash: "hash-2"}
+
+	configLocation := filepath.Join(t.TempDir(), "kube_env.yaml")
+	b, err := utils.YamlMarshal(&nodeup.BootConfig{InstanceGroupName: "nodes", NodeupConfigHash: "hash-1"})
+	if err != nil {
+		t.Fatalf("marshaling boot config: %v", err)
+	}
+	if err := os.WriteFile(configLocation, b, 0o600); err != nil {
+		t.Fatalf("writing boot config: %v", err)
+	}
+
+	if err := request.updateBootConfig(configLocation); err != nil {
+		t.Fatalf("updateBootConfig: %v", err)
+	}
+	b, err = os.ReadFile(configLocation)
+	if err != nil {
+		t.Fatalf("reading boot config: %v", err)
+	}
+	var bootConfig nodeup.BootConfig
+	if err := utils.YamlUnmarshal(b, &bootConfig); err != nil {
+		t.Fatalf("parsing boot config: %v", err)
+	}
+	if bootConfig.NodeupConfigHash != "hash-2" || bootConfig.InstanceGroupName != "nodes" {
+		t.Errorf("unexpected boot config: %+v", bootConfig)
+	}
+	if stat, err := os.Stat(configLocation); err != nil || stat.Mode().Perm() != 0o600 {
+		t.Errorf("expected boot config mode to be kept, got %v (%v)", stat.Mode(), err)
+	}
+
+	if err := request.complete(ctx); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	node, err := k8sClient.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("getting node: %v", err)
+	}
+	if node.Annotations[nodeup.AnnotationReconfigured] != "hash-2" {
+		t.Errorf("expected node to be annotated as reconfigured, got %v", node.Annotations)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// DefaultRunLogPath is where nodeup writes the record of its last run.
+const DefaultRunLogPath = "/var/log/kops-nodeup-run.json"
+
+var tracer = otel.Tracer("k8s.io/kops/upup/pkg/fi/nodeup")
+
+// RunRecord is the structured record of a nodeup run, written to the run log.
+type RunRecord struct {
+	// Version is the version of nodeup.
+	Version string `json:"version"`
+	// NodeupConfigHash is the hash of the nodeup configuration which was applied.
+	NodeupConfigHash string `json:"nodeupConfigHash,omitempty"`
+	// Started is when nodeup started.
+	Started time.Time `json:"started"`
+	// Finished is when nodeup finished running the tasks.
+	Finished time.Time `json:"finished"`
+	// DurationSeconds is the time nodeup took to configure the node.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Error is the error running the tasks, if they did not all succeed.
+	Error string `json:"error,omitempty"`
+	// Tasks are the records of the tasks, ordered by the time they were first run.
+	Tasks []TaskRecord `json:"tasks"`
+}
+
+// TaskRecord is the record of running a task.
+type TaskRecord struct {
+	// Key is the key of the task.
+	Key string `json:"key"`
+	// Started is when the task was first run, unless it never ran.
+	Started *time.Time `json:"started,omitempty"`
+	// DurationSeconds is the time spent running the task, over all attempts.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Retries is the number of times the task was run again after failing.
+	Retries int `json:"retries"`
+	// Done is true if the task succeeded.
+	Done bool `json:"done"`
+	// Changed is true if the task changed the node.
+	Changed bool `json:"changed"`
+	// LastError is the error returned by the last attempt, if the task did not succeed.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// buildRunRecord builds the record of a run from the results of its tasks.
+func buildRunRecord(record *RunRecord, results []fi.TaskResult, finished time.Time, err error) {
+	record.Finished = finished
+	record.DurationSeconds = finished.Sub(record.Started).Seconds()
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	record.Tasks = make([]TaskRecord, 0, len(results))
+	for _, result := range results {
+		task := TaskRecord{
+			Key:             result.Key,
+			DurationSeconds: result.Duration.Seconds(),
+			Done:            result.Done,
+			Changed:         result.Changed,
+		}
+		if !result.Started.IsZero() {
+			started := result.Started
+			task.Started = &started
+		}
+		if result.Attempts > 1 {
+			task.Retries = result.Attempts - 1
+		}
+		if result.LastError != nil {
+			task.LastError = result.LastError.Error()
+		}
+		record.Tasks = append(record.Tasks, task)
+	}
+}
+
+// writeRunLog replaces the run log with the record of this run.
+func writeRunLog(path string, record *RunRecord) error {
+	b, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling run record to json: %w", err)
+	}
+	b = append(b, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating directory for run log %q: %w", path, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("error writing run log %q: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error replacing run log %q: %w", path, err)
+	}
+	return nil
+}
+
+// flushTraces exports the spans recorded so far, before nodeup exits without running deferred functions.
+func flushTraces(ctx context.Context) {
+	if tp, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error }); ok {
+		if err := tp.ForceFlush(ctx); err != nil {
+			klog.Warningf("error flushing traces: %v", err)
+		}
+	}
+}
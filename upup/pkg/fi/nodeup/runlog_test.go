@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestRunLog(t *testing.T) {
+	started := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	record := &RunRecord{
+		Version:          "1.29.0",
+		NodeupConfigHash: "hash-1",
+		Started:          started,
+	}
+	results := []fi.TaskResult{
+		{
+			Key:      "Package/containerd",
+			Started:  started.Add(time.Second),
+			Duration: 4 * time.Minute,
+			Attempts: 3,
+			Done:     true,
+			Changed:  true,
+		},
+		{
+			Key:       "Service/kubelet.service",
+			Started:   started.Add(2 * time.Second),
+			Duration:  time.Second,
+			Attempts:  1,
+			LastError: errors.New("unit not found"),
+		},
+		{
+			Key: "File//etc/kubernetes/kubelet.conf",
+		},
+	}
+	buildRunRecord(record, results, started.Add(5*time.Minute), errors.New("deadline exceeded"))
+
+	path := filepath.Join(t.TempDir(), "log", "nodeup-run.json")
+	if err := writeRunLog(path, record); err != nil {
+		t.Fatalf("writing run log: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading run log: %v", err)
+	}
+
+	expected := `{
+  "version": "1.29.0",
+  "nodeupConfigHash": "hash-1",
+  "started": "2024-01-01T10:00:00Z",
+  "finished": "2024-01-01T10:05:00Z",
+  "durationSeconds": 300,
+  "error": "deadline exceeded",
+  "tasks": [
+    {
+      "key": "Package/containerd",
+      "started": "2024-01-01T10:00:01Z",
+      "durationSeconds": 240,
+      "retries": 2,
+      "done": true,
+      "changed": true
+    },
+    {
+      "key": "Service/kubelet.service",
+      "started": "2024-01-01T10:00:02Z",
+      "durationSeconds": 1,
+      "retries": 0,
+      "done": false,
+      "changed": false,
+      "lastError": "unit not found"
+    },
+    {
+      "key": "File//etc/kubernetes/kubelet.conf",
+      "durationSeconds": 0,
+      "retries": 0,
+      "done": false,
+      "changed": false
+    }
+  ]
+}
+`
+	assert.Equal(t, expected, string(b))
+
+	var parsed RunRecord
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		t.Fatalf("parsing run log: %v", err)
+	}
+	assert.Equal(t, record, &parsed)
+}
@@ -22,19 +22,25 @@ import (
 	"fmt"
 	"os"
 
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
 type VFSSecretStoreReader struct {
 	basedir vfs.Path
+
+	// encryption configures the keys which secrets are encrypted with, or is nil if they are not encrypted.
+	encryption *kops.StateStoreEncryptionSpec
 }
 
 var _ fi.SecretStoreReader = &VFSSecretStoreReader{}
 
-func NewVFSSecretStoreReader(basedir vfs.Path) fi.SecretStoreReader {
+func NewVFSSecretStoreReader(basedir vfs.Path, encryption *kops.StateStoreEncryptionSpec) fi.SecretStoreReader {
 	c := &VFSSecretStoreReader{
-		basedir: basedir,
+		basedir:    basedir,
+		encryption: encryption,
 	}
 	return c
 }
@@ -85,5 +91,9 @@ func (c *VFSSecretStoreReader) loadSecret(ctx context.Context, p vfs.Path) (*fi.
 	if err != nil {
 		return nil, fmt.Errorf("parsing secret from %q: %v", p, err)
 	}
+	s.Data, err = envelope.Decrypt(ctx, c.encryption, s.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secret from %q: %w", p, err)
+	}
 	return s, nil
 }
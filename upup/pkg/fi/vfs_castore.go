@@ -30,6 +30,7 @@ import (
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/sshcredentials"
 	"k8s.io/kops/util/pkg/vfs"
@@ -52,6 +53,9 @@ func NewVFSCAStore(cluster *kops.Cluster, basedir vfs.Path) *VFSCAStore {
 		},
 		cluster: cluster,
 	}
+	if cluster != nil {
+		c.encryption = cluster.Spec.StateStoreEncryption
+	}
 
 	return c
 }
@@ -127,6 +131,10 @@ func writeKeysetBundle(ctx context.Context, cluster *kops.Cluster, p vfs.Path, n
 		return err
 	}
 
+	if err := encryptPrivateMaterial(ctx, cluster, o); err != nil {
+		return err
+	}
+
 	objectData, err := serializeKeysetBundle(o)
 	if err != nil {
 		return err
@@ -139,6 +147,26 @@ func writeKeysetBundle(ctx context.Context, cluster *kops.Cluster, p vfs.Path, n
 	return p.WriteFile(ctx, bytes.NewReader(objectData), acl)
 }
 
+// encryptPrivateMaterial encrypts the private keys of a Keyset bundle, if the cluster's state store is encrypted.
+func encryptPrivateMaterial(ctx context.Context, cluster *kops.Cluster, o *kops.Keyset) error {
+	kms, err := envelope.ForCluster(cluster)
+	if err != nil || kms == nil {
+		return err
+	}
+
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		if len(key.PrivateMaterial) == 0 {
+			continue
+		}
+		key.PrivateMaterial, err = envelope.Encrypt(ctx, kms, key.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("encrypting private key %q: %w", key.Id, err)
+		}
+	}
+	return nil
+}
+
 // serializeKeysetBundle converts a Keyset bundle to yaml, for writing to VFS.
 func serializeKeysetBundle(o *kops.Keyset) ([]byte, error) {
 	var objectData bytes.Buffer
@@ -18,12 +18,17 @@ package fi
 
 import (
 	"context"
+	"crypto/x509/pkix"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -158,3 +163,115 @@ spec:
 		}
 	}
 }
+
+func TestVFSCAStoreEncryption(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=\n"), 0o600); err != nil {
+		t.Fatalf("error writing key file: %v", err)
+	}
+	cluster := &kops.Cluster{}
+	cluster.Spec.StateStoreEncryption = &kops.StateStoreEncryptionSpec{KeyFile: keyFile}
+
+	cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes-ca"},
+		Serial:  big.NewInt(1),
+	}, nil)
+	if err != nil {
+		t.Fatalf("error from IssueCert: %v", err)
+	}
+	item := &KeysetItem{
+		Id:          "1",
+		Certificate: cert,
+		PrivateKey:  privateKey,
+	}
+	keyset := &Keyset{
+		Items:   map[string]*KeysetItem{"1": item},
+		Primary: item,
+	}
+
+	privateMaterial := func() []byte {
+		data, err := basePath.Join("private", "kubernetes-ca", "keyset.yaml").ReadFile(ctx)
+		if err != nil {
+			t.Fatalf("error reading keyset.yaml: %v", err)
+		}
+		o, _, err := NewVFSKeystoreReader(basePath, nil).parseKeysetYaml(data)
+		if err != nil {
+			t.Fatalf("error parsing keyset.yaml: %v", err)
+		}
+		return o.Spec.Keys[0].PrivateMaterial
+	}
+
+	// A keyset stored before encryption was enabled is reported as being in a legacy format.
+	if err := NewVFSCAStore(&kops.Cluster{}, basePath).StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+	if envelope.IsEncrypted(privateMaterial()) {
+		t.Fatalf("private key was encrypted without encryption configured")
+	}
+
+	s := NewVFSCAStore(cluster, basePath)
+	loaded, err := s.FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error from FindKeyset: %v", err)
+	}
+	if !loaded.LegacyFormat {
+		t.Errorf("unencrypted keyset was not reported as being in a legacy format")
+	}
+
+	// Storing the keyset again encrypts its private key.
+	if err := s.StoreKeyset(ctx, "kubernetes-ca", loaded); err != nil {
+		t.Fatalf("error from StoreKeyset: %v", err)
+	}
+	if !envelope.IsEncrypted(privateMaterial()) {
+		t.Fatalf("private key was not encrypted")
+	}
+
+	// Readers configured with the key decrypt the private key.
+	for _, reader := range []KeystoreReader{NewVFSCAStore(cluster, basePath), NewVFSKeystoreReader(basePath, cluster.Spec.StateStoreEncryption)} {
+		loaded, err := reader.FindKeyset(ctx, "kubernetes-ca")
+		if err != nil {
+			t.Fatalf("error from FindKeyset: %v", err)
+		}
+		if loaded.LegacyFormat {
+			t.Errorf("encrypted keyset was reported as being in a legacy format")
+		}
+		expected, _ := privateKey.AsString()
+		actual, _ := loaded.Primary.PrivateKey.AsString()
+		if actual != expected {
+			t.Errorf("unexpected decrypted private key: %q", actual)
+		}
+	}
+
+	// Readers without the key do not use the key recorded in the keyset.
+	if _, err := NewVFSKeystoreReader(basePath, nil).FindKeyset(ctx, "kubernetes-ca"); err == nil {
+		t.Errorf("expected error reading encrypted keyset without encryption configured")
+	}
+
+	// After the key is rotated, keysets encrypted with the previous key are reported as being in a legacy format.
+	newKeyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(newKeyFile, []byte("ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=\n"), 0o600); err != nil {
+		t.Fatalf("error writing key file: %v", err)
+	}
+	rotated := &kops.Cluster{}
+	rotated.Spec.StateStoreEncryption = &kops.StateStoreEncryptionSpec{
+		KeyFile:      newKeyFile,
+		PreviousKeys: []kops.StateStoreEncryptionKeySpec{{KeyFile: keyFile}},
+	}
+	loaded, err = NewVFSCAStore(rotated, basePath).FindKeyset(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error from FindKeyset: %v", err)
+	}
+	if !loaded.LegacyFormat {
+		t.Errorf("keyset encrypted with a previous key was not reported as being in a legacy format")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -32,15 +33,21 @@ import (
 type VFSKeystoreReader struct {
 	basedir vfs.Path
 
+	// encryption configures the keys which private keys are encrypted with, or is nil if they are not encrypted.
+	// Keysets with private keys which are unencrypted, or encrypted with a previous key,
+	// are reported as being in a legacy format, so that they are rewritten.
+	encryption *kops.StateStoreEncryptionSpec
+
 	mutex    sync.Mutex
 	cachedCA *Keyset
 }
 
 var _ KeystoreReader = &VFSKeystoreReader{}
 
-func NewVFSKeystoreReader(basedir vfs.Path) *VFSKeystoreReader {
+func NewVFSKeystoreReader(basedir vfs.Path, encryption *kops.StateStoreEncryptionSpec) *VFSKeystoreReader {
 	k := &VFSKeystoreReader{
-		basedir: basedir,
+		basedir:    basedir,
+		encryption: encryption,
 	}
 
 	return k
@@ -92,6 +99,20 @@ func (c *VFSKeystoreReader) loadKeyset(ctx context.Context, p vfs.Path) (*Keyset
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
 	}
 
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		if len(key.PrivateMaterial) == 0 {
+			continue
+		}
+		if c.encryption != nil && !envelope.IsEncryptedWith(c.encryption, key.PrivateMaterial) {
+			legacyFormat = true
+		}
+		key.PrivateMaterial, err = envelope.Decrypt(ctx, c.encryption, key.PrivateMaterial)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting private key %q in bundle %q: %w", key.Id, p, err)
+		}
+	}
+
 	keyset, err := parseKeyset(o)
 	if err != nil {
 		return nil, fmt.Errorf("error mapping bundle %q: %v", p, err)
@@ -110,7 +131,8 @@ var legacyKeysetMappings = map[string]string{
 
 func (c *VFSKeystoreReader) FindKeyset(ctx context.Context, id string) (*Keyset, error) {
 	keys, err := c.findPrivateKeyset(ctx, id)
-	if keys == nil || os.IsNotExist(err) {
+	// Errors such as a failure to decrypt the keyset must not be mistaken for a missing keyset.
+	if (keys == nil && err == nil) || os.IsNotExist(err) {
 		if legacyId := legacyKeysetMappings[id]; legacyId != "" {
 			keys, err = c.findPrivateKeyset(ctx, legacyId)
 			if keys != nil {
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"k8s.io/klog/v2"
 
@@ -74,15 +75,24 @@ func ValueAsString(value reflect.Value) string {
 			return SkipReflection
 
 		case reflect.Map:
-			keys := v.MapKeys()
+			// The keys are sorted, so that the same map is always printed the same way
+			type mapEntry struct {
+				key   string
+				value reflect.Value
+			}
+			var entries []mapEntry
+			for _, key := range v.MapKeys() {
+				entries = append(entries, mapEntry{key: ValueAsString(key), value: v.MapIndex(key)})
+			}
+			sort.Slice(entries, func(i, j int) bool {
+				return entries[i].key < entries[j].key
+			})
 			fmt.Fprintf(b, "{")
-			for i, key := range keys {
-				mv := v.MapIndex(key)
-
+			for i, entry := range entries {
 				if i != 0 {
 					fmt.Fprintf(b, ", ")
 				}
-				fmt.Fprintf(b, "%s: %s", ValueAsString(key), ValueAsString(mv))
+				fmt.Fprintf(b, "%s: %s", entry.key, ValueAsString(entry.value))
 			}
 			fmt.Fprintf(b, "}")
 			return SkipReflection